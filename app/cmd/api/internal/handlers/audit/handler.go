@@ -0,0 +1,171 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AuditHandler struct {
+	logger       *zap.Logger
+	auditService audit.AuditService
+}
+
+// New creates a new instance of AuditHandler with the provided logger and audit service.
+// Parameters:
+//   - logger: an instance of zap.Logger used for logging within the handler.
+//   - auditService: an instance of audit.AuditService that reads the audit log of transactions.
+//
+// Returns:
+//   - A pointer to a newly created AuditHandler.
+func New(logger *zap.Logger, auditService audit.AuditService) *AuditHandler {
+	return &AuditHandler{
+		logger:       logger,
+		auditService: auditService,
+	}
+}
+
+// GetHistoryHandler handles the request to retrieve every recorded mutation of a transaction, oldest first.
+// A merchant only sees the history of its own transactions, and the transactions of other merchants are not found.
+//
+// @Summary Retrieve the audit log of a transaction
+// @Tags transactions
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Success 200 {object} []audit.Entry
+// @Failure 401 {object} []utils.Errors "Missing or unknown API key"
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /gateways/transactions/{id}/history [get]
+func (c *AuditHandler) GetHistoryHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	id := ctx.Param("id")
+	c.logger.Info("Starting request to get transaction history", zap.String("correlation_id", correlationId), zap.String("transaction_id", id))
+
+	result, err := c.auditService.GetHistory(id)
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to get transaction history", err)
+		return
+	}
+
+	if !ownsHistory(result, identity.Id) {
+		c.errorResponse(ctx, correlationId, "Transaction history of another merchant requested", audit.ErrHistoryNotFound)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully retrieved transaction history", zap.String("correlation_id", correlationId), zap.Int("entry_count", len(result)))
+}
+
+// GetStateAtHandler handles the request to reconstruct the state of a transaction at a point in time.
+// It expects an optional query parameter "at" in RFC 3339 format. If it is not provided, the current time is used.
+// A merchant only sees the state of its own transactions, and the transactions of other merchants are not found.
+//
+// @Summary Reconstruct the state of a transaction at a point in time
+// @Tags transactions
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Param at query string false "Point in time in RFC 3339 format"
+// @Success 200 {object} audit.State
+// @Failure 400 {object} []utils.Errors
+// @Failure 401 {object} []utils.Errors "Missing or unknown API key"
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /gateways/transactions/{id}/state [get]
+func (c *AuditHandler) GetStateAtHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	at := time.Now()
+	if value := ctx.Query("at"); !utils.IsEmptyOrNull(value) {
+		at, err = time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			c.logger.Error("Invalid point in time", zap.String("correlation_id", correlationId), zap.Error(err))
+			utils.ApiResponse(ctx, http.StatusBadRequest, []utils.Errors{{Field: "at", Message: "at must be a date in RFC 3339 format"}})
+			return
+		}
+	}
+
+	id := ctx.Param("id")
+	c.logger.Info("Starting request to get transaction state", zap.String("correlation_id", correlationId), zap.String("transaction_id", id))
+
+	result, err := c.auditService.GetStateAt(id, at)
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to get transaction state", err)
+		return
+	}
+
+	if merchantOf(result.State) != identity.Id {
+		c.errorResponse(ctx, correlationId, "Transaction state of another merchant requested", audit.ErrHistoryNotFound)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully retrieved transaction state", zap.String("correlation_id", correlationId), zap.String("transaction_id", id))
+}
+
+// errorResponse logs the error and responds with 404 Not Found for transactions without history at the
+// requested time, or 500 Internal Server Error otherwise.
+func (c *AuditHandler) errorResponse(ctx *gin.Context, correlationId string, message string, err error) {
+	c.logger.Error(message, zap.String("correlation_id", correlationId), zap.Error(err))
+
+	if errors.Is(err, audit.ErrHistoryNotFound) || errors.Is(err, audit.ErrNoStateAt) {
+		utils.ApiResponse(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+}
+
+// ownsHistory reports whether the transaction of an audit log belongs to a merchant, as recorded by the
+// first snapshot of the transaction.
+func ownsHistory(entries []audit.Entry, merchantId string) bool {
+	for _, entry := range entries {
+		for _, snapshot := range []json.RawMessage{entry.After, entry.Before} {
+			if owner := merchantOf(snapshot); owner != "" {
+				return owner == merchantId
+			}
+		}
+	}
+	return false
+}
+
+// merchantOf returns the merchant of a transaction snapshot, or an empty string if it has none.
+func merchantOf(snapshot json.RawMessage) string {
+	var transaction struct {
+		MerchantId string `json:"merchant_id"`
+	}
+	if err := json.Unmarshal(snapshot, &transaction); err != nil {
+		return ""
+	}
+	return transaction.MerchantId
+}
@@ -0,0 +1,204 @@
+package audit_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	handler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/handlers/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type AuditServiceMock struct {
+	mock.Mock
+}
+
+func (m *AuditServiceMock) Record(transactionId string, action string, origin audit.Origin, before interface{}, after interface{}) error {
+	args := m.Called(transactionId, action, origin, before, after)
+	return args.Error(0)
+}
+
+func (m *AuditServiceMock) Write(key string, item interface{}, transactionId string, changes ...audit.Change) error {
+	args := m.Called(key, item, transactionId, changes)
+	return args.Error(0)
+}
+
+func (m *AuditServiceMock) GetHistory(transactionId string) ([]audit.Entry, error) {
+	args := m.Called(transactionId)
+	var result []audit.Entry
+	if args.Get(0) != nil {
+		result = args.Get(0).([]audit.Entry)
+	}
+	return result, args.Error(1)
+}
+
+func (m *AuditServiceMock) GetStateAt(transactionId string, at time.Time) (*audit.State, error) {
+	args := m.Called(transactionId, at)
+	var result *audit.State
+	if args.Get(0) != nil {
+		result = args.Get(0).(*audit.State)
+	}
+	return result, args.Error(1)
+}
+
+var merchant = middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant}
+
+func TestGetHistoryHandler_Success(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockAuditService := new(AuditServiceMock)
+	handler := handler.New(zap.NewNop(), mockAuditService)
+
+	mockAuditService.On("GetHistory", "transaction1").Return([]audit.Entry{{Id: "entry1", After: json.RawMessage(`{"id":"transaction1","merchant_id":"merchant1"}`)}}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, merchant)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/gateways/transactions/transaction1/history", nil)
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+	ctx.Params = gin.Params{{Key: "id", Value: "transaction1"}}
+
+	// Action
+	handler.GetHistoryHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockAuditService.AssertExpectations(t)
+}
+
+func TestGetHistoryHandler_NotFound(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockAuditService := new(AuditServiceMock)
+	handler := handler.New(zap.NewNop(), mockAuditService)
+
+	mockAuditService.On("GetHistory", "transaction1").Return(nil, audit.ErrHistoryNotFound)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, merchant)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/gateways/transactions/transaction1/history", nil)
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+	ctx.Params = gin.Params{{Key: "id", Value: "transaction1"}}
+
+	// Action
+	handler.GetHistoryHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockAuditService.AssertExpectations(t)
+}
+
+func TestGetStateAtHandler_Success(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockAuditService := new(AuditServiceMock)
+	handler := handler.New(zap.NewNop(), mockAuditService)
+
+	at := time.Date(2024, 10, 1, 10, 3, 0, 0, time.UTC)
+	mockAuditService.On("GetStateAt", "transaction1", at).Return(&audit.State{TransactionId: "transaction1", State: json.RawMessage(`{"id":"transaction1","merchant_id":"merchant1"}`)}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, merchant)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/gateways/transactions/transaction1/state?at=2024-10-01T10:03:00Z", nil)
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+	ctx.Params = gin.Params{{Key: "id", Value: "transaction1"}}
+
+	// Action
+	handler.GetStateAtHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockAuditService.AssertExpectations(t)
+}
+
+func TestGetStateAtHandler_Failure_InvalidAt(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockAuditService := new(AuditServiceMock)
+	handler := handler.New(zap.NewNop(), mockAuditService)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, merchant)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/gateways/transactions/transaction1/state?at=yesterday", nil)
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+	ctx.Params = gin.Params{{Key: "id", Value: "transaction1"}}
+
+	// Action
+	handler.GetStateAtHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockAuditService.AssertExpectations(t)
+}
+
+func TestAuditHandlers_OtherMerchant(t *testing.T) {
+	tests := []struct {
+		name    string
+		arrange func(auditService *AuditServiceMock)
+		handle  func(h *handler.AuditHandler) gin.HandlerFunc
+	}{
+		{"History", func(auditService *AuditServiceMock) {
+			auditService.On("GetHistory", "transaction1").Return([]audit.Entry{
+				{Id: "entry1", Before: json.RawMessage(`null`), After: json.RawMessage(`{"id":"transaction1","merchant_id":"merchant2"}`)},
+			}, nil)
+		}, func(h *handler.AuditHandler) gin.HandlerFunc { return h.GetHistoryHandler }},
+		{"State", func(auditService *AuditServiceMock) {
+			auditService.On("GetStateAt", "transaction1", mock.Anything).Return(&audit.State{TransactionId: "transaction1", State: json.RawMessage(`{"id":"transaction1","merchant_id":"merchant2"}`)}, nil)
+		}, func(h *handler.AuditHandler) gin.HandlerFunc { return h.GetStateAtHandler }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			mockAuditService := new(AuditServiceMock)
+			handler := handler.New(zap.NewNop(), mockAuditService)
+			tt.arrange(mockAuditService)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			middleware.SetIdentity(ctx, merchant)
+			ctx.Request, _ = http.NewRequest(http.MethodGet, "/gateways/transactions/transaction1", nil)
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+			ctx.Params = gin.Params{{Key: "id", Value: "transaction1"}}
+
+			// Action
+			tt.handle(handler)(ctx)
+
+			// Assert
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			mockAuditService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetHistoryHandler_Unauthenticated(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockAuditService := new(AuditServiceMock)
+	handler := handler.New(zap.NewNop(), mockAuditService)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/gateways/transactions/transaction1/history", nil)
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+	ctx.Params = gin.Params{{Key: "id", Value: "transaction1"}}
+
+	// Action
+	handler.GetHistoryHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockAuditService.AssertExpectations(t)
+}
@@ -1,10 +1,14 @@
 package currency
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/quote"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -13,25 +17,29 @@ import (
 type CurrencyHandler struct {
 	logger          *zap.Logger
 	currencyService currency.CurrencyService
+	quoteService    quote.QuoteService
 }
 
-// New creates a new instance of CurrencyHandler with the provided logger and currency service.
+// New creates a new instance of CurrencyHandler with the provided logger, currency service and quote service.
 // Parameters:
 //   - logger: an instance of zap.Logger used for logging within the handler.
 //   - currencyService: an instance of currency.CurrencyService that provides currency-related operations.
+//   - quoteService: an instance of quote.QuoteService that locks conversions for a while.
 //
 // Returns:
 //   - A pointer to a newly created CurrencyHandler.
-func New(logger *zap.Logger, currencyService currency.CurrencyService) *CurrencyHandler {
+func New(logger *zap.Logger, currencyService currency.CurrencyService, quoteService quote.QuoteService) *CurrencyHandler {
 	return &CurrencyHandler{
 		logger:          logger,
 		currencyService: currencyService,
+		quoteService:    quoteService,
 	}
 }
 
 // GetAllCurrencyHandler handles the request to retrieve all currencies.
 // It extracts the correlation ID from the context, logs the request initiation,
 // calls the currency service to get all currencies, and returns the result in the response.
+// With the query parameter "expand=metadata", the ISO 4217 metadata of each currency is returned instead of its code.
 // If any error occurs during the process, it logs the error and returns an appropriate
 // HTTP response with the error message.
 //
@@ -40,6 +48,7 @@ func New(logger *zap.Logger, currencyService currency.CurrencyService) *Currency
 // @Tags currency
 // @Accept json
 // @Produce json
+// @Param expand query string false "metadata to return the ISO 4217 metadata of each currency"
 // @Success 200 {object} []Currency
 // @Failure 400 {object} ErrorResponse
 // @Router /currencies [get]
@@ -55,7 +64,17 @@ func (c *CurrencyHandler) GetAllCurrencyHandler(ctx *gin.Context) {
 
 	c.logger.Info("Initialize request to get all currency", zap.String("correlation_id", correlationId))
 
-	result, err := c.currencyService.GetAllCurrency()
+	var result any
+	switch expand := ctx.Query("expand"); expand {
+	case "":
+		result, err = c.currencyService.GetAllCurrency()
+	case "metadata":
+		result, err = c.currencyService.GetAllCurrencyMetadata()
+	default:
+		c.logger.Error("Unsupported expand", zap.String("correlation_id", correlationId), zap.String("expand", expand))
+		utils.ApiResponse(ctx, http.StatusBadRequest, []utils.Errors{{Field: "expand", Message: "expand must be metadata"}})
+		return
+	}
 
 	if err != nil {
 		c.logger.Error("Failed to get all currency", zap.String("CorrelationId", correlationId), zap.Error(err))
@@ -67,9 +86,180 @@ func (c *CurrencyHandler) GetAllCurrencyHandler(ctx *gin.Context) {
 	c.logger.Info("Successfully retrieved all currency", zap.String("CorrelationId", correlationId))
 }
 
+// GetCurrencyHandler handles the request to retrieve the ISO 4217 metadata of a currency:
+// its name, numeric code, symbol, minor units and the countries that use it.
+//
+// @Summary Retrieve the metadata of a currency
+// @Tags currency
+// @Produce json
+// @Param code path string true "ISO 4217 currency code"
+// @Success 200 {object} iso4217.Currency
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Router /currencies/{code} [get]
+func (c *CurrencyHandler) GetCurrencyHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	code := ctx.Param("code")
+	c.logger.Info("Starting request to get currency", zap.String("correlation_id", correlationId), zap.String("code", code))
+
+	result, err := c.currencyService.GetCurrency(code)
+	if err != nil {
+		c.logger.Error("Failed to get currency", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully retrieved currency", zap.String("correlation_id", correlationId), zap.String("code", result.Code))
+}
+
+// GetRatesHandler handles the request to retrieve the exchange rates of a day.
+// It expects an optional query parameter "date" in the format YYYY-MM-DD. If it is not provided, the latest rates are returned.
+//
+// @Summary Retrieve the exchange rates of a day
+// @Tags currency
+// @Produce json
+// @Param date query string false "Day of the rates in the format YYYY-MM-DD"
+// @Success 200 {object} models.CurrencyDataResponse
+// @Failure 400 {object} []utils.Errors
+// @Failure 500 {object} string
+// @Router /currencies/rates [get]
+func (c *CurrencyHandler) GetRatesHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	date := ctx.Query("date")
+	c.logger.Info("Starting request to get exchange rates", zap.String("correlation_id", correlationId), zap.String("date", date))
+
+	result, err := c.currencyService.GetRates(date)
+	if err != nil {
+		c.logger.Error("Failed to get exchange rates", zap.String("correlation_id", correlationId), zap.Error(err))
+
+		if errors.Is(err, currency.ErrInvalidDate) {
+			utils.ApiResponse(ctx, http.StatusBadRequest, []utils.Errors{{Field: "date", Message: err.Error()}})
+			return
+		}
+
+		utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully retrieved exchange rates", zap.String("correlation_id", correlationId), zap.String("date", result.Date))
+}
+
+// FormatAmountHandler handles the request to format an amount of a currency for a locale.
+// It expects the query parameters "amount", "currency" and "locale", such as "pt_BR".
+//
+// @Summary Format an amount of a currency for a locale
+// @Tags currency
+// @Produce json
+// @Param amount query number true "Amount"
+// @Param currency query string true "ISO 4217 currency code"
+// @Param locale query string true "Locale, such as pt_BR or en-US"
+// @Success 200 {object} models.CurrencyFormatResponse
+// @Failure 400 {object} []utils.Errors
+// @Router /currencies/format [get]
+func (c *CurrencyHandler) FormatAmountHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var query models.CurrencyFormat
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		c.logger.Error("Failed to bind query parameters", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, utils.ValidatorError(err))
+		return
+	}
+	query.Currency = strings.ToUpper(query.Currency)
+
+	c.logger.Info("Starting request to format amount", zap.String("correlation_id", correlationId), zap.String("currency", query.Currency), zap.String("locale", query.Locale))
+
+	result, err := c.currencyService.FormatAmount(query)
+	if err != nil {
+		c.logger.Error("Failed to format amount", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, []utils.Errors{{Field: "locale", Message: err.Error()}})
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully formatted amount", zap.String("correlation_id", correlationId))
+}
+
+// GetTimeSeriesHandler handles the request to retrieve the daily exchange rates of currencies over a range of days.
+// It expects the query parameters "base", "symbols" (comma-separated), "from" and "to" in the format YYYY-MM-DD,
+// and returns the rates of each day with the minimum, maximum, mean and percent change of each symbol.
+//
+// @Summary Retrieve the exchange rates of currencies over a range of days
+// @Tags currency
+// @Produce json
+// @Param base query string true "Base currency"
+// @Param symbols query string true "Comma-separated currencies"
+// @Param from query string true "First day in the format YYYY-MM-DD"
+// @Param to query string true "Last day in the format YYYY-MM-DD"
+// @Success 200 {object} models.CurrencyTimeSeriesResponse
+// @Failure 400 {object} []utils.Errors
+// @Failure 500 {object} string
+// @Router /currencies/timeseries [get]
+func (c *CurrencyHandler) GetTimeSeriesHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var query models.CurrencyTimeSeries
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		c.logger.Error("Failed to bind query parameters", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, utils.ValidatorError(err))
+		return
+	}
+	query.Base = strings.ToUpper(query.Base)
+
+	c.logger.Info("Starting request to get exchange rate time series", zap.String("correlation_id", correlationId), zap.String("from", query.From), zap.String("to", query.To))
+
+	result, err := c.currencyService.GetTimeSeries(query)
+	if err != nil {
+		c.logger.Error("Failed to get exchange rate time series", zap.String("correlation_id", correlationId), zap.Error(err))
+
+		switch {
+		case errors.Is(err, currency.ErrInvalidDate), errors.Is(err, currency.ErrInvalidRange):
+			utils.ApiResponse(ctx, http.StatusBadRequest, []utils.Errors{{Field: "from", Message: err.Error()}})
+		case errors.Is(err, currency.ErrMissingCurrency):
+			utils.ApiResponse(ctx, http.StatusBadRequest, []utils.Errors{{Field: "symbols", Message: err.Error()}})
+		default:
+			utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+		}
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully retrieved exchange rate time series", zap.String("correlation_id", correlationId), zap.Int("day_count", len(result.Rates)))
+}
+
 // ConvertExchangeRateHandler handles the request to convert currency exchange rates.
 // It retrieves the correlation ID from the context, binds the JSON payload to the CurrencyConvert model,
-// and calls the currencyService to perform the conversion. If any error occurs during these steps,
+// and calls the currencyService to perform the conversion. When the payload references a quote, the quote is used
+// instead of the current rates, and it is rejected if it expired, was already used or does not match the payload.
+// If any error occurs during these steps,
 // it logs the error and sends an appropriate HTTP response. On success, it returns the conversion result
 // and logs the successful completion of the request.
 //
@@ -81,6 +271,7 @@ func (c *CurrencyHandler) GetAllCurrencyHandler(ctx *gin.Context) {
 // @Param payload body models.CurrencyConvert true "Currency conversion payload"
 // @Success 200 {object} models.CurrencyConvertResponse
 // @Failure 400 {object} utils.ApiErrorResponse
+// @Failure 401 {object} []utils.Errors "Missing or unknown API key"
 // @Router /currency/convert [post]
 func (c *CurrencyHandler) ConvertExchangeRateHandler(ctx *gin.Context) {
 
@@ -91,19 +282,38 @@ func (c *CurrencyHandler) ConvertExchangeRateHandler(ctx *gin.Context) {
 		return
 	}
 
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
 	var payload models.CurrencyConvert
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
 		c.logger.Error("Failed to bind JSON payload", zap.String("correlation_id", correlationId), zap.Error(err))
 		utils.ApiResponse(ctx, http.StatusBadRequest, utils.ValidatorError(err))
 		return
 	}
+	payload.FromCurrency = strings.ToUpper(payload.FromCurrency)
+	payload.ToCurrency = strings.ToUpper(payload.ToCurrency)
+	payload.MerchantId = identity.Id
 
-	c.logger.Info("Starting currency conversion request", zap.String("correlation_id", correlationId))
+	c.logger.Info("Starting currency conversion request", zap.String("correlation_id", correlationId), zap.String("quote_id", payload.QuoteId))
 
-	res, err := c.currencyService.ConvertExchangeRate(payload)
+	var res *models.CurrencyConvertResponse
+	if payload.QuoteId != "" {
+		res, err = c.quoteService.RedeemForConversion(payload.QuoteId, payload)
+	} else {
+		res, err = c.currencyService.ConvertExchangeRate(payload)
+	}
 
 	if err != nil {
 		c.logger.Error("Currency conversion failed", zap.String("correlation_id", correlationId), zap.Error(err))
+
+		if isQuoteError(err) {
+			utils.ApiResponse(ctx, http.StatusBadRequest, []utils.Errors{{Field: "quote_id", Message: err.Error()}})
+			return
+		}
+
 		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -111,3 +321,161 @@ func (c *CurrencyHandler) ConvertExchangeRateHandler(ctx *gin.Context) {
 	utils.ApiResponse(ctx, http.StatusOK, res)
 	c.logger.Info("Currency conversion completed successfully", zap.String("correlation_id", correlationId))
 }
+
+// ConvertExchangeRateBatchHandler handles the request to convert the amounts of many items at once.
+// Every item is converted with the same snapshot of rates, and the response has the result or the error
+// of each item in the order of the items.
+//
+// @Summary Convert many amounts between currencies
+// @Description Converts up to 10000 items with one snapshot of the exchange rates
+// @Tags currency
+// @Accept json
+// @Produce json
+// @Param payload body models.CurrencyConvertBatch true "Batch conversion payload"
+// @Success 200 {object} models.CurrencyConvertBatchResponse
+// @Failure 400 {object} utils.ApiErrorResponse
+// @Failure 401 {object} []utils.Errors "Missing or unknown API key"
+// @Router /currencies/convert/batch [post]
+func (c *CurrencyHandler) ConvertExchangeRateBatchHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	var payload models.CurrencyConvertBatch
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		c.logger.Error("Failed to bind JSON payload", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, utils.ValidatorError(err))
+		return
+	}
+	for i := range payload.Items {
+		payload.Items[i].FromCurrency = strings.ToUpper(payload.Items[i].FromCurrency)
+		payload.Items[i].ToCurrency = strings.ToUpper(payload.Items[i].ToCurrency)
+	}
+	payload.MerchantId = identity.Id
+
+	c.logger.Info("Starting batch currency conversion request", zap.String("correlation_id", correlationId), zap.Int("item_count", len(payload.Items)))
+
+	res, err := c.currencyService.ConvertExchangeRateBatch(payload)
+	if err != nil {
+		c.logger.Error("Batch currency conversion failed", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, res)
+	c.logger.Info("Batch currency conversion completed successfully", zap.String("correlation_id", correlationId))
+}
+
+// CreateQuoteHandler handles the request to quote a conversion. The quote locks the rate and amounts of the
+// conversion until it expires, and its ID can be used once by a later conversion or payment.
+//
+// @Summary Quote a conversion
+// @Tags currency
+// @Accept json
+// @Produce json
+// @Param payload body models.CurrencyQuoteCreate true "Quote payload"
+// @Success 201 {object} models.CurrencyQuote
+// @Failure 400 {object} utils.ApiErrorResponse
+// @Failure 401 {object} []utils.Errors "Missing or unknown API key"
+// @Router /currencies/quotes [post]
+func (c *CurrencyHandler) CreateQuoteHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	var payload models.CurrencyQuoteCreate
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		c.logger.Error("Failed to bind JSON payload", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, utils.ValidatorError(err))
+		return
+	}
+	payload.FromCurrency = strings.ToUpper(payload.FromCurrency)
+	payload.ToCurrency = strings.ToUpper(payload.ToCurrency)
+	payload.MerchantId = identity.Id
+
+	c.logger.Info("Starting request to create quote", zap.String("correlation_id", correlationId))
+
+	result, err := c.quoteService.CreateQuote(payload)
+	if err != nil {
+		c.logger.Error("Failed to create quote", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusCreated, result)
+	c.logger.Info("Successfully created quote", zap.String("correlation_id", correlationId), zap.String("quote_id", result.Id))
+}
+
+// GetQuoteHandler handles the request to retrieve a quote, with the time it was used if it was.
+// Only the merchant the quote was created for can retrieve it, and the quotes of other merchants are not found.
+//
+// @Summary Retrieve a quote
+// @Tags currency
+// @Produce json
+// @Param id path string true "Quote ID"
+// @Success 200 {object} models.CurrencyQuote
+// @Failure 401 {object} []utils.Errors "Missing or unknown API key"
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /currencies/quotes/{id} [get]
+func (c *CurrencyHandler) GetQuoteHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	id := ctx.Param("id")
+	c.logger.Info("Starting request to get quote", zap.String("correlation_id", correlationId), zap.String("quote_id", id))
+
+	result, err := c.quoteService.GetQuote(id)
+	if err == nil && result.MerchantId != identity.Id {
+		err = quote.ErrQuoteNotFound
+	}
+	if err != nil {
+		c.logger.Error("Failed to get quote", zap.String("correlation_id", correlationId), zap.Error(err))
+
+		if errors.Is(err, quote.ErrQuoteNotFound) {
+			utils.ApiResponse(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+
+		utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully retrieved quote", zap.String("correlation_id", correlationId), zap.String("quote_id", id))
+}
+
+// isQuoteError reports whether a quote cannot be used because it is unknown, expired, used, being used by another
+// request or does not match.
+func isQuoteError(err error) bool {
+	return errors.Is(err, quote.ErrQuoteNotFound) || errors.Is(err, quote.ErrQuoteExpired) ||
+		errors.Is(err, quote.ErrQuoteUsed) || errors.Is(err, quote.ErrQuoteInUse) || errors.Is(err, quote.ErrQuoteMismatch)
+}
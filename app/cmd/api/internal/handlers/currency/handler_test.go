@@ -4,10 +4,16 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/handlers/currency"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	currencyService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/quote"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/iso4217"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/money"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/assert/v2"
@@ -29,19 +35,129 @@ func (m *CurrencyServiceMock) GetAllCurrency() (*[]string, error) {
 	return result, args.Error(1)
 }
 
-func (m *CurrencyServiceMock) ConvertExchangeRate(currency models.CurrencyConvert) (*float64, error) {
+func (m *CurrencyServiceMock) GetAllCurrencyMetadata() (*[]iso4217.Currency, error) {
+	args := m.Called()
+	var result *[]iso4217.Currency
+	if args.Get(0) != nil {
+		res := args.Get(0).([]iso4217.Currency)
+		result = &res
+	}
+	return result, args.Error(1)
+}
+
+func (m *CurrencyServiceMock) GetCurrency(code string) (*iso4217.Currency, error) {
+	args := m.Called(code)
+	var result *iso4217.Currency
+	if args.Get(0) != nil {
+		result = args.Get(0).(*iso4217.Currency)
+	}
+	return result, args.Error(1)
+}
+
+func (m *CurrencyServiceMock) FormatAmount(query models.CurrencyFormat) (*models.CurrencyFormatResponse, error) {
+	args := m.Called(query)
+	var result *models.CurrencyFormatResponse
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyFormatResponse)
+	}
+	return result, args.Error(1)
+}
+
+func (m *CurrencyServiceMock) GetRates(date string) (*models.CurrencyDataResponse, error) {
+	args := m.Called(date)
+	var result *models.CurrencyDataResponse
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyDataResponse)
+	}
+	return result, args.Error(1)
+}
+
+func (m *CurrencyServiceMock) GetTimeSeries(query models.CurrencyTimeSeries) (*models.CurrencyTimeSeriesResponse, error) {
+	args := m.Called(query)
+	var result *models.CurrencyTimeSeriesResponse
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyTimeSeriesResponse)
+	}
+	return result, args.Error(1)
+}
+
+func (m *CurrencyServiceMock) ConvertExchangeRateBatch(batch models.CurrencyConvertBatch) (*models.CurrencyConvertBatchResponse, error) {
+	args := m.Called(batch)
+	var result *models.CurrencyConvertBatchResponse
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyConvertBatchResponse)
+	}
+	return result, args.Error(1)
+}
+
+func (m *CurrencyServiceMock) ConvertExchangeRate(currency models.CurrencyConvert) (*models.CurrencyConvertResponse, error) {
 	args := m.Called(currency.FromCurrency, currency.ToCurrency, currency.Amount)
-	result := args.Get(0).(float64)
-	return &result, args.Error(1)
+	var result *models.CurrencyConvertResponse
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyConvertResponse)
+	}
+	return result, args.Error(1)
+}
+
+type QuoteServiceMock struct {
+	mock.Mock
 }
 
+func (m *QuoteServiceMock) CreateQuote(request models.CurrencyQuoteCreate) (*models.CurrencyQuote, error) {
+	args := m.Called(request)
+	var result *models.CurrencyQuote
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyQuote)
+	}
+	return result, args.Error(1)
+}
+
+func (m *QuoteServiceMock) GetQuote(id string) (*models.CurrencyQuote, error) {
+	args := m.Called(id)
+	var result *models.CurrencyQuote
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyQuote)
+	}
+	return result, args.Error(1)
+}
+
+func (m *QuoteServiceMock) RedeemForConversion(id string, currency models.CurrencyConvert) (*models.CurrencyConvertResponse, error) {
+	args := m.Called(id, currency)
+	var result *models.CurrencyConvertResponse
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyConvertResponse)
+	}
+	return result, args.Error(1)
+}
+
+func (m *QuoteServiceMock) ReserveForPayment(payment models.Gateway) (*models.CurrencyQuote, error) {
+	args := m.Called(payment)
+	var result *models.CurrencyQuote
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyQuote)
+	}
+	return result, args.Error(1)
+}
+
+func (m *QuoteServiceMock) Commit(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *QuoteServiceMock) Release(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+var merchant = middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant}
+
 func TestGetAllCurrencyHandler_Success(t *testing.T) {
 
 	// Arrange
 	gin.SetMode(gin.TestMode)
 	mockCurrencyService := new(CurrencyServiceMock)
 	mockLogger := zap.NewNop()
-	handler := currency.New(mockLogger, mockCurrencyService)
+	handler := currency.New(mockLogger, mockCurrencyService, new(QuoteServiceMock))
 	mockCurrencies := []string{"USD", "EUR"}
 	mockCurrencyService.On("GetAllCurrency").Return(mockCurrencies, nil)
 
@@ -64,7 +180,7 @@ func TestGetAllCurrencyHandler_Failure_GetCorrelationId(t *testing.T) {
 	// Arrange
 	mockCurrencyService := new(CurrencyServiceMock)
 	mockLogger := zap.NewNop()
-	handler := currency.New(mockLogger, mockCurrencyService)
+	handler := currency.New(mockLogger, mockCurrencyService, new(QuoteServiceMock))
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
 	ctx.Request, _ = http.NewRequest(http.MethodGet, "/currencies", nil)
@@ -83,7 +199,7 @@ func TestGetAllCurrencyHandler_Failure_GetAllCurrency(t *testing.T) {
 	// Arrange
 	mockCurrencyService := new(CurrencyServiceMock)
 	mockLogger := zap.NewNop()
-	handler := currency.New(mockLogger, mockCurrencyService)
+	handler := currency.New(mockLogger, mockCurrencyService, new(QuoteServiceMock))
 	mockCurrencyService.On("GetAllCurrency").Return(nil, errors.New("service error"))
 
 	w := httptest.NewRecorder()
@@ -105,19 +221,20 @@ func TestConvertExchangeRateHandler_Success(t *testing.T) {
 
 	mockCurrencyService := new(CurrencyServiceMock)
 	mockLogger := zap.NewNop()
-	handler := currency.New(mockLogger, mockCurrencyService)
+	handler := currency.New(mockLogger, mockCurrencyService, new(QuoteServiceMock))
 
 	payload := models.CurrencyConvert{
 		FromCurrency: "USD",
 		ToCurrency:   "EUR",
 		Amount:       100,
 	}
-	expectedResult := 85.0
+	expectedResult := &models.CurrencyConvertResponse{Amount: 85.0, FromCurrency: "USD", ToCurrency: "EUR", Provider: "ecb"}
 
 	mockCurrencyService.On("ConvertExchangeRate", payload.FromCurrency, payload.ToCurrency, payload.Amount).Return(expectedResult, nil)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, merchant)
 	ctx.Request, _ = http.NewRequest(http.MethodPost, "/currency/convert", utils.ToJSONReader(payload))
 	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
 
@@ -135,10 +252,11 @@ func TestConvertExchangeRateHandler_Failure_GetCorrelationId(t *testing.T) {
 	// Arrange
 	mockCurrencyService := new(CurrencyServiceMock)
 	mockLogger := zap.NewNop()
-	handler := currency.New(mockLogger, mockCurrencyService)
+	handler := currency.New(mockLogger, mockCurrencyService, new(QuoteServiceMock))
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, merchant)
 	ctx.Request, _ = http.NewRequest(http.MethodPost, "/currency/convert", nil)
 
 	// Action
@@ -149,16 +267,179 @@ func TestConvertExchangeRateHandler_Failure_GetCorrelationId(t *testing.T) {
 	mockCurrencyService.AssertExpectations(t)
 }
 
+func TestConvertExchangeRateHandler_Unauthenticated(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockCurrencyService := new(CurrencyServiceMock)
+	handler := currency.New(zap.NewNop(), mockCurrencyService, new(QuoteServiceMock))
+
+	payload := models.CurrencyConvert{FromCurrency: "USD", ToCurrency: "EUR", Amount: 100}
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/currency/convert", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+	// Action
+	handler.ConvertExchangeRateHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockCurrencyService.AssertExpectations(t)
+}
+
+func TestCreateQuoteHandler_MerchantFromIdentity(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockQuoteService := new(QuoteServiceMock)
+	handler := currency.New(zap.NewNop(), new(CurrencyServiceMock), mockQuoteService)
+
+	expected := models.CurrencyQuoteCreate{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL", MerchantId: "merchant1"}
+	mockQuoteService.On("CreateQuote", expected).Return(&models.CurrencyQuote{Id: "quote1", ConvertedAmount: 550}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, merchant)
+	body := `{"amount": 100, "from_currency": "USD", "to_currency": "BRL", "merchant_id": "merchant2"}`
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/currencies/quotes", strings.NewReader(body))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+	// Action
+	handler.CreateQuoteHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockQuoteService.AssertExpectations(t)
+}
+
+func TestCurrencyHandlers_LowerCaseCurrencies(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		url     string
+		body    string
+		arrange func(currencyService *CurrencyServiceMock, quoteService *QuoteServiceMock)
+		handle  func(handler *currency.CurrencyHandler, ctx *gin.Context)
+		status  int
+	}{
+		{
+			name:   "Convert",
+			method: http.MethodPost,
+			url:    "/currencies/convert",
+			body:   `{"amount": 100, "from_currency": "usd", "to_currency": "Eur"}`,
+			arrange: func(currencyService *CurrencyServiceMock, quoteService *QuoteServiceMock) {
+				currencyService.On("ConvertExchangeRate", "USD", "EUR", 100.0).Return(&models.CurrencyConvertResponse{Amount: 85}, nil)
+			},
+			handle: (*currency.CurrencyHandler).ConvertExchangeRateHandler,
+			status: http.StatusOK,
+		},
+		{
+			name:   "Batch",
+			method: http.MethodPost,
+			url:    "/currencies/convert/batch",
+			body:   `{"items": [{"amount": 100, "from_currency": "usd", "to_currency": "eur"}]}`,
+			arrange: func(currencyService *CurrencyServiceMock, quoteService *QuoteServiceMock) {
+				batch := models.CurrencyConvertBatch{MerchantId: "merchant1", Items: []models.CurrencyConvertItem{{Amount: 100, FromCurrency: "USD", ToCurrency: "EUR"}}}
+				currencyService.On("ConvertExchangeRateBatch", batch).Return(&models.CurrencyConvertBatchResponse{}, nil)
+			},
+			handle: (*currency.CurrencyHandler).ConvertExchangeRateBatchHandler,
+			status: http.StatusOK,
+		},
+		{
+			name:   "Quote",
+			method: http.MethodPost,
+			url:    "/currencies/quotes",
+			body:   `{"amount": 100, "from_currency": "usd", "to_currency": "brl"}`,
+			arrange: func(currencyService *CurrencyServiceMock, quoteService *QuoteServiceMock) {
+				quote := models.CurrencyQuoteCreate{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL", MerchantId: "merchant1"}
+				quoteService.On("CreateQuote", quote).Return(&models.CurrencyQuote{Id: "quote1"}, nil)
+			},
+			handle: (*currency.CurrencyHandler).CreateQuoteHandler,
+			status: http.StatusCreated,
+		},
+		{
+			name:   "Time series",
+			method: http.MethodGet,
+			url:    "/currencies/timeseries?base=usd&symbols=brl&from=2024-09-27&to=2024-09-29",
+			arrange: func(currencyService *CurrencyServiceMock, quoteService *QuoteServiceMock) {
+				query := models.CurrencyTimeSeries{Base: "USD", Symbols: "brl", From: "2024-09-27", To: "2024-09-29"}
+				currencyService.On("GetTimeSeries", query).Return(&models.CurrencyTimeSeriesResponse{Base: "USD"}, nil)
+			},
+			handle: (*currency.CurrencyHandler).GetTimeSeriesHandler,
+			status: http.StatusOK,
+		},
+		{
+			name:   "Format",
+			method: http.MethodGet,
+			url:    "/currencies/format?amount=10&currency=brl&locale=pt_BR",
+			arrange: func(currencyService *CurrencyServiceMock, quoteService *QuoteServiceMock) {
+				query := models.CurrencyFormat{Amount: 10, Currency: "BRL", Locale: "pt_BR"}
+				currencyService.On("FormatAmount", query).Return(&models.CurrencyFormatResponse{Formatted: "R$ 10,00"}, nil)
+			},
+			handle: (*currency.CurrencyHandler).FormatAmountHandler,
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			mockCurrencyService := new(CurrencyServiceMock)
+			mockQuoteService := new(QuoteServiceMock)
+			handler := currency.New(zap.NewNop(), mockCurrencyService, mockQuoteService)
+			tt.arrange(mockCurrencyService, mockQuoteService)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			middleware.SetIdentity(ctx, merchant)
+			ctx.Request, _ = http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+			// Action
+			tt.handle(handler, ctx)
+
+			// Assert
+			assert.Equal(t, tt.status, w.Code)
+			mockCurrencyService.AssertExpectations(t)
+			mockQuoteService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateQuoteHandler_InvalidAmount(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockQuoteService := new(QuoteServiceMock)
+	handler := currency.New(zap.NewNop(), new(CurrencyServiceMock), mockQuoteService)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, merchant)
+	body := `{"amount": -100, "from_currency": "USD", "to_currency": "BRL"}`
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/currencies/quotes", strings.NewReader(body))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+	// Action
+	handler.CreateQuoteHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockQuoteService.AssertNotCalled(t, "CreateQuote", mock.Anything)
+}
+
 func TestConvertExchangeRateHandler_Failure_BindJSON(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	// Arrange
 	mockCurrencyService := new(CurrencyServiceMock)
 	mockLogger := zap.NewNop()
-	handler := currency.New(mockLogger, mockCurrencyService)
+	handler := currency.New(mockLogger, mockCurrencyService, new(QuoteServiceMock))
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, merchant)
 	ctx.Request, _ = http.NewRequest(http.MethodPost, "/currency/convert", nil)
 	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
 
@@ -176,7 +457,7 @@ func TestConvertExchangeRateHandler_Failure_ConvertExchangeRate(t *testing.T) {
 
 	mockCurrencyService := new(CurrencyServiceMock)
 	mockLogger := zap.NewNop()
-	handler := currency.New(mockLogger, mockCurrencyService)
+	handler := currency.New(mockLogger, mockCurrencyService, new(QuoteServiceMock))
 
 	payload := models.CurrencyConvert{
 		FromCurrency: "USD",
@@ -184,10 +465,11 @@ func TestConvertExchangeRateHandler_Failure_ConvertExchangeRate(t *testing.T) {
 		Amount:       100,
 	}
 
-	mockCurrencyService.On("ConvertExchangeRate", payload.FromCurrency, payload.ToCurrency, payload.Amount).Return(float64(0), errors.New("conversion error"))
+	mockCurrencyService.On("ConvertExchangeRate", payload.FromCurrency, payload.ToCurrency, payload.Amount).Return(nil, errors.New("conversion error"))
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, merchant)
 	ctx.Request, _ = http.NewRequest(http.MethodPost, "/currency/convert", utils.ToJSONReader(payload))
 	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
 
@@ -198,3 +480,338 @@ func TestConvertExchangeRateHandler_Failure_ConvertExchangeRate(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	mockCurrencyService.AssertExpectations(t)
 }
+
+func TestGetRatesHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		date           string
+		result         *models.CurrencyDataResponse
+		err            error
+		expectedStatus int
+	}{
+		{"Success", "2024-09-27", &models.CurrencyDataResponse{Base: "EUR", Date: "2024-09-27", Rates: map[string]float64{"EUR": 1}}, nil, http.StatusOK},
+		{"Latest rates", "", &models.CurrencyDataResponse{Base: "USD", Rates: map[string]float64{"USD": 1}}, nil, http.StatusOK},
+		{"Invalid date", "2999-01-01", nil, currencyService.ErrInvalidDate, http.StatusBadRequest},
+		{"Provider failure", "2024-09-27", nil, errors.New("all exchange rate providers failed"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			mockCurrencyService := new(CurrencyServiceMock)
+			handler := currency.New(zap.NewNop(), mockCurrencyService, new(QuoteServiceMock))
+			mockCurrencyService.On("GetRates", tt.date).Return(tt.result, tt.err)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest(http.MethodGet, "/currencies/rates?date="+tt.date, nil)
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+			// Action
+			handler.GetRatesHandler(ctx)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockCurrencyService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetTimeSeriesHandler(t *testing.T) {
+	query := models.CurrencyTimeSeries{Base: "USD", Symbols: "BRL", From: "2024-09-27", To: "2024-09-29"}
+
+	tests := []struct {
+		name           string
+		url            string
+		result         *models.CurrencyTimeSeriesResponse
+		err            error
+		expectedStatus int
+	}{
+		{"Success", "/currencies/timeseries?base=USD&symbols=BRL&from=2024-09-27&to=2024-09-29", &models.CurrencyTimeSeriesResponse{Base: "USD"}, nil, http.StatusOK},
+		{"Missing parameters", "/currencies/timeseries?base=USD", nil, nil, http.StatusBadRequest},
+		{"Unknown symbol", "/currencies/timeseries?base=USD&symbols=BRL,XYZ&from=2024-09-27&to=2024-09-29", nil, nil, http.StatusBadRequest},
+		{"Empty symbol", "/currencies/timeseries?base=USD&symbols=BRL,,EUR&from=2024-09-27&to=2024-09-29", nil, nil, http.StatusBadRequest},
+		{"Invalid range", "/currencies/timeseries?base=USD&symbols=BRL&from=2024-09-27&to=2024-09-29", nil, currencyService.ErrInvalidRange, http.StatusBadRequest},
+		{"Missing currency", "/currencies/timeseries?base=USD&symbols=BRL&from=2024-09-27&to=2024-09-29", nil, currencyService.ErrMissingCurrency, http.StatusBadRequest},
+		{"Provider failure", "/currencies/timeseries?base=USD&symbols=BRL&from=2024-09-27&to=2024-09-29", nil, errors.New("all exchange rate providers failed"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			mockCurrencyService := new(CurrencyServiceMock)
+			handler := currency.New(zap.NewNop(), mockCurrencyService, new(QuoteServiceMock))
+			mockCurrencyService.On("GetTimeSeries", query).Return(tt.result, tt.err)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest(http.MethodGet, tt.url, nil)
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+			// Action
+			handler.GetTimeSeriesHandler(ctx)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestConvertExchangeRateBatchHandler(t *testing.T) {
+	batch := models.CurrencyConvertBatch{MerchantId: "merchant1", Items: []models.CurrencyConvertItem{{Amount: 100, FromCurrency: "USD", ToCurrency: "EUR"}}}
+	amount := 85.0
+
+	tests := []struct {
+		name           string
+		payload        models.CurrencyConvertBatch
+		result         *models.CurrencyConvertBatchResponse
+		err            error
+		expectedStatus int
+	}{
+		{"Success", batch, &models.CurrencyConvertBatchResponse{Provider: "ecb", Results: []models.CurrencyConvertBatchResult{{Amount: &amount, FromCurrency: "USD", ToCurrency: "EUR"}}}, nil, http.StatusOK},
+		{"Empty batch", models.CurrencyConvertBatch{}, nil, nil, http.StatusBadRequest},
+		{"Invalid item", models.CurrencyConvertBatch{Items: []models.CurrencyConvertItem{{Amount: 100, FromCurrency: "US"}}}, nil, nil, http.StatusBadRequest},
+		{"Negative amount", models.CurrencyConvertBatch{Items: []models.CurrencyConvertItem{{Amount: -100, FromCurrency: "USD", ToCurrency: "EUR"}}}, nil, nil, http.StatusBadRequest},
+		{"Rates failure", batch, nil, errors.New("all exchange rate providers failed"), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			mockCurrencyService := new(CurrencyServiceMock)
+			handler := currency.New(zap.NewNop(), mockCurrencyService, new(QuoteServiceMock))
+			mockCurrencyService.On("ConvertExchangeRateBatch", batch).Return(tt.result, tt.err)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			middleware.SetIdentity(ctx, merchant)
+			ctx.Request, _ = http.NewRequest(http.MethodPost, "/currencies/convert/batch", utils.ToJSONReader(tt.payload))
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+			// Action
+			handler.ConvertExchangeRateBatchHandler(ctx)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestGetAllCurrencyHandler_Expand(t *testing.T) {
+	tests := []struct {
+		name           string
+		expand         string
+		expectedStatus int
+	}{
+		{"Metadata", "metadata", http.StatusOK},
+		{"Unsupported expand", "rates", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			mockCurrencyService := new(CurrencyServiceMock)
+			handler := currency.New(zap.NewNop(), mockCurrencyService, new(QuoteServiceMock))
+			brl, _ := iso4217.Get("BRL")
+			mockCurrencyService.On("GetAllCurrencyMetadata").Return([]iso4217.Currency{brl}, nil)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest(http.MethodGet, "/currencies?expand="+tt.expand, nil)
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+			// Action
+			handler.GetAllCurrencyHandler(ctx)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockCurrencyService.AssertNotCalled(t, "GetAllCurrency")
+		})
+	}
+}
+
+func TestGetCurrencyHandler(t *testing.T) {
+	brl, _ := iso4217.Get("BRL")
+
+	tests := []struct {
+		name           string
+		code           string
+		result         *iso4217.Currency
+		err            error
+		expectedStatus int
+	}{
+		{"Success", "BRL", &brl, nil, http.StatusOK},
+		{"Not found", "XYZ", nil, currencyService.ErrCurrencyNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			mockCurrencyService := new(CurrencyServiceMock)
+			handler := currency.New(zap.NewNop(), mockCurrencyService, new(QuoteServiceMock))
+			mockCurrencyService.On("GetCurrency", tt.code).Return(tt.result, tt.err)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Params = gin.Params{{Key: "code", Value: tt.code}}
+			ctx.Request, _ = http.NewRequest(http.MethodGet, "/currencies/"+tt.code, nil)
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+			// Action
+			handler.GetCurrencyHandler(ctx)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockCurrencyService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFormatAmountHandler(t *testing.T) {
+	query := models.CurrencyFormat{Amount: 1234.56, Currency: "BRL", Locale: "pt_BR"}
+
+	tests := []struct {
+		name           string
+		url            string
+		result         *models.CurrencyFormatResponse
+		err            error
+		expectedStatus int
+	}{
+		{"Success", "/currencies/format?amount=1234.56&currency=BRL&locale=pt_BR", &models.CurrencyFormatResponse{Formatted: "R$\u00a01.234,56"}, nil, http.StatusOK},
+		{"Invalid currency", "/currencies/format?amount=1234.56&currency=XYZ&locale=pt_BR", nil, nil, http.StatusBadRequest},
+		{"Unsupported locale", "/currencies/format?amount=1234.56&currency=BRL&locale=pt_BR", nil, money.ErrUnsupportedLocale, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			mockCurrencyService := new(CurrencyServiceMock)
+			handler := currency.New(zap.NewNop(), mockCurrencyService, new(QuoteServiceMock))
+			mockCurrencyService.On("FormatAmount", query).Return(tt.result, tt.err)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest(http.MethodGet, tt.url, nil)
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+			// Action
+			handler.FormatAmountHandler(ctx)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestConvertExchangeRateHandler_Quote(t *testing.T) {
+	tests := []struct {
+		name         string
+		result       *models.CurrencyConvertResponse
+		err          error
+		expectedCode int
+	}{
+		{"Quote used", &models.CurrencyConvertResponse{Amount: 541.75, QuoteId: "quote1"}, nil, http.StatusOK},
+		{"Quote already used", nil, quote.ErrQuoteUsed, http.StatusBadRequest},
+		{"Quote expired", nil, quote.ErrQuoteExpired, http.StatusBadRequest},
+		{"Quote in use", nil, quote.ErrQuoteInUse, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			mockCurrencyService := new(CurrencyServiceMock)
+			mockQuoteService := new(QuoteServiceMock)
+			handler := currency.New(zap.NewNop(), mockCurrencyService, mockQuoteService)
+
+			payload := models.CurrencyConvert{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL", MerchantId: "merchant1", QuoteId: "quote1"}
+			mockQuoteService.On("RedeemForConversion", "quote1", payload).Return(tt.result, tt.err)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			middleware.SetIdentity(ctx, merchant)
+			ctx.Request, _ = http.NewRequest(http.MethodPost, "/currencies/convert", utils.ToJSONReader(payload))
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+			// Action
+			handler.ConvertExchangeRateHandler(ctx)
+
+			// Assert
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockQuoteService.AssertExpectations(t)
+			mockCurrencyService.AssertNotCalled(t, "ConvertExchangeRate", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateQuoteHandler_Success(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockQuoteService := new(QuoteServiceMock)
+	handler := currency.New(zap.NewNop(), new(CurrencyServiceMock), mockQuoteService)
+
+	payload := models.CurrencyQuoteCreate{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL", MerchantId: "merchant1"}
+	mockQuoteService.On("CreateQuote", payload).Return(&models.CurrencyQuote{Id: "quote1", ConvertedAmount: 550}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, merchant)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/currencies/quotes", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+	// Action
+	handler.CreateQuoteHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockQuoteService.AssertExpectations(t)
+}
+
+func TestGetQuoteHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		identity     *middleware.Identity
+		result       *models.CurrencyQuote
+		err          error
+		expectedCode int
+	}{
+		{"Own quote", &merchant, &models.CurrencyQuote{Id: "quote1", MerchantId: "merchant1"}, nil, http.StatusOK},
+		{"Quote of another merchant", &merchant, &models.CurrencyQuote{Id: "quote1", MerchantId: "merchant2"}, nil, http.StatusNotFound},
+		{"Not found", &merchant, nil, quote.ErrQuoteNotFound, http.StatusNotFound},
+		{"Unauthenticated", nil, nil, nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			mockQuoteService := new(QuoteServiceMock)
+			handler := currency.New(zap.NewNop(), new(CurrencyServiceMock), mockQuoteService)
+			mockQuoteService.On("GetQuote", "quote1").Return(tt.result, tt.err)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			if tt.identity != nil {
+				middleware.SetIdentity(ctx, *tt.identity)
+			}
+			ctx.Request, _ = http.NewRequest(http.MethodGet, "/currencies/quotes/quote1", nil)
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+			ctx.Params = gin.Params{{Key: "id", Value: "quote1"}}
+
+			// Action
+			handler.GetQuoteHandler(ctx)
+
+			// Assert
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.identity == nil {
+				mockQuoteService.AssertNotCalled(t, "GetQuote", mock.Anything)
+			}
+		})
+	}
+}
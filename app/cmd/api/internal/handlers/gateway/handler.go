@@ -1,12 +1,18 @@
 package gateway
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
 	gatewayService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider"
+	quoteService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/quote"
+	riskService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/risk"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
 
 	"github.com/gin-gonic/gin"
@@ -16,19 +22,29 @@ import (
 type GatewayHandler struct {
 	logger         *zap.Logger
 	gatewayService gatewayService.GatewayService
+	riskService    riskService.RiskService
+	quoteService   quoteService.QuoteService
+	asyncPayments  bool
 }
 
-// New creates a new instance of GatewayHandler with the provided logger and gateway service.
+// New creates a new instance of GatewayHandler with the provided logger, gateway service, risk service and quote service.
 // Parameters:
 //   - logger: an instance of zap.Logger for logging purposes.
 //   - gatewayService: an instance of GatewayService to handle gateway operations.
+//   - riskService: an instance of RiskService to screen payments before they are sent to the provider.
+//   - quoteService: an instance of QuoteService to use the exchange rate quotes payments reference.
+//   - asyncPayments: whether payments are enqueued to the payment workers instead of being sent to the provider
+//     during the request.
 //
 // Returns:
 //   - A pointer to a newly created GatewayHandler.
-func New(logger *zap.Logger, gatewayService gatewayService.GatewayService) *GatewayHandler {
+func New(logger *zap.Logger, gatewayService gatewayService.GatewayService, riskService riskService.RiskService, quoteService quoteService.QuoteService, asyncPayments bool) *GatewayHandler {
 	return &GatewayHandler{
 		logger:         logger,
 		gatewayService: gatewayService,
+		riskService:    riskService,
+		quoteService:   quoteService,
+		asyncPayments:  asyncPayments,
 	}
 }
 
@@ -109,10 +125,18 @@ func (c *GatewayHandler) GetAllTransactionsByDateHandler(ctx *gin.Context) {
 }
 
 // PaymentHandler handles payment requests by processing the payment through the specified gateway provider.
+// The payment belongs to the authenticated merchant, who is notified of the status changes of its transaction.
 // It retrieves the correlation ID from the context, binds the JSON payload to the Gateway model, and logs the start of the payment request.
+// The payment is screened by the risk service before reaching the provider: blocked payments are rejected and payments
+// sent to review are stored without being charged. In both cases the transaction is stored with the risk decision.
 // The handler then initializes the appropriate payment provider based on the payload's gateway type and processes the payment.
 // If any errors occur during these steps, appropriate error responses are returned to the client.
+// A payment that references an exchange rate quote must be of the merchant, converted amount and target currency of
+// the quote. The quote is reserved before the payment is screened, marked as used once the transaction is stored,
+// and released when the payment is blocked or fails, so it can be used again.
 // Upon successful payment processing, the transaction is added to the gateway service, and a no-content response is returned.
+// In async mode, a pending transaction is stored and the payment is enqueued to the payment workers instead,
+// and an accepted response carrying the transaction ID is returned.
 //
 // @Summary Process payment request
 // @Description Processes a payment request through the specified gateway provider
@@ -121,7 +145,10 @@ func (c *GatewayHandler) GetAllTransactionsByDateHandler(ctx *gin.Context) {
 // @Produce json
 // @Param payload body models.Gateway true "Payment payload"
 // @Success 204 "No Content"
+// @Success 202 {object} models.PaymentResponse "Payment sent to review or enqueued"
 // @Failure 400 {object} utils.ApiError "Bad Request"
+// @Failure 401 {object} []utils.Errors "Missing or unknown API key"
+// @Failure 403 {object} models.PaymentResponse "Payment blocked"
 // @Router /payment [post]
 func (c *GatewayHandler) PaymentHandler(ctx *gin.Context) {
 
@@ -132,23 +159,68 @@ func (c *GatewayHandler) PaymentHandler(ctx *gin.Context) {
 		return
 	}
 
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
 	var payload models.Gateway
 	if err := ctx.ShouldBindJSON(&payload); err != nil {
 		c.logger.Error("Failed to bind JSON payload", zap.String("correlation_id", correlationId), zap.Error(err))
 		utils.ApiResponse(ctx, http.StatusBadRequest, utils.ValidatorError(err))
 		return
 	}
+	payload.Currency = strings.ToUpper(payload.Currency)
+	payload.MerchantId = identity.Id
 
 	c.logger.Info("Starting payment request", zap.String("correlation_id", correlationId))
 
-	providerType := provider.ProviderType(payload.Gateway)
-	provider, err := provider.NewProvider(providerType)
+	if payload.QuoteId != "" {
+		if _, err := c.quoteService.ReserveForPayment(payload); err != nil {
+			c.logger.Error("Failed to reserve quote", zap.String("correlation_id", correlationId), zap.String("quote_id", payload.QuoteId), zap.Error(err))
+
+			if errors.Is(err, quoteService.ErrQuoteNotFound) || errors.Is(err, quoteService.ErrQuoteExpired) ||
+				errors.Is(err, quoteService.ErrQuoteUsed) || errors.Is(err, quoteService.ErrQuoteInUse) ||
+				errors.Is(err, quoteService.ErrQuoteMismatch) {
+				utils.ApiResponse(ctx, http.StatusBadRequest, []utils.Errors{{Field: "quote_id", Message: err.Error()}})
+				return
+			}
+
+			utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+			return
+		}
+
+		// The quote is committed once the transaction of the payment is stored, and released by any other outcome.
+		defer c.releaseQuote(correlationId, payload.QuoteId)
+	}
+
+	assessment, err := c.riskService.Assess(payload, ctx.ClientIP(), ctx.GetHeader("x-mgc-ipCountry"))
+	if err != nil {
+		c.logger.Error("Risk assessment failed", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+		return
+	}
+
+	c.logger.Info("Risk assessment completed", zap.String("correlation_id", correlationId), zap.Int("risk_score", assessment.Score),
+		zap.String("risk_decision", string(assessment.Decision)), zap.Strings("risk_reasons", assessment.Reasons))
+
+	if assessment.Decision != models.RiskAllow {
+		c.holdPayment(ctx, correlationId, payload, assessment)
+		return
+	}
+
+	provider, err := c.gatewayService.GetProvider(payload.Gateway)
 	if err != nil {
 		c.logger.Error("Unsupported payment gateway type", zap.String("correlation_id", correlationId), zap.Error(err))
 		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	if c.asyncPayments {
+		c.enqueuePayment(ctx, correlationId, provider, payload, assessment)
+		return
+	}
+
 	res, err := provider.ProcessPayment(payload, correlationId)
 
 	if err != nil {
@@ -157,7 +229,7 @@ func (c *GatewayHandler) PaymentHandler(ctx *gin.Context) {
 		return
 	}
 
-	err = c.gatewayService.AddTransaction(*res, payload)
+	err = c.gatewayService.AddTransaction(*res, payload, "pending", assessment, audit.ApiRequest(correlationId))
 
 	if err != nil {
 		c.logger.Error("Payment processing failed", zap.String("correlation_id", correlationId), zap.Error(err))
@@ -165,6 +237,97 @@ func (c *GatewayHandler) PaymentHandler(ctx *gin.Context) {
 		return
 	}
 
+	c.commitQuote(correlationId, payload)
 	utils.ApiResponse(ctx, http.StatusNoContent, nil)
 	c.logger.Info("Payment request completed successfully", zap.String("correlation_id", correlationId))
 }
+
+// enqueuePayment tokenizes the card of the payment at its provider, stores a pending transaction for the payment
+// and enqueues it to the payment workers with the payment token, answering with 202 Accepted carrying the
+// transaction ID. The card details are never enqueued.
+func (c *GatewayHandler) enqueuePayment(ctx *gin.Context, correlationId string, paymentGateway provider.PaymentGateway, payload models.Gateway, assessment *models.RiskAssessment) {
+	paymentToken, err := paymentGateway.Tokenize(payload)
+	if err != nil {
+		c.logger.Error("Payment tokenization failed", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := utils.GenerateGUID()
+
+	if err := c.gatewayService.AddTransaction(id, payload, "pending", assessment, audit.ApiRequest(correlationId)); err != nil {
+		c.logger.Error("Failed to store pending payment", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+		return
+	}
+
+	job := models.PaymentJob{
+		TransactionId: id,
+		CorrelationId: correlationId,
+		Gateway:       payload.Gateway,
+		PaymentMethod: payload.PaymentMethod,
+		PaymentToken:  paymentToken,
+		Amount:        payload.Amount,
+		Currency:      payload.Currency,
+		CreatedAt:     time.Now().Format(time.RFC3339),
+	}
+
+	if err := c.gatewayService.EnqueuePayment(job); err != nil {
+		c.logger.Error("Failed to enqueue payment", zap.String("correlation_id", correlationId), zap.String("transaction_id", id), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+		return
+	}
+
+	c.commitQuote(correlationId, payload)
+	utils.ApiResponse(ctx, http.StatusAccepted, models.PaymentResponse{Id: id, Status: "pending"})
+	c.logger.Info("Payment enqueued", zap.String("correlation_id", correlationId), zap.String("transaction_id", id))
+}
+
+// commitQuote marks the quote of a payment as used once the transaction of the payment is stored.
+// The payment is already stored, so a quote that cannot be marked is only logged, and its reservation expires.
+func (c *GatewayHandler) commitQuote(correlationId string, payload models.Gateway) {
+	if payload.QuoteId == "" {
+		return
+	}
+
+	if err := c.quoteService.Commit(payload.QuoteId); err != nil {
+		c.logger.Error("Failed to mark quote as used", zap.String("correlation_id", correlationId), zap.String("quote_id", payload.QuoteId), zap.Error(err))
+	}
+}
+
+// releaseQuote releases the reservation of the quote of a payment that was not stored, so the quote can be used
+// again. Quotes already committed are left untouched.
+func (c *GatewayHandler) releaseQuote(correlationId string, quoteId string) {
+	if err := c.quoteService.Release(quoteId); err != nil {
+		c.logger.Error("Failed to release quote", zap.String("correlation_id", correlationId), zap.String("quote_id", quoteId), zap.Error(err))
+	}
+}
+
+// holdPayment stores a payment that was blocked or sent to review by the risk assessment
+// without sending it to the provider. Blocked payments are answered with 403 Forbidden and
+// payments sent to review with 202 Accepted, both carrying the transaction ID.
+func (c *GatewayHandler) holdPayment(ctx *gin.Context, correlationId string, payload models.Gateway, assessment *models.RiskAssessment) {
+	id := utils.GenerateGUID()
+	status := http.StatusAccepted
+	transactionStatus := "review"
+
+	if assessment.Decision == models.RiskBlock {
+		status = http.StatusForbidden
+		transactionStatus = "blocked"
+	}
+
+	if err := c.gatewayService.AddTransaction(id, payload, transactionStatus, assessment, audit.ApiRequest(correlationId)); err != nil {
+		c.logger.Error("Failed to store held payment", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+		return
+	}
+
+	// A blocked payment never reaches its provider, so its quote is released, while a payment sent to review
+	// can still be approved with the rates of its quote.
+	if assessment.Decision != models.RiskBlock {
+		c.commitQuote(correlationId, payload)
+	}
+
+	utils.ApiResponse(ctx, status, models.PaymentResponse{Id: id, Status: transactionStatus})
+	c.logger.Info("Payment held by risk assessment", zap.String("correlation_id", correlationId), zap.String("transaction_id", id), zap.String("status", transactionStatus))
+}
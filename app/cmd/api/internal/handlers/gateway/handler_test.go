@@ -5,9 +5,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/handlers/gateway"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/quote"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/assert/v2"
@@ -28,6 +33,15 @@ func (m *GatewayServiceMock) GetAllAvaiablesGateways() []string {
 	return result
 }
 
+func (m *GatewayServiceMock) GetProvider(gateway string) (provider.PaymentGateway, error) {
+	args := m.Called(gateway)
+	var result provider.PaymentGateway
+	if args.Get(0) != nil {
+		result = args.Get(0).(provider.PaymentGateway)
+	}
+	return result, args.Error(1)
+}
+
 func (m *GatewayServiceMock) GetAllTransactionsByDate(date string) (*[]models.Transaction, error) {
 	args := m.Called(date)
 	var result []models.Transaction
@@ -37,8 +51,99 @@ func (m *GatewayServiceMock) GetAllTransactionsByDate(date string) (*[]models.Tr
 	return &result, args.Error(1)
 }
 
-func (m *GatewayServiceMock) AddTransaction(id string, payment models.Gateway) error {
-	args := m.Called(id, payment)
+func (m *GatewayServiceMock) AddTransaction(id string, payment models.Gateway, status string, risk *models.RiskAssessment, origin audit.Origin) error {
+	args := m.Called(id, payment, status, risk, origin)
+	return args.Error(0)
+}
+
+func (m *GatewayServiceMock) AddTransactionStatus(id string, createdAt time.Time, status string, providerReference string, origin audit.Origin) error {
+	args := m.Called(id, createdAt, status, providerReference, origin)
+	return args.Error(0)
+}
+
+func (m *GatewayServiceMock) EnqueuePayment(job models.PaymentJob) error {
+	args := m.Called(job)
+	return args.Error(0)
+}
+
+type PaymentGatewayMock struct {
+	mock.Mock
+}
+
+func (m *PaymentGatewayMock) ProcessPayment(payment models.Gateway, correlationId string) (*string, error) {
+	args := m.Called(payment, correlationId)
+	var result *string
+	if args.Get(0) != nil {
+		result = args.Get(0).(*string)
+	}
+	return result, args.Error(1)
+}
+
+func (m *PaymentGatewayMock) Tokenize(payment models.Gateway) (string, error) {
+	args := m.Called(payment)
+	return args.String(0), args.Error(1)
+}
+
+type RiskServiceMock struct {
+	mock.Mock
+}
+
+func (m *RiskServiceMock) Assess(payment models.Gateway, ip string, ipCountry string) (*models.RiskAssessment, error) {
+	args := m.Called(payment, ip, ipCountry)
+	var result *models.RiskAssessment
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.RiskAssessment)
+	}
+	return result, args.Error(1)
+}
+
+type QuoteServiceMock struct {
+	mock.Mock
+}
+
+func (m *QuoteServiceMock) CreateQuote(request models.CurrencyQuoteCreate) (*models.CurrencyQuote, error) {
+	args := m.Called(request)
+	var result *models.CurrencyQuote
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyQuote)
+	}
+	return result, args.Error(1)
+}
+
+func (m *QuoteServiceMock) GetQuote(id string) (*models.CurrencyQuote, error) {
+	args := m.Called(id)
+	var result *models.CurrencyQuote
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyQuote)
+	}
+	return result, args.Error(1)
+}
+
+func (m *QuoteServiceMock) RedeemForConversion(id string, currency models.CurrencyConvert) (*models.CurrencyConvertResponse, error) {
+	args := m.Called(id, currency)
+	var result *models.CurrencyConvertResponse
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyConvertResponse)
+	}
+	return result, args.Error(1)
+}
+
+func (m *QuoteServiceMock) ReserveForPayment(payment models.Gateway) (*models.CurrencyQuote, error) {
+	args := m.Called(payment)
+	var result *models.CurrencyQuote
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyQuote)
+	}
+	return result, args.Error(1)
+}
+
+func (m *QuoteServiceMock) Commit(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *QuoteServiceMock) Release(id string) error {
+	args := m.Called(id)
 	return args.Error(0)
 }
 
@@ -47,7 +152,7 @@ func TestGetAllAvaiablesGateways_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockGatewayService := new(GatewayServiceMock)
 	mockLogger := zap.NewNop()
-	handler := gateway.New(mockLogger, mockGatewayService)
+	handler := gateway.New(mockLogger, mockGatewayService, new(RiskServiceMock), new(QuoteServiceMock), false)
 	mockGateways := []string{"Stripe", "Paypal"}
 	mockGatewayService.On("GetAllAvaiablesGateways").Return(mockGateways, nil)
 
@@ -69,7 +174,7 @@ func TestGetAllAvaiablesGateways_Failure_GetCorrelationId(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockGatewayService := new(GatewayServiceMock)
 	mockLogger := zap.NewNop()
-	handler := gateway.New(mockLogger, mockGatewayService)
+	handler := gateway.New(mockLogger, mockGatewayService, new(RiskServiceMock), new(QuoteServiceMock), false)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
@@ -89,7 +194,7 @@ func TestGetAllTransactionsByDateHandler_Success(t *testing.T) {
 
 	mockGatewayService := new(GatewayServiceMock)
 	mockLogger := zap.NewNop()
-	handler := gateway.New(mockLogger, mockGatewayService)
+	handler := gateway.New(mockLogger, mockGatewayService, new(RiskServiceMock), new(QuoteServiceMock), false)
 
 	date := "20/01/2025"
 	mockTransactions := []models.Transaction{
@@ -131,7 +236,7 @@ func TestGetAllTransactionsByDateHandler_Failure_GetCorrelationId(t *testing.T)
 
 	mockGatewayService := new(GatewayServiceMock)
 	mockLogger := zap.NewNop()
-	handler := gateway.New(mockLogger, mockGatewayService)
+	handler := gateway.New(mockLogger, mockGatewayService, new(RiskServiceMock), new(QuoteServiceMock), false)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
@@ -151,7 +256,7 @@ func TestGetAllTransactionsByDateHandler_Failure_GetAllTransactionsByDate(t *tes
 
 	mockGatewayService := new(GatewayServiceMock)
 	mockLogger := zap.NewNop()
-	handler := gateway.New(mockLogger, mockGatewayService)
+	handler := gateway.New(mockLogger, mockGatewayService, new(RiskServiceMock), new(QuoteServiceMock), false)
 
 	date := "01_01_2023"
 	mockGatewayService.On("GetAllTransactionsByDate", date).Return(nil, errors.New("service error"))
@@ -168,3 +273,465 @@ func TestGetAllTransactionsByDateHandler_Failure_GetAllTransactionsByDate(t *tes
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	mockGatewayService.AssertExpectations(t)
 }
+
+func TestPaymentHandler_RiskBlock(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockGatewayService := new(GatewayServiceMock)
+	mockRiskService := new(RiskServiceMock)
+	mockLogger := zap.NewNop()
+	handler := gateway.New(mockLogger, mockGatewayService, mockRiskService, new(QuoteServiceMock), false)
+
+	payload := paymentPayload()
+	assessment := &models.RiskAssessment{Score: 100, Decision: models.RiskBlock, Reasons: []string{"country KP is blocked"}}
+
+	mockRiskService.On("Assess", payload, mock.Anything, "KP").Return(assessment, nil)
+	mockGatewayService.On("AddTransaction", mock.Anything, payload, "blocked", assessment, mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+	ctx.Request.Header.Set("x-mgc-ipCountry", "KP")
+
+	// Action
+	handler.PaymentHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRiskService.AssertExpectations(t)
+	mockGatewayService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_RiskReview(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockGatewayService := new(GatewayServiceMock)
+	mockRiskService := new(RiskServiceMock)
+	mockLogger := zap.NewNop()
+	handler := gateway.New(mockLogger, mockGatewayService, mockRiskService, new(QuoteServiceMock), false)
+
+	payload := paymentPayload()
+	assessment := &models.RiskAssessment{Score: 60, Decision: models.RiskReview, Reasons: []string{"card velocity exceeded"}}
+
+	mockRiskService.On("Assess", payload, mock.Anything, "").Return(assessment, nil)
+	mockGatewayService.On("AddTransaction", mock.Anything, payload, "review", assessment, mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+	// Action
+	handler.PaymentHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	mockRiskService.AssertExpectations(t)
+	mockGatewayService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_Failure_Assess(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockGatewayService := new(GatewayServiceMock)
+	mockRiskService := new(RiskServiceMock)
+	mockLogger := zap.NewNop()
+	handler := gateway.New(mockLogger, mockGatewayService, mockRiskService, new(QuoteServiceMock), false)
+
+	payload := paymentPayload()
+	mockRiskService.On("Assess", payload, mock.Anything, "").Return(nil, errors.New("cache error"))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+	// Action
+	handler.PaymentHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockRiskService.AssertExpectations(t)
+	mockGatewayService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_Success(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockGatewayService := new(GatewayServiceMock)
+	mockRiskService := new(RiskServiceMock)
+	mockGateway := new(PaymentGatewayMock)
+	mockLogger := zap.NewNop()
+	handler := gateway.New(mockLogger, mockGatewayService, mockRiskService, new(QuoteServiceMock), false)
+
+	payload := paymentPayload()
+	correlationId := utils.GenerateGUID()
+	assessment := &models.RiskAssessment{Decision: models.RiskAllow}
+	reference := "pi_123"
+
+	mockRiskService.On("Assess", payload, mock.Anything, "").Return(assessment, nil)
+	mockGatewayService.On("GetProvider", "Stripe").Return(mockGateway, nil)
+	mockGateway.On("ProcessPayment", payload, correlationId).Return(&reference, nil)
+	mockGatewayService.On("AddTransaction", reference, payload, "pending", assessment, audit.ApiRequest(correlationId)).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", correlationId)
+
+	// Action
+	handler.PaymentHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockGateway.AssertExpectations(t)
+	mockGatewayService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_LowerCaseCurrency(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockGatewayService := new(GatewayServiceMock)
+	mockRiskService := new(RiskServiceMock)
+	mockGateway := new(PaymentGatewayMock)
+	mockLogger := zap.NewNop()
+	handler := gateway.New(mockLogger, mockGatewayService, mockRiskService, new(QuoteServiceMock), false)
+
+	payload := paymentPayload()
+	request := paymentPayload()
+	request.Currency = "usd"
+	correlationId := utils.GenerateGUID()
+	assessment := &models.RiskAssessment{Decision: models.RiskAllow}
+	reference := "pi_123"
+
+	mockRiskService.On("Assess", payload, mock.Anything, "").Return(assessment, nil)
+	mockGatewayService.On("GetProvider", "Stripe").Return(mockGateway, nil)
+	mockGateway.On("ProcessPayment", payload, correlationId).Return(&reference, nil)
+	mockGatewayService.On("AddTransaction", reference, payload, "pending", assessment, audit.ApiRequest(correlationId)).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(request))
+	ctx.Request.Header.Set("x-mgc-correlationId", correlationId)
+
+	// Action
+	handler.PaymentHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockRiskService.AssertExpectations(t)
+	mockGateway.AssertExpectations(t)
+	mockGatewayService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_Failure_UnsupportedGateway(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockGatewayService := new(GatewayServiceMock)
+	mockRiskService := new(RiskServiceMock)
+	mockLogger := zap.NewNop()
+	handler := gateway.New(mockLogger, mockGatewayService, mockRiskService, new(QuoteServiceMock), false)
+
+	payload := paymentPayload()
+	payload.Gateway = "Unknown"
+	assessment := &models.RiskAssessment{Decision: models.RiskAllow}
+
+	mockRiskService.On("Assess", payload, mock.Anything, "").Return(assessment, nil)
+	mockGatewayService.On("GetProvider", "Unknown").Return(nil, errors.New("unsupported payment gateway type"))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+	// Action
+	handler.PaymentHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockGatewayService.AssertNotCalled(t, "AddTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPaymentHandler_Async(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockGatewayService := new(GatewayServiceMock)
+	mockRiskService := new(RiskServiceMock)
+	mockLogger := zap.NewNop()
+	handler := gateway.New(mockLogger, mockGatewayService, mockRiskService, new(QuoteServiceMock), true)
+
+	payload := paymentPayload()
+	correlationId := utils.GenerateGUID()
+	assessment := &models.RiskAssessment{Decision: models.RiskAllow}
+
+	mockRiskService.On("Assess", payload, mock.Anything, "").Return(assessment, nil)
+	mockGateway := new(PaymentGatewayMock)
+	mockGateway.On("Tokenize", payload).Return("pm_123", nil)
+	mockGatewayService.On("GetProvider", "Stripe").Return(mockGateway, nil)
+	mockGatewayService.On("AddTransaction", mock.Anything, payload, "pending", assessment, audit.ApiRequest(correlationId)).Return(nil)
+	mockGatewayService.On("EnqueuePayment", mock.MatchedBy(func(job models.PaymentJob) bool {
+		return job.CorrelationId == correlationId && job.PaymentToken == "pm_123" && job.Amount == payload.Amount && job.TransactionId != ""
+	})).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", correlationId)
+
+	// Action
+	handler.PaymentHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	mockRiskService.AssertExpectations(t)
+	mockGatewayService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_Async_Failure_EnqueuePayment(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockGatewayService := new(GatewayServiceMock)
+	mockRiskService := new(RiskServiceMock)
+	mockLogger := zap.NewNop()
+	handler := gateway.New(mockLogger, mockGatewayService, mockRiskService, new(QuoteServiceMock), true)
+
+	payload := paymentPayload()
+	assessment := &models.RiskAssessment{Decision: models.RiskAllow}
+
+	mockRiskService.On("Assess", payload, mock.Anything, "").Return(assessment, nil)
+	mockGateway := new(PaymentGatewayMock)
+	mockGateway.On("Tokenize", payload).Return("pm_123", nil)
+	mockGatewayService.On("GetProvider", "Stripe").Return(mockGateway, nil)
+	mockGatewayService.On("AddTransaction", mock.Anything, payload, "pending", assessment, mock.Anything).Return(nil)
+	mockGatewayService.On("EnqueuePayment", mock.Anything).Return(errors.New("queue error"))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+	// Action
+	handler.PaymentHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockGatewayService.AssertExpectations(t)
+}
+
+func paymentPayload() models.Gateway {
+	return models.Gateway{
+		Gateway:       "Stripe",
+		Amount:        100,
+		Currency:      "USD",
+		PaymentMethod: "card",
+		CardDetails: models.CardDetails{
+			Number: "4242424242424242",
+			Expiry: "12/30",
+			Cvv:    "123",
+		},
+		MerchantId: "merchant1",
+	}
+}
+
+func TestPaymentHandler_Quote(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{"Quote does not match", quote.ErrQuoteMismatch, http.StatusBadRequest},
+		{"Quote already used", quote.ErrQuoteUsed, http.StatusBadRequest},
+		{"Quote in use", quote.ErrQuoteInUse, http.StatusBadRequest},
+		{"Cache error", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+
+			mockGatewayService := new(GatewayServiceMock)
+			mockRiskService := new(RiskServiceMock)
+			mockQuoteService := new(QuoteServiceMock)
+			handler := gateway.New(zap.NewNop(), mockGatewayService, mockRiskService, mockQuoteService, false)
+
+			payload := paymentPayload()
+			payload.QuoteId = "quote1"
+			mockQuoteService.On("ReserveForPayment", payload).Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+			ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(payload))
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+			// Action
+			handler.PaymentHandler(ctx)
+
+			// Assert
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockQuoteService.AssertExpectations(t)
+			mockRiskService.AssertNotCalled(t, "Assess", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPaymentHandler_QuoteUsed(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockGatewayService := new(GatewayServiceMock)
+	mockRiskService := new(RiskServiceMock)
+	mockQuoteService := new(QuoteServiceMock)
+	handler := gateway.New(zap.NewNop(), mockGatewayService, mockRiskService, mockQuoteService, false)
+
+	payload := paymentPayload()
+	payload.QuoteId = "quote1"
+	assessment := &models.RiskAssessment{Score: 60, Decision: models.RiskReview, Reasons: []string{"card velocity exceeded"}}
+
+	mockQuoteService.On("ReserveForPayment", payload).Return(&models.CurrencyQuote{Id: "quote1"}, nil)
+	mockQuoteService.On("Commit", "quote1").Return(nil)
+	mockQuoteService.On("Release", "quote1").Return(nil)
+	mockRiskService.On("Assess", payload, mock.Anything, "").Return(assessment, nil)
+	mockGatewayService.On("AddTransaction", mock.Anything, payload, "review", assessment, mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+	// Action
+	handler.PaymentHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	mockQuoteService.AssertExpectations(t)
+	mockGatewayService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_QuoteReleased(t *testing.T) {
+	tests := []struct {
+		name         string
+		arrange      func(gatewayService *GatewayServiceMock, riskService *RiskServiceMock, paymentGateway *PaymentGatewayMock, payload models.Gateway)
+		expectedCode int
+	}{
+		{"Payment blocked", func(gatewayService *GatewayServiceMock, riskService *RiskServiceMock, paymentGateway *PaymentGatewayMock, payload models.Gateway) {
+			assessment := &models.RiskAssessment{Score: 90, Decision: models.RiskBlock}
+			riskService.On("Assess", payload, mock.Anything, "").Return(assessment, nil)
+			gatewayService.On("AddTransaction", mock.Anything, payload, "blocked", assessment, mock.Anything).Return(nil)
+		}, http.StatusForbidden},
+		{"Risk assessment failed", func(gatewayService *GatewayServiceMock, riskService *RiskServiceMock, paymentGateway *PaymentGatewayMock, payload models.Gateway) {
+			riskService.On("Assess", payload, mock.Anything, "").Return(nil, errors.New("cache error"))
+		}, http.StatusInternalServerError},
+		{"Payment declined", func(gatewayService *GatewayServiceMock, riskService *RiskServiceMock, paymentGateway *PaymentGatewayMock, payload models.Gateway) {
+			riskService.On("Assess", payload, mock.Anything, "").Return(&models.RiskAssessment{Decision: models.RiskAllow}, nil)
+			gatewayService.On("GetProvider", "Stripe").Return(paymentGateway, nil)
+			paymentGateway.On("ProcessPayment", payload, mock.Anything).Return(nil, errors.New("card declined"))
+		}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+
+			mockGatewayService := new(GatewayServiceMock)
+			mockRiskService := new(RiskServiceMock)
+			mockQuoteService := new(QuoteServiceMock)
+			mockGateway := new(PaymentGatewayMock)
+			handler := gateway.New(zap.NewNop(), mockGatewayService, mockRiskService, mockQuoteService, false)
+
+			payload := paymentPayload()
+			payload.QuoteId = "quote1"
+			mockQuoteService.On("ReserveForPayment", payload).Return(&models.CurrencyQuote{Id: "quote1"}, nil)
+			mockQuoteService.On("Release", "quote1").Return(nil)
+			tt.arrange(mockGatewayService, mockRiskService, mockGateway, payload)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+			ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(payload))
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+			// Action
+			handler.PaymentHandler(ctx)
+
+			// Assert
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockQuoteService.AssertExpectations(t)
+			mockQuoteService.AssertNotCalled(t, "Commit", mock.Anything)
+		})
+	}
+}
+
+func TestPaymentHandler_Unauthenticated(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockGatewayService := new(GatewayServiceMock)
+	mockRiskService := new(RiskServiceMock)
+	handler := gateway.New(zap.NewNop(), mockGatewayService, mockRiskService, new(QuoteServiceMock), false)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(paymentPayload()))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+	// Action
+	handler.PaymentHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockRiskService.AssertExpectations(t)
+	mockGatewayService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_Async_Failure_Tokenize(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+
+	mockGatewayService := new(GatewayServiceMock)
+	mockRiskService := new(RiskServiceMock)
+	mockLogger := zap.NewNop()
+	handler := gateway.New(mockLogger, mockGatewayService, mockRiskService, new(QuoteServiceMock), true)
+
+	payload := paymentPayload()
+	assessment := &models.RiskAssessment{Decision: models.RiskAllow}
+
+	mockGateway := new(PaymentGatewayMock)
+	mockGateway.On("Tokenize", payload).Return("", errors.New("card refused"))
+	mockRiskService.On("Assess", payload, mock.Anything, "").Return(assessment, nil)
+	mockGatewayService.On("GetProvider", "Stripe").Return(mockGateway, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/gateways", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+	// Action
+	handler.PaymentHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockGatewayService.AssertNotCalled(t, "AddTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockGatewayService.AssertNotCalled(t, "EnqueuePayment", mock.Anything)
+}
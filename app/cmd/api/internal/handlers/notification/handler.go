@@ -0,0 +1,278 @@
+package notification
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/notification"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type NotificationHandler struct {
+	logger              *zap.Logger
+	notificationService notification.NotificationService
+}
+
+// New creates a new instance of NotificationHandler with the provided logger and notification service.
+// Parameters:
+//   - logger: an instance of zap.Logger used for logging within the handler.
+//   - notificationService: an instance of notification.NotificationService that manages merchant endpoints.
+//
+// Returns:
+//   - A pointer to a newly created NotificationHandler.
+func New(logger *zap.Logger, notificationService notification.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		logger:              logger,
+		notificationService: notificationService,
+	}
+}
+
+// CreateEndpointHandler handles the request to register a callback endpoint of the authenticated merchant.
+// The response carries the endpoint signing secret, which is not returned again. Urls that are not http or https
+// or that resolve to loopback, private, link-local or reserved addresses are rejected.
+//
+// @Summary Register a callback endpoint
+// @Description Registers a merchant endpoint notified of the selected transaction event types
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param payload body notification.Endpoint true "Endpoint payload"
+// @Success 201 {object} notification.Endpoint
+// @Failure 400 {object} []utils.Errors
+// @Failure 401 {object} []utils.Errors
+// @Failure 500 {object} string
+// @Router /notifications/endpoints [post]
+func (c *NotificationHandler) CreateEndpointHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	var payload notification.Endpoint
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		c.logger.Error("Failed to bind JSON payload", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, utils.ValidatorError(err))
+		return
+	}
+
+	c.logger.Info("Starting request to create notification endpoint", zap.String("correlation_id", correlationId))
+
+	result, err := c.notificationService.CreateEndpoint(identity.Id, payload)
+	if err != nil {
+		c.logger.Error("Failed to create notification endpoint", zap.String("correlation_id", correlationId), zap.Error(err))
+
+		if errors.Is(err, notification.ErrInvalidEndpointUrl) {
+			utils.ApiResponse(ctx, http.StatusBadRequest, []utils.Errors{{Field: "url", Message: err.Error()}})
+			return
+		}
+
+		utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusCreated, result)
+	c.logger.Info("Successfully created notification endpoint", zap.String("correlation_id", correlationId), zap.String("endpoint_id", result.Id))
+}
+
+// GetAllEndpointsHandler handles the request to retrieve all callback endpoints of the authenticated merchant.
+//
+// @Summary Retrieve all callback endpoints
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} []notification.Endpoint
+// @Failure 500 {object} string
+// @Router /notifications/endpoints [get]
+func (c *NotificationHandler) GetAllEndpointsHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	c.logger.Info("Starting request to get all notification endpoints", zap.String("correlation_id", correlationId))
+
+	result, err := c.notificationService.GetAllEndpoints(identity.Id)
+	if err != nil {
+		c.logger.Error("Failed to get all notification endpoints", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully retrieved all notification endpoints", zap.String("correlation_id", correlationId))
+}
+
+// DeleteEndpointHandler handles the request to remove a callback endpoint of the authenticated merchant.
+//
+// @Summary Remove a callback endpoint
+// @Tags notifications
+// @Param id path string true "Endpoint ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} string
+// @Router /notifications/endpoints/{id} [delete]
+func (c *NotificationHandler) DeleteEndpointHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	id := ctx.Param("id")
+	c.logger.Info("Starting request to delete notification endpoint", zap.String("correlation_id", correlationId), zap.String("endpoint_id", id))
+
+	if err := c.notificationService.DeleteEndpoint(identity.Id, id); err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to delete notification endpoint", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusNoContent, nil)
+	c.logger.Info("Successfully deleted notification endpoint", zap.String("correlation_id", correlationId), zap.String("endpoint_id", id))
+}
+
+// EnableEndpointHandler handles the request to enable a callback endpoint disabled after repeated failures.
+//
+// @Summary Enable a callback endpoint
+// @Tags notifications
+// @Produce json
+// @Param id path string true "Endpoint ID"
+// @Success 200 {object} notification.Endpoint
+// @Failure 404 {object} string
+// @Router /notifications/endpoints/{id}/enable [post]
+func (c *NotificationHandler) EnableEndpointHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	id := ctx.Param("id")
+	c.logger.Info("Starting request to enable notification endpoint", zap.String("correlation_id", correlationId), zap.String("endpoint_id", id))
+
+	result, err := c.notificationService.EnableEndpoint(identity.Id, id)
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to enable notification endpoint", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully enabled notification endpoint", zap.String("correlation_id", correlationId), zap.String("endpoint_id", id))
+}
+
+// GetAllDeliveriesHandler handles the request to retrieve the delivery logs of a callback endpoint.
+//
+// @Summary Retrieve the delivery logs of a callback endpoint
+// @Tags notifications
+// @Produce json
+// @Param id path string true "Endpoint ID"
+// @Success 200 {object} []notification.Delivery
+// @Failure 404 {object} string
+// @Router /notifications/endpoints/{id}/deliveries [get]
+func (c *NotificationHandler) GetAllDeliveriesHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	id := ctx.Param("id")
+	c.logger.Info("Starting request to get notification deliveries", zap.String("correlation_id", correlationId), zap.String("endpoint_id", id))
+
+	result, err := c.notificationService.GetAllDeliveries(identity.Id, id)
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to get notification deliveries", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully retrieved notification deliveries", zap.String("correlation_id", correlationId), zap.String("endpoint_id", id))
+}
+
+// RedeliverHandler handles the request to send the event of a previous delivery again.
+//
+// @Summary Redeliver a notification event
+// @Tags notifications
+// @Produce json
+// @Param id path string true "Endpoint ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 200 {object} notification.Delivery
+// @Failure 404 {object} string
+// @Router /notifications/endpoints/{id}/deliveries/{deliveryId}/redeliver [post]
+func (c *NotificationHandler) RedeliverHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	id := ctx.Param("id")
+	deliveryId := ctx.Param("deliveryId")
+	c.logger.Info("Starting request to redeliver notification", zap.String("correlation_id", correlationId), zap.String("endpoint_id", id), zap.String("delivery_id", deliveryId))
+
+	result, err := c.notificationService.Redeliver(identity.Id, id, deliveryId)
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to redeliver notification", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully redelivered notification", zap.String("correlation_id", correlationId), zap.Bool("success", result.Success))
+}
+
+// errorResponse logs the error and responds with 404 Not Found for unknown endpoints and deliveries,
+// or 500 Internal Server Error otherwise.
+func (c *NotificationHandler) errorResponse(ctx *gin.Context, correlationId string, message string, err error) {
+	c.logger.Error(message, zap.String("correlation_id", correlationId), zap.Error(err))
+
+	if errors.Is(err, notification.ErrEndpointNotFound) || errors.Is(err, notification.ErrDeliveryNotFound) {
+		utils.ApiResponse(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+}
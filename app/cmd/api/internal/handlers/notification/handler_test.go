@@ -0,0 +1,226 @@
+package notification_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	handler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/handlers/notification"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/notification"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type NotificationServiceMock struct {
+	mock.Mock
+}
+
+func (m *NotificationServiceMock) Publish(merchantId string, eventType string, data interface{}) {
+	m.Called(merchantId, eventType, data)
+}
+
+func (m *NotificationServiceMock) CreateEndpoint(merchantId string, endpoint notification.Endpoint) (*notification.Endpoint, error) {
+	args := m.Called(merchantId, endpoint)
+	var result *notification.Endpoint
+	if args.Get(0) != nil {
+		result = args.Get(0).(*notification.Endpoint)
+	}
+	return result, args.Error(1)
+}
+
+func (m *NotificationServiceMock) GetAllEndpoints(merchantId string) ([]notification.Endpoint, error) {
+	args := m.Called(merchantId)
+	var result []notification.Endpoint
+	if args.Get(0) != nil {
+		result = args.Get(0).([]notification.Endpoint)
+	}
+	return result, args.Error(1)
+}
+
+func (m *NotificationServiceMock) DeleteEndpoint(merchantId string, id string) error {
+	args := m.Called(merchantId, id)
+	return args.Error(0)
+}
+
+func (m *NotificationServiceMock) EnableEndpoint(merchantId string, id string) (*notification.Endpoint, error) {
+	args := m.Called(merchantId, id)
+	var result *notification.Endpoint
+	if args.Get(0) != nil {
+		result = args.Get(0).(*notification.Endpoint)
+	}
+	return result, args.Error(1)
+}
+
+func (m *NotificationServiceMock) GetAllDeliveries(merchantId string, endpointId string) ([]notification.Delivery, error) {
+	args := m.Called(merchantId, endpointId)
+	var result []notification.Delivery
+	if args.Get(0) != nil {
+		result = args.Get(0).([]notification.Delivery)
+	}
+	return result, args.Error(1)
+}
+
+func (m *NotificationServiceMock) Redeliver(merchantId string, endpointId string, deliveryId string) (*notification.Delivery, error) {
+	args := m.Called(merchantId, endpointId, deliveryId)
+	var result *notification.Delivery
+	if args.Get(0) != nil {
+		result = args.Get(0).(*notification.Delivery)
+	}
+	return result, args.Error(1)
+}
+
+func (m *NotificationServiceMock) Run(ctx context.Context) {
+	m.Called(ctx)
+}
+
+func newTestContext(w *httptest.ResponseRecorder, method string, url string, body io.Reader) *gin.Context {
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(method, url, body)
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+	middleware.SetIdentity(ctx, middleware.Identity{Id: "merchant1", Role: middleware.RoleMerchant})
+	return ctx
+}
+
+func TestCreateEndpointHandler_Success(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockNotificationService := new(NotificationServiceMock)
+	handler := handler.New(zap.NewNop(), mockNotificationService)
+
+	payload := notification.Endpoint{Url: "https://merchant.test/hook", EventTypes: []string{"transaction.success"}}
+	mockNotificationService.On("CreateEndpoint", "merchant1", payload).Return(&notification.Endpoint{Id: "endpoint1"}, nil)
+
+	w := httptest.NewRecorder()
+	ctx := newTestContext(w, http.MethodPost, "/notifications/endpoints", utils.ToJSONReader(payload))
+
+	// Action
+	handler.CreateEndpointHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockNotificationService.AssertExpectations(t)
+}
+
+func TestCreateEndpointHandler_Failure_BindJSON(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockNotificationService := new(NotificationServiceMock)
+	handler := handler.New(zap.NewNop(), mockNotificationService)
+
+	payload := notification.Endpoint{Url: "not a url"}
+
+	w := httptest.NewRecorder()
+	ctx := newTestContext(w, http.MethodPost, "/notifications/endpoints", utils.ToJSONReader(payload))
+
+	// Action
+	handler.CreateEndpointHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockNotificationService.AssertExpectations(t)
+}
+
+func TestGetAllEndpointsHandler_Failure_GetAllEndpoints(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockNotificationService := new(NotificationServiceMock)
+	handler := handler.New(zap.NewNop(), mockNotificationService)
+
+	mockNotificationService.On("GetAllEndpoints", "merchant1").Return(nil, errors.New("cache error"))
+
+	w := httptest.NewRecorder()
+	ctx := newTestContext(w, http.MethodGet, "/notifications/endpoints", nil)
+
+	// Action
+	handler.GetAllEndpointsHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockNotificationService.AssertExpectations(t)
+}
+
+func TestDeleteEndpointHandler_NotFound(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockNotificationService := new(NotificationServiceMock)
+	handler := handler.New(zap.NewNop(), mockNotificationService)
+
+	mockNotificationService.On("DeleteEndpoint", "merchant1", "endpoint1").Return(notification.ErrEndpointNotFound)
+
+	w := httptest.NewRecorder()
+	ctx := newTestContext(w, http.MethodDelete, "/notifications/endpoints/endpoint1", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "endpoint1"}}
+
+	// Action
+	handler.DeleteEndpointHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockNotificationService.AssertExpectations(t)
+}
+
+func TestRedeliverHandler_Success(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockNotificationService := new(NotificationServiceMock)
+	handler := handler.New(zap.NewNop(), mockNotificationService)
+
+	mockNotificationService.On("Redeliver", "merchant1", "endpoint1", "delivery1").Return(&notification.Delivery{Id: "delivery2", Success: true}, nil)
+
+	w := httptest.NewRecorder()
+	ctx := newTestContext(w, http.MethodPost, "/notifications/endpoints/endpoint1/deliveries/delivery1/redeliver", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "endpoint1"}, {Key: "deliveryId", Value: "delivery1"}}
+
+	// Action
+	handler.RedeliverHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockNotificationService.AssertExpectations(t)
+}
+
+func TestCreateEndpointHandler_Failure_InvalidUrl(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockNotificationService := new(NotificationServiceMock)
+	handler := handler.New(zap.NewNop(), mockNotificationService)
+
+	payload := notification.Endpoint{Url: "http://169.254.169.254/latest/meta-data", EventTypes: []string{"*"}}
+	mockNotificationService.On("CreateEndpoint", "merchant1", payload).Return(nil, notification.ErrInvalidEndpointUrl)
+
+	w := httptest.NewRecorder()
+	ctx := newTestContext(w, http.MethodPost, "/notifications/endpoints", utils.ToJSONReader(payload))
+
+	// Action
+	handler.CreateEndpointHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockNotificationService.AssertExpectations(t)
+}
+
+func TestGetAllEndpointsHandler_Unauthenticated(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockNotificationService := new(NotificationServiceMock)
+	handler := handler.New(zap.NewNop(), mockNotificationService)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/notifications/endpoints", nil)
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+
+	// Action
+	handler.GetAllEndpointsHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockNotificationService.AssertExpectations(t)
+}
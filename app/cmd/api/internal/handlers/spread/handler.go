@@ -0,0 +1,255 @@
+package spread
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/spread"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type SpreadHandler struct {
+	logger        *zap.Logger
+	spreadService spread.SpreadService
+}
+
+// New creates a new instance of SpreadHandler with the provided logger and spread service.
+// Parameters:
+//   - logger: an instance of zap.Logger used for logging within the handler.
+//   - spreadService: an instance of spread.SpreadService that manages the exchange rate spreads.
+//
+// Returns:
+//   - A pointer to a newly created SpreadHandler.
+func New(logger *zap.Logger, spreadService spread.SpreadService) *SpreadHandler {
+	return &SpreadHandler{
+		logger:        logger,
+		spreadService: spreadService,
+	}
+}
+
+// CreateSpreadHandler handles the request to configure the spread of a merchant, a currency pair, both,
+// or neither for the default spread.
+//
+// @Summary Configure a spread
+// @Tags spreads
+// @Accept json
+// @Produce json
+// @Param payload body models.SpreadCreate true "Spread payload"
+// @Success 201 {object} models.Spread
+// @Failure 400 {object} []utils.Errors
+// @Failure 401 {object} []utils.Errors "Missing or unknown API key"
+// @Failure 403 {object} []utils.Errors "API key is not an administrator key"
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /currencies/spreads [post]
+func (c *SpreadHandler) CreateSpreadHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	var payload models.SpreadCreate
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		c.logger.Error("Failed to bind JSON payload", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, utils.ValidatorError(err))
+		return
+	}
+	payload.FromCurrency = strings.ToUpper(payload.FromCurrency)
+	payload.ToCurrency = strings.ToUpper(payload.ToCurrency)
+
+	c.logger.Info("Starting request to create spread", zap.String("correlation_id", correlationId))
+
+	result, err := c.spreadService.CreateSpread(payload, audit.Admin(identity.Id, correlationId))
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to create spread", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusCreated, result)
+	c.logger.Info("Successfully created spread", zap.String("correlation_id", correlationId), zap.String("spread_id", result.Id))
+}
+
+// GetAllSpreadsHandler handles the request to retrieve all configured spreads.
+//
+// @Summary Retrieve all spreads
+// @Tags spreads
+// @Produce json
+// @Success 200 {object} []models.Spread
+// @Failure 401 {object} []utils.Errors "Missing or unknown API key"
+// @Failure 403 {object} []utils.Errors "API key is not an administrator key"
+// @Failure 500 {object} string
+// @Router /currencies/spreads [get]
+func (c *SpreadHandler) GetAllSpreadsHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.logger.Info("Starting request to get all spreads", zap.String("correlation_id", correlationId))
+
+	result, err := c.spreadService.GetAllSpreads()
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to get all spreads", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully retrieved all spreads", zap.String("correlation_id", correlationId))
+}
+
+// UpdateSpreadHandler handles the request to change the basis points of a spread.
+//
+// @Summary Change a spread
+// @Tags spreads
+// @Accept json
+// @Produce json
+// @Param id path string true "Spread ID"
+// @Param payload body models.SpreadUpdate true "Spread payload"
+// @Success 200 {object} models.Spread
+// @Failure 400 {object} []utils.Errors
+// @Failure 401 {object} []utils.Errors "Missing or unknown API key"
+// @Failure 403 {object} []utils.Errors "API key is not an administrator key"
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /currencies/spreads/{id} [put]
+func (c *SpreadHandler) UpdateSpreadHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	var payload models.SpreadUpdate
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		c.logger.Error("Failed to bind JSON payload", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, utils.ValidatorError(err))
+		return
+	}
+
+	id := ctx.Param("id")
+	c.logger.Info("Starting request to update spread", zap.String("correlation_id", correlationId), zap.String("spread_id", id))
+
+	result, err := c.spreadService.UpdateSpread(id, payload, audit.Admin(identity.Id, correlationId))
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to update spread", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully updated spread", zap.String("correlation_id", correlationId), zap.String("spread_id", id))
+}
+
+// DeleteSpreadHandler handles the request to remove a spread.
+//
+// @Summary Remove a spread
+// @Tags spreads
+// @Param id path string true "Spread ID"
+// @Success 204
+// @Failure 401 {object} []utils.Errors "Missing or unknown API key"
+// @Failure 403 {object} []utils.Errors "API key is not an administrator key"
+// @Failure 404 {object} string
+// @Failure 409 {object} string
+// @Failure 500 {object} string
+// @Router /currencies/spreads/{id} [delete]
+func (c *SpreadHandler) DeleteSpreadHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	identity, ok := middleware.RequireIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	id := ctx.Param("id")
+	c.logger.Info("Starting request to delete spread", zap.String("correlation_id", correlationId), zap.String("spread_id", id))
+
+	if err := c.spreadService.DeleteSpread(id, audit.Admin(identity.Id, correlationId)); err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to delete spread", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusNoContent, nil)
+	c.logger.Info("Successfully deleted spread", zap.String("correlation_id", correlationId), zap.String("spread_id", id))
+}
+
+// GetHistoryHandler handles the request to retrieve every recorded change of a spread, oldest first.
+//
+// @Summary Retrieve the audit trail of a spread
+// @Tags spreads
+// @Produce json
+// @Param id path string true "Spread ID"
+// @Success 200 {object} []audit.Entry
+// @Failure 401 {object} []utils.Errors "Missing or unknown API key"
+// @Failure 403 {object} []utils.Errors "API key is not an administrator key"
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /currencies/spreads/{id}/history [get]
+func (c *SpreadHandler) GetHistoryHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := ctx.Param("id")
+	c.logger.Info("Starting request to get spread history", zap.String("correlation_id", correlationId), zap.String("spread_id", id))
+
+	result, err := c.spreadService.GetHistory(id)
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to get spread history", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully retrieved spread history", zap.String("correlation_id", correlationId), zap.Int("entry_count", len(result)))
+}
+
+// errorResponse logs the error and responds with 400 Bad Request for incomplete currency pairs, 404 Not Found
+// for unknown spreads, 409 Conflict for scopes that already have a spread and spreads being changed by another
+// request, or 500 Internal Server Error otherwise.
+func (c *SpreadHandler) errorResponse(ctx *gin.Context, correlationId string, message string, err error) {
+	c.logger.Error(message, zap.String("correlation_id", correlationId), zap.Error(err))
+
+	switch {
+	case errors.Is(err, spread.ErrIncompletePair):
+		utils.ApiResponse(ctx, http.StatusBadRequest, []utils.Errors{{Field: "to_currency", Message: err.Error()}})
+	case errors.Is(err, spread.ErrSpreadNotFound):
+		utils.ApiResponse(ctx, http.StatusNotFound, err.Error())
+	case errors.Is(err, spread.ErrSpreadConflict), errors.Is(err, spread.ErrSpreadLocked):
+		utils.ApiResponse(ctx, http.StatusConflict, err.Error())
+	default:
+		utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+	}
+}
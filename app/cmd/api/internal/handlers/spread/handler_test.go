@@ -0,0 +1,300 @@
+package spread_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	handler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/handlers/spread"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/spread"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type SpreadServiceMock struct {
+	mock.Mock
+}
+
+func (m *SpreadServiceMock) CreateSpread(spread models.SpreadCreate, origin audit.Origin) (*models.Spread, error) {
+	args := m.Called(spread, origin)
+	var result *models.Spread
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.Spread)
+	}
+	return result, args.Error(1)
+}
+
+func (m *SpreadServiceMock) GetAllSpreads() ([]models.Spread, error) {
+	args := m.Called()
+	var result []models.Spread
+	if args.Get(0) != nil {
+		result = args.Get(0).([]models.Spread)
+	}
+	return result, args.Error(1)
+}
+
+func (m *SpreadServiceMock) UpdateSpread(id string, update models.SpreadUpdate, origin audit.Origin) (*models.Spread, error) {
+	args := m.Called(id, update, origin)
+	var result *models.Spread
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.Spread)
+	}
+	return result, args.Error(1)
+}
+
+func (m *SpreadServiceMock) DeleteSpread(id string, origin audit.Origin) error {
+	args := m.Called(id, origin)
+	return args.Error(0)
+}
+
+func (m *SpreadServiceMock) GetHistory(id string) ([]audit.Entry, error) {
+	args := m.Called(id)
+	var result []audit.Entry
+	if args.Get(0) != nil {
+		result = args.Get(0).([]audit.Entry)
+	}
+	return result, args.Error(1)
+}
+
+func (m *SpreadServiceMock) Resolve(merchantId string, fromCurrency string, toCurrency string) (*models.Spread, error) {
+	args := m.Called(merchantId, fromCurrency, toCurrency)
+	var result *models.Spread
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.Spread)
+	}
+	return result, args.Error(1)
+}
+
+var admin = middleware.Identity{Id: "ops", Role: middleware.RoleAdmin}
+
+func basisPoints(value int) *int {
+	return &value
+}
+
+func TestCreateSpreadHandler_Success(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockSpreadService := new(SpreadServiceMock)
+	handler := handler.New(zap.NewNop(), mockSpreadService)
+
+	correlationId := utils.GenerateGUID()
+	payload := models.SpreadCreate{MerchantId: "merchant-1", FromCurrency: "USD", ToCurrency: "BRL", BasisPoints: basisPoints(150)}
+	mockSpreadService.On("CreateSpread", payload, audit.Admin("ops", correlationId)).Return(&models.Spread{Id: "spread1", BasisPoints: 150}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, admin)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/currencies/spreads", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", correlationId)
+
+	// Action
+	handler.CreateSpreadHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockSpreadService.AssertExpectations(t)
+}
+
+func TestCreateSpreadHandler_LowerCaseCurrencies(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockSpreadService := new(SpreadServiceMock)
+	handler := handler.New(zap.NewNop(), mockSpreadService)
+
+	correlationId := utils.GenerateGUID()
+	expected := models.SpreadCreate{FromCurrency: "USD", ToCurrency: "BRL", BasisPoints: basisPoints(150)}
+	mockSpreadService.On("CreateSpread", expected, audit.Admin("ops", correlationId)).Return(&models.Spread{Id: "spread1", BasisPoints: 150}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, admin)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/currencies/spreads", strings.NewReader(`{"from_currency": "usd", "to_currency": "Brl", "basis_points": 150}`))
+	ctx.Request.Header.Set("x-mgc-correlationId", correlationId)
+
+	// Action
+	handler.CreateSpreadHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockSpreadService.AssertExpectations(t)
+}
+
+func TestCreateSpreadHandler_Failure(t *testing.T) {
+	tests := []struct {
+		name         string
+		payload      models.SpreadCreate
+		err          error
+		expectedCode int
+	}{
+		{"Missing basis points", models.SpreadCreate{MerchantId: "merchant-1"}, nil, http.StatusBadRequest},
+		{"Too many basis points", models.SpreadCreate{BasisPoints: basisPoints(1001)}, nil, http.StatusBadRequest},
+		{"Invalid currency", models.SpreadCreate{FromCurrency: "XYZ", ToCurrency: "BRL", BasisPoints: basisPoints(10)}, nil, http.StatusBadRequest},
+		{"Incomplete pair", models.SpreadCreate{FromCurrency: "USD", BasisPoints: basisPoints(10)}, spread.ErrIncompletePair, http.StatusBadRequest},
+		{"Conflict", models.SpreadCreate{MerchantId: "merchant-1", BasisPoints: basisPoints(10)}, spread.ErrSpreadConflict, http.StatusConflict},
+		{"Storage error", models.SpreadCreate{BasisPoints: basisPoints(10)}, errors.New("cache error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			mockSpreadService := new(SpreadServiceMock)
+			handler := handler.New(zap.NewNop(), mockSpreadService)
+
+			correlationId := utils.GenerateGUID()
+			if tt.err != nil {
+				mockSpreadService.On("CreateSpread", tt.payload, audit.Admin("ops", correlationId)).Return(nil, tt.err)
+			}
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			middleware.SetIdentity(ctx, admin)
+			ctx.Request, _ = http.NewRequest(http.MethodPost, "/currencies/spreads", utils.ToJSONReader(tt.payload))
+			ctx.Request.Header.Set("x-mgc-correlationId", correlationId)
+
+			// Action
+			handler.CreateSpreadHandler(ctx)
+
+			// Assert
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockSpreadService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateSpreadHandler_NotFound(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockSpreadService := new(SpreadServiceMock)
+	handler := handler.New(zap.NewNop(), mockSpreadService)
+
+	payload := models.SpreadUpdate{BasisPoints: basisPoints(80)}
+	mockSpreadService.On("UpdateSpread", "spread1", payload, mock.Anything).Return(nil, spread.ErrSpreadNotFound)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, admin)
+	ctx.Request, _ = http.NewRequest(http.MethodPut, "/currencies/spreads/spread1", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+	ctx.Params = gin.Params{{Key: "id", Value: "spread1"}}
+
+	// Action
+	handler.UpdateSpreadHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockSpreadService.AssertExpectations(t)
+}
+
+func TestUpdateSpreadHandler_Locked(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockSpreadService := new(SpreadServiceMock)
+	handler := handler.New(zap.NewNop(), mockSpreadService)
+
+	payload := models.SpreadUpdate{BasisPoints: basisPoints(80)}
+	mockSpreadService.On("UpdateSpread", "spread1", payload, mock.Anything).Return(nil, spread.ErrSpreadLocked)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, admin)
+	ctx.Request, _ = http.NewRequest(http.MethodPut, "/currencies/spreads/spread1", utils.ToJSONReader(payload))
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+	ctx.Params = gin.Params{{Key: "id", Value: "spread1"}}
+
+	// Action
+	handler.UpdateSpreadHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockSpreadService.AssertExpectations(t)
+}
+
+func TestDeleteSpreadHandler_Success(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockSpreadService := new(SpreadServiceMock)
+	handler := handler.New(zap.NewNop(), mockSpreadService)
+
+	correlationId := utils.GenerateGUID()
+	mockSpreadService.On("DeleteSpread", "spread1", audit.Admin("ops", correlationId)).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	middleware.SetIdentity(ctx, admin)
+	ctx.Request, _ = http.NewRequest(http.MethodDelete, "/currencies/spreads/spread1", nil)
+	ctx.Request.Header.Set("x-mgc-correlationId", correlationId)
+	ctx.Params = gin.Params{{Key: "id", Value: "spread1"}}
+
+	// Action
+	handler.DeleteSpreadHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockSpreadService.AssertExpectations(t)
+}
+
+func TestSpreadHandlers_Unauthenticated(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   interface{}
+		handle func(h *handler.SpreadHandler) gin.HandlerFunc
+	}{
+		{"Create", http.MethodPost, models.SpreadCreate{BasisPoints: basisPoints(10)}, func(h *handler.SpreadHandler) gin.HandlerFunc { return h.CreateSpreadHandler }},
+		{"Update", http.MethodPut, models.SpreadUpdate{BasisPoints: basisPoints(10)}, func(h *handler.SpreadHandler) gin.HandlerFunc { return h.UpdateSpreadHandler }},
+		{"Delete", http.MethodDelete, nil, func(h *handler.SpreadHandler) gin.HandlerFunc { return h.DeleteSpreadHandler }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			mockSpreadService := new(SpreadServiceMock)
+			handler := handler.New(zap.NewNop(), mockSpreadService)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest(tt.method, "/currencies/spreads/spread1", utils.ToJSONReader(tt.body))
+			ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+			ctx.Params = gin.Params{{Key: "id", Value: "spread1"}}
+
+			// Action
+			tt.handle(handler)(ctx)
+
+			// Assert
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			mockSpreadService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetHistoryHandler_Success(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	mockSpreadService := new(SpreadServiceMock)
+	handler := handler.New(zap.NewNop(), mockSpreadService)
+
+	mockSpreadService.On("GetHistory", "spread1").Return([]audit.Entry{{Id: "entry1", Action: audit.ActionSpreadCreated}}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/currencies/spreads/spread1/history", nil)
+	ctx.Request.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+	ctx.Params = gin.Params{{Key: "id", Value: "spread1"}}
+
+	// Action
+	handler.GetHistoryHandler(ctx)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSpreadService.AssertExpectations(t)
+}
@@ -1,9 +1,71 @@
 package models
 
+// RatesDateFormat is the format of the dates of exchange rates.
+const RatesDateFormat = "2006-01-02"
+
 type CurrencyConvert struct {
 	Amount       float64 `json:"amount" binding:"required"`
-	FromCurrency string  `json:"from_currency" binding:"required,len=3"`
-	ToCurrency   string  `json:"to_currency" binding:"required,len=3"`
+	FromCurrency string  `json:"from_currency" binding:"required,iso4217_anycase"`
+	ToCurrency   string  `json:"to_currency" binding:"required,iso4217_anycase"`
+	Date         string  `json:"date" binding:"omitempty,datetime=2006-01-02"`
+	Format       string  `json:"format"`
+	Rounding     string  `json:"rounding" binding:"omitempty,oneof=half_even half_up floor ceiling"`
+	MerchantId   string  `json:"-"`
+	QuoteId      string  `json:"quote_id"`
+}
+
+// CurrencyConvertResponse is the result of a conversion. The amount is converted at the applied rate, which is
+// the mid-market rate with the spread taken off, and the markup is the difference to the amount at the mid rate.
+type CurrencyConvertResponse struct {
+	Amount            float64 `json:"amount"`
+	FromCurrency      string  `json:"from_currency"`
+	ToCurrency        string  `json:"to_currency"`
+	Date              string  `json:"date,omitempty"`
+	MidRate           string  `json:"mid_rate"`
+	Rate              string  `json:"rate"`
+	SpreadBasisPoints int     `json:"spread_basis_points"`
+	Markup            float64 `json:"markup"`
+	Rounding          string  `json:"rounding"`
+	Provider          string  `json:"provider"`
+	AsOf              string  `json:"as_of,omitempty"`
+	Stale             bool    `json:"stale"`
+	Formatted         string  `json:"formatted,omitempty"`
+	QuoteId           string  `json:"quote_id,omitempty"`
+}
+
+type CurrencyConvertBatch struct {
+	Date       string                `json:"date" binding:"omitempty,datetime=2006-01-02"`
+	MerchantId string                `json:"-"`
+	Rounding   string                `json:"rounding" binding:"omitempty,oneof=half_even half_up floor ceiling"`
+	Items      []CurrencyConvertItem `json:"items" binding:"required,min=1,max=10000,dive"`
+}
+
+type CurrencyConvertItem struct {
+	Amount       float64 `json:"amount" binding:"required,gt=0"`
+	FromCurrency string  `json:"from_currency" binding:"required,iso4217_anycase"`
+	ToCurrency   string  `json:"to_currency" binding:"required,iso4217_anycase"`
+}
+
+type CurrencyConvertBatchResponse struct {
+	Date     string                       `json:"date,omitempty"`
+	Rounding string                       `json:"rounding"`
+	Provider string                       `json:"provider"`
+	AsOf     string                       `json:"as_of,omitempty"`
+	Stale    bool                         `json:"stale"`
+	Results  []CurrencyConvertBatchResult `json:"results"`
+}
+
+// CurrencyConvertBatchResult is the result of an item of a batch, in the same position as the item.
+// It has either the converted amount or the error of the item.
+type CurrencyConvertBatchResult struct {
+	Amount            *float64 `json:"amount,omitempty"`
+	FromCurrency      string   `json:"from_currency"`
+	ToCurrency        string   `json:"to_currency"`
+	MidRate           string   `json:"mid_rate,omitempty"`
+	Rate              string   `json:"rate,omitempty"`
+	SpreadBasisPoints int      `json:"spread_basis_points,omitempty"`
+	Markup            *float64 `json:"markup,omitempty"`
+	Error             string   `json:"error,omitempty"`
 }
 
 type Currency struct {
@@ -11,6 +73,79 @@ type Currency struct {
 	Currency string  `json:"currency"`
 }
 
+// CurrencyDataResponse is a snapshot of exchange rates. AsOf is the time the latest rates were fetched from the provider,
+// and Stale marks the last known rates served because no provider could refresh them.
 type CurrencyDataResponse struct {
-	Rates map[string]float64 `json:"rates"`
+	Base     string             `json:"base,omitempty"`
+	Date     string             `json:"date,omitempty"`
+	Provider string             `json:"provider,omitempty"`
+	AsOf     string             `json:"as_of,omitempty"`
+	Stale    bool               `json:"stale"`
+	Rates    map[string]float64 `json:"rates"`
+}
+
+type CurrencyTimeSeries struct {
+	Base    string `form:"base" binding:"required,iso4217_anycase"`
+	Symbols string `form:"symbols" binding:"required,iso4217_list"`
+	From    string `form:"from" binding:"required,datetime=2006-01-02"`
+	To      string `form:"to" binding:"required,datetime=2006-01-02"`
+}
+
+type CurrencyTimeSeriesResponse struct {
+	Base       string                                  `json:"base"`
+	From       string                                  `json:"from"`
+	To         string                                  `json:"to"`
+	Rates      map[string]map[string]float64           `json:"rates"`
+	Statistics map[string]CurrencyTimeSeriesStatistics `json:"statistics"`
+}
+
+type CurrencyTimeSeriesStatistics struct {
+	Min           float64 `json:"min"`
+	Max           float64 `json:"max"`
+	Mean          float64 `json:"mean"`
+	PercentChange float64 `json:"percent_change"`
+}
+
+type CurrencyFormat struct {
+	Amount   float64 `form:"amount" binding:"required"`
+	Currency string  `form:"currency" binding:"required,iso4217_anycase"`
+	Locale   string  `form:"locale" binding:"required"`
+}
+
+type CurrencyFormatResponse struct {
+	Amount    float64 `json:"amount"`
+	Currency  string  `json:"currency"`
+	Locale    string  `json:"locale"`
+	Formatted string  `json:"formatted"`
+}
+
+type CurrencyQuoteCreate struct {
+	Amount       float64 `json:"amount" binding:"required,gt=0"`
+	FromCurrency string  `json:"from_currency" binding:"required,iso4217_anycase"`
+	ToCurrency   string  `json:"to_currency" binding:"required,iso4217_anycase"`
+	Rounding     string  `json:"rounding" binding:"omitempty,oneof=half_even half_up floor ceiling"`
+	MerchantId   string  `json:"-"`
+}
+
+// CurrencyQuote locks the conversion of an amount from one currency to another until it expires.
+// A quote can be used once, by a conversion or by a payment.
+type CurrencyQuote struct {
+	Id                string  `json:"id"`
+	Amount            float64 `json:"amount"`
+	ConvertedAmount   float64 `json:"converted_amount"`
+	FromCurrency      string  `json:"from_currency"`
+	ToCurrency        string  `json:"to_currency"`
+	MerchantId        string  `json:"merchant_id,omitempty"`
+	Date              string  `json:"date,omitempty"`
+	MidRate           string  `json:"mid_rate"`
+	Rate              string  `json:"rate"`
+	SpreadBasisPoints int     `json:"spread_basis_points"`
+	Markup            float64 `json:"markup"`
+	Rounding          string  `json:"rounding"`
+	Provider          string  `json:"provider"`
+	AsOf              string  `json:"as_of,omitempty"`
+	Stale             bool    `json:"stale"`
+	CreatedAt         string  `json:"created_at"`
+	ExpiresAt         string  `json:"expires_at"`
+	UsedAt            string  `json:"used_at,omitempty"`
 }
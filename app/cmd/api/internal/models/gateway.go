@@ -9,7 +9,18 @@ type CardDetails struct {
 type Gateway struct {
 	Gateway       string      `json:"gateway" binding:"required"`
 	Amount        float64     `json:"amount" binding:"required"`
-	Currency      string      `json:"currency" binding:"required,len=3"`
+	Currency      string      `json:"currency" binding:"required,iso4217_anycase"`
 	PaymentMethod string      `json:"payment_method" binding:"required"`
 	CardDetails   CardDetails `json:"card_details" binding:"required"`
+	CustomerId    string      `json:"customer_id"`
+	Country       string      `json:"country" binding:"omitempty,len=2"`
+	QuoteId       string      `json:"quote_id"`
+	MerchantId    string      `json:"-"`
+	TransactionId string      `json:"-"`
+	PaymentToken  string      `json:"-"`
+}
+
+type PaymentResponse struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
 }
@@ -0,0 +1,26 @@
+package models
+
+// PaymentJob is a payment enqueued to the payment workers. It never carries the card details: the card is
+// tokenized at the provider before the payment is enqueued, and only the payment token is kept.
+type PaymentJob struct {
+	TransactionId string  `json:"transaction_id"`
+	CorrelationId string  `json:"correlation_id"`
+	Gateway       string  `json:"gateway"`
+	PaymentMethod string  `json:"payment_method"`
+	PaymentToken  string  `json:"payment_token"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+// Payment returns the payment of the job, to be sent to its provider with the payment token.
+func (j PaymentJob) Payment() Gateway {
+	return Gateway{
+		Gateway:       j.Gateway,
+		Amount:        j.Amount,
+		Currency:      j.Currency,
+		PaymentMethod: j.PaymentMethod,
+		PaymentToken:  j.PaymentToken,
+		TransactionId: j.TransactionId,
+	}
+}
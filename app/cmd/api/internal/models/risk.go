@@ -0,0 +1,15 @@
+package models
+
+type RiskDecision string
+
+const (
+	RiskAllow  RiskDecision = "allow"
+	RiskReview RiskDecision = "review"
+	RiskBlock  RiskDecision = "block"
+)
+
+type RiskAssessment struct {
+	Score    int          `json:"score"`
+	Decision RiskDecision `json:"decision"`
+	Reasons  []string     `json:"reasons"`
+}
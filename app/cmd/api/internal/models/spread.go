@@ -0,0 +1,24 @@
+package models
+
+// Spread is the markup applied on top of the mid-market rate of conversions, in basis points.
+// A spread without a merchant applies to every merchant, and a spread without currencies applies to every pair.
+type Spread struct {
+	Id           string `json:"id"`
+	MerchantId   string `json:"merchant_id,omitempty"`
+	FromCurrency string `json:"from_currency,omitempty"`
+	ToCurrency   string `json:"to_currency,omitempty"`
+	BasisPoints  int    `json:"basis_points"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type SpreadCreate struct {
+	MerchantId   string `json:"merchant_id"`
+	FromCurrency string `json:"from_currency" binding:"omitempty,iso4217_anycase"`
+	ToCurrency   string `json:"to_currency" binding:"omitempty,iso4217_anycase"`
+	BasisPoints  *int   `json:"basis_points" binding:"required,min=0,max=1000"`
+}
+
+type SpreadUpdate struct {
+	BasisPoints *int `json:"basis_points" binding:"required,min=0,max=1000"`
+}
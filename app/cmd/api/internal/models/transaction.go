@@ -2,12 +2,19 @@ package models
 
 type Transaction struct {
 	Id                string              `json:"id"`
+	MerchantId        string              `json:"merchant_id,omitempty"`
 	Amount            float64             `json:"amount"`
 	Currency          string              `json:"currency"`
 	TransactionStatus []TransactionStatus `json:"transaction_status"`
+	Risk              *RiskAssessment     `json:"risk,omitempty"`
+	ProviderReference string              `json:"provider_reference,omitempty"`
+	QuoteId           string              `json:"quote_id,omitempty"`
 }
 
 type TransactionStatus struct {
 	Status   string `json:"status" `
 	DateTime string `json:"dateTime"`
+	Code     string `json:"code,omitempty"`
+	Message  string `json:"message,omitempty"`
+	EventId  string `json:"eventId,omitempty"`
 }
@@ -1,40 +1,173 @@
 package router
 
 import (
+	"context"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
 
+	auditHandler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/handlers/audit"
 	currencyHandler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/handlers/currency"
 	gatewayHandler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/handlers/gateway"
+	notificationHandler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/handlers/notification"
+	spreadHandler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/handlers/spread"
 	currencyService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency"
+	rateProvider "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency/provider"
 	gatewayService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider/paypal"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider/stripe"
+	quoteService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/quote"
+	riskService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/risk"
+	spreadService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/spread"
 
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/index"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/notification"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/pending"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/queue"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 func Init(route *gin.Engine, logger *zap.Logger) {
-	cacheClient := cache.New()
+	cacheConfig := cache.LoadConfig()
+	cacheClient, err := cache.NewClient(cacheConfig)
+	if err != nil {
+		logger.Fatal("Error creating cache client", zap.Error(err))
+	}
+
+	asyncPayments, _ := strconv.ParseBool(os.Getenv("PAYMENT_ASYNC_ENABLED"))
+	if asyncPayments && cacheConfig.Driver == cache.MemoryDriver {
+		logger.Fatal("Asynchronous payments use Redis Streams and cannot run with the memory cache driver")
+	}
+
+	fingerprintSecret, err := utils.LoadFingerprintSecret()
+	if err != nil {
+		logger.Fatal("Error loading card fingerprint secret", zap.Error(err))
+	}
+
+	trustedProxies, err := middleware.LoadTrustedProxies()
+	if err != nil {
+		logger.Fatal("Error loading trusted proxies", zap.Error(err))
+	}
+
+	trustedProxyRanges := make([]string, 0, len(trustedProxies))
+	for _, proxy := range trustedProxies {
+		trustedProxyRanges = append(trustedProxyRanges, proxy.String())
+	}
+
+	if err := route.SetTrustedProxies(trustedProxyRanges); err != nil {
+		logger.Fatal("Error setting trusted proxies", zap.Error(err))
+	}
+
+	route.Use(middleware.EdgeHeaders(trustedProxies, "x-mgc-ipCountry"))
+
+	apiKeys, err := middleware.LoadAPIKeys()
+	if err != nil {
+		logger.Fatal("Error loading API keys", zap.Error(err))
+	}
+	merchantAuth := middleware.Authenticate(apiKeys, middleware.RoleMerchant)
+	adminAuth := middleware.Authenticate(apiKeys, middleware.RoleAdmin)
 
-	currencyService := currencyService.New(cacheClient)
-	currencyHandler := currencyHandler.New(logger, currencyService)
+	queueClient := queue.NewClient(cacheConfig)
 
-	gatewayService := gatewayService.New(cacheClient)
-	gatewayHandler := gatewayHandler.New(logger, gatewayService)
+	auditService := audit.New(cacheClient)
+	auditHandler := auditHandler.New(logger, auditService)
+
+	notificationService := notification.New(cacheClient, queueClient, logger, notification.LoadConfig())
+	go notificationService.Run(context.Background())
+	notificationHandler := notificationHandler.New(logger, notificationService)
+
+	rateProviders, err := rateProvider.NewChain(logger, rateProvider.LoadConfig())
+	if err != nil {
+		logger.Fatal("Error loading exchange rate providers", zap.Error(err))
+	}
+
+	spreadService := spreadService.New(cacheClient, audit.NewLog(cacheClient, cache.SpreadAuditLogKey))
+	spreadHandler := spreadHandler.New(logger, spreadService)
+
+	currencyService := currencyService.New(cacheClient, rateProviders, spreadService, logger, currencyService.LoadConfig())
+	go currencyService.Run(context.Background())
+	quoteService := quoteService.New(cacheClient, currencyService, quoteService.LoadConfig())
+	currencyHandler := currencyHandler.New(logger, currencyService, quoteService)
+
+	riskService := riskService.New(cacheClient, riskService.LoadConfig(), fingerprintSecret)
+
+	providers := provider.NewRegistry(stripe.LoadConfig(), paypal.LoadConfig())
+	gatewayService := gatewayService.New(cacheClient, notificationService, auditService, queueClient, pending.New(cacheClient, logger, pending.LoadConfig()), index.New(cacheClient, index.LoadConfig()), providers, logger)
+	gatewayHandler := gatewayHandler.New(logger, gatewayService, riskService, quoteService, asyncPayments)
+
+	convertRateLimit := middleware.RateLimiter(cacheClient, logger, middleware.LoadRateLimit(middleware.RateLimit{
+		Name:   "currency_convert",
+		Limit:  60,
+		Window: time.Minute,
+		Key:    middleware.FirstOf(middleware.ByAPIKey, middleware.ByIP),
+	}))
+
+	convertBatchRateLimit := middleware.RateLimiter(cacheClient, logger, middleware.LoadRateLimit(middleware.RateLimit{
+		Name:   "currency_convert_batch",
+		Limit:  10000,
+		Window: time.Minute,
+		Key:    middleware.FirstOf(middleware.ByAPIKey, middleware.ByIP),
+		Cost:   middleware.ByItemCount("items"),
+	}))
+
+	paymentIpRateLimit := middleware.RateLimiter(cacheClient, logger, middleware.LoadRateLimit(middleware.RateLimit{
+		Name:   "payment_ip",
+		Limit:  20,
+		Window: time.Minute,
+		Key:    middleware.ByIP,
+	}))
+
+	paymentCardRateLimit := middleware.RateLimiter(cacheClient, logger, middleware.LoadRateLimit(middleware.RateLimit{
+		Name:   "payment_card",
+		Limit:  5,
+		Window: time.Hour,
+		Key:    middleware.ByCardFingerprint(fingerprintSecret),
+	}))
 
 	groupRoute := route.Group("/api/v1")
 
 	currencyRoute := groupRoute.Group("/currencies")
 	{
 		currencyRoute.GET("", currencyHandler.GetAllCurrencyHandler)
-		currencyRoute.POST("convert", currencyHandler.ConvertExchangeRateHandler)
+		currencyRoute.GET("rates", currencyHandler.GetRatesHandler)
+		currencyRoute.GET("timeseries", currencyHandler.GetTimeSeriesHandler)
+		currencyRoute.GET("format", currencyHandler.FormatAmountHandler)
+		currencyRoute.GET("spreads", adminAuth, spreadHandler.GetAllSpreadsHandler)
+		currencyRoute.POST("spreads", adminAuth, spreadHandler.CreateSpreadHandler)
+		currencyRoute.PUT("spreads/:id", adminAuth, spreadHandler.UpdateSpreadHandler)
+		currencyRoute.DELETE("spreads/:id", adminAuth, spreadHandler.DeleteSpreadHandler)
+		currencyRoute.GET("spreads/:id/history", adminAuth, spreadHandler.GetHistoryHandler)
+		currencyRoute.GET(":code", currencyHandler.GetCurrencyHandler)
+		currencyRoute.POST("quotes", merchantAuth, convertRateLimit, currencyHandler.CreateQuoteHandler)
+		currencyRoute.GET("quotes/:id", merchantAuth, currencyHandler.GetQuoteHandler)
+		currencyRoute.POST("convert", merchantAuth, convertRateLimit, currencyHandler.ConvertExchangeRateHandler)
+		currencyRoute.POST("convert/batch", merchantAuth, convertBatchRateLimit, currencyHandler.ConvertExchangeRateBatchHandler)
 	}
 
 	gatewayRoute := groupRoute.Group("/gateways")
 	{
 		gatewayRoute.GET("avaiables", gatewayHandler.GetAllAvaiablesGateways)
 		gatewayRoute.GET("transactions", gatewayHandler.GetAllTransactionsByDateHandler)
-		gatewayRoute.POST("", gatewayHandler.PaymentHandler)
+		gatewayRoute.GET("transactions/:id/history", merchantAuth, auditHandler.GetHistoryHandler)
+		gatewayRoute.GET("transactions/:id/state", merchantAuth, auditHandler.GetStateAtHandler)
+		gatewayRoute.POST("", merchantAuth, paymentIpRateLimit, paymentCardRateLimit, gatewayHandler.PaymentHandler)
+	}
+
+	notificationRoute := groupRoute.Group("/notifications/endpoints", merchantAuth)
+	{
+		notificationRoute.GET("", notificationHandler.GetAllEndpointsHandler)
+		notificationRoute.POST("", notificationHandler.CreateEndpointHandler)
+		notificationRoute.DELETE(":id", notificationHandler.DeleteEndpointHandler)
+		notificationRoute.POST(":id/enable", notificationHandler.EnableEndpointHandler)
+		notificationRoute.GET(":id/deliveries", notificationHandler.GetAllDeliveriesHandler)
+		notificationRoute.POST(":id/deliveries/:deliveryId/redeliver", notificationHandler.RedeliverHandler)
 	}
 
 	route.GET("/ping", func(ctx *gin.Context) {
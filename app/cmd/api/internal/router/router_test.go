@@ -14,6 +14,8 @@ import (
 func TestInit(t *testing.T) {
 	// Arrange
 	gin.SetMode(gin.TestMode)
+	t.Setenv("EXCHANGE_RATE_PROVIDERS", "static")
+	t.Setenv("CARD_FINGERPRINT_SECRET", "test-secret")
 	router := gin.Default()
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
@@ -26,10 +28,24 @@ func TestInit(t *testing.T) {
 		expected int
 	}{
 		{"GET", "/api/v1/currencies", http.StatusBadRequest},
-		{"POST", "/api/v1/currencies/convert", http.StatusBadRequest},
+		{"GET", "/api/v1/currencies/rates", http.StatusInternalServerError},
+		{"GET", "/api/v1/currencies/timeseries", http.StatusBadRequest},
+		{"GET", "/api/v1/currencies/BRL", http.StatusOK},
+		{"GET", "/api/v1/currencies/format?amount=1234.56&currency=BRL&locale=pt_BR", http.StatusOK},
+		{"GET", "/api/v1/currencies/spreads", http.StatusUnauthorized},
+		{"POST", "/api/v1/currencies/spreads", http.StatusUnauthorized},
+		{"PUT", "/api/v1/currencies/spreads/unknown", http.StatusUnauthorized},
+		{"DELETE", "/api/v1/currencies/spreads/unknown", http.StatusUnauthorized},
+		{"POST", "/api/v1/currencies/quotes", http.StatusUnauthorized},
+		{"GET", "/api/v1/currencies/quotes/unknown", http.StatusUnauthorized},
+		{"POST", "/api/v1/currencies/convert", http.StatusUnauthorized},
+		{"POST", "/api/v1/currencies/convert/batch", http.StatusUnauthorized},
 		{"GET", "/api/v1/gateways/avaiables", http.StatusOK},
 		{"GET", "/api/v1/gateways/transactions", http.StatusOK},
-		{"POST", "/api/v1/gateways", http.StatusBadRequest},
+		{"GET", "/api/v1/gateways/transactions/unknown/history", http.StatusUnauthorized},
+		{"GET", "/api/v1/gateways/transactions/unknown/state", http.StatusUnauthorized},
+		{"POST", "/api/v1/gateways", http.StatusUnauthorized},
+		{"GET", "/api/v1/notifications/endpoints", http.StatusUnauthorized},
 		{"GET", "/ping", http.StatusOK},
 	}
 
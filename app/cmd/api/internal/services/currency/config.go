@@ -0,0 +1,31 @@
+package currency
+
+import (
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	TTL             time.Duration
+	RefreshInterval time.Duration
+	MaxStaleness    time.Duration
+}
+
+// LoadConfig loads the latest exchange rates configuration from the environment variables.
+//
+// Environment Variables:
+//   - EXCHANGE_RATE_TTL: how long the latest rates are fresh after they are fetched, e.g. "5m".
+//   - EXCHANGE_RATE_REFRESH_INTERVAL: how often the latest rates are refreshed in the background, e.g. "4m".
+//   - EXCHANGE_RATE_MAX_STALENESS: how old the last known rates can be to still be served when every
+//     rate provider fails, e.g. "1h".
+//
+// Returns:
+//   - Config: the latest exchange rates configuration.
+func LoadConfig() Config {
+	return Config{
+		TTL:             utils.GetEnvDuration("EXCHANGE_RATE_TTL", 5*time.Minute),
+		RefreshInterval: utils.GetEnvDuration("EXCHANGE_RATE_REFRESH_INTERVAL", 4*time.Minute),
+		MaxStaleness:    utils.GetEnvDuration("EXCHANGE_RATE_MAX_STALENESS", time.Hour),
+	}
+}
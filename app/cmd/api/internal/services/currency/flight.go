@@ -0,0 +1,44 @@
+package currency
+
+import (
+	"sync"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+)
+
+// flight deduplicates concurrent fetches of the latest rates, so when the cached rates expire under load
+// the rate provider is called once and every request waiting for the rates shares the result.
+type flight struct {
+	mu   sync.Mutex
+	call *flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	res  *models.CurrencyDataResponse
+	err  error
+}
+
+// do calls fetch, unless a call is already in flight, in which case it waits for that call and returns its result.
+func (f *flight) do(fetch func() (*models.CurrencyDataResponse, error)) (*models.CurrencyDataResponse, error) {
+	f.mu.Lock()
+	if call := f.call; call != nil {
+		f.mu.Unlock()
+		<-call.done
+		return call.res, call.err
+	}
+
+	call := &flightCall{done: make(chan struct{})}
+	f.call = call
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.call = nil
+		f.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.res, call.err = fetch()
+	return call.res, call.err
+}
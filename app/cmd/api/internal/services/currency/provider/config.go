@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency/provider/ecb"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency/provider/frankfurter"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency/provider/openexchangerates"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency/provider/static"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	Providers         []string
+	Timeout           time.Duration
+	OpenExchangeRates openexchangerates.Config
+	ECB               ecb.Config
+	Frankfurter       frankfurter.Config
+	Static            static.Config
+}
+
+// LoadConfig loads the exchange rate providers configuration from the environment variables.
+//
+// Environment Variables:
+//   - EXCHANGE_RATE_PROVIDERS: the comma-separated providers in order of priority, among "openexchangerates",
+//     "ecb", "frankfurter" and "static", e.g. "openexchangerates,ecb,static".
+//   - EXCHANGE_RATE_TIMEOUT: the timeout of each request to a provider, e.g. "10s".
+//
+// The providers are configured with their own variables, read by the LoadConfig of their packages.
+//
+// Returns:
+//   - Config: the exchange rate providers configuration.
+func LoadConfig() Config {
+	return Config{
+		Providers:         utils.GetEnvList("EXCHANGE_RATE_PROVIDERS", []string{openexchangerates.Name, ecb.Name, frankfurter.Name}),
+		Timeout:           utils.GetEnvDuration("EXCHANGE_RATE_TIMEOUT", 10*time.Second),
+		OpenExchangeRates: openexchangerates.LoadConfig(),
+		ECB:               ecb.LoadConfig(),
+		Frankfurter:       frankfurter.LoadConfig(),
+		Static:            static.LoadConfig(),
+	}
+}
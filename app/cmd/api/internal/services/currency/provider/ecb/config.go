@@ -0,0 +1,23 @@
+package ecb
+
+import "github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+
+type Config struct {
+	URL           string
+	HistoricalURL string
+}
+
+// LoadConfig loads the European Central Bank configuration from the environment variables.
+//
+// Environment Variables:
+//   - ECB_RATES_URL: the URL of the daily reference rates XML feed, defaulting to DailyURL.
+//   - ECB_HISTORICAL_RATES_URL: the URL of the historical reference rates XML feed, defaulting to HistoricalURL.
+//
+// Returns:
+//   - Config: the European Central Bank configuration.
+func LoadConfig() Config {
+	return Config{
+		URL:           utils.GetEnvString("ECB_RATES_URL", DailyURL),
+		HistoricalURL: utils.GetEnvString("ECB_HISTORICAL_RATES_URL", HistoricalURL),
+	}
+}
@@ -0,0 +1,148 @@
+package ecb
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+)
+
+const (
+	Name = "ecb"
+
+	// DailyURL is the URL of the daily reference rates published by the European Central Bank.
+	DailyURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+	// HistoricalURL is the URL of every reference rate published by the European Central Bank since 1999,
+	// from the most recent day to the oldest one.
+	HistoricalURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
+
+	base = "EUR"
+)
+
+// envelope is a reference rates feed, with the rates of each day nested in Cube elements.
+type envelope struct {
+	Cube struct {
+		Days []day `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+type day struct {
+	Time  string `xml:"time,attr"`
+	Rates []struct {
+		Currency string `xml:"currency,attr"`
+		Rate     string `xml:"rate,attr"`
+	} `xml:"Cube"`
+}
+
+type ECB struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// New creates a new instance of ECB with the provided configuration and HTTP client.
+//
+// Parameters:
+//   - config: the European Central Bank configuration.
+//   - httpClient: the HTTP client the rates are requested with.
+//
+// Returns:
+//   - *ECB: a pointer to the newly created ECB.
+func New(config Config, httpClient *http.Client) *ECB {
+	return &ECB{
+		config:     config,
+		httpClient: httpClient,
+	}
+}
+
+// Name returns the name of the provider.
+func (p *ECB) Name() string {
+	return Name
+}
+
+// GetRates fetches the daily reference rates of the European Central Bank, based on EUR.
+// The feed does not list EUR, so it is added with rate 1.
+//
+// Returns:
+//   - *models.CurrencyDataResponse: the reference exchange rates of the day.
+//   - error: an error if the request fails or the feed cannot be decoded.
+func (p *ECB) GetRates() (*models.CurrencyDataResponse, error) {
+	days, err := p.fetch(p.config.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return days[0].toRates()
+}
+
+// GetHistoricalRates fetches the reference rates of the European Central Bank of a past date, based on EUR.
+// No rates are published on weekends and holidays, so the rates of the last day published on or before
+// the date are returned.
+//
+// Parameters:
+//   - date: the day of the rates.
+//
+// Returns:
+//   - *models.CurrencyDataResponse: the reference exchange rates of the day.
+//   - error: an error if the request fails, the feed cannot be decoded or has no rates up to the date.
+func (p *ECB) GetHistoricalRates(date time.Time) (*models.CurrencyDataResponse, error) {
+	days, err := p.fetch(p.config.HistoricalURL)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := date.Format(models.RatesDateFormat)
+	for _, day := range days {
+		// The dates share the same layout, so they sort lexically.
+		if day.Time <= requested {
+			return day.toRates()
+		}
+	}
+
+	return nil, fmt.Errorf("API response has no rates on or before %s", requested)
+}
+
+// fetch requests a reference rates feed and returns its days, from the most recent to the oldest one.
+func (p *ECB) fetch(url string) ([]day, error) {
+	resp, err := p.httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status: %s", resp.Status)
+	}
+
+	var feed envelope
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, err
+	}
+
+	if len(feed.Cube.Days) == 0 {
+		return nil, fmt.Errorf("API response has no rates")
+	}
+
+	return feed.Cube.Days, nil
+}
+
+// toRates parses the rates of the day. The feed does not list EUR, so it is added with rate 1.
+func (d day) toRates() (*models.CurrencyDataResponse, error) {
+	if len(d.Rates) == 0 {
+		return nil, fmt.Errorf("API response has no rates")
+	}
+
+	rates := map[string]float64{base: 1}
+	for _, item := range d.Rates {
+		rate, err := strconv.ParseFloat(item.Rate, 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid rate of %s: %s", item.Currency, item.Rate)
+		}
+		rates[item.Currency] = rate
+	}
+
+	return &models.CurrencyDataResponse{Base: base, Date: d.Time, Rates: rates}, nil
+}
@@ -0,0 +1,135 @@
+package ecb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const dailyFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time="2024-10-01">
+			<Cube currency="USD" rate="1.1134"/>
+			<Cube currency="JPY" rate="159.88"/>
+			<Cube currency="BRL" rate="6.0528"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+const historicalFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<Cube>
+		<Cube time="2024-10-01">
+			<Cube currency="USD" rate="1.1134"/>
+		</Cube>
+		<Cube time="2024-09-27">
+			<Cube currency="USD" rate="1.1158"/>
+		</Cube>
+		<Cube time="2024-09-26">
+			<Cube currency="USD" rate="1.1155"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func mockServer(status int, response string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+}
+
+func TestGetRates_Success(t *testing.T) {
+	// Arrange
+	server := mockServer(http.StatusOK, dailyFeed)
+	defer server.Close()
+	provider := New(Config{URL: server.URL}, server.Client())
+
+	// Action
+	data, err := provider.GetRates()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", data.Base)
+	assert.Equal(t, "2024-10-01", data.Date)
+	assert.Equal(t, map[string]float64{"EUR": 1, "USD": 1.1134, "JPY": 159.88, "BRL": 6.0528}, data.Rates)
+}
+
+func TestGetRates_Failure(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"Error status", http.StatusServiceUnavailable, ""},
+		{"Invalid feed", http.StatusOK, "<html>"},
+		{"No rates", http.StatusOK, `<Envelope><Cube><Cube time="2024-10-01"></Cube></Cube></Envelope>`},
+		{"Invalid rate", http.StatusOK, `<Envelope><Cube><Cube time="2024-10-01"><Cube currency="USD" rate="N/A"/></Cube></Cube></Envelope>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := mockServer(tt.status, tt.body)
+			defer server.Close()
+			provider := New(Config{URL: server.URL}, server.Client())
+
+			// Action
+			data, err := provider.GetRates()
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, data)
+		})
+	}
+}
+
+func TestGetHistoricalRates(t *testing.T) {
+	tests := []struct {
+		name         string
+		date         time.Time
+		expectedDate string
+		expectedRate float64
+	}{
+		{"Published day", time.Date(2024, 9, 26, 0, 0, 0, 0, time.UTC), "2024-09-26", 1.1155},
+		{"Weekend falls back to the previous day", time.Date(2024, 9, 29, 0, 0, 0, 0, time.UTC), "2024-09-27", 1.1158},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := mockServer(http.StatusOK, historicalFeed)
+			defer server.Close()
+			provider := New(Config{HistoricalURL: server.URL}, server.Client())
+
+			// Action
+			data, err := provider.GetHistoricalRates(tt.date)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedDate, data.Date)
+			assert.Equal(t, map[string]float64{"EUR": 1, "USD": tt.expectedRate}, data.Rates)
+		})
+	}
+}
+
+func TestGetHistoricalRates_BeforeFirstDay(t *testing.T) {
+	// Arrange
+	server := mockServer(http.StatusOK, historicalFeed)
+	defer server.Close()
+	provider := New(Config{HistoricalURL: server.URL}, server.Client())
+
+	// Action
+	data, err := provider.GetHistoricalRates(time.Date(1998, 12, 31, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.EqualError(t, err, "API response has no rates on or before 1998-12-31")
+	assert.Nil(t, data)
+}
@@ -0,0 +1,24 @@
+package frankfurter
+
+import (
+	"strings"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	URL string
+}
+
+// LoadConfig loads the Frankfurter configuration from the environment variables.
+//
+// Environment Variables:
+//   - FRANKFURTER_URL: the base URL of the Frankfurter API, defaulting to DefaultURL.
+//
+// Returns:
+//   - Config: the Frankfurter configuration.
+func LoadConfig() Config {
+	return Config{
+		URL: strings.TrimSuffix(utils.GetEnvString("FRANKFURTER_URL", DefaultURL), "/"),
+	}
+}
@@ -0,0 +1,94 @@
+package frankfurter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+)
+
+const (
+	Name = "frankfurter"
+
+	// DefaultURL is the base URL of the public Frankfurter API.
+	DefaultURL = "https://api.frankfurter.app"
+)
+
+type Frankfurter struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// New creates a new instance of Frankfurter with the provided configuration and HTTP client.
+//
+// Parameters:
+//   - config: the Frankfurter configuration.
+//   - httpClient: the HTTP client the rates are requested with.
+//
+// Returns:
+//   - *Frankfurter: a pointer to the newly created Frankfurter.
+func New(config Config, httpClient *http.Client) *Frankfurter {
+	return &Frankfurter{
+		config:     config,
+		httpClient: httpClient,
+	}
+}
+
+// Name returns the name of the provider.
+func (p *Frankfurter) Name() string {
+	return Name
+}
+
+// GetRates fetches the latest exchange rates from the Frankfurter API, based on EUR.
+//
+// Returns:
+//   - *models.CurrencyDataResponse: the latest exchange rates.
+//   - error: an error if the request fails or the response cannot be decoded.
+func (p *Frankfurter) GetRates() (*models.CurrencyDataResponse, error) {
+	return p.fetch("latest")
+}
+
+// GetHistoricalRates fetches the exchange rates of a past date from the Frankfurter API, based on EUR.
+// On weekends and holidays the API answers with the rates of the last working day before the date.
+//
+// Parameters:
+//   - date: the day of the rates.
+//
+// Returns:
+//   - *models.CurrencyDataResponse: the exchange rates of the day.
+//   - error: an error if the request fails or the response cannot be decoded.
+func (p *Frankfurter) GetHistoricalRates(date time.Time) (*models.CurrencyDataResponse, error) {
+	return p.fetch(date.Format(models.RatesDateFormat))
+}
+
+// fetch requests the rates at a path of the Frankfurter API. The response does not list its base currency,
+// so it is added with rate 1.
+func (p *Frankfurter) fetch(path string) (*models.CurrencyDataResponse, error) {
+	resp, err := p.httpClient.Get(p.config.URL + "/" + path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status: %s", resp.Status)
+	}
+
+	var data struct {
+		Base  string             `json:"base"`
+		Date  string             `json:"date"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	if len(data.Rates) == 0 || data.Base == "" {
+		return nil, fmt.Errorf("API response has no rates")
+	}
+
+	data.Rates[data.Base] = 1
+	return &models.CurrencyDataResponse{Base: data.Base, Date: data.Date, Rates: data.Rates}, nil
+}
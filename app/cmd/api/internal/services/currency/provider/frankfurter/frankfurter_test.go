@@ -0,0 +1,64 @@
+package frankfurter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRates_Success(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/latest", r.URL.Path)
+		w.Write([]byte(`{"amount": 1.0, "base": "EUR", "date": "2024-10-01", "rates": {"USD": 1.1134, "BRL": 6.0528}}`))
+	}))
+	defer server.Close()
+	provider := New(Config{URL: server.URL}, server.Client())
+
+	// Action
+	data, err := provider.GetRates()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", data.Base)
+	assert.Equal(t, "2024-10-01", data.Date)
+	assert.Equal(t, map[string]float64{"EUR": 1, "USD": 1.1134, "BRL": 6.0528}, data.Rates)
+}
+
+func TestGetRates_Failure(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "not found"}`))
+	}))
+	defer server.Close()
+	provider := New(Config{URL: server.URL}, server.Client())
+
+	// Action
+	data, err := provider.GetRates()
+
+	// Assert
+	assert.EqualError(t, err, "API request failed with status: 404 Not Found")
+	assert.Nil(t, data)
+}
+
+func TestGetHistoricalRates(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2024-09-29", r.URL.Path)
+		w.Write([]byte(`{"amount": 1.0, "base": "EUR", "date": "2024-09-27", "rates": {"USD": 1.1158}}`))
+	}))
+	defer server.Close()
+	provider := New(Config{URL: server.URL}, server.Client())
+
+	// Action
+	data, err := provider.GetHistoricalRates(time.Date(2024, 9, 29, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-09-27", data.Date)
+	assert.Equal(t, map[string]float64{"EUR": 1, "USD": 1.1158}, data.Rates)
+}
@@ -0,0 +1,35 @@
+package openexchangerates
+
+import (
+	"os"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+// HistoricalURL is the URL of the historical rates of Open Exchange Rates, with placeholders for the date and the app ID.
+const HistoricalURL = "https://openexchangerates.org/api/historical/%s.json?app_id=%s"
+
+type Config struct {
+	URL           string
+	HistoricalURL string
+	SecretKey     string
+}
+
+// LoadConfig loads the Open Exchange Rates configuration from the environment variables.
+//
+// Environment Variables:
+//   - OPEN_EXCHANGE_RATES_URL: the URL of the latest rates, with a %s placeholder for the app ID,
+//     e.g. "https://openexchangerates.org/api/latest.json?app_id=%s".
+//   - OPEN_EXCHANGE_RATES_HISTORICAL_URL: the URL of the rates of a day, with %s placeholders for the date and
+//     the app ID, defaulting to HistoricalURL.
+//   - OPEN_EXCHANGE_RATES_SECRET_KEY: the app ID the rates are requested with.
+//
+// Returns:
+//   - Config: the Open Exchange Rates configuration.
+func LoadConfig() Config {
+	return Config{
+		URL:           os.Getenv("OPEN_EXCHANGE_RATES_URL"),
+		HistoricalURL: utils.GetEnvString("OPEN_EXCHANGE_RATES_HISTORICAL_URL", HistoricalURL),
+		SecretKey:     os.Getenv("OPEN_EXCHANGE_RATES_SECRET_KEY"),
+	}
+}
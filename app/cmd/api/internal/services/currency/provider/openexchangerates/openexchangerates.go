@@ -0,0 +1,107 @@
+package openexchangerates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+const Name = "openexchangerates"
+
+type OpenExchangeRates struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// New creates a new instance of OpenExchangeRates with the provided configuration and HTTP client.
+//
+// Parameters:
+//   - config: the Open Exchange Rates configuration.
+//   - httpClient: the HTTP client the rates are requested with.
+//
+// Returns:
+//   - *OpenExchangeRates: a pointer to the newly created OpenExchangeRates.
+func New(config Config, httpClient *http.Client) *OpenExchangeRates {
+	return &OpenExchangeRates{
+		config:     config,
+		httpClient: httpClient,
+	}
+}
+
+// Name returns the name of the provider.
+func (p *OpenExchangeRates) Name() string {
+	return Name
+}
+
+// GetRates fetches the latest currency exchange rates from the Open Exchange Rates API, based on USD.
+//
+// Returns:
+//   - *models.CurrencyDataResponse: the latest exchange rates.
+//   - error: an error if the provider is not configured, the request fails or the response cannot be decoded.
+func (p *OpenExchangeRates) GetRates() (*models.CurrencyDataResponse, error) {
+	if utils.IsEmptyOrNull(p.config.URL) {
+		return nil, fmt.Errorf("open exchange rates url is not set or is empty")
+	}
+	if utils.IsEmptyOrNull(p.config.SecretKey) {
+		return nil, fmt.Errorf("secret key is not set or is empty")
+	}
+
+	return p.fetch(fmt.Sprintf(p.config.URL, p.config.SecretKey))
+}
+
+// GetHistoricalRates fetches the end of day exchange rates of a past date from the Open Exchange Rates API, based on USD.
+//
+// Parameters:
+//   - date: the day of the rates.
+//
+// Returns:
+//   - *models.CurrencyDataResponse: the exchange rates of the day.
+//   - error: an error if the provider is not configured, the request fails or the response cannot be decoded.
+func (p *OpenExchangeRates) GetHistoricalRates(date time.Time) (*models.CurrencyDataResponse, error) {
+	if utils.IsEmptyOrNull(p.config.HistoricalURL) {
+		return nil, fmt.Errorf("open exchange rates historical url is not set or is empty")
+	}
+	if utils.IsEmptyOrNull(p.config.SecretKey) {
+		return nil, fmt.Errorf("secret key is not set or is empty")
+	}
+
+	day := date.Format(models.RatesDateFormat)
+	data, err := p.fetch(fmt.Sprintf(p.config.HistoricalURL, day, p.config.SecretKey))
+	if err != nil {
+		return nil, err
+	}
+
+	data.Date = day
+	return data, nil
+}
+
+// fetch requests the rates at a URL of the Open Exchange Rates API.
+func (p *OpenExchangeRates) fetch(url string) (*models.CurrencyDataResponse, error) {
+	resp, err := p.httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status: %s", resp.Status)
+	}
+
+	var data struct {
+		Base  string             `json:"base"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	if len(data.Rates) == 0 {
+		return nil, fmt.Errorf("API response has no rates")
+	}
+
+	return &models.CurrencyDataResponse{Base: data.Base, Rates: data.Rates}, nil
+}
@@ -0,0 +1,94 @@
+package openexchangerates
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockServer(status int, response string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+}
+
+func TestGetRates_Success(t *testing.T) {
+	// Arrange
+	server := mockServer(http.StatusOK, `{"base": "USD", "rates": {"USD": 1.0, "EUR": 0.9}}`)
+	defer server.Close()
+	provider := New(Config{URL: server.URL + "/%s", SecretKey: "test_api_key"}, server.Client())
+
+	// Action
+	data, err := provider.GetRates()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", data.Base)
+	assert.Equal(t, 1.0, data.Rates["USD"])
+	assert.Equal(t, 0.9, data.Rates["EUR"])
+}
+
+func TestGetRates_Failure(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		config func(url string) Config
+	}{
+		{"Empty response", http.StatusOK, "", func(url string) Config { return Config{URL: url + "/%s", SecretKey: "key"} }},
+		{"No rates", http.StatusOK, `{"rates": {}}`, func(url string) Config { return Config{URL: url + "/%s", SecretKey: "key"} }},
+		{"Error status", http.StatusUnauthorized, `{"error": true}`, func(url string) Config { return Config{URL: url + "/%s", SecretKey: "key"} }},
+		{"Missing secret key", http.StatusOK, "", func(url string) Config { return Config{URL: url + "/%s"} }},
+		{"Missing url", http.StatusOK, "", func(url string) Config { return Config{SecretKey: "key"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := mockServer(tt.status, tt.body)
+			defer server.Close()
+			provider := New(tt.config(server.URL), server.Client())
+
+			// Action
+			data, err := provider.GetRates()
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, data)
+		})
+	}
+}
+
+func TestGetHistoricalRates(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2024-10-01/test_api_key", r.URL.Path)
+		w.Write([]byte(`{"base": "USD", "rates": {"USD": 1.0, "BRL": 5.45}}`))
+	}))
+	defer server.Close()
+	provider := New(Config{HistoricalURL: server.URL + "/%s/%s", SecretKey: "test_api_key"}, server.Client())
+
+	// Action
+	data, err := provider.GetHistoricalRates(time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-10-01", data.Date)
+	assert.Equal(t, 5.45, data.Rates["BRL"])
+}
+
+func TestGetHistoricalRates_NotConfigured(t *testing.T) {
+	// Arrange
+	provider := New(Config{HistoricalURL: "http://localhost/%s/%s"}, http.DefaultClient)
+
+	// Action
+	data, err := provider.GetHistoricalRates(time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC))
+
+	// Assert
+	assert.EqualError(t, err, "secret key is not set or is empty")
+	assert.Nil(t, data)
+}
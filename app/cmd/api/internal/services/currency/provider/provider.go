@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency/provider/ecb"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency/provider/frankfurter"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency/provider/openexchangerates"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency/provider/static"
+	"go.uber.org/zap"
+)
+
+const ChainName = "chain"
+
+// RateProvider supplies the latest and the historical currency exchange rates. Rates are relative to
+// a base currency, which is listed with rate 1.
+type RateProvider interface {
+	Name() string
+	GetRates() (*models.CurrencyDataResponse, error)
+	GetHistoricalRates(date time.Time) (*models.CurrencyDataResponse, error)
+}
+
+type Chain struct {
+	logger    *zap.Logger
+	providers []RateProvider
+}
+
+// NewChain creates a new instance of Chain with the providers in the order of the configured priority.
+//
+// Parameters:
+//   - logger: an instance of zap.Logger used to log the providers that failed.
+//   - config: the exchange rate providers configuration.
+//
+// Returns:
+//   - *Chain: a pointer to the newly created Chain.
+//   - error: an error if a configured provider is unknown or no provider is configured.
+func NewChain(logger *zap.Logger, config Config) (*Chain, error) {
+	httpClient := &http.Client{Timeout: config.Timeout}
+
+	providers := make([]RateProvider, 0, len(config.Providers))
+	for _, name := range config.Providers {
+		switch strings.ToLower(name) {
+		case openexchangerates.Name:
+			providers = append(providers, openexchangerates.New(config.OpenExchangeRates, httpClient))
+		case ecb.Name:
+			providers = append(providers, ecb.New(config.ECB, httpClient))
+		case frankfurter.Name:
+			providers = append(providers, frankfurter.New(config.Frankfurter, httpClient))
+		case static.Name:
+			providers = append(providers, static.New(config.Static))
+		default:
+			return nil, fmt.Errorf("unknown exchange rate provider: %s", name)
+		}
+	}
+
+	if len(providers) == 0 {
+		return nil, errors.New("no exchange rate provider is configured")
+	}
+
+	return New(logger, providers...), nil
+}
+
+// New creates a new instance of Chain with the provided providers, in order of priority.
+//
+// Parameters:
+//   - logger: an instance of zap.Logger used to log the providers that failed.
+//   - providers: the providers, from the highest to the lowest priority.
+//
+// Returns:
+//   - *Chain: a pointer to the newly created Chain.
+func New(logger *zap.Logger, providers ...RateProvider) *Chain {
+	return &Chain{
+		logger:    logger,
+		providers: providers,
+	}
+}
+
+// Name returns the name of the chain.
+func (c *Chain) Name() string {
+	return ChainName
+}
+
+// GetRates fetches the rates from the providers in order of priority, falling back to the next provider
+// when one fails. The rates carry the name of the provider that supplied them.
+//
+// Returns:
+//   - *models.CurrencyDataResponse: the rates of the first provider that succeeded.
+//   - error: an error wrapping the errors of every provider if all of them failed.
+func (c *Chain) GetRates() (*models.CurrencyDataResponse, error) {
+	return c.fallback(func(provider RateProvider) (*models.CurrencyDataResponse, error) {
+		return provider.GetRates()
+	})
+}
+
+// GetHistoricalRates fetches the rates of a past date from the providers in order of priority, falling back
+// to the next provider when one fails. The rates carry the name of the provider that supplied them.
+//
+// Parameters:
+//   - date: the day of the rates.
+//
+// Returns:
+//   - *models.CurrencyDataResponse: the rates of the first provider that succeeded.
+//   - error: an error wrapping the errors of every provider if all of them failed.
+func (c *Chain) GetHistoricalRates(date time.Time) (*models.CurrencyDataResponse, error) {
+	return c.fallback(func(provider RateProvider) (*models.CurrencyDataResponse, error) {
+		return provider.GetHistoricalRates(date)
+	})
+}
+
+// fallback calls fetch with each provider in order of priority until one of them succeeds.
+func (c *Chain) fallback(fetch func(provider RateProvider) (*models.CurrencyDataResponse, error)) (*models.CurrencyDataResponse, error) {
+	var errs []error
+
+	for _, provider := range c.providers {
+		rates, err := fetch(provider)
+		if err == nil {
+			rates.Provider = provider.Name()
+			return rates, nil
+		}
+
+		c.logger.Warn("Exchange rate provider failed, falling back to the next one", zap.String("provider", provider.Name()), zap.Error(err))
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+	}
+
+	return nil, fmt.Errorf("all exchange rate providers failed: %w", errors.Join(errs...))
+}
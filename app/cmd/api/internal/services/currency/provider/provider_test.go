@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeProvider struct {
+	name  string
+	rates *models.CurrencyDataResponse
+	err   error
+	calls int
+	date  time.Time
+}
+
+func (p *fakeProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeProvider) GetRates() (*models.CurrencyDataResponse, error) {
+	p.calls++
+	return p.rates, p.err
+}
+
+func (p *fakeProvider) GetHistoricalRates(date time.Time) (*models.CurrencyDataResponse, error) {
+	p.calls++
+	p.date = date
+	return p.rates, p.err
+}
+
+func TestChain_GetRates(t *testing.T) {
+	tests := []struct {
+		name             string
+		providers        []*fakeProvider
+		expectedProvider string
+		expectedCalls    []int
+	}{
+		{
+			name: "First provider succeeds",
+			providers: []*fakeProvider{
+				{name: "openexchangerates", rates: &models.CurrencyDataResponse{Rates: map[string]float64{"USD": 1}}},
+				{name: "ecb", rates: &models.CurrencyDataResponse{Rates: map[string]float64{"EUR": 1}}},
+			},
+			expectedProvider: "openexchangerates",
+			expectedCalls:    []int{1, 0},
+		},
+		{
+			name: "Falls back on errors",
+			providers: []*fakeProvider{
+				{name: "openexchangerates", err: errors.New("API request failed with status: 503 Service Unavailable")},
+				{name: "ecb", err: errors.New("timeout")},
+				{name: "static", rates: &models.CurrencyDataResponse{Rates: map[string]float64{"USD": 1}}},
+			},
+			expectedProvider: "static",
+			expectedCalls:    []int{1, 1, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			providers := make([]RateProvider, 0, len(tt.providers))
+			for _, provider := range tt.providers {
+				providers = append(providers, provider)
+			}
+			chain := New(zap.NewNop(), providers...)
+
+			// Action
+			rates, err := chain.GetRates()
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedProvider, rates.Provider)
+			for i, provider := range tt.providers {
+				assert.Equal(t, tt.expectedCalls[i], provider.calls, provider.name)
+			}
+		})
+	}
+}
+
+func TestChain_GetRates_AllFail(t *testing.T) {
+	// Arrange
+	chain := New(zap.NewNop(),
+		&fakeProvider{name: "openexchangerates", err: errors.New("secret key is not set or is empty")},
+		&fakeProvider{name: "ecb", err: errors.New("timeout")},
+	)
+
+	// Action
+	rates, err := chain.GetRates()
+
+	// Assert
+	assert.Nil(t, rates)
+	assert.EqualError(t, err, "all exchange rate providers failed: openexchangerates: secret key is not set or is empty\necb: timeout")
+}
+
+func TestChain_GetHistoricalRates(t *testing.T) {
+	// Arrange
+	date := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	failing := &fakeProvider{name: "openexchangerates", err: errors.New("timeout")}
+	fallback := &fakeProvider{name: "ecb", rates: &models.CurrencyDataResponse{Date: "2024-10-01", Rates: map[string]float64{"EUR": 1}}}
+	chain := New(zap.NewNop(), failing, fallback)
+
+	// Action
+	rates, err := chain.GetHistoricalRates(date)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "ecb", rates.Provider)
+	assert.Equal(t, date, failing.date)
+	assert.Equal(t, date, fallback.date)
+}
+
+func TestNewChain(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []string
+		wantErr   string
+	}{
+		{"All providers", []string{"openexchangerates", "ecb", "frankfurter", "static"}, ""},
+		{"Unknown provider", []string{"ecb", "bank"}, "unknown exchange rate provider: bank"},
+		{"No provider", []string{}, "no exchange rate provider is configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Action
+			chain, err := NewChain(zap.NewNop(), Config{Providers: tt.providers})
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, chain)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, chain.providers, len(tt.providers))
+		})
+	}
+}
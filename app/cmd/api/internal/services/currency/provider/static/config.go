@@ -0,0 +1,20 @@
+package static
+
+import "os"
+
+type Config struct {
+	Path string
+}
+
+// LoadConfig loads the static rates configuration from the environment variables.
+//
+// Environment Variables:
+//   - EXCHANGE_RATES_FILE: the path of a JSON file with the rates, e.g. {"base": "USD", "rates": {"USD": 1, "BRL": 5.6}}.
+//
+// Returns:
+//   - Config: the static rates configuration.
+func LoadConfig() Config {
+	return Config{
+		Path: os.Getenv("EXCHANGE_RATES_FILE"),
+	}
+}
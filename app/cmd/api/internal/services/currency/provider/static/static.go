@@ -0,0 +1,71 @@
+package static
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+const Name = "static"
+
+type Static struct {
+	config Config
+}
+
+// New creates a new instance of Static with the provided configuration.
+//
+// Parameters:
+//   - config: the static rates configuration.
+//
+// Returns:
+//   - *Static: a pointer to the newly created Static.
+func New(config Config) *Static {
+	return &Static{
+		config: config,
+	}
+}
+
+// Name returns the name of the provider.
+func (p *Static) Name() string {
+	return Name
+}
+
+// GetRates reads the exchange rates from the configured file. The file is read on each call,
+// so it can be updated without restarting the application.
+//
+// Returns:
+//   - *models.CurrencyDataResponse: the exchange rates of the file.
+//   - error: an error if the file is not configured, cannot be read or has no rates.
+func (p *Static) GetRates() (*models.CurrencyDataResponse, error) {
+	if utils.IsEmptyOrNull(p.config.Path) {
+		return nil, fmt.Errorf("exchange rates file is not set or is empty")
+	}
+
+	content, err := os.ReadFile(p.config.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var data models.CurrencyDataResponse
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("invalid exchange rates file: %w", err)
+	}
+
+	if len(data.Rates) == 0 {
+		return nil, fmt.Errorf("exchange rates file has no rates")
+	}
+
+	return &data, nil
+}
+
+// GetHistoricalRates always fails, as the file only holds the current exchange rates.
+//
+// Returns:
+//   - error: an error stating that historical rates are not supported.
+func (p *Static) GetHistoricalRates(date time.Time) (*models.CurrencyDataResponse, error) {
+	return nil, fmt.Errorf("historical exchange rates are not supported")
+}
@@ -0,0 +1,67 @@
+package static
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestGetRates_Success(t *testing.T) {
+	// Arrange
+	provider := New(Config{Path: writeFile(t, `{"base": "USD", "date": "2024-10-01", "rates": {"USD": 1, "BRL": 5.6}}`)})
+
+	// Action
+	data, err := provider.GetRates()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", data.Base)
+	assert.Equal(t, map[string]float64{"USD": 1, "BRL": 5.6}, data.Rates)
+}
+
+func TestGetRates_Failure(t *testing.T) {
+	tests := []struct {
+		name string
+		path func(t *testing.T) string
+	}{
+		{"Not configured", func(t *testing.T) string { return "" }},
+		{"Missing file", func(t *testing.T) string { return filepath.Join(t.TempDir(), "missing.json") }},
+		{"Invalid file", func(t *testing.T) string { return writeFile(t, "rates") }},
+		{"No rates", func(t *testing.T) string { return writeFile(t, `{"base": "USD"}`) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			provider := New(Config{Path: tt.path(t)})
+
+			// Action
+			data, err := provider.GetRates()
+
+			// Assert
+			assert.Error(t, err)
+			assert.Nil(t, data)
+		})
+	}
+}
+
+func TestGetHistoricalRates(t *testing.T) {
+	// Arrange
+	provider := New(Config{Path: writeFile(t, `{"base": "USD", "rates": {"USD": 1}}`)})
+
+	// Action
+	data, err := provider.GetHistoricalRates(time.Now())
+
+	// Assert
+	assert.EqualError(t, err, "historical exchange rates are not supported")
+	assert.Nil(t, data)
+}
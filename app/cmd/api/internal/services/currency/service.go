@@ -1,38 +1,82 @@
 package currency
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
-	"os"
+	"math"
+	"math/big"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/currency/provider"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/spread"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
-	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/iso4217"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/money"
+	"go.uber.org/zap"
+)
+
+// MaxTimeSeriesDays is the maximum number of days of a time series.
+const MaxTimeSeriesDays = 366
+
+var (
+	// ErrInvalidDate is returned when the date of the rates is malformed or in the future.
+	ErrInvalidDate = errors.New("date must be today or a past day in the format YYYY-MM-DD")
+	// ErrInvalidRange is returned when a time series ends before it starts or spans too many days.
+	ErrInvalidRange = fmt.Errorf("from must not be after to and the range must not exceed %d days", MaxTimeSeriesDays)
+	// ErrMissingCurrency is returned when the rates do not list a requested currency.
+	ErrMissingCurrency = errors.New("missing or unavailable currency keys")
+	// ErrCurrencyNotFound is returned when a code is not an active ISO 4217 currency.
+	ErrCurrencyNotFound = errors.New("currency not found")
 )
 
 type CurrencyService interface {
 	GetAllCurrency() (*[]string, error)
-	ConvertExchangeRate(currency models.CurrencyConvert) (*float64, error)
+	GetAllCurrencyMetadata() (*[]iso4217.Currency, error)
+	GetCurrency(code string) (*iso4217.Currency, error)
+	GetRates(date string) (*models.CurrencyDataResponse, error)
+	GetTimeSeries(query models.CurrencyTimeSeries) (*models.CurrencyTimeSeriesResponse, error)
+	ConvertExchangeRate(currency models.CurrencyConvert) (*models.CurrencyConvertResponse, error)
+	ConvertExchangeRateBatch(batch models.CurrencyConvertBatch) (*models.CurrencyConvertBatchResponse, error)
+	FormatAmount(query models.CurrencyFormat) (*models.CurrencyFormatResponse, error)
 }
 
 type currencyService struct {
-	cache cache.CacheClient
+	cache   cache.CacheClient
+	rates   provider.RateProvider
+	spreads spread.SpreadService
+	logger  *zap.Logger
+	config  Config
+	refresh *flight
+	now     func() time.Time
 }
 
-// New creates a new instance of currencyService with the provided cache client.
-// It returns a pointer to the newly created currencyService.
+// New creates a new instance of currencyService with the provided cache client, rate provider, spreads,
+// logger and configuration. It returns a pointer to the newly created currencyService.
 //
 // Parameters:
 //   - cache: an instance of cache.CacheClient used for caching.
+//   - rates: the provider.RateProvider the exchange rates are fetched from, usually a chain of providers.
+//   - spreads: the spread.SpreadService that resolves the markup applied on top of the mid-market rates.
+//   - logger: an instance of zap.Logger used to log the failed background refreshes.
+//   - config: the latest exchange rates configuration.
 //
 // Returns:
 //   - *currencyService: a pointer to the initialized currencyService.
-func New(cache cache.CacheClient) *currencyService {
+func New(cache cache.CacheClient, rates provider.RateProvider, spreads spread.SpreadService, logger *zap.Logger, config Config) *currencyService {
 	return &currencyService{
-		cache: cache,
+		cache:   cache,
+		rates:   rates,
+		spreads: spreads,
+		logger:  logger,
+		config:  config,
+		refresh: &flight{},
+		now:     time.Now,
 	}
 }
 
@@ -56,24 +100,160 @@ func (p *currencyService) GetAllCurrency() (*[]string, error) {
 	return &currencies, nil
 }
 
+// GetAllCurrencyMetadata retrieves the ISO 4217 metadata of all available currencies, sorted by code.
+// Currencies listed by the rates that are not in ISO 4217, such as precious metals, only have their code.
+//
+// Returns:
+//   - *[]iso4217.Currency: the metadata of the available currencies.
+//   - error: an error if the rates cannot be retrieved.
+func (p *currencyService) GetAllCurrencyMetadata() (*[]iso4217.Currency, error) {
+	codes, err := p.GetAllCurrency()
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := make([]iso4217.Currency, 0, len(*codes))
+	for _, code := range *codes {
+		currency, ok := iso4217.Get(code)
+		if !ok {
+			currency = iso4217.Currency{Code: code}
+		}
+		currencies = append(currencies, currency)
+	}
+
+	return &currencies, nil
+}
+
+// GetCurrency retrieves the ISO 4217 metadata of a currency.
+//
+// Parameters:
+//   - code: the alphabetic code of the currency, in any case.
+//
+// Returns:
+//   - *iso4217.Currency: the metadata of the currency.
+//   - error: ErrCurrencyNotFound if the code is not an active ISO 4217 currency.
+func (p *currencyService) GetCurrency(code string) (*iso4217.Currency, error) {
+	currency, ok := iso4217.Get(strings.ToUpper(code))
+	if !ok {
+		return nil, ErrCurrencyNotFound
+	}
+
+	return &currency, nil
+}
+
+// GetRates retrieves the exchange rates of a day. The latest rates are returned when the date is empty or today.
+//
+// Parameters:
+//   - date: the day of the rates in the format YYYY-MM-DD, or empty for the latest rates.
+//
+// Returns:
+//   - *models.CurrencyDataResponse: the exchange rates of the day.
+//   - error: ErrInvalidDate if the date is malformed or in the future, or an error if the rates cannot be retrieved.
+func (p *currencyService) GetRates(date string) (*models.CurrencyDataResponse, error) {
+	return p.ratesAt(date)
+}
+
+// GetTimeSeries retrieves the daily exchange rates of the symbols against the base currency over a range of days,
+// with the minimum, maximum, mean and percent change of each symbol over the range.
+// The rates of each day come from the stored historical snapshots, and the days that are not stored yet
+// are fetched from the rate provider and stored.
+//
+// Parameters:
+//   - query: the base currency, the comma-separated symbols and the first and last days of the range.
+//
+// Returns:
+//   - *models.CurrencyTimeSeriesResponse: the rates of each day, keyed by day and symbol, and the statistics of each symbol.
+//   - error: ErrInvalidDate or ErrInvalidRange if the range is invalid, ErrMissingCurrency if a day has no rate
+//     for a currency, or an error if the rates cannot be retrieved.
+func (p *currencyService) GetTimeSeries(query models.CurrencyTimeSeries) (*models.CurrencyTimeSeriesResponse, error) {
+	from, err := time.Parse(models.RatesDateFormat, query.From)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+
+	to, err := time.Parse(models.RatesDateFormat, query.To)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+
+	if to.Before(from) || to.Sub(from) >= MaxTimeSeriesDays*24*time.Hour {
+		return nil, ErrInvalidRange
+	}
+
+	symbols := parseSymbols(query.Symbols)
+	series := make(map[string][]float64, len(symbols))
+	result := &models.CurrencyTimeSeriesResponse{
+		Base:       query.Base,
+		From:       query.From,
+		To:         query.To,
+		Rates:      map[string]map[string]float64{},
+		Statistics: make(map[string]models.CurrencyTimeSeriesStatistics, len(symbols)),
+	}
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(models.RatesDateFormat)
+
+		res, err := p.ratesAt(date)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = checkMissingKeys(res.Rates, append([]string{query.Base}, symbols...)...); err != nil {
+			return nil, err
+		}
+
+		rates := make(map[string]float64, len(symbols))
+		for _, symbol := range symbols {
+			rates[symbol], _ = crossRate(res.Rates[query.Base], res.Rates[symbol]).Float64()
+			series[symbol] = append(series[symbol], rates[symbol])
+		}
+		result.Rates[date] = rates
+	}
+
+	for symbol, values := range series {
+		result.Statistics[symbol] = statistics(values)
+	}
+
+	return result, nil
+}
+
+// parseSymbols splits comma-separated currency codes, trimming and upper-casing them and dropping repeated codes.
+func parseSymbols(value string) []string {
+	symbols := []string{}
+	for _, symbol := range strings.Split(value, ",") {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol != "" && !slices.Contains(symbols, symbol) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
 // ConvertExchangeRate converts the amount from one currency to another based on the exchange rates.
 // It takes a CurrencyConvert model as input which contains the amount to be converted and the source and target currencies.
-// It returns the converted amount as a pointer to float64 and an error if any occurs during the conversion process.
+// When the CurrencyConvert has a date, the rates of that day are used instead of the latest ones, and when it has
+// a format, the converted amount is also formatted for that locale.
+// The spread of the merchant and the currencies is taken off the mid-market rate, and the difference between
+// the amount at the mid rate and the converted amount is returned as the markup.
+// It returns the converted amount with the provider that supplied the rates, and an error if any occurs during the conversion process.
 //
 // The function performs the following steps:
-// 1. Retrieves and serializes the exchange rate data.
+// 1. Retrieves the exchange rate data of the requested day.
 // 2. Checks for missing keys in the exchange rate data for the source and target currencies.
-// 3. Converts the amount using the exchange rates for the source and target currencies.
+// 3. Resolves the spread that applies to the merchant and the currencies.
+// 4. Converts the amount with the exact cross rate of the currencies less the spread, rounded to the minor units
+// of the target currency with the requested rounding mode, half-even by default.
 //
 // Parameters:
-// - currency: A models.CurrencyConvert struct containing the amount, source currency, and target currency.
+// - currency: A models.CurrencyConvert struct containing the amount, source currency, target currency and merchant.
 //
 // Returns:
-// - A pointer to the converted amount as float64.
+// - A pointer to the conversion result, with the converted amount, the mid and applied rates, the spread, the markup,
+// the day and the provider of the rates.
 // - An error if any issue occurs during the retrieval of exchange rates or the conversion process.
-func (p *currencyService) ConvertExchangeRate(currency models.CurrencyConvert) (*float64, error) {
+func (p *currencyService) ConvertExchangeRate(currency models.CurrencyConvert) (*models.CurrencyConvertResponse, error) {
 
-	res, err := p.getAndSerializerData()
+	res, err := p.ratesAt(currency.Date)
 	if err != nil {
 		return nil, err
 	}
@@ -83,124 +263,391 @@ func (p *currencyService) ConvertExchangeRate(currency models.CurrencyConvert) (
 		return nil, err
 	}
 
-	amount := convert(currency.Amount, res.Rates[currency.FromCurrency], res.Rates[currency.ToCurrency])
-	return &amount, nil
+	basisPoints, err := p.spreadBasisPoints(currency.MerchantId, currency.FromCurrency, currency.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	rounding := roundingMode(currency.Rounding)
+	midRate := crossRate(res.Rates[currency.FromCurrency], res.Rates[currency.ToCurrency])
+	rate := applySpread(midRate, basisPoints)
+	amount, markup := convertWithMarkup(currency.Amount, midRate, rate, currency.ToCurrency, rounding)
+	result := &models.CurrencyConvertResponse{
+		Amount:            amount,
+		FromCurrency:      currency.FromCurrency,
+		ToCurrency:        currency.ToCurrency,
+		Date:              res.Date,
+		MidRate:           money.DecimalString(midRate),
+		Rate:              money.DecimalString(rate),
+		SpreadBasisPoints: basisPoints,
+		Markup:            markup,
+		Rounding:          string(rounding),
+		Provider:          res.Provider,
+		AsOf:              res.AsOf,
+		Stale:             res.Stale,
+	}
+
+	if currency.Format != "" {
+		if result.Formatted, err = money.Format(result.Amount, currency.ToCurrency, currency.Format); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
 }
 
-// GetAndSerializerData retrieves currency data from the cache or an external service,
-// serializes it, and stores it in the cache if not already present.
-// It returns the currency data response or an error if any operation fails.
+// FormatAmount formats an amount of a currency for a locale, with the grouping, decimal separator and
+// symbol placement of the locale and the minor units of the currency.
+//
+// Parameters:
+//   - query: the amount, the ISO 4217 code of its currency and the locale, such as "pt_BR".
 //
 // Returns:
-//   - *models.CurrencyDataResponse: The currency data response.
-//   - error: An error if any operation fails.
-func (p currencyService) getAndSerializerData() (*models.CurrencyDataResponse, error) {
+//   - *models.CurrencyFormatResponse: the formatted amount.
+//   - error: money.ErrUnsupportedLocale or money.ErrUnknownCurrency if the locale or the currency is not supported.
+func (p *currencyService) FormatAmount(query models.CurrencyFormat) (*models.CurrencyFormatResponse, error) {
+	formatted, err := money.Format(query.Amount, query.Currency, query.Locale)
+	if err != nil {
+		return nil, err
+	}
 
-	c, err := p.cache.Get(cache.ExchangeRateKey)
-	var res *models.CurrencyDataResponse
+	return &models.CurrencyFormatResponse{
+		Amount:    query.Amount,
+		Currency:  query.Currency,
+		Locale:    query.Locale,
+		Formatted: formatted,
+	}, nil
+}
 
+// ConvertExchangeRateBatch converts the amounts of many items, each one from a currency to another.
+// The rates are loaded once for the whole batch, so every item is converted with the same snapshot of rates.
+// The spread of the merchant of the batch is resolved once for each pair of currencies.
+// An item whose currencies are missing from the rates gets an error, without failing the other items.
+//
+// Parameters:
+//   - batch: the items to convert, the optional day of the rates and the optional merchant.
+//
+// Returns:
+//   - *models.CurrencyConvertBatchResponse: the result of each item, in the order of the items, with the day and the provider of the rates.
+//   - error: an error if the rates or the spreads cannot be retrieved.
+func (p *currencyService) ConvertExchangeRateBatch(batch models.CurrencyConvertBatch) (*models.CurrencyConvertBatchResponse, error) {
+	res, err := p.ratesAt(batch.Date)
 	if err != nil {
-		secretKey, err := getSecretKey()
-		if err != nil {
-			return nil, err
-		}
+		return nil, err
+	}
 
-		ulr, err := getUlr()
-		if err != nil {
-			return nil, err
+	// Catalogs repeat the same pairs of currencies, so each cross rate and spread is resolved once.
+	type pair struct{ from, to string }
+	type quote struct {
+		midRate, rate             *big.Rat
+		midRateString, rateString string
+		basisPoints               int
+	}
+	quotes := map[pair]*quote{}
+
+	rounding := roundingMode(batch.Rounding)
+
+	// The converted amounts and markups share one allocation each instead of one per item.
+	amounts := make([]float64, len(batch.Items))
+	markups := make([]float64, len(batch.Items))
+	results := make([]models.CurrencyConvertBatchResult, len(batch.Items))
+	for i, item := range batch.Items {
+		results[i] = models.CurrencyConvertBatchResult{FromCurrency: item.FromCurrency, ToCurrency: item.ToCurrency}
+
+		if err := checkMissingKeys(res.Rates, item.FromCurrency, item.ToCurrency); err != nil {
+			results[i].Error = err.Error()
+			continue
 		}
 
-		res, err = getRates(ulr, secretKey)
-		if err != nil {
-			return nil, err
+		key := pair{item.FromCurrency, item.ToCurrency}
+		q, ok := quotes[key]
+		if !ok {
+			basisPoints, err := p.spreadBasisPoints(batch.MerchantId, item.FromCurrency, item.ToCurrency)
+			if err != nil {
+				return nil, err
+			}
+
+			q = &quote{midRate: crossRate(res.Rates[item.FromCurrency], res.Rates[item.ToCurrency]), basisPoints: basisPoints}
+			q.rate = applySpread(q.midRate, basisPoints)
+			q.midRateString = money.DecimalString(q.midRate)
+			q.rateString = q.midRateString
+			if q.rate != q.midRate {
+				q.rateString = money.DecimalString(q.rate)
+			}
+			quotes[key] = q
 		}
 
-		ratesSerializer, err := json.Marshal(res)
-		if err != nil {
+		amounts[i], markups[i] = convertWithMarkup(item.Amount, q.midRate, q.rate, item.ToCurrency, rounding)
+		results[i].Amount = &amounts[i]
+		results[i].Markup = &markups[i]
+		results[i].MidRate = q.midRateString
+		results[i].Rate = q.rateString
+		results[i].SpreadBasisPoints = q.basisPoints
+	}
+
+	return &models.CurrencyConvertBatchResponse{
+		Date:     res.Date,
+		Rounding: string(rounding),
+		Provider: res.Provider,
+		AsOf:     res.AsOf,
+		Stale:    res.Stale,
+		Results:  results,
+	}, nil
+}
+
+// ratesAt retrieves the exchange rates of a day. An empty date or today resolve to the latest rates, which
+// are refreshed every few minutes. Past rates never change, so they are cached without expiration.
+//
+// Parameters:
+//   - date: the day of the rates in the format YYYY-MM-DD, or empty for the latest rates.
+//
+// Returns:
+//   - *models.CurrencyDataResponse: the exchange rates of the day.
+//   - error: ErrInvalidDate if the date is malformed or in the future, or an error if the rates cannot be retrieved.
+func (p *currencyService) ratesAt(date string) (*models.CurrencyDataResponse, error) {
+	if date == "" {
+		return p.getAndSerializerData()
+	}
+
+	day, err := time.Parse(models.RatesDateFormat, date)
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+
+	today := p.now().UTC().Format(models.RatesDateFormat)
+	switch {
+	case date == today:
+		return p.getAndSerializerData()
+	case date > today:
+		return nil, ErrInvalidDate
+	}
+
+	key := fmt.Sprintf("%s_%s", cache.ExchangeRateHistoryKey, day.Format("2006_01_02"))
+	c, err := p.cache.Get(key)
+	if err == nil {
+		var res *models.CurrencyDataResponse
+		if err = json.Unmarshal(c, &res); err != nil {
 			return nil, err
 		}
+		return res, nil
+	}
 
-		if err = p.cache.Set(cache.ExchangeRateKey, ratesSerializer, time.Minute*5); err != nil {
+	res, err := p.rates.GetHistoricalRates(day)
+	if err != nil {
+		return nil, err
+	}
+	if res.Date == "" {
+		res.Date = date
+	}
+
+	ratesSerializer, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.cache.Set(key, ratesSerializer, 0); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// getAndSerializerData retrieves the latest rates from the cache while they are fresh. Once they are older than
+// the TTL or missing, they are fetched again from the rate provider, once for all the concurrent requests of the
+// instance. If the rate provider fails, the last known rates are served marked as stale, as long as they are not
+// older than the maximum staleness.
+//
+// Returns:
+//   - *models.CurrencyDataResponse: the latest rates, with the time they were fetched.
+//   - error: an error if the rates cannot be fetched and no recent enough rates are cached.
+func (p *currencyService) getAndSerializerData() (*models.CurrencyDataResponse, error) {
+	var cached *models.CurrencyDataResponse
+
+	c, err := p.cache.Get(cache.ExchangeRateKey)
+	if err == nil {
+		if err = json.Unmarshal(c, &cached); err != nil {
 			return nil, err
 		}
-	} else {
-		if err = json.Unmarshal([]byte(c), &res); err != nil {
-			return nil, err
+		if p.age(cached) < p.config.TTL {
+			return cached, nil
+		}
+	}
+
+	res, err := p.refresh.do(p.fetchRates)
+	if err != nil {
+		if cached != nil && p.age(cached) < p.config.MaxStaleness {
+			cached.Stale = true
+			return cached, nil
 		}
+		return nil, err
 	}
 
 	return res, nil
 }
 
-// getRates fetches the latest currency exchange rates from the Open Exchange Rates API.
-// It takes an API key as a parameter and returns a CurrencyDataResponse object containing
-// the exchange rates, or an error if the request fails or the response cannot be decoded.
+// Run refreshes the latest rates at the configured interval until the context is canceled, starting right away,
+// so requests find fresh rates in the cache instead of waiting for the rate provider. Rates that another instance
+// refreshed less than an interval ago are not fetched again.
 //
 // Parameters:
-//   - apiKey: A string containing the API key for accessing the Open Exchange Rates API.
-//
-// Returns:
-//   - *models.CurrencyDataResponse: A pointer to a CurrencyDataResponse object containing
-//     the latest exchange rates.
-//   - error: An error object if the request fails or the response cannot be decoded.
-func getRates(urlBase, apiKey string) (*models.CurrencyDataResponse, error) {
+//   - ctx: the context whose cancellation stops the refreshes.
+func (p *currencyService) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.refreshDue(); err != nil {
+			p.logger.Error("Failed to refresh exchange rates", zap.Error(err))
+		}
 
-	url := fmt.Sprintf(urlBase, apiKey)
-	resp, err := http.Get(url)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshDue fetches the latest rates unless the cached ones were fetched less than a refresh interval ago.
+func (p *currencyService) refreshDue() error {
+	if c, err := p.cache.Get(cache.ExchangeRateKey); err == nil {
+		var cached *models.CurrencyDataResponse
+		if json.Unmarshal(c, &cached) == nil && cached.AsOf != "" && p.age(cached) < p.config.RefreshInterval {
+			return nil
+		}
+	}
+
+	_, err := p.refresh.do(p.fetchRates)
+	return err
+}
+
+// fetchRates fetches the latest rates from the rate provider and caches them, with the time they were fetched,
+// for the maximum staleness, so they can still be served if the rate provider fails later.
+func (p *currencyService) fetchRates() (*models.CurrencyDataResponse, error) {
+	res, err := p.rates.GetRates()
 	if err != nil {
 		return nil, err
 	}
+	res.AsOf = p.now().UTC().Format(time.RFC3339)
 
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status: %s", resp.Status)
+	ratesSerializer, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
 	}
 
-	var data models.CurrencyDataResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	if err = p.cache.Set(cache.ExchangeRateKey, ratesSerializer, max(p.config.TTL, p.config.MaxStaleness)); err != nil {
 		return nil, err
 	}
 
-	return &data, nil
+	return res, nil
 }
 
-// convert converts an amount from one currency to another using the provided exchange rates.
-// It takes three parameters:
-// - amount: the amount of money to be converted.
-// - rateFrom: the exchange rate of the original currency.
-// - rateTo: the exchange rate of the target currency.
-// It returns the converted amount in the target currency.
-func convert(amount float64, rateFrom, rateTo float64) float64 {
-	return amount * (rateTo / rateFrom)
+// age returns how long ago the rates were fetched. Rates cached without the time they were fetched are
+// considered just fetched.
+func (p *currencyService) age(rates *models.CurrencyDataResponse) time.Duration {
+	asOf, err := time.Parse(time.RFC3339, rates.AsOf)
+	if err != nil {
+		return 0
+	}
+	return p.now().Sub(asOf)
 }
 
-// getSecretKey retrieves the Open Exchange Rates secret key from the environment variables.
-// It returns the secret key as a string and an error if the key is not set or is empty.
-//
-// Returns:
-//   - string: The secret key retrieved from the environment variables.
-//   - error: An error if the secret key is not set or is empty.
-func getSecretKey() (string, error) {
-	secretKey := os.Getenv("OPEN_EXCHANGE_RATES_SECRET_KEY")
-	if utils.IsEmptyOrNull(secretKey) {
-		return "", fmt.Errorf("secret key is not set or is empty")
+// spreadBasisPoints returns the basis points of the spread that applies to a conversion, or zero if none applies.
+func (p *currencyService) spreadBasisPoints(merchantId, fromCurrency, toCurrency string) (int, error) {
+	spread, err := p.spreads.Resolve(merchantId, fromCurrency, toCurrency)
+	if err != nil || spread == nil {
+		return 0, err
 	}
-	return secretKey, nil
+	return spread.BasisPoints, nil
 }
 
-// getUlr retrieves the URL for the Open Exchange Rates service from the environment variables.
-// It returns the URL as a string and an error if the URL is not set or is empty.
+// statistics computes the minimum, maximum, mean and the percent change from the first to the last of the rates.
+//
+// Parameters:
+//   - rates: the rates in chronological order, with at least one rate.
 //
 // Returns:
-//   - string: The URL for the Open Exchange Rates service.
-//   - error: An error if the URL is not set or is empty.
-func getUlr() (string, error) {
+//   - models.CurrencyTimeSeriesStatistics: the statistics of the rates.
+func statistics(rates []float64) models.CurrencyTimeSeriesStatistics {
+	stats := models.CurrencyTimeSeriesStatistics{Min: rates[0], Max: rates[0]}
+
+	var sum float64
+	for _, rate := range rates {
+		stats.Min = math.Min(stats.Min, rate)
+		stats.Max = math.Max(stats.Max, rate)
+		sum += rate
+	}
+
+	stats.Mean = sum / float64(len(rates))
+	stats.PercentChange = (rates[len(rates)-1] - rates[0]) / rates[0] * 100
+	return stats
+}
+
+// crossRate computes the exact rate from one currency to another, with the decimal rates of both currencies
+// against the base currency of the rates.
+// It takes two parameters:
+// - rateFrom: the exchange rate of the original currency, which must be positive.
+// - rateTo: the exchange rate of the target currency.
+// It returns the amount of the target currency one unit of the original currency is worth.
+func crossRate(rateFrom, rateTo float64) *big.Rat {
+	return new(big.Rat).Quo(money.Rat(rateTo), money.Rat(rateFrom))
+}
+
+// applySpread takes the spread off the mid-market rate, so the customer receives less of the target currency.
+// It takes two parameters:
+// - midRate: the cross rate from the original currency to the target currency.
+// - basisPoints: the spread in hundredths of a percent.
+// It returns the applied rate, which is the mid rate itself when there is no spread.
+func applySpread(midRate *big.Rat, basisPoints int) *big.Rat {
+	if basisPoints == 0 {
+		return midRate
+	}
+	return new(big.Rat).Mul(midRate, big.NewRat(int64(10000-basisPoints), 10000))
+}
+
+// convertWithMarkup converts an amount at the applied rate and computes the markup, which is the difference
+// between the amount converted at the mid rate and the amount converted at the applied rate.
+// It takes five parameters:
+// - amount: the amount of money to be converted.
+// - midRate: the cross rate from the original currency to the target currency.
+// - rate: the applied rate, the mid rate less the spread.
+// - toCurrency: the target currency, whose minor units the results are rounded to.
+// - rounding: the rounding mode of the results.
+// It returns the converted amount and the markup in the target currency.
+func convertWithMarkup(amount float64, midRate, rate *big.Rat, toCurrency string, rounding money.RoundingMode) (float64, float64) {
+	converted := convert(amount, rate, toCurrency, rounding)
+	result, _ := converted.Float64()
+	if rate == midRate {
+		return result, 0
+	}
+
+	markup, _ := new(big.Rat).Sub(convert(amount, midRate, toCurrency, rounding), converted).Float64()
+	return result, markup
+}
+
+// convert converts an amount from one currency to another with exact decimal arithmetic.
+// It takes four parameters:
+// - amount: the amount of money to be converted.
+// - rate: the cross rate from the original currency to the target currency.
+// - toCurrency: the target currency, whose minor units the result is rounded to.
+// - rounding: the rounding mode of the result.
+// It returns the exact converted amount in the target currency.
+func convert(amount float64, rate *big.Rat, toCurrency string, rounding money.RoundingMode) *big.Rat {
+	minorUnits := 2
+	if currency, ok := iso4217.Get(toCurrency); ok {
+		minorUnits = currency.MinorUnits
+	}
+
+	converted := new(big.Rat).Mul(money.Rat(amount), rate)
+	return money.Round(converted, minorUnits, rounding)
+}
 
-	secretKey := os.Getenv("OPEN_EXCHANGE_RATES_URL")
-	if utils.IsEmptyOrNull(secretKey) {
-		return "", fmt.Errorf("secret key is not set or is empty")
+// roundingMode returns the requested rounding mode, or half-even when none is requested.
+func roundingMode(mode string) money.RoundingMode {
+	if mode == "" {
+		return money.HalfEven
 	}
-	return secretKey, nil
+	return money.RoundingMode(mode)
 }
 
 // checkMissingKeys checks if the provided map contains all the specified keys.
@@ -215,13 +662,14 @@ func getUlr() (string, error) {
 func checkMissingKeys(m map[string]float64, keys ...string) error {
 	var missingKeys []string
 	for _, key := range keys {
-		if _, exists := m[key]; !exists {
+		// Rates that are not positive cannot convert amounts, so they are unavailable.
+		if rate, exists := m[key]; !exists || rate <= 0 {
 			missingKeys = append(missingKeys, key)
 		}
 	}
 
 	if len(missingKeys) > 0 {
-		return fmt.Errorf("missing or unavailable currency keys: %v", missingKeys)
+		return fmt.Errorf("%w: %v", ErrMissingCurrency, missingKeys)
 	}
 
 	return nil
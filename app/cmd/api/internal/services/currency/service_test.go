@@ -2,75 +2,95 @@ package currency
 
 import (
 	"errors"
-	"net/http"
-	"net/http/httptest"
-	"os"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/spread"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/iso4217"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/money"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
 )
 
-type MockCacheClient struct {
+type failingCache struct {
+	cache.CacheClient
+}
+
+func (c failingCache) Set(key string, item interface{}, expiration time.Duration) error {
+	return errors.New("cache set error")
+}
+
+type MockRateProvider struct {
 	mock.Mock
 }
 
-func (m *MockCacheClient) Get(key string) ([]byte, error) {
-	args := m.Called(key)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+func (m *MockRateProvider) Name() string {
+	return "mock"
+}
+
+func (m *MockRateProvider) GetRates() (*models.CurrencyDataResponse, error) {
+	args := m.Called()
+	var result *models.CurrencyDataResponse
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyDataResponse)
 	}
-	return []byte(args.String(0)), args.Error(1)
+	return result, args.Error(1)
 }
 
-func (m *MockCacheClient) Set(key string, item interface{}, expiration time.Duration) error {
-	args := m.Called(key, item, expiration)
-	if args.Get(0) == nil {
-		return nil
+func (m *MockRateProvider) GetHistoricalRates(date time.Time) (*models.CurrencyDataResponse, error) {
+	args := m.Called(date)
+	var result *models.CurrencyDataResponse
+	if args.Get(0) != nil {
+		result = args.Get(0).(*models.CurrencyDataResponse)
 	}
-	return args.Error(0)
+	return result, args.Error(1)
 }
 
-func (m *MockCacheClient) CheckCache() bool {
-	args := m.Called()
-	return args.Bool(0)
+// stubSpreads resolves the same spread for every conversion.
+type stubSpreads struct {
+	spread.SpreadService
+	resolved *models.Spread
+	err      error
 }
 
-func (m *MockCacheClient) Delete(key string) (*int64, error) {
-	args := m.Called(key)
-	return args.Get(0).(*int64), args.Error(1)
+func (s stubSpreads) Resolve(merchantId string, fromCurrency string, toCurrency string) (*models.Spread, error) {
+	return s.resolved, s.err
+}
+
+var testConfig = Config{TTL: 5 * time.Minute, RefreshInterval: 4 * time.Minute, MaxStaleness: time.Hour}
+
+func newAt(cache cache.CacheClient, rates *MockRateProvider, now time.Time) *currencyService {
+	service := New(cache, rates, stubSpreads{}, zap.NewNop(), testConfig)
+	service.now = func() time.Time { return now }
+	return service
 }
 
 func TestNew(t *testing.T) {
-	mockCache := new(MockCacheClient)
-	service := New(mockCache)
+	memory := cache.NewMemory()
+	mockRates := new(MockRateProvider)
+	service := New(memory, mockRates, stubSpreads{}, zap.NewNop(), testConfig)
 
 	assert.NotNil(t, service)
-	assert.Equal(t, mockCache, service.cache)
+	assert.Equal(t, memory, service.cache)
+	assert.Equal(t, mockRates, service.rates)
 }
 
 func TestGetAllCurrency_Success(t *testing.T) {
 	// Arrange
-	mockResponseStr := `{
-		"rates": {
-			"USD": 1.0,
-			"EUR": 0.85,
-			"JPY": 110.0
-		}
-	}`
-	server := mockServer(mockResponseStr)
-	os.Setenv("OPEN_EXCHANGE_RATES_SECRET_KEY", "test_api_key")
-	os.Setenv("OPEN_EXCHANGE_RATES_URL", server.URL+"/%s")
+	memory := cache.NewMemory()
+	mockRates := new(MockRateProvider)
+	service := newAt(memory, mockRates, time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC))
 
-	mockCache := new(MockCacheClient)
-	service := New(mockCache)
-
-	mockCache.On("Get", cache.ExchangeRateKey).Return(nil, errors.New("cache miss"))
-	mockCache.On("Set", cache.ExchangeRateKey, mock.Anything, time.Minute*5).Return(nil)
+	rates := map[string]float64{"USD": 1.0, "EUR": 0.85, "JPY": 110.0}
+	stored := &models.CurrencyDataResponse{Base: "USD", Provider: "ecb", AsOf: "2024-10-01T12:00:00Z", Rates: rates}
+	mockRates.On("GetRates").Return(&models.CurrencyDataResponse{Base: "USD", Provider: "ecb", Rates: rates}, nil)
 
 	// Action
 	currencies, err := service.GetAllCurrency()
@@ -79,13 +99,16 @@ func TestGetAllCurrency_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, currencies)
 	assert.Equal(t, []string{"EUR", "JPY", "USD"}, *currencies)
-	mockCache.AssertExpectations(t)
+
+	cached, _ := memory.Get(cache.ExchangeRateKey)
+	assert.JSONEq(t, utils.ToJSON(stored), string(cached))
 }
 
 func TestGetAllCurrency_CacheHint(t *testing.T) {
 	// Arrange
-	mockCache := new(MockCacheClient)
-	service := New(mockCache)
+	memory := cache.NewMemory()
+	mockRates := new(MockRateProvider)
+	service := New(memory, mockRates, stubSpreads{}, zap.NewNop(), testConfig)
 
 	mockResponse := &models.CurrencyDataResponse{
 		Rates: map[string]float64{
@@ -95,7 +118,7 @@ func TestGetAllCurrency_CacheHint(t *testing.T) {
 		},
 	}
 
-	mockCache.On("Get", cache.ExchangeRateKey).Return(utils.ToJSON(mockResponse), nil)
+	memory.Set(cache.ExchangeRateKey, utils.ToJSON(mockResponse), 0)
 
 	// Action
 	currencies, err := service.GetAllCurrency()
@@ -104,18 +127,16 @@ func TestGetAllCurrency_CacheHint(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, currencies)
 	assert.Equal(t, []string{"EUR", "JPY", "USD"}, *currencies)
-	mockCache.AssertExpectations(t)
+	mockRates.AssertNotCalled(t, "GetRates")
 }
 
 func TestGetAllCurrency_Failure_GetAndSerializerData(t *testing.T) {
 
 	// Arrange
-	os.Setenv("OPEN_EXCHANGE_RATES_SECRET_KEY", "test_api_key")
-	mockCache := new(MockCacheClient)
-	service := New(mockCache)
+	mockRates := new(MockRateProvider)
+	service := New(failingCache{cache.NewMemory()}, mockRates, stubSpreads{}, zap.NewNop(), testConfig)
 
-	mockCache.On("Get", cache.ExchangeRateKey).Return("", errors.New("cache miss"))
-	mockCache.On("Set", cache.ExchangeRateKey, mock.Anything, time.Minute*5).Return(errors.New("cache set error"))
+	mockRates.On("GetRates").Return(&models.CurrencyDataResponse{Rates: map[string]float64{"USD": 1.0}}, nil)
 
 	// Action
 	currencies, err := service.GetAllCurrency()
@@ -123,36 +144,33 @@ func TestGetAllCurrency_Failure_GetAndSerializerData(t *testing.T) {
 	// Assert
 	assert.Error(t, err)
 	assert.Nil(t, currencies)
-	mockCache.AssertExpectations(t)
 }
 
-func TestGetRates_Success(t *testing.T) {
+func TestGetAllCurrency_Failure_RateProvider(t *testing.T) {
 	// Arrange
-	mockResponse := `{
-		"rates": {
-			"USD": 1.0,
-			"EUR": 0.9
-		}
-	}`
-	server := mockServer(mockResponse)
+	memory := cache.NewMemory()
+	mockRates := new(MockRateProvider)
+	service := New(memory, mockRates, stubSpreads{}, zap.NewNop(), testConfig)
+
+	mockRates.On("GetRates").Return(nil, errors.New("all exchange rate providers failed"))
 
-	apiKey := "test_api_key"
 	// Action
-	data, err := getRates(server.URL+"/%s", apiKey)
+	currencies, err := service.GetAllCurrency()
 
 	// Assert
-	assert.NoError(t, err)
-	assert.NotNil(t, data)
-	assert.Equal(t, 1.0, data.Rates["USD"])
-	assert.Equal(t, 0.9, data.Rates["EUR"])
+	assert.Error(t, err)
+	assert.Nil(t, currencies)
+
+	_, err = memory.Get(cache.ExchangeRateKey)
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
 }
 
 func TestConvertExchangeRate_SuccessfulConversion(t *testing.T) {
 	// Arrange
-	mockCache := new(MockCacheClient)
-	service := New(mockCache)
+	memory := cache.NewMemory()
+	service := New(memory, new(MockRateProvider), stubSpreads{}, zap.NewNop(), testConfig)
 
-	mockCache.On("Get", cache.ExchangeRateKey).Return(`{"rates":{"USD":1.0,"EUR":0.85}}`, nil)
+	memory.Set(cache.ExchangeRateKey, `{"provider":"ecb","rates":{"USD":1.0,"EUR":0.85}}`, 0)
 
 	currency := models.CurrencyConvert{
 		Amount:       100,
@@ -166,15 +184,72 @@ func TestConvertExchangeRate_SuccessfulConversion(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
-	assert.Equal(t, 85.0, *result)
+	assert.Equal(t, models.CurrencyConvertResponse{Amount: 85.0, FromCurrency: "USD", ToCurrency: "EUR", MidRate: "0.85", Rate: "0.85", Rounding: "half_even", Provider: "ecb"}, *result)
+}
+
+func TestConvertExchangeRate_Rounding(t *testing.T) {
+	tests := []struct {
+		name           string
+		amount         float64
+		from           string
+		to             string
+		rounding       string
+		expectedAmount float64
+		expectedRate   string
+	}{
+		// 1.03 USD is exactly 5.665 BRL, a tie between 5.66 and 5.67.
+		{"Half even by default", 1.03, "USD", "BRL", "", 5.66, "5.5"},
+		{"Half even", 1.03, "USD", "BRL", "half_even", 5.66, "5.5"},
+		{"Half up", 1.03, "USD", "BRL", "half_up", 5.67, "5.5"},
+		{"Floor", 1.03, "USD", "BRL", "floor", 5.66, "5.5"},
+		{"Ceiling", 1.03, "USD", "BRL", "ceiling", 5.67, "5.5"},
+		{"Negative half up", -1.03, "USD", "BRL", "half_up", -5.67, "5.5"},
+		{"Negative floor", -1.03, "USD", "BRL", "floor", -5.67, "5.5"},
+		{"Negative ceiling", -1.03, "USD", "BRL", "ceiling", -5.66, "5.5"},
+		// 10 EUR is 64.7058... BRL, at a cross rate without finite decimal expansion.
+		{"Repeating cross rate", 10, "EUR", "BRL", "floor", 64.70, "110/17"},
+		{"Zero minor units", 1.5, "USD", "JPY", "", 226, "150.5"},
+		{"Three minor units", 1, "BRL", "KWD", "half_up", 0.056, "0.0558"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			memory := cache.NewMemory()
+			service := New(memory, new(MockRateProvider), stubSpreads{}, zap.NewNop(), testConfig)
+			memory.Set(cache.ExchangeRateKey, `{"base":"USD","provider":"ecb","rates":{"USD":1,"BRL":5.5,"EUR":0.85,"JPY":150.5,"KWD":0.3069}}`, 0)
+
+			// Action
+			result, err := service.ConvertExchangeRate(models.CurrencyConvert{Amount: tt.amount, FromCurrency: tt.from, ToCurrency: tt.to, Rounding: tt.rounding})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAmount, result.Amount)
+			assert.Equal(t, tt.expectedRate, result.Rate)
+		})
+	}
+}
+
+func TestConvertExchangeRate_NonPositiveRate(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	service := New(memory, new(MockRateProvider), stubSpreads{}, zap.NewNop(), testConfig)
+	memory.Set(cache.ExchangeRateKey, `{"rates":{"USD":0,"EUR":0.85}}`, 0)
+
+	// Action
+	result, err := service.ConvertExchangeRate(models.CurrencyConvert{Amount: 100, FromCurrency: "USD", ToCurrency: "EUR"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrMissingCurrency)
+	assert.Nil(t, result)
 }
 
 func TestConvertExchangeRate_MissingCurrencyKey(t *testing.T) {
 	// Arrange
-	mockCache := new(MockCacheClient)
-	service := New(mockCache)
+	memory := cache.NewMemory()
+	service := New(memory, new(MockRateProvider), stubSpreads{}, zap.NewNop(), testConfig)
 
-	mockCache.On("Get", cache.ExchangeRateKey).Return(`{"rates":{"USD":1.0}}`, nil)
+	memory.Set(cache.ExchangeRateKey, `{"rates":{"USD":1.0}}`, 0)
 
 	currency := models.CurrencyConvert{
 		Amount:       100,
@@ -191,24 +266,609 @@ func TestConvertExchangeRate_MissingCurrencyKey(t *testing.T) {
 	assert.Equal(t, "missing or unavailable currency keys: [EUR]", err.Error())
 }
 
-func TestGetRates_Failure(t *testing.T) {
+func TestGetRates_Historical(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	mockRates := new(MockRateProvider)
+	service := newAt(memory, mockRates, time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC))
+
+	rates := &models.CurrencyDataResponse{Base: "EUR", Date: "2024-09-27", Provider: "ecb", Rates: map[string]float64{"EUR": 1, "USD": 1.1158}}
+	mockRates.On("GetHistoricalRates", time.Date(2024, 9, 29, 0, 0, 0, 0, time.UTC)).Return(rates, nil)
+
+	// Action
+	result, err := service.GetRates("2024-09-29")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, rates, result)
+
+	cached, _ := memory.Get("exchange_rate_history_key_2024_09_29")
+	assert.JSONEq(t, utils.ToJSON(rates), string(cached))
+	mockRates.AssertExpectations(t)
+}
+
+func TestGetRates_HistoricalCacheHit(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	mockRates := new(MockRateProvider)
+	service := newAt(memory, mockRates, time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC))
+
+	memory.Set("exchange_rate_history_key_2024_09_27", `{"base":"EUR","date":"2024-09-27","provider":"ecb","rates":{"EUR":1,"USD":1.1158}}`, 0)
+
+	// Action
+	result, err := service.GetRates("2024-09-27")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-09-27", result.Date)
+	assert.Equal(t, 1.1158, result.Rates["USD"])
+	mockRates.AssertNotCalled(t, "GetHistoricalRates", mock.Anything)
+}
+
+func TestGetRates_Today(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	mockRates := new(MockRateProvider)
+	service := newAt(memory, mockRates, time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC))
+
+	memory.Set(cache.ExchangeRateKey, `{"base":"USD","rates":{"USD":1,"BRL":5.6}}`, 0)
+
+	// Action
+	result, err := service.GetRates("2024-10-18")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 5.6, result.Rates["BRL"])
+	mockRates.AssertNotCalled(t, "GetHistoricalRates", mock.Anything)
+}
+
+func TestGetRates_InvalidDate(t *testing.T) {
+	tests := []struct {
+		name string
+		date string
+	}{
+		{"Malformed date", "18/10/2024"},
+		{"Future date", "2024-10-19"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			memory := cache.NewMemory()
+			mockRates := new(MockRateProvider)
+			service := newAt(memory, mockRates, time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC))
+
+			// Action
+			result, err := service.GetRates(tt.date)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidDate)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestGetRates_HistoricalFailure(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	mockRates := new(MockRateProvider)
+	service := newAt(memory, mockRates, time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC))
+
+	mockRates.On("GetHistoricalRates", mock.Anything).Return(nil, errors.New("all exchange rate providers failed"))
+
+	// Action
+	result, err := service.GetRates("2024-09-29")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, result)
+
+	_, err = memory.Get("exchange_rate_history_key_2024_09_29")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+}
+
+func TestConvertExchangeRate_AtDate(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	service := newAt(memory, new(MockRateProvider), time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC))
+
+	memory.Set("exchange_rate_history_key_2024_09_29", `{"date":"2024-09-27","provider":"ecb","rates":{"EUR":1,"USD":1.25}}`, 0)
+
+	currency := models.CurrencyConvert{
+		Amount:       100,
+		FromCurrency: "USD",
+		ToCurrency:   "EUR",
+		Date:         "2024-09-29",
+	}
+
+	// Action
+	result, err := service.ConvertExchangeRate(currency)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.CurrencyConvertResponse{Amount: 80.0, FromCurrency: "USD", ToCurrency: "EUR", Date: "2024-09-27", MidRate: "0.8", Rate: "0.8", Rounding: "half_even", Provider: "ecb"}, *result)
+}
+
+func TestGetTimeSeries_Success(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	mockRates := new(MockRateProvider)
+	service := newAt(memory, mockRates, time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC))
+
+	memory.Set("exchange_rate_history_key_2024_09_27", `{"base":"EUR","rates":{"EUR":1,"USD":2,"BRL":10}}`, 0)
+	missing := &models.CurrencyDataResponse{Base: "EUR", Date: "2024-09-27", Provider: "ecb", Rates: map[string]float64{"EUR": 1, "USD": 2, "BRL": 12}}
+	mockRates.On("GetHistoricalRates", time.Date(2024, 9, 28, 0, 0, 0, 0, time.UTC)).Return(missing, nil)
+	memory.Set("exchange_rate_history_key_2024_09_29", `{"base":"EUR","rates":{"EUR":1,"USD":2,"BRL":11}}`, 0)
+
+	query := models.CurrencyTimeSeries{Base: "USD", Symbols: "BRL,EUR", From: "2024-09-27", To: "2024-09-29"}
+
+	// Action
+	result, err := service.GetTimeSeries(query)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]float64{
+		"2024-09-27": {"BRL": 5, "EUR": 0.5},
+		"2024-09-28": {"BRL": 6, "EUR": 0.5},
+		"2024-09-29": {"BRL": 5.5, "EUR": 0.5},
+	}, result.Rates)
+	assert.Equal(t, models.CurrencyTimeSeriesStatistics{Min: 5, Max: 6, Mean: 5.5, PercentChange: 10}, result.Statistics["BRL"])
+	assert.Equal(t, models.CurrencyTimeSeriesStatistics{Min: 0.5, Max: 0.5, Mean: 0.5, PercentChange: 0}, result.Statistics["EUR"])
+
+	cached, _ := memory.Get("exchange_rate_history_key_2024_09_28")
+	assert.JSONEq(t, utils.ToJSON(missing), string(cached))
+	mockRates.AssertExpectations(t)
+}
+
+func TestGetTimeSeries_NormalizesSymbols(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	service := newAt(memory, new(MockRateProvider), time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC))
+	memory.Set("exchange_rate_history_key_2024_09_27", `{"base":"EUR","rates":{"EUR":1,"USD":2,"BRL":10}}`, 0)
+
+	query := models.CurrencyTimeSeries{Base: "USD", Symbols: " brl, EUR ,BRL", From: "2024-09-27", To: "2024-09-27"}
+
+	// Action
+	result, err := service.GetTimeSeries(query)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]float64{"2024-09-27": {"BRL": 5, "EUR": 0.5}}, result.Rates)
+	assert.Len(t, result.Statistics, 2)
+}
+
+func TestGetTimeSeries_Failure(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    models.CurrencyTimeSeries
+		expected error
+	}{
+		{"Malformed date", models.CurrencyTimeSeries{Base: "USD", Symbols: "BRL", From: "27/09/2024", To: "2024-09-29"}, ErrInvalidDate},
+		{"Range ends before it starts", models.CurrencyTimeSeries{Base: "USD", Symbols: "BRL", From: "2024-09-29", To: "2024-09-27"}, ErrInvalidRange},
+		{"Range too long", models.CurrencyTimeSeries{Base: "USD", Symbols: "BRL", From: "2022-01-01", To: "2024-09-27"}, ErrInvalidRange},
+		{"Future day", models.CurrencyTimeSeries{Base: "USD", Symbols: "BRL", From: "2024-10-19", To: "2024-10-20"}, ErrInvalidDate},
+		{"Missing currency", models.CurrencyTimeSeries{Base: "USD", Symbols: "XYZ", From: "2024-09-27", To: "2024-09-27"}, ErrMissingCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			memory := cache.NewMemory()
+			service := newAt(memory, new(MockRateProvider), time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC))
+			memory.Set("exchange_rate_history_key_2024_09_27", `{"base":"EUR","rates":{"EUR":1,"USD":2}}`, 0)
+
+			// Action
+			result, err := service.GetTimeSeries(tt.query)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestConvertExchangeRateBatch(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	service := New(memory, new(MockRateProvider), stubSpreads{}, zap.NewNop(), testConfig)
+
+	memory.Set(cache.ExchangeRateKey, `{"provider":"ecb","rates":{"USD":1.0,"EUR":0.85,"BRL":5.5}}`, 0)
+
+	batch := models.CurrencyConvertBatch{Items: []models.CurrencyConvertItem{
+		{Amount: 100, FromCurrency: "USD", ToCurrency: "EUR"},
+		{Amount: 100, FromCurrency: "USD", ToCurrency: "XYZ"},
+		{Amount: 11, FromCurrency: "BRL", ToCurrency: "USD"},
+	}}
+
+	// Action
+	result, err := service.ConvertExchangeRateBatch(batch)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "ecb", result.Provider)
+	assert.Equal(t, "half_even", result.Rounding)
+	assert.Len(t, result.Results, 3)
+	assert.Equal(t, 85.0, *result.Results[0].Amount)
+	assert.Equal(t, "0.85", result.Results[0].Rate)
+	assert.Nil(t, result.Results[1].Amount)
+	assert.Equal(t, "missing or unavailable currency keys: [XYZ]", result.Results[1].Error)
+	assert.Equal(t, 2.0, *result.Results[2].Amount)
+	assert.Empty(t, result.Results[2].Error)
+}
+
+func TestConvertExchangeRateBatch_Failure(t *testing.T) {
 	// Arrange
-	server := mockServer("")
-	apiKey := "test_api_key"
+	memory := cache.NewMemory()
+	mockRates := new(MockRateProvider)
+	service := New(memory, mockRates, stubSpreads{}, zap.NewNop(), testConfig)
+
+	mockRates.On("GetRates").Return(nil, errors.New("all exchange rate providers failed"))
 
 	// Action
-	data, err := getRates(server.URL+"/%s", apiKey)
+	result, err := service.ConvertExchangeRateBatch(models.CurrencyConvertBatch{Items: []models.CurrencyConvertItem{{Amount: 1, FromCurrency: "USD", ToCurrency: "EUR"}}})
 
 	// Assert
 	assert.Error(t, err)
-	assert.Nil(t, data)
+	assert.Nil(t, result)
+}
+
+func TestConvertExchangeRate_Spread(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	service := New(memory, new(MockRateProvider), stubSpreads{resolved: &models.Spread{BasisPoints: 150}}, zap.NewNop(), testConfig)
+	memory.Set(cache.ExchangeRateKey, `{"provider":"ecb","rates":{"USD":1.0,"BRL":5.5}}`, 0)
+
+	// Action
+	result, err := service.ConvertExchangeRate(models.CurrencyConvert{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL", MerchantId: "merchant-1"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 541.75, result.Amount)
+	assert.Equal(t, "5.5", result.MidRate)
+	assert.Equal(t, "5.4175", result.Rate)
+	assert.Equal(t, 150, result.SpreadBasisPoints)
+	assert.Equal(t, 8.25, result.Markup)
+}
+
+func TestConvertExchangeRate_SpreadFailure(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	service := New(memory, new(MockRateProvider), stubSpreads{err: errors.New("connection refused")}, zap.NewNop(), testConfig)
+	memory.Set(cache.ExchangeRateKey, `{"provider":"ecb","rates":{"USD":1.0,"BRL":5.5}}`, 0)
+
+	// Action
+	result, err := service.ConvertExchangeRate(models.CurrencyConvert{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL"})
+
+	// Assert
+	assert.EqualError(t, err, "connection refused")
+	assert.Nil(t, result)
+}
+
+func TestConvertExchangeRateBatch_Spread(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	service := New(memory, new(MockRateProvider), stubSpreads{resolved: &models.Spread{BasisPoints: 150}}, zap.NewNop(), testConfig)
+	memory.Set(cache.ExchangeRateKey, `{"provider":"ecb","rates":{"USD":1.0,"BRL":5.5}}`, 0)
+
+	batch := models.CurrencyConvertBatch{MerchantId: "merchant-1", Items: []models.CurrencyConvertItem{
+		{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL"},
+		{Amount: 10, FromCurrency: "USD", ToCurrency: "BRL"},
+	}}
+
+	// Action
+	result, err := service.ConvertExchangeRateBatch(batch)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 541.75, *result.Results[0].Amount)
+	assert.Equal(t, 8.25, *result.Results[0].Markup)
+	assert.Equal(t, 54.18, *result.Results[1].Amount)
+	assert.Equal(t, 0.82, *result.Results[1].Markup)
+	for _, item := range result.Results {
+		assert.Equal(t, "5.5", item.MidRate)
+		assert.Equal(t, "5.4175", item.Rate)
+		assert.Equal(t, 150, item.SpreadBasisPoints)
+	}
+}
+
+// benchmarkRates returns a snapshot of rates with as many currencies as the providers list.
+// The benchmarks read it from the in-memory cache, so they include unmarshalling the snapshot.
+func benchmarkRates() (string, []string) {
+	rates := map[string]float64{"USD": 1}
+	currencies := []string{"USD"}
+	for i := 0; len(rates) < 170; i++ {
+		currency := fmt.Sprintf("%c%c%c", 'A'+i/676%26, 'A'+i/26%26, 'A'+i%26)
+		rates[currency] = float64(i+1) / 10
+		currencies = append(currencies, currency)
+	}
+	return utils.ToJSON(&models.CurrencyDataResponse{Base: "USD", Provider: "ecb", Rates: rates}), currencies
+}
+
+func benchmarkItems(currencies []string) []models.CurrencyConvertItem {
+	items := make([]models.CurrencyConvertItem, 10000)
+	for i := range items {
+		items[i] = models.CurrencyConvertItem{Amount: float64(i + 1), FromCurrency: currencies[i%len(currencies)], ToCurrency: currencies[(i*7)%len(currencies)]}
+	}
+	return items
+}
+
+func BenchmarkConvertExchangeRateBatch_10kItems(b *testing.B) {
+	rates, currencies := benchmarkRates()
+	memory := cache.NewMemory()
+	if err := memory.Set(cache.ExchangeRateKey, rates, time.Hour); err != nil {
+		b.Fatal(err)
+	}
+	service := New(memory, new(MockRateProvider), stubSpreads{}, zap.NewNop(), testConfig)
+	batch := models.CurrencyConvertBatch{Items: benchmarkItems(currencies)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := service.ConvertExchangeRateBatch(batch); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkConvertExchangeRate_10kItems(b *testing.B) {
+	rates, currencies := benchmarkRates()
+	memory := cache.NewMemory()
+	if err := memory.Set(cache.ExchangeRateKey, rates, time.Hour); err != nil {
+		b.Fatal(err)
+	}
+	service := New(memory, new(MockRateProvider), stubSpreads{}, zap.NewNop(), testConfig)
+	items := benchmarkItems(currencies)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, item := range items {
+			if _, err := service.ConvertExchangeRate(models.CurrencyConvert{Amount: item.Amount, FromCurrency: item.FromCurrency, ToCurrency: item.ToCurrency}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestGetAllCurrencyMetadata(t *testing.T) {
+	// Arrange
+	memory := cache.NewMemory()
+	service := New(memory, new(MockRateProvider), stubSpreads{}, zap.NewNop(), testConfig)
+
+	memory.Set(cache.ExchangeRateKey, `{"rates":{"USD":1.0,"BRL":5.6,"XAU":0.0004}}`, 0)
+
+	// Action
+	currencies, err := service.GetAllCurrencyMetadata()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, *currencies, 3)
+	assert.Equal(t, "Brazilian Real", (*currencies)[0].Name)
+	assert.Equal(t, "US Dollar", (*currencies)[1].Name)
+	assert.Equal(t, iso4217.Currency{Code: "XAU"}, (*currencies)[2])
+}
+
+func TestGetCurrency(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         string
+		expectedName string
+		expectedErr  error
+	}{
+		{"Upper case", "JPY", "Yen", nil},
+		{"Lower case", "brl", "Brazilian Real", nil},
+		{"Unknown code", "XYZ", "", ErrCurrencyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := New(cache.NewMemory(), new(MockRateProvider), stubSpreads{}, zap.NewNop(), testConfig)
+
+			// Action
+			currency, err := service.GetCurrency(tt.code)
+
+			// Assert
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, currency)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedName, currency.Name)
+		})
+	}
+}
+
+func TestConvertExchangeRate_Format(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		expected    string
+		expectedErr error
+	}{
+		{"Formatted for the locale", "pt_BR", "R$\u00a0550,00", nil},
+		{"Unsupported locale", "xx_XX", "", money.ErrUnsupportedLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			memory := cache.NewMemory()
+			service := New(memory, new(MockRateProvider), stubSpreads{}, zap.NewNop(), testConfig)
+			memory.Set(cache.ExchangeRateKey, `{"provider":"ecb","rates":{"USD":1.0,"BRL":5.5}}`, 0)
+
+			// Action
+			result, err := service.ConvertExchangeRate(models.CurrencyConvert{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL", Format: tt.format})
+
+			// Assert
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Formatted)
+		})
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	// Arrange
+	service := New(cache.NewMemory(), new(MockRateProvider), stubSpreads{}, zap.NewNop(), testConfig)
+
+	// Action
+	result, err := service.FormatAmount(models.CurrencyFormat{Amount: 1234.56, Currency: "USD", Locale: "en-US"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.CurrencyFormatResponse{Amount: 1234.56, Currency: "USD", Locale: "en-US", Formatted: "$1,234.56"}, *result)
+}
+
+// blockingRates counts the fetches of the latest rates, which wait until release is closed.
+type blockingRates struct {
+	MockRateProvider
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (b *blockingRates) GetRates() (*models.CurrencyDataResponse, error) {
+	b.calls.Add(1)
+	<-b.release
+	return &models.CurrencyDataResponse{Provider: "ecb", Rates: map[string]float64{"USD": 1, "BRL": 5.5}}, nil
+}
+
+func TestGetRates_LatestFreshness(t *testing.T) {
+	tests := []struct {
+		name          string
+		age           time.Duration
+		providerErr   error
+		expectedRate  float64
+		expectedStale bool
+		expectedErr   bool
+	}{
+		{"Fresh rates are served from the cache", 4 * time.Minute, nil, 5.5, false, false},
+		{"Expired rates are fetched again", 6 * time.Minute, nil, 5.6, false, false},
+		{"Expired rates are served as stale when the provider fails", 30 * time.Minute, errors.New("all exchange rate providers failed"), 5.5, true, false},
+		{"Rates older than the maximum staleness are not served", 2 * time.Hour, errors.New("all exchange rate providers failed"), 0, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+			memory := cache.NewMemory()
+			mockRates := new(MockRateProvider)
+			service := New(memory, mockRates, stubSpreads{}, zap.NewNop(), testConfig)
+			service.now = func() time.Time { return now }
+
+			asOf := now.Add(-tt.age).Format(time.RFC3339)
+			_ = memory.Set(cache.ExchangeRateKey, `{"provider":"ecb","as_of":"`+asOf+`","rates":{"USD":1,"BRL":5.5}}`, 3*time.Hour)
+			if tt.providerErr != nil {
+				mockRates.On("GetRates").Return(nil, tt.providerErr)
+			} else {
+				mockRates.On("GetRates").Return(&models.CurrencyDataResponse{Provider: "ecb", Rates: map[string]float64{"USD": 1, "BRL": 5.6}}, nil)
+			}
+
+			// Action
+			result, err := service.GetRates("")
+
+			// Assert
+			if tt.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRate, result.Rates["BRL"])
+			assert.Equal(t, tt.expectedStale, result.Stale)
+			assert.NotEmpty(t, result.AsOf)
+		})
+	}
+}
+
+func TestGetRates_ConcurrentMissesFetchOnce(t *testing.T) {
+	// Arrange
+	rates := &blockingRates{release: make(chan struct{})}
+	service := New(cache.NewMemory(), rates, stubSpreads{}, zap.NewNop(), testConfig)
+
+	var wg sync.WaitGroup
+	results := make([]*models.CurrencyDataResponse, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = service.GetRates("")
+		}(i)
+	}
+
+	// Action
+	assert.Eventually(t, func() bool { return rates.calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(rates.release)
+	wg.Wait()
+
+	// Assert
+	for _, result := range results {
+		assert.NotNil(t, result)
+	}
+	assert.Equal(t, int32(1), rates.calls.Load())
 }
 
-func mockServer(mockResponse string) *httptest.Server {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(mockResponse))
-	}))
+func TestConvertExchangeRate_StaleRates(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	memory := cache.NewMemory()
+	mockRates := new(MockRateProvider)
+	service := New(memory, mockRates, stubSpreads{}, zap.NewNop(), testConfig)
+	service.now = func() time.Time { return now }
+
+	_ = memory.Set(cache.ExchangeRateKey, `{"provider":"ecb","as_of":"2024-10-01T11:40:00Z","rates":{"USD":1,"BRL":5.5}}`, time.Hour)
+	mockRates.On("GetRates").Return(nil, errors.New("all exchange rate providers failed"))
+
+	// Action
+	result, err := service.ConvertExchangeRate(models.CurrencyConvert{Amount: 10, FromCurrency: "USD", ToCurrency: "BRL"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 55.0, result.Amount)
+	assert.Equal(t, "2024-10-01T11:40:00Z", result.AsOf)
+	assert.True(t, result.Stale)
+}
 
-	return server
+func TestRun_RefreshesDueRates(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	memory := cache.NewMemory()
+	mockRates := new(MockRateProvider)
+	service := New(memory, mockRates, stubSpreads{}, zap.NewNop(), testConfig)
+	service.now = func() time.Time { return now }
+
+	_ = memory.Set(cache.ExchangeRateKey, `{"provider":"ecb","as_of":"2024-10-01T11:57:00Z","rates":{"USD":1,"BRL":5.5}}`, time.Hour)
+	mockRates.On("GetRates").Return(&models.CurrencyDataResponse{Provider: "ecb", Rates: map[string]float64{"USD": 1, "BRL": 5.6}}, nil).Once()
+
+	// Action
+	skipErr := service.refreshDue()
+	now = now.Add(time.Minute)
+	refreshErr := service.refreshDue()
+
+	// Assert
+	assert.NoError(t, skipErr)
+	assert.NoError(t, refreshErr)
+	mockRates.AssertNumberOfCalls(t, "GetRates", 1)
+
+	result, err := service.GetRates("")
+	assert.NoError(t, err)
+	assert.Equal(t, 5.6, result.Rates["BRL"])
+	assert.Equal(t, "2024-10-01T12:01:00Z", result.AsOf)
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Arrange
+	t.Setenv("EXCHANGE_RATE_TTL", "1m")
+	t.Setenv("EXCHANGE_RATE_REFRESH_INTERVAL", "invalid")
+
+	// Action
+	config := LoadConfig()
+
+	// Assert
+	assert.Equal(t, Config{TTL: time.Minute, RefreshInterval: 4 * time.Minute, MaxStaleness: time.Hour}, config)
 }
@@ -0,0 +1,37 @@
+package paypal
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	ClientId     string
+	ClientSecret string
+	BaseURL      string
+	Timeout      time.Duration
+	HTTPClient   *http.Client
+}
+
+// LoadConfig loads the PayPal API configuration from the environment variables.
+//
+// Environment Variables:
+//   - PAYPAL_CLIENT_ID: the client ID of the PayPal REST app the payments are created with.
+//   - PAYPAL_CLIENT_SECRET: the secret of the PayPal REST app.
+//   - PAYPAL_API_BASE_URL: the base URL of the PayPal API, e.g. "https://api-m.paypal.com" in production or
+//     "https://api-m.sandbox.paypal.com" in the sandbox. PayPal payments fail while it is not set.
+//   - PAYPAL_API_TIMEOUT: the timeout of each request to the PayPal API, e.g. "30s".
+//
+// Returns:
+//   - Config: the PayPal API configuration.
+func LoadConfig() Config {
+	return Config{
+		ClientId:     os.Getenv("PAYPAL_CLIENT_ID"),
+		ClientSecret: os.Getenv("PAYPAL_CLIENT_SECRET"),
+		BaseURL:      os.Getenv("PAYPAL_API_BASE_URL"),
+		Timeout:      utils.GetEnvDuration("PAYPAL_API_TIMEOUT", 30*time.Second),
+	}
+}
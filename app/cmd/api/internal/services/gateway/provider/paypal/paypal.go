@@ -1,19 +1,359 @@
 package paypal
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/iso4217"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
 )
 
-type PayPalGateway struct{}
+// tokenExpiryMargin is how long before its expiry an access token is renewed.
+const tokenExpiryMargin = time.Minute
 
-// ProcessPayment processes a payment using the PayPal gateway.
-// It takes a payment of type models.Gateway and a correlationId string as parameters.
-// It returns a pointer to a string and an error.
-// If the payment cannot be processed, it returns an error indicating the failure reason.
+var supportedMethods = map[string]bool{
+	"card": true,
+}
+
+var (
+	ErrNoBaseURL          = errors.New("paypal api base url is not configured")
+	ErrPaymentNotCaptured = errors.New("paypal payment not captured")
+)
+
+// Error is an error answered by the PayPal API.
+type Error struct {
+	StatusCode int    `json:"-"`
+	Name       string `json:"name"`
+	Message    string `json:"message"`
+	DebugId    string `json:"debug_id"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("paypal api error %d: %s: %s (debug id %s)", e.StatusCode, e.Name, e.Message, e.DebugId)
+}
+
+type accessToken struct {
+	Value     string `json:"access_token"`
+	ExpiresIn int64  `json:"expires_in"`
+}
+
+type amount struct {
+	CurrencyCode string `json:"currency_code"`
+	Value        string `json:"value"`
+}
+
+type purchaseUnit struct {
+	CustomId string `json:"custom_id,omitempty"`
+	Amount   amount `json:"amount"`
+}
+
+type card struct {
+	Number       string `json:"number,omitempty"`
+	Expiry       string `json:"expiry,omitempty"`
+	SecurityCode string `json:"security_code,omitempty"`
+	VaultId      string `json:"vault_id,omitempty"`
+}
+
+type paymentSource struct {
+	Card card `json:"card"`
+}
+
+type vaultToken struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type vaultSource struct {
+	Card  *card       `json:"card,omitempty"`
+	Token *vaultToken `json:"token,omitempty"`
+}
+
+type vaultRequest struct {
+	PaymentSource vaultSource `json:"payment_source"`
+}
+
+type orderRequest struct {
+	Intent        string         `json:"intent"`
+	PurchaseUnits []purchaseUnit `json:"purchase_units"`
+	PaymentSource paymentSource  `json:"payment_source"`
+}
+
+type capture struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+}
+
+type orderPayments struct {
+	Captures []capture `json:"captures"`
+}
+
+type orderUnit struct {
+	Payments orderPayments `json:"payments"`
+}
+
+type order struct {
+	Id            string      `json:"id"`
+	Status        string      `json:"status"`
+	PurchaseUnits []orderUnit `json:"purchase_units"`
+}
+
+type PayPalGateway struct {
+	config      Config
+	httpClient  *http.Client
+	mutex       sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	now         func() time.Time
+}
+
+// New creates a new instance of PayPalGateway with the provided configuration.
+// Each gateway authenticates with its own credentials, so gateways of different PayPal apps can be used concurrently.
+//
+// Parameters:
+//   - config: the PayPal API configuration, with the credentials, base URL, timeout and HTTP client of the requests.
+//     A client with the configured timeout is created when no HTTP client is given.
+//
+// Returns:
+//   - *PayPalGateway: a pointer to the newly created PayPalGateway.
+func New(config Config) *PayPalGateway {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: config.Timeout}
+	}
+
+	return &PayPalGateway{
+		config:     config,
+		httpClient: httpClient,
+		now:        time.Now,
+	}
+}
+
+// ProcessPayment processes a card payment using the PayPal gateway.
+// It creates an order captured at once with the card of the payment, or the card vaulted by Tokenize when the
+// payment carries a payment token, and returns the ID of the order.
+// The transaction ID, when the transaction was created before the payment, is sent as the custom ID of the order,
+// which identifies the transaction in the PayPal webhooks, and as request ID, so a payment retried by the workers
+// is never charged twice. The amount is sent with the minor units of its currency. Orders that are not completed,
+// such as orders requiring an action of the payer, and declined captures are answered with ErrPaymentNotCaptured.
+//
+// Parameters:
+//   - payment: models.Gateway containing payment details such as card information and amount.
+//   - correlationId: string representing a unique identifier for the request.
+//
+// Returns:
+//   - *string: Pointer to the order ID if the payment is successful.
+//   - error: an *Error if the payment is refused by PayPal, ErrPaymentNotCaptured if the order is not captured,
+//     or an error if PayPal cannot be reached.
 func (pg *PayPalGateway) ProcessPayment(payment models.Gateway, correlationId string) (*string, error) {
-	fmt.Printf("Processing PayPal payment of $%.2f\n", payment.Amount)
+	if !supportedMethods[payment.PaymentMethod] {
+		return nil, fmt.Errorf("unsupported payment method: %s. Supported methods are: [card]", payment.PaymentMethod)
+	}
+
+	source := card{VaultId: payment.PaymentToken}
+	if utils.IsEmptyOrNull(source.VaultId) {
+		card, err := cardSource(payment.CardDetails)
+		if err != nil {
+			return nil, err
+		}
+		source = card
+	}
+
+	currencyCode := strings.ToUpper(payment.Currency)
+	value, err := formatAmount(payment.Amount, currencyCode)
+	if err != nil {
+		return nil, err
+	}
+
+	request := orderRequest{
+		Intent: "CAPTURE",
+		PurchaseUnits: []purchaseUnit{{
+			CustomId: payment.TransactionId,
+			Amount: amount{
+				CurrencyCode: currencyCode,
+				Value:        value,
+			},
+		}},
+		PaymentSource: paymentSource{Card: source},
+	}
+
+	var result order
+	if err := pg.post("/v2/checkout/orders", requestId(payment, correlationId), request, &result); err != nil {
+		return nil, fmt.Errorf("error creating order: %w", err)
+	}
+
+	if result.Status != "COMPLETED" {
+		return nil, fmt.Errorf("%w: order %s is %s", ErrPaymentNotCaptured, result.Id, result.Status)
+	}
+
+	for _, unit := range result.PurchaseUnits {
+		for _, capture := range unit.Payments.Captures {
+			if capture.Status == "DECLINED" || capture.Status == "FAILED" {
+				return nil, fmt.Errorf("%w: capture %s of order %s is %s", ErrPaymentNotCaptured, capture.Id, result.Id, capture.Status)
+			}
+		}
+	}
+
+	return &result.Id, nil
+}
+
+// Tokenize saves the card of a payment in the PayPal vault, so the payment can be processed later without keeping
+// the card. The card is saved in a setup token, which is then turned into a payment token.
+//
+// Parameters:
+//   - payment: models.Gateway containing the card details.
+//
+// Returns:
+//   - string: the ID of the payment token.
+//   - error: an *Error if the card is refused by PayPal, or an error if PayPal cannot be reached.
+func (pg *PayPalGateway) Tokenize(payment models.Gateway) (string, error) {
+	if !supportedMethods[payment.PaymentMethod] {
+		return "", fmt.Errorf("unsupported payment method: %s. Supported methods are: [card]", payment.PaymentMethod)
+	}
+
+	source, err := cardSource(payment.CardDetails)
+	if err != nil {
+		return "", err
+	}
+
+	var setupToken vaultToken
+	if err := pg.post("/v3/vault/setup-tokens", utils.GenerateGUID(), vaultRequest{PaymentSource: vaultSource{Card: &source}}, &setupToken); err != nil {
+		return "", fmt.Errorf("error creating setup token: %w", err)
+	}
+
+	var paymentToken vaultToken
+	request := vaultRequest{PaymentSource: vaultSource{Token: &vaultToken{Id: setupToken.Id, Type: "SETUP_TOKEN"}}}
+	if err := pg.post("/v3/vault/payment-tokens", utils.GenerateGUID(), request, &paymentToken); err != nil {
+		return "", fmt.Errorf("error creating payment token: %w", err)
+	}
+
+	return paymentToken.Id, nil
+}
+
+// post sends an authenticated JSON request to the PayPal API with the given idempotency key and decodes its response.
+func (pg *PayPalGateway) post(path string, requestId string, request interface{}, result interface{}) error {
+	if utils.IsEmptyOrNull(pg.config.BaseURL) {
+		return ErrNoBaseURL
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	token, err := pg.accessToken()
+	if err != nil {
+		return fmt.Errorf("error authenticating at paypal: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, pg.config.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("PayPal-Request-Id", requestId)
+
+	return pg.do(req, result)
+}
+
+// accessToken returns the OAuth access token of the configured credentials, requesting a new one when it is about to expire.
+func (pg *PayPalGateway) accessToken() (string, error) {
+	pg.mutex.Lock()
+	defer pg.mutex.Unlock()
+
+	if pg.token != "" && pg.now().Before(pg.tokenExpiry) {
+		return pg.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequest(http.MethodPost, pg.config.BaseURL+"/v1/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(pg.config.ClientId, pg.config.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var result accessToken
+	if err := pg.do(req, &result); err != nil {
+		return "", err
+	}
+
+	pg.token = result.Value
+	pg.tokenExpiry = pg.now().Add(time.Duration(result.ExpiresIn)*time.Second - tokenExpiryMargin)
+	return pg.token, nil
+}
+
+// do sends a request to the PayPal API and decodes its response, turning error responses into an *Error.
+func (pg *PayPalGateway) do(req *http.Request, result interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	res, err := pg.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		apiErr := &Error{StatusCode: res.StatusCode}
+		json.Unmarshal(body, apiErr)
+		return apiErr
+	}
+
+	return json.Unmarshal(body, result)
+}
+
+// formatAmount formats an amount with the minor units of its currency, as expected by PayPal, such as "100.50" for
+// USD and "100" for JPY.
+func formatAmount(value float64, currencyCode string) (string, error) {
+	currency, ok := iso4217.Get(currencyCode)
+	if !ok {
+		return "", fmt.Errorf("unsupported currency: %s", currencyCode)
+	}
+	return strconv.FormatFloat(value, 'f', currency.MinorUnits, 64), nil
+}
+
+// cardSource returns the card of a payment in the format expected by PayPal.
+func cardSource(details models.CardDetails) (card, error) {
+	expiry, err := cardExpiry(details.Expiry)
+	if err != nil {
+		return card{}, err
+	}
+
+	return card{
+		Number:       details.Number,
+		Expiry:       expiry,
+		SecurityCode: details.Cvv,
+	}, nil
+}
+
+// cardExpiry converts a card expiry in the MM/YY format to the YYYY-MM format expected by PayPal.
+func cardExpiry(expiry string) (string, error) {
+	value, err := time.Parse("01/06", expiry)
+	if err != nil {
+		return "", fmt.Errorf("invalid card expiry: %s", expiry)
+	}
+	return value.Format("2006-01"), nil
+}
 
-	return nil, fmt.Errorf("unable to process payment using gateway: %s", payment.Gateway)
+// requestId returns the idempotency key of a payment: its transaction ID, or the correlation ID of the request.
+func requestId(payment models.Gateway, correlationId string) string {
+	if !utils.IsEmptyOrNull(payment.TransactionId) {
+		return payment.TransactionId
+	}
+	return correlationId
 }
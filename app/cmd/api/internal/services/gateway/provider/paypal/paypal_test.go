@@ -1,49 +1,240 @@
 package paypal
 
 import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/stretchr/testify/assert"
 )
 
-func TestProcessPayment(t *testing.T) {
+// newTestGateway creates a PayPalGateway calling a local server, which grants access tokens, vaults cards and answers the order requests.
+func newTestGateway(t *testing.T, tokenRequests *int32, orders http.HandlerFunc) *PayPalGateway {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/oauth2/token":
+			atomic.AddInt32(tokenRequests, 1)
+			clientId, clientSecret, _ := r.BasicAuth()
+			assert.Equal(t, "client1", clientId)
+			assert.Equal(t, "secret1", clientSecret)
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			w.Write([]byte(`{"access_token": "token1", "token_type": "Bearer", "expires_in": 32400}`))
+		case "/v3/vault/setup-tokens":
+			var request vaultRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			assert.Equal(t, "2030-12", request.PaymentSource.Card.Expiry)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "setup1", "status": "APPROVED"}`))
+		case "/v3/vault/payment-tokens":
+			var request vaultRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			assert.Equal(t, &vaultToken{Id: "setup1", Type: "SETUP_TOKEN"}, request.PaymentSource.Token)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "vault1"}`))
+		case "/v2/checkout/orders":
+			assert.Equal(t, "Bearer token1", r.Header.Get("Authorization"))
+			orders(w, r)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return New(Config{ClientId: "client1", ClientSecret: "secret1", BaseURL: server.URL, Timeout: 5 * time.Second})
+}
+
+func testPayment() models.Gateway {
+	return models.Gateway{
+		Gateway:       "PayPal",
+		Amount:        100.5,
+		Currency:      "usd",
+		PaymentMethod: "card",
+		CardDetails: models.CardDetails{
+			Number: "4111111111111111",
+			Expiry: "12/30",
+			Cvv:    "123",
+		},
+		TransactionId: "transaction1",
+	}
+}
+
+func TestProcessPayment_Successful(t *testing.T) {
+	// Arrange
+	var tokenRequests int32
+	pg := newTestGateway(t, &tokenRequests, func(w http.ResponseWriter, r *http.Request) {
+		var request orderRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "CAPTURE", request.Intent)
+		assert.Equal(t, "transaction1", request.PurchaseUnits[0].CustomId)
+		assert.Equal(t, amount{CurrencyCode: "USD", Value: "100.50"}, request.PurchaseUnits[0].Amount)
+		assert.Equal(t, "2030-12", request.PaymentSource.Card.Expiry)
+		assert.Equal(t, "transaction1", r.Header.Get("PayPal-Request-Id"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "order1", "status": "COMPLETED"}`))
+	})
+
+	// Action
+	first, err := pg.ProcessPayment(testPayment(), "correlation1")
+	second, errAgain := pg.ProcessPayment(testPayment(), "correlation2")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, errAgain)
+	assert.Equal(t, "order1", *first)
+	assert.Equal(t, "order1", *second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenRequests))
+}
+
+func TestProcessPayment_WithPaymentToken(t *testing.T) {
+	// Arrange
+	var tokenRequests int32
+	pg := newTestGateway(t, &tokenRequests, func(w http.ResponseWriter, r *http.Request) {
+		var request orderRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, card{VaultId: "vault1"}, request.PaymentSource.Card)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "order1", "status": "COMPLETED"}`))
+	})
+	payment := models.Gateway{Gateway: "PayPal", Amount: 100.5, Currency: "USD", PaymentMethod: "card", PaymentToken: "vault1", TransactionId: "transaction1"}
+
+	// Action
+	orderId, err := pg.ProcessPayment(payment, "correlation1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "order1", *orderId)
+}
+
+func TestTokenize_Successful(t *testing.T) {
+	// Arrange
+	var tokenRequests int32
+	pg := newTestGateway(t, &tokenRequests, nil)
+
+	// Action
+	token, err := pg.Tokenize(testPayment())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "vault1", token)
+}
+
+func TestProcessPayment_ProviderError(t *testing.T) {
+	// Arrange
+	var tokenRequests int32
+	pg := newTestGateway(t, &tokenRequests, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"name": "UNPROCESSABLE_ENTITY", "message": "The requested action could not be performed.", "debug_id": "debug1"}`))
+	})
+
+	// Action
+	orderId, err := pg.ProcessPayment(testPayment(), "correlation1")
+
+	// Assert
+	assert.Nil(t, orderId)
+	var apiErr *Error
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	assert.Equal(t, "UNPROCESSABLE_ENTITY", apiErr.Name)
+	assert.Equal(t, "debug1", apiErr.DebugId)
+}
+
+func TestProcessPayment_InvalidPayment(t *testing.T) {
 	tests := []struct {
-		name          string
-		payment       models.Gateway
-		correlationId string
-		wantErr       bool
+		name    string
+		payment models.Gateway
+		wantErr string
 	}{
 		{
-			name: "valid payment",
-			payment: models.Gateway{
-				Amount:  100.00,
-				Gateway: "PayPal",
-			},
-			correlationId: "12345",
-			wantErr:       true,
+			name:    "unsupported payment method",
+			payment: models.Gateway{PaymentMethod: "pix"},
+			wantErr: "unsupported payment method: pix. Supported methods are: [card]",
 		},
 		{
-			name: "invalid payment gateway",
-			payment: models.Gateway{
-				Amount:  50.00,
-				Gateway: "InvalidGateway",
-			},
-			correlationId: "67890",
-			wantErr:       true,
+			name:    "invalid card expiry",
+			payment: models.Gateway{PaymentMethod: "card", CardDetails: models.CardDetails{Expiry: "2030-12"}},
+			wantErr: "invalid card expiry: 2030-12",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pg := &PayPalGateway{}
-			got, err := pg.ProcessPayment(tt.payment, tt.correlationId)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("ProcessPayment() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if got != nil {
-				t.Errorf("ProcessPayment() got = %v, want nil", *got)
-			}
+			pg := New(Config{BaseURL: "http://localhost:0"})
+			got, err := pg.ProcessPayment(tt.payment, "correlation1")
+			assert.Nil(t, got)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestProcessPayment_NotCaptured(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+	}{
+		{"payer action required", `{"id": "order1", "status": "PAYER_ACTION_REQUIRED"}`},
+		{"capture declined", `{"id": "order1", "status": "COMPLETED", "purchase_units": [{"payments": {"captures": [{"id": "capture1", "status": "DECLINED"}]}}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var tokenRequests int32
+			pg := newTestGateway(t, &tokenRequests, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(tt.response))
+			})
+
+			// Action
+			orderId, err := pg.ProcessPayment(testPayment(), "correlation1")
+
+			// Assert
+			assert.Nil(t, orderId)
+			assert.ErrorIs(t, err, ErrPaymentNotCaptured)
+		})
+	}
+}
+
+func TestProcessPayment_WithoutBaseURL(t *testing.T) {
+	// Arrange
+	pg := New(Config{ClientId: "client1", ClientSecret: "secret1"})
+
+	// Action
+	orderId, err := pg.ProcessPayment(testPayment(), "correlation1")
+
+	// Assert
+	assert.Nil(t, orderId)
+	assert.ErrorIs(t, err, ErrNoBaseURL)
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		value    float64
+		currency string
+		expected string
+		wantErr  bool
+	}{
+		{100.5, "USD", "100.50", false},
+		{1000, "JPY", "1000", false},
+		{1.5, "BHD", "1.500", false},
+		{10, "XYZ", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			// Action
+			value, err := formatAmount(tt.value, tt.currency)
+
+			// Assert
+			assert.Equal(t, tt.expected, value)
+			assert.Equal(t, tt.wantErr, err != nil)
 		})
 	}
 }
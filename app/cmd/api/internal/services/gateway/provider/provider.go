@@ -2,34 +2,84 @@ package provider
 
 import (
 	"errors"
+	"net"
+	"sort"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider/paypal"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider/stripe"
+	stripeSdk "github.com/stripe/stripe-go"
 )
 
 type PaymentGateway interface {
 	ProcessPayment(payment models.Gateway, correlationId string) (*string, error)
+	Tokenize(payment models.Gateway) (string, error)
 }
 
-var Providers = map[ProviderType]PaymentGateway{
-	PayPalGateway: &paypal.PayPalGateway{},
-	StripeGateway: &stripe.StripeGateway{},
+// Registry holds the payment gateways by their type.
+type Registry map[ProviderType]PaymentGateway
+
+// NewRegistry creates the registry of the supported payment gateways with the provided configurations.
+//
+// Parameters:
+//   - stripeConfig: the Stripe API configuration.
+//   - paypalConfig: the PayPal API configuration.
+//
+// Returns:
+//   - Registry: the registry of the payment gateways.
+func NewRegistry(stripeConfig stripe.Config, paypalConfig paypal.Config) Registry {
+	return Registry{
+		PayPalGateway: paypal.New(paypalConfig),
+		StripeGateway: stripe.New(stripeConfig),
+	}
 }
 
-// NewProvider creates a new instance of a PaymentGateway based on the provided ProviderType.
-// It returns the corresponding PaymentGateway if the type exists in the Providers map,
-// otherwise, it returns an error indicating that the payment gateway type is unsupported.
+// Get returns the PaymentGateway of the provided ProviderType.
+// It returns an error indicating that the payment gateway type is unsupported when it is not registered.
 //
 // Parameters:
-//   - gwType: The type of the payment gateway to be created.
+//   - gwType: The type of the payment gateway.
 //
 // Returns:
-//   - PaymentGateway: The created payment gateway instance.
+//   - PaymentGateway: The payment gateway.
 //   - error: An error if the payment gateway type is unsupported.
-func NewProvider(gwType ProviderType) (PaymentGateway, error) {
-	if gateway, exists := Providers[gwType]; exists {
+func (r Registry) Get(gwType ProviderType) (PaymentGateway, error) {
+	if gateway, exists := r[gwType]; exists {
 		return gateway, nil
 	}
 	return nil, errors.New("unsupported payment gateway type")
 }
+
+// Types returns the sorted types of the registered payment gateways.
+func (r Registry) Types() []string {
+	types := make([]string, 0, len(r))
+	for gwType := range r {
+		types = append(types, string(gwType))
+	}
+	sort.Strings(types)
+	return types
+}
+
+// IsTransient reports whether a payment processing error is temporary, so the payment can be retried safely:
+// network failures, provider rate limits and provider server errors.
+//
+// Parameters:
+//   - err: The error returned by a PaymentGateway.
+//
+// Returns:
+//   - bool: true if the payment can be retried, otherwise false.
+func IsTransient(err error) bool {
+	var stripeErr *stripeSdk.Error
+	if errors.As(err, &stripeErr) {
+		return stripeErr.Type == stripeSdk.ErrorTypeAPIConnection || stripeErr.Type == stripeSdk.ErrorTypeRateLimit ||
+			stripeErr.HTTPStatusCode == 429 || stripeErr.HTTPStatusCode >= 500
+	}
+
+	var paypalErr *paypal.Error
+	if errors.As(err, &paypalErr) {
+		return paypalErr.StatusCode == 429 || paypalErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
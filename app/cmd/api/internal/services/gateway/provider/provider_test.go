@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider/paypal"
+	stripeSdk "github.com/stripe/stripe-go"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"Network error", fmt.Errorf("error creating payment intent: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), true},
+		{"Stripe rate limit", fmt.Errorf("error creating payment intent: %w", &stripeSdk.Error{Type: stripeSdk.ErrorTypeRateLimit, HTTPStatusCode: 429}), true},
+		{"Stripe server error", &stripeSdk.Error{Type: stripeSdk.ErrorTypeAPI, HTTPStatusCode: 500}, true},
+		{"Stripe card declined", &stripeSdk.Error{Type: stripeSdk.ErrorTypeCard, HTTPStatusCode: 402}, false},
+		{"PayPal rate limit", fmt.Errorf("error creating order: %w", &paypal.Error{StatusCode: 429, Name: "RATE_LIMIT_REACHED"}), true},
+		{"PayPal server error", &paypal.Error{StatusCode: 503, Name: "SERVICE_UNAVAILABLE"}, true},
+		{"PayPal unprocessable order", &paypal.Error{StatusCode: 422, Name: "UNPROCESSABLE_ENTITY"}, false},
+		{"Other error", errors.New("unsupported payment method"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
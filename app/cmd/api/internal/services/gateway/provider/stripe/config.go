@@ -0,0 +1,37 @@
+package stripe
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/stripe/stripe-go"
+)
+
+type Config struct {
+	SecretKey         string
+	BaseURL           string
+	Timeout           time.Duration
+	MaxNetworkRetries int
+	HTTPClient        *http.Client
+}
+
+// LoadConfig loads the Stripe API configuration from the environment variables.
+//
+// Environment Variables:
+//   - STRIPE_SECRET_KEY: the secret key the payments are created with.
+//   - STRIPE_API_BASE_URL: the base URL of the Stripe API, e.g. "http://localhost:12111" for stripe-mock.
+//   - STRIPE_API_TIMEOUT: the timeout of each request to the Stripe API, e.g. "30s".
+//   - STRIPE_API_MAX_RETRIES: how many times the requests failing with network errors are retried by the Stripe client.
+//
+// Returns:
+//   - Config: the Stripe API configuration.
+func LoadConfig() Config {
+	return Config{
+		SecretKey:         os.Getenv("STRIPE_SECRET_KEY"),
+		BaseURL:           utils.GetEnvString("STRIPE_API_BASE_URL", stripe.APIURL),
+		Timeout:           utils.GetEnvDuration("STRIPE_API_TIMEOUT", 30*time.Second),
+		MaxNetworkRetries: utils.GetEnvInt("STRIPE_API_MAX_RETRIES", 0),
+	}
+}
@@ -2,28 +2,62 @@ package stripe
 
 import (
 	"fmt"
-	"os"
+	"math/big"
+	"net/http"
 	"strings"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/iso4217"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/money"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
 	"github.com/stripe/stripe-go"
 	"github.com/stripe/stripe-go/paymentintent"
-	"github.com/stripe/stripe-go/token"
+	"github.com/stripe/stripe-go/paymentmethod"
 )
 
 var supportedMethods = map[string]bool{
 	"card": true,
 }
 
-type StripeGateway struct{}
+type StripeGateway struct {
+	paymentIntents paymentintent.Client
+	paymentMethods paymentmethod.Client
+}
+
+// New creates a new instance of StripeGateway with the provided configuration.
+// Each gateway has its own Stripe client, so gateways with different keys or base URLs can be used concurrently.
+//
+// Parameters:
+//   - config: the Stripe API configuration, with the secret key, base URL, timeout and HTTP client of the requests.
+//     A client with the configured timeout is created when no HTTP client is given.
+//
+// Returns:
+//   - *StripeGateway: a pointer to the newly created StripeGateway.
+func New(config Config) *StripeGateway {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: config.Timeout}
+	}
+
+	backend := stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+		URL:               config.BaseURL,
+		HTTPClient:        httpClient,
+		MaxNetworkRetries: config.MaxNetworkRetries,
+	})
+
+	return &StripeGateway{
+		paymentIntents: paymentintent.Client{B: backend, Key: config.SecretKey},
+		paymentMethods: paymentmethod.Client{B: backend, Key: config.SecretKey},
+	}
+}
 
 // ProcessPayment processes a payment using the Stripe gateway.
 // It takes a payment model and a correlation ID as input parameters.
 // The function returns the payment intent ID and an error, if any.
 //
 // Parameters:
-// - payment: models.Gateway containing payment details such as card information and amount.
+// - payment: models.Gateway containing payment details such as card information and amount, or the payment token
+// created by Tokenize.
 // - correlationId: string representing a unique identifier for the transaction.
 //
 // Returns:
@@ -31,101 +65,128 @@ type StripeGateway struct{}
 // - error: Error if there is any issue during the payment processing.
 //
 // The function performs the following steps:
-// 1. Retrieves the Stripe secret key from the environment variables.
-// 2. Parses the card expiry date and validates its format.
-// 3. Creates a Stripe token using the card details.
-// 4. Creates a Stripe payment intent with the specified amount, currency, and payment method.
-// 5. Adds metadata to the payment intent.
-// 6. Returns the payment intent ID or an error if the payment intent creation fails.
+// 1. Creates a Stripe PaymentMethod with the card details, unless the payment carries a payment token.
+// 2. Creates a Stripe payment intent with the specified amount in the minor units of its currency, the currency, and
+// the payment method.
+// 3. Adds metadata to the payment intent, including the transaction ID when the transaction was created before the payment,
+// which is also used as idempotency key so a payment retried by the workers is never charged twice.
+// 4. Returns the payment intent ID or an error if the payment intent creation fails.
 func (sg *StripeGateway) ProcessPayment(payment models.Gateway, correlationId string) (*string, error) {
 
-	stripe.Key = os.Getenv("STRIPE_SECRET_KEY")
-
 	if !supportedMethods[payment.PaymentMethod] {
 		return nil, fmt.Errorf("unsupported payment method: %s. Supported methods are: %v", payment.PaymentMethod, keys(supportedMethods))
 	}
 
-	token, err := sg.createToken(payment)
-	paymentMethodTest := getPaymentMethodTest(err, payment)
+	paymentMethod := payment.PaymentToken
+	if utils.IsEmptyOrNull(paymentMethod) {
+		token, err := sg.Tokenize(payment)
+		if err != nil {
+			return nil, err
+		}
+		paymentMethod = token
+	}
+
+	amount, err := minorAmount(payment.Amount, payment.Currency)
+	if err != nil {
+		return nil, err
+	}
 
 	param := &stripe.PaymentIntentParams{
-		Amount:             stripe.Int64(int64(payment.Amount * 100)),
+		Amount:             stripe.Int64(amount),
 		Currency:           stripe.String(string(stripe.Currency(payment.Currency))),
 		PaymentMethodTypes: stripe.StringSlice([]string{payment.PaymentMethod}),
+		PaymentMethod:      stripe.String(paymentMethod),
 		Confirm:            stripe.Bool(true),
 	}
 
-	if utils.IsEmptyOrNull(paymentMethodTest) {
-		param.Source = &token.ID
-	} else {
-		param.PaymentMethod = stripe.String(paymentMethodTest)
-	}
-
 	param.AddMetadata("correlation_id", correlationId)
+	if !utils.IsEmptyOrNull(payment.TransactionId) {
+		param.AddMetadata("transaction_id", payment.TransactionId)
+		param.SetIdempotencyKey(payment.TransactionId)
+	}
 
-	pi, err := paymentintent.New(param)
+	pi, err := sg.paymentIntents.New(param)
 	if err != nil {
-		return nil, fmt.Errorf("error creating payment intent: %v", err)
+		return nil, fmt.Errorf("error creating payment intent: %w", err)
 	}
 
 	return &pi.ID, nil
 }
 
-// createToken generates a Stripe token for the provided payment details.
-// It extracts the card expiry month and year from the payment's CardDetails,
-// and uses them along with the card number and CVC to create a stripe.TokenParams object.
-// The function then calls the Stripe API to create and return a new token.
+// Tokenize creates a Stripe PaymentMethod with the card details of a payment, so the payment can be processed
+// later without keeping the card. When Stripe refuses the card details, as it does for raw card numbers in test
+// mode, the test payment method matching a test card number is used.
 //
 // Parameters:
 //   - payment: A models.Gateway object containing the card details.
 //
 // Returns:
-//   - *stripe.Token: A pointer to the created Stripe token.
-//   - error: An error object if the token creation fails.
-func (sg *StripeGateway) createToken(payment models.Gateway) (*stripe.Token, error) {
+//   - string: The ID of the PaymentMethod.
+//   - error: An error if the payment method is unsupported, the card expiry is invalid or Stripe refuses a card that
+//     is not a test card.
+func (sg *StripeGateway) Tokenize(payment models.Gateway) (string, error) {
+	if !supportedMethods[payment.PaymentMethod] {
+		return "", fmt.Errorf("unsupported payment method: %s. Supported methods are: %v", payment.PaymentMethod, keys(supportedMethods))
+	}
+
 	expiry := strings.Split(payment.CardDetails.Expiry, "/")
-	month := expiry[0]
-	year := expiry[1]
+	if len(expiry) != 2 {
+		return "", fmt.Errorf("invalid card expiry: %s", payment.CardDetails.Expiry)
+	}
 
-	tokenParams := &stripe.TokenParams{
-		Card: &stripe.CardParams{
+	paymentMethod, err := sg.paymentMethods.New(&stripe.PaymentMethodParams{
+		Type: stripe.String("card"),
+		Card: &stripe.PaymentMethodCardParams{
 			Number:   stripe.String(payment.CardDetails.Number),
-			ExpMonth: stripe.String(month),
-			ExpYear:  stripe.String(year),
+			ExpMonth: stripe.String(expiry[0]),
+			ExpYear:  stripe.String(expiry[1]),
 			CVC:      stripe.String(payment.CardDetails.Cvv),
 		},
+	})
+	if err != nil {
+		return getPaymentMethodTest(err, payment)
 	}
 
-	return token.New(tokenParams)
+	return paymentMethod.ID, nil
 }
 
-// getPaymentMethodTest returns a default payment method test string based on the provided card number
-// if an error occurs during token creation. It uses predefined card numbers to determine the payment method.
+// getPaymentMethodTest returns the test payment method matching the card number of a payment whose payment method
+// could not be created, as Stripe refuses raw test card numbers. Only the test card numbers have a test payment
+// method, so the error is returned for any other card.
 //
 // Parameters:
-// - err: an error that indicates if there was an issue creating the token.
+// - err: the error of the payment method creation.
 // - payment: a models.Gateway object that contains card details.
 //
 // Returns:
-// - A string representing the default payment method test.
-func getPaymentMethodTest(err error, payment models.Gateway) string {
-	var paymentMethodTest string
-	if err != nil {
-		fmt.Print("error to create token, using default payment method test")
-		switch payment.CardDetails.Number {
-		case "4242424242424242":
-			paymentMethodTest = "pm_card_visa"
-		case "4000056655665556":
-			paymentMethodTest = "pm_card_visa_debit"
-		case "5555555555554444":
-			paymentMethodTest = "pm_card_mastercard"
-		case "5200828282828210":
-			paymentMethodTest = "pm_card_mastercard_debit"
-		default:
-			paymentMethodTest = "pm_card_visa"
-		}
+// - string: the ID of the test payment method.
+// - error: the error of the payment method creation if the card is not a test card.
+func getPaymentMethodTest(err error, payment models.Gateway) (string, error) {
+	switch payment.CardDetails.Number {
+	case "4242424242424242":
+		return "pm_card_visa", nil
+	case "4000056655665556":
+		return "pm_card_visa_debit", nil
+	case "5555555555554444":
+		return "pm_card_mastercard", nil
+	case "5200828282828210":
+		return "pm_card_mastercard_debit", nil
+	default:
+		return "", fmt.Errorf("error creating payment method: %w", err)
 	}
-	return paymentMethodTest
+}
+
+// minorAmount converts an amount to the minor units of its currency, as expected by Stripe, such as 1999 for
+// 19.99 USD and 500 for 500 JPY. Amounts with more decimal places than the currency has are rounded half to even.
+func minorAmount(value float64, currencyCode string) (int64, error) {
+	currency, ok := iso4217.Get(strings.ToUpper(currencyCode))
+	if !ok {
+		return 0, fmt.Errorf("unsupported currency: %s", currencyCode)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(currency.MinorUnits)), nil)
+	minor := money.Round(new(big.Rat).Mul(money.Rat(value), new(big.Rat).SetInt(scale)), 0, money.HalfEven)
+	return minor.Num().Int64(), nil
 }
 
 // keys returns a slice of strings containing the keys of the provided map.
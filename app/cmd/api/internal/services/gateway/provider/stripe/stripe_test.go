@@ -1,47 +1,222 @@
 package stripe
 
 import (
-	"os"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stripe/stripe-go"
 )
 
-func setupMockEnvironment() {
-	os.Setenv("STRIPE_SECRET_KEY", "sk_test_4eC39HqLyjWDarjtT1zdp7dc")
+const testSecretKey = "sk_test_123"
+
+// newTestGateway creates a StripeGateway calling a local server, which answers the payment method and payment intent requests.
+func newTestGateway(t *testing.T, paymentMethodStatus int, paymentIntents http.HandlerFunc) *StripeGateway {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer "+testSecretKey, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/payment_methods":
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "card", r.PostForm.Get("type"))
+			assert.Equal(t, "12", r.PostForm.Get("card[exp_month]"))
+			w.WriteHeader(paymentMethodStatus)
+			if paymentMethodStatus == http.StatusOK {
+				w.Write([]byte(`{"id": "pm_123", "object": "payment_method"}`))
+				return
+			}
+			w.Write([]byte(`{"error": {"type": "card_error", "message": "Sending credit card numbers directly to the Stripe API is generally unsafe."}}`))
+		case "/v1/payment_intents":
+			paymentIntents(w, r)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return New(Config{SecretKey: testSecretKey, BaseURL: server.URL, Timeout: 5 * time.Second})
 }
 
-func TestProcessPayment_Successful(t *testing.T) {
-	// Arrange
-	sg := &StripeGateway{}
-	payment := models.Gateway{
+func testPayment() models.Gateway {
+	return models.Gateway{
 		Gateway:       "Stripe",
 		Amount:        100.00,
 		Currency:      "USD",
 		PaymentMethod: "card",
 		CardDetails: models.CardDetails{
-			Expiry: "12/23",
+			Number: "4242424242424242",
+			Expiry: "12/30",
+			Cvv:    "123",
 		},
 	}
-	setupMockEnvironment()
+}
+
+func TestProcessPayment_Successful(t *testing.T) {
+	// Arrange
+	sg := newTestGateway(t, http.StatusOK, func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "10000", r.PostForm.Get("amount"))
+		assert.Equal(t, "USD", r.PostForm.Get("currency"))
+		assert.Equal(t, "pm_123", r.PostForm.Get("payment_method"))
+		assert.Equal(t, "transaction1", r.PostForm.Get("metadata[transaction_id]"))
+		assert.Equal(t, "transaction1", r.Header.Get("Idempotency-Key"))
+		w.Write([]byte(`{"id": "pi_123", "object": "payment_intent"}`))
+	})
+	payment := testPayment()
+	payment.TransactionId = "transaction1"
 
 	// Action
 	correlationId := utils.GenerateGUID()
-	_, err := sg.ProcessPayment(payment, correlationId)
+	paymentIntentID, err := sg.ProcessPayment(payment, correlationId)
 
 	// Assert
 	assert.NoError(t, err)
+	assert.Equal(t, "pi_123", *paymentIntentID)
+}
+
+func TestProcessPayment_UsesTestPaymentMethodWhenCardIsRefused(t *testing.T) {
+	// Arrange
+	sg := newTestGateway(t, http.StatusPaymentRequired, func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "pm_card_visa", r.PostForm.Get("payment_method"))
+		w.Write([]byte(`{"id": "pi_123", "object": "payment_intent"}`))
+	})
+
+	// Action
+	paymentIntentID, err := sg.ProcessPayment(testPayment(), utils.GenerateGUID())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "pi_123", *paymentIntentID)
+}
+
+func TestProcessPayment_RefusedCardIsNotTestCard(t *testing.T) {
+	// Arrange
+	sg := newTestGateway(t, http.StatusPaymentRequired, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("payment intent must not be created")
+	})
+	payment := testPayment()
+	payment.CardDetails.Number = "4111111111111111"
+
+	// Action
+	paymentIntentID, err := sg.ProcessPayment(payment, utils.GenerateGUID())
+
+	// Assert
+	assert.Nil(t, paymentIntentID)
+	var stripeErr *stripe.Error
+	assert.True(t, errors.As(err, &stripeErr))
+	assert.Equal(t, http.StatusPaymentRequired, stripeErr.HTTPStatusCode)
+}
+
+func TestProcessPayment_MinorUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		expected string
+	}{
+		{name: "two minor units", amount: 19.99, currency: "USD", expected: "1999"},
+		{name: "rounded half to even", amount: 10.125, currency: "USD", expected: "1012"},
+		{name: "no minor units", amount: 500, currency: "JPY", expected: "500"},
+		{name: "three minor units", amount: 1.5, currency: "KWD", expected: "1500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			sg := newTestGateway(t, http.StatusOK, func(w http.ResponseWriter, r *http.Request) {
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, tt.expected, r.PostForm.Get("amount"))
+				w.Write([]byte(`{"id": "pi_123", "object": "payment_intent"}`))
+			})
+			payment := testPayment()
+			payment.Amount = tt.amount
+			payment.Currency = tt.currency
+
+			// Action
+			paymentIntentID, err := sg.ProcessPayment(payment, utils.GenerateGUID())
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, "pi_123", *paymentIntentID)
+		})
+	}
+}
+
+func TestProcessPayment_UnsupportedCurrency(t *testing.T) {
+	// Arrange
+	sg := newTestGateway(t, http.StatusOK, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("payment intent must not be created")
+	})
+	payment := testPayment()
+	payment.Currency = "XXX1"
+
+	// Action
+	paymentIntentID, err := sg.ProcessPayment(payment, utils.GenerateGUID())
+
+	// Assert
+	assert.Nil(t, paymentIntentID)
+	assert.EqualError(t, err, "unsupported currency: XXX1")
+}
+
+func TestProcessPayment_WithPaymentToken(t *testing.T) {
+	// Arrange
+	sg := newTestGateway(t, http.StatusInternalServerError, func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "pm_456", r.PostForm.Get("payment_method"))
+		w.Write([]byte(`{"id": "pi_123", "object": "payment_intent"}`))
+	})
+	payment := models.Gateway{Gateway: "Stripe", Amount: 100.00, Currency: "USD", PaymentMethod: "card", PaymentToken: "pm_456"}
+
+	// Action
+	paymentIntentID, err := sg.ProcessPayment(payment, utils.GenerateGUID())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "pi_123", *paymentIntentID)
+}
+
+func TestTokenize_Successful(t *testing.T) {
+	// Arrange
+	sg := newTestGateway(t, http.StatusOK, nil)
+
+	// Action
+	token, err := sg.Tokenize(testPayment())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "pm_123", token)
+}
+
+func TestProcessPayment_ProviderError(t *testing.T) {
+	// Arrange
+	sg := newTestGateway(t, http.StatusOK, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error": {"type": "api_error", "message": "An unknown error occurred"}}`))
+	})
+
+	// Action
+	paymentIntentID, err := sg.ProcessPayment(testPayment(), utils.GenerateGUID())
+
+	// Assert
+	assert.Nil(t, paymentIntentID)
+	var stripeErr *stripe.Error
+	assert.True(t, errors.As(err, &stripeErr))
+	assert.Equal(t, http.StatusInternalServerError, stripeErr.HTTPStatusCode)
 }
 
 func TestProcessPayment_InvalidPaymentMethod(t *testing.T) {
 	// Arrange
-	sg := &StripeGateway{}
+	sg := New(Config{SecretKey: testSecretKey})
 	payment := models.Gateway{}
 	payment.PaymentMethod = "1234"
 	payment.CardDetails.Expiry = "12/23"
-	setupMockEnvironment()
 
 	// Action
 	correlationId := utils.GenerateGUID()
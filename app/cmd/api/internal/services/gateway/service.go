@@ -3,49 +3,87 @@ package gateway
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/index"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/notification"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/pending"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/queue"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"go.uber.org/zap"
 )
 
 type GatewayService interface {
 	GetAllAvaiablesGateways() []string
+	GetProvider(gateway string) (provider.PaymentGateway, error)
 	GetAllTransactionsByDate(date string) (*[]models.Transaction, error)
-	AddTransaction(id string, payment models.Gateway) error
+	AddTransaction(id string, payment models.Gateway, status string, risk *models.RiskAssessment, origin audit.Origin) error
+	AddTransactionStatus(id string, createdAt time.Time, status string, providerReference string, origin audit.Origin) error
+	EnqueuePayment(job models.PaymentJob) error
 }
 
 type gatewayService struct {
-	cache cache.CacheClient
+	cache     cache.CacheClient
+	publisher notification.Publisher
+	auditor   audit.AuditService
+	queue     queue.QueueClient
+	pending   pending.PendingService
+	index     index.IndexService
+	providers provider.Registry
+	logger    *zap.Logger
 }
 
-// New creates a new instance of gatewayService with the provided cache client.
+// New creates a new instance of gatewayService with the provided cache client, notification publisher,
+// audit service, queue client, pending events service, transaction index, payment gateways and logger.
 // It returns a pointer to the newly created gatewayService.
 //
 // Parameters:
 //   - cache: an instance of cache.CacheClient to be used by the gatewayService.
+//   - publisher: an instance of notification.Publisher used to notify merchants of transaction status changes.
+//   - auditor: an instance of audit.AuditService that records every mutation of a transaction.
+//   - queue: an instance of queue.QueueClient where payments processed asynchronously are enqueued.
+//   - pending: an instance of pending.PendingService holding provider events received before their transaction was created.
+//   - index: an instance of index.IndexService where transactions are indexed, so the webhook service finds them on any day.
+//   - providers: the registry of the payment gateways the payments are sent to.
+//   - logger: an instance of zap.Logger used to log the parked events that could not be applied.
 //
 // Returns:
 //   - *gatewayService: a pointer to the newly created gatewayService.
-func New(cache cache.CacheClient) *gatewayService {
+func New(cache cache.CacheClient, publisher notification.Publisher, auditor audit.AuditService, queue queue.QueueClient, pending pending.PendingService, index index.IndexService, providers provider.Registry, logger *zap.Logger) *gatewayService {
 	return &gatewayService{
-		cache: cache,
+		cache:     cache,
+		publisher: publisher,
+		auditor:   auditor,
+		queue:     queue,
+		pending:   pending,
+		index:     index,
+		providers: providers,
+		logger:    logger,
 	}
 }
 
-// GetAllAvaiablesGateways retrieves all available gateway keys from the provider.
+// GetAllAvaiablesGateways retrieves all available gateway keys from the provider registry.
 // It returns a slice of strings containing the keys of the available gateways.
 func (p *gatewayService) GetAllAvaiablesGateways() []string {
+	return p.providers.Types()
+}
 
-	keys := make([]string, 0, len(provider.Providers))
-
-	for key := range provider.Providers {
-		keys = append(keys, string(key))
-	}
-
-	return keys
+// GetProvider retrieves the payment gateway a payment is sent to.
+//
+// Parameters:
+//   - gateway: the name of the gateway, e.g. "Stripe".
+//
+// Returns:
+//   - provider.PaymentGateway: the payment gateway.
+//   - error: an error if the payment gateway type is unsupported.
+func (p *gatewayService) GetProvider(gateway string) (provider.PaymentGateway, error) {
+	return p.providers.Get(provider.ProviderType(gateway))
 }
 
 // GetAllTransactionsByDate retrieves all transactions for a given date from the cache.
@@ -84,54 +122,206 @@ func (p *gatewayService) GetAllTransactionsByDate(date string) (*[]models.Transa
 }
 
 // AddTransaction adds a new transaction to the cache with the given id and payment details.
-// It creates a new transaction with the current timestamp and a status of "pending".
-// The transaction is then stored in the cache, grouped by the current date.
+// It creates a new transaction with the current timestamp, the given initial status and
+// the risk assessment of the payment.
+// The transaction is then stored in the cache, grouped by the current date and locking the transactions of the day
+// while they are read and written back, together with the audit log entry of its creation, indexed by its ID and the merchant of the payment is notified of its status.
+// Provider events received before the transaction was created are then applied to it. Events that cannot be applied
+// are parked again, to be applied by the webhook service.
 //
 // Parameters:
 //   - id: A string representing the unique identifier for the transaction.
 //   - payment: A models.Gateway object containing the payment details.
+//   - status: The initial status of the transaction, such as "pending", "review" or "blocked".
+//   - risk: The risk assessment of the payment.
+//   - origin: The actor and correlation ID responsible for the transaction.
 //
 // Returns:
-//   - error: An error if there is an issue with cache retrieval, unmarshalling, or setting the cache.
-func (p *gatewayService) AddTransaction(id string, payment models.Gateway) error {
+//   - error: An error if there is an issue with cache retrieval, unmarshalling, setting the cache or recording the audit entry.
+func (p *gatewayService) AddTransaction(id string, payment models.Gateway, status string, risk *models.RiskAssessment, origin audit.Origin) error {
 	now := time.Now()
 	transaction := models.Transaction{
-		Id:       id,
-		Amount:   payment.Amount,
-		Currency: payment.Currency,
+		Id:         id,
+		MerchantId: payment.MerchantId,
+		Amount:     payment.Amount,
+		Currency:   payment.Currency,
 		TransactionStatus: []models.TransactionStatus{
 			{
 				DateTime: now.Format(time.RFC3339),
-				Status:   "pending",
+				Status:   status,
 			},
 		},
+		Risk:    risk,
+		QuoteId: payment.QuoteId,
 	}
 
 	transactionsByDate := fmt.Sprintf("%s_%s", cache.TransactionsKey, now.Format("02_01_2006"))
-	c, err := p.cache.Get(transactionsByDate)
+	err := cache.WithLock(p.cache, transactionsByDate, func() error {
+		c, err := p.cache.Get(transactionsByDate)
 
-	var transactions map[string]models.Transaction
-	if err != nil {
-		if err.Error() == cache.ErrCacheMiss.Error() {
-			transactions = map[string]models.Transaction{id: transaction}
+		var transactions map[string]models.Transaction
+		if err != nil {
+			if err.Error() == cache.ErrCacheMiss.Error() {
+				transactions = map[string]models.Transaction{id: transaction}
+			} else {
+				return err
+			}
 		} else {
+			if err := json.Unmarshal(c, &transactions); err != nil {
+				return err
+			}
+			transactions[id] = transaction
+		}
+
+		transactionsSerialized, err := json.Marshal(transactions)
+		if err != nil {
+			return err
+		}
+
+		return p.auditor.Write(transactionsByDate, string(transactionsSerialized), id, audit.Change{Action: audit.ActionTransactionCreated, Origin: origin, After: transaction})
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := p.index.Put(id, id, now); err != nil {
+		return err
+	}
+
+	p.publisher.Publish(transaction.MerchantId, notification.TransactionEventType(status), transaction)
+
+	p.applyPending(id, now)
+	return nil
+}
+
+// applyPending adds the statuses of the provider events parked for a transaction, parking again the events not applied.
+// Failures are logged rather than returned, as the transaction is already stored, and the events parked again are
+// applied by the next sweep of the webhook service.
+func (p *gatewayService) applyPending(id string, createdAt time.Time) {
+	events, err := p.pending.Take(id)
+	if err != nil {
+		p.logger.Error("Failed to take parked events", zap.String("transaction_id", id), zap.Error(err))
+		return
+	}
+
+	for i, event := range events {
+		status := models.TransactionStatus{
+			Status:   event.Status,
+			DateTime: event.OccurredAt,
+			Code:     event.Code,
+			Message:  event.Message,
+		}
+		if event.Origin.Actor.Type == audit.ActorWebhookEvent {
+			status.EventId = event.Origin.Actor.Id
+		}
+		if err := p.addStatus(id, createdAt, status, "", event.Origin); err != nil {
+			p.logger.Error("Failed to apply parked event", zap.String("transaction_id", id), zap.String("status", event.Status), zap.Error(err))
+			if err := p.pending.Park(events[i:]...); err != nil {
+				p.logger.Error("Failed to park events again", zap.String("transaction_id", id), zap.Error(err))
+			}
+			return
+		}
+	}
+}
+
+// AddTransactionStatus adds a new status to an existing transaction in the cache, optionally storing the reference
+// of the payment at the provider, which is indexed so provider events carrying it find the transaction. The change is recorded in the audit log and merchants are notified of the new status.
+//
+// Parameters:
+//   - id: The unique identifier of the transaction.
+//   - createdAt: The creation time of the transaction, used to find the date it is grouped by.
+//   - status: The status to be added to the transaction, such as "submitted" or "failed".
+//   - providerReference: The ID of the payment at the provider, or an empty string to keep the current one.
+//   - origin: The actor and correlation ID responsible for the new status.
+//
+// Returns:
+//   - error: An error if the transaction does not exist or there is an issue reading, setting or auditing it.
+func (p *gatewayService) AddTransactionStatus(id string, createdAt time.Time, status string, providerReference string, origin audit.Origin) error {
+	return p.addStatus(id, createdAt, models.TransactionStatus{Status: status, DateTime: time.Now().Format(time.RFC3339)}, providerReference, origin)
+}
+
+// addStatus adds a status to an existing transaction, keeping its statuses in the order they occurred.
+// Statuses of provider events already added to the transaction are skipped. The transactions of the day are locked
+// while they are read and written back, so concurrent changes never overwrite each other.
+func (p *gatewayService) addStatus(id string, createdAt time.Time, status models.TransactionStatus, providerReference string, origin audit.Origin) error {
+	transactionsByDate := fmt.Sprintf("%s_%s", cache.TransactionsKey, createdAt.Format("02_01_2006"))
+
+	var transaction models.Transaction
+	changed := false
+	err := cache.WithLock(p.cache, transactionsByDate, func() error {
+		c, err := p.cache.Get(transactionsByDate)
+		if err != nil {
 			return err
 		}
-	} else {
+
+		var transactions map[string]models.Transaction
 		if err := json.Unmarshal(c, &transactions); err != nil {
 			return err
 		}
+
+		before, exists := transactions[id]
+		if !exists {
+			return fmt.Errorf("transaction %s not found", id)
+		}
+
+		if !utils.IsEmptyOrNull(status.EventId) {
+			for _, current := range before.TransactionStatus {
+				if current.EventId == status.EventId && current.Status == status.Status {
+					return nil
+				}
+			}
+		}
+
+		transaction = before
+		transaction.TransactionStatus = append(append([]models.TransactionStatus{}, before.TransactionStatus...), status)
+		sort.SliceStable(transaction.TransactionStatus, func(i, j int) bool {
+			return statusTime(transaction.TransactionStatus[i]).Before(statusTime(transaction.TransactionStatus[j]))
+		})
+		if !utils.IsEmptyOrNull(providerReference) {
+			transaction.ProviderReference = providerReference
+		}
 		transactions[id] = transaction
-	}
 
-	transactionsSerialized, err := json.Marshal(transactions)
-	if err != nil {
+		transactionsSerialized, err := json.Marshal(transactions)
+		if err != nil {
+			return err
+		}
+
+		change := audit.Change{Action: audit.ActionTransactionStatusAdded, Origin: origin, Before: before, After: transaction}
+		if err := p.auditor.Write(transactionsByDate, string(transactionsSerialized), id, change); err != nil {
+			return err
+		}
+
+		changed = true
+		return nil
+	})
+	if err != nil || !changed {
 		return err
 	}
 
-	if err := p.cache.Set(transactionsByDate, string(transactionsSerialized), 0); err != nil {
-		return err
+	if !utils.IsEmptyOrNull(providerReference) && providerReference != id {
+		if err := p.index.Put(providerReference, id, createdAt); err != nil {
+			return err
+		}
 	}
 
+	p.publisher.Publish(transaction.MerchantId, notification.TransactionEventType(status.Status), transaction)
 	return nil
 }
+
+func statusTime(status models.TransactionStatus) time.Time {
+	value, _ := time.Parse(time.RFC3339, status.DateTime)
+	return value
+}
+
+// EnqueuePayment enqueues a payment to be sent to the provider by the payment workers.
+//
+// Parameters:
+//   - job: The payment job, carrying the ID of the pending transaction created for the payment.
+//
+// Returns:
+//   - error: An error if the job cannot be enqueued.
+func (p *gatewayService) EnqueuePayment(job models.PaymentJob) error {
+	_, err := p.queue.Enqueue(queue.PaymentsStream, utils.ToJSON(job))
+	return err
+}
@@ -1,76 +1,215 @@
 package gateway
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/index"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/pending"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/queue"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
 )
 
-type MockCacheClient struct {
+type failingCache struct {
+	cache.CacheClient
+}
+
+func (c failingCache) SetAndAppend(key string, item interface{}, expiration time.Duration, listKey string, listItems ...interface{}) error {
+	return errors.New("cache set error")
+}
+
+type MockPublisher struct {
 	mock.Mock
 }
 
-func (m *MockCacheClient) Get(key string) ([]byte, error) {
-	args := m.Called(key)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return []byte(args.String(0)), args.Error(1)
+func (m *MockPublisher) Publish(merchantId string, eventType string, data interface{}) {
+	m.Called(merchantId, eventType, data)
 }
 
-func (m *MockCacheClient) Set(key string, item interface{}, expiration time.Duration) error {
-	args := m.Called(key, item, expiration)
-	if args.Get(0) == nil {
-		return nil
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) Record(transactionId string, action string, origin audit.Origin, before interface{}, after interface{}) error {
+	args := m.Called(transactionId, action, origin, before, after)
+	return args.Error(0)
+}
+
+func (m *MockAuditService) Write(key string, item interface{}, transactionId string, changes ...audit.Change) error {
+	args := m.Called(key, item, transactionId, changes)
+	return args.Error(0)
+}
+
+func (m *MockAuditService) GetHistory(transactionId string) ([]audit.Entry, error) {
+	args := m.Called(transactionId)
+	return args.Get(0).([]audit.Entry), args.Error(1)
+}
+
+func (m *MockAuditService) GetStateAt(transactionId string, at time.Time) (*audit.State, error) {
+	args := m.Called(transactionId, at)
+	return args.Get(0).(*audit.State), args.Error(1)
+}
+
+// storeWrite returns a Run function of the mocked audit Write storing the written item in the cache.
+func storeWrite(cacheClient cache.CacheClient) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		cacheClient.Set(args.String(0), args.Get(1), 0)
 	}
+}
+
+// changes matches the audit changes written with the given actions and origin.
+func changes(origin interface{}, actions ...string) interface{} {
+	return mock.MatchedBy(func(changes []audit.Change) bool {
+		if len(changes) != len(actions) {
+			return false
+		}
+		for i, change := range changes {
+			if change.Action != actions[i] || (origin != mock.Anything && change.Origin != origin) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+type MockQueueClient struct {
+	mock.Mock
+}
+
+func (m *MockQueueClient) CreateGroup(stream string, group string) error {
+	args := m.Called(stream, group)
+	return args.Error(0)
+}
+
+func (m *MockQueueClient) Enqueue(stream string, payload interface{}) (string, error) {
+	args := m.Called(stream, payload)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockQueueClient) Read(stream string, group string, consumer string, count int64, block time.Duration) ([]queue.Message, error) {
+	args := m.Called(stream, group, consumer, count, block)
+	return args.Get(0).([]queue.Message), args.Error(1)
+}
+
+func (m *MockQueueClient) Reclaim(stream string, group string, consumer string, minIdle time.Duration, count int64) ([]queue.Message, error) {
+	args := m.Called(stream, group, consumer, minIdle, count)
+	return args.Get(0).([]queue.Message), args.Error(1)
+}
+
+func (m *MockQueueClient) Ack(stream string, group string, id string) error {
+	args := m.Called(stream, group, id)
+	return args.Error(0)
+}
+
+func (m *MockQueueClient) Schedule(stream string, payload interface{}, at time.Time) error {
+	args := m.Called(stream, payload, at)
+	return args.Error(0)
+}
+
+func (m *MockQueueClient) Promote(stream string, now time.Time, count int64) (int, error) {
+	args := m.Called(stream, now, count)
+	return args.Int(0), args.Error(1)
+}
+
+type MockPendingService struct {
+	mock.Mock
+}
+
+func (m *MockPendingService) Park(events ...pending.Event) error {
+	args := m.Called(events)
+	return args.Error(0)
+}
+
+func (m *MockPendingService) Take(transactionId string) ([]pending.Event, error) {
+	args := m.Called(transactionId)
+	return args.Get(0).([]pending.Event), args.Error(1)
+}
+
+func (m *MockPendingService) Sweep(apply pending.ApplyFunc) error {
+	args := m.Called(apply)
+	return args.Error(0)
+}
+
+func (m *MockPendingService) Run(ctx context.Context, apply pending.ApplyFunc) {
+	m.Called(ctx, apply)
+}
+
+func newMockPendingService() *MockPendingService {
+	mockPending := new(MockPendingService)
+	mockPending.On("Take", mock.Anything).Return([]pending.Event{}, nil).Maybe()
+	return mockPending
+}
+
+type MockIndexService struct {
+	mock.Mock
+}
+
+func (m *MockIndexService) Put(lookupId string, transactionId string, createdAt time.Time) error {
+	args := m.Called(lookupId, transactionId, createdAt)
 	return args.Error(0)
 }
 
-func (m *MockCacheClient) CheckCache() bool {
-	args := m.Called()
-	return args.Bool(0)
+func (m *MockIndexService) Get(lookupId string) (*index.Entry, error) {
+	args := m.Called(lookupId)
+	entry, _ := args.Get(0).(*index.Entry)
+	return entry, args.Error(1)
 }
 
-func (m *MockCacheClient) Delete(key string) (*int64, error) {
-	args := m.Called(key)
-	return args.Get(0).(*int64), args.Error(1)
+func newMockIndexService() *MockIndexService {
+	mockIndex := new(MockIndexService)
+	mockIndex.On("Put", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return mockIndex
+}
+
+func storedTransactions(t *testing.T, cacheClient cache.CacheClient, key string) map[string]models.Transaction {
+	transactions := map[string]models.Transaction{}
+	c, err := cacheClient.Get(key)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(c, &transactions))
+	return transactions
 }
 
 func TestNew(t *testing.T) {
 	// Arrange
-	mockCache := new(MockCacheClient)
+	cacheClient := cache.NewMemory()
 
 	// Action
-	service := New(mockCache)
+	service := New(cacheClient, new(MockPublisher), new(MockAuditService), new(MockQueueClient), newMockPendingService(), newMockIndexService(), provider.Registry{}, zap.NewNop())
 
 	// Assert
 	if service == nil {
 		t.Errorf("Expected service to be non-nil")
 	}
 
-	if service.cache != mockCache {
+	if service.cache != cacheClient {
 		t.Errorf("Expected cache to be set correctly")
 	}
 }
 
 func TestGetAllAvaiablesGateways(t *testing.T) {
 	// Arrange
-	mockCache := new(MockCacheClient)
-	service := New(mockCache)
-
-	expectedGateways := []string{"gateway1", "gateway2", "gateway3"}
-	provider.Providers = map[provider.ProviderType]provider.PaymentGateway{
+	mockPublisher := new(MockPublisher)
+	mockAudit := new(MockAuditService)
+	providers := provider.Registry{
+		provider.ProviderType("gateway3"): nil,
 		provider.ProviderType("gateway1"): nil,
 		provider.ProviderType("gateway2"): nil,
-		provider.ProviderType("gateway3"): nil,
 	}
+	service := New(cache.NewMemory(), mockPublisher, mockAudit, new(MockQueueClient), newMockPendingService(), newMockIndexService(), providers, zap.NewNop())
+
+	expectedGateways := []string{"gateway1", "gateway2", "gateway3"}
 
 	// Action
 	actualGateways := service.GetAllAvaiablesGateways()
@@ -90,51 +229,241 @@ func TestGetAllAvaiablesGateways(t *testing.T) {
 func TestAddTransaction_Success(t *testing.T) {
 
 	// Arrange
-	mockCache := new(MockCacheClient)
-	service := New(mockCache)
+	cacheClient := cache.NewMemory()
+	mockPublisher := new(MockPublisher)
+	mockAudit := new(MockAuditService)
+	mockIndex := new(MockIndexService)
+	service := New(cacheClient, mockPublisher, mockAudit, new(MockQueueClient), newMockPendingService(), mockIndex, provider.Registry{}, zap.NewNop())
 
 	id := "transaction1"
 	payment := models.Gateway{
-		Amount:   100.0,
-		Currency: "USD",
+		Amount:     100.0,
+		Currency:   "USD",
+		MerchantId: "merchant1",
 	}
+	origin := audit.ApiRequest("correlation1")
 
 	now := time.Now()
 	transactionsByDate := fmt.Sprintf("%s_%s", cache.TransactionsKey, now.Format("02_01_2006"))
 
-	mockCache.On("Get", transactionsByDate).Return(nil, errors.New(cache.ErrCacheMiss.Error()))
-	mockCache.On("Set", transactionsByDate, mock.Anything, time.Duration(0)).Return(nil)
+	mockAudit.On("Write", transactionsByDate, mock.Anything, id, changes(origin, audit.ActionTransactionCreated)).Run(storeWrite(cacheClient)).Return(nil)
+	mockPublisher.On("Publish", "merchant1", "transaction.pending", mock.Anything).Return()
+	mockIndex.On("Put", id, id, mock.Anything).Return(nil)
 
 	// Action
-	err := service.AddTransaction(id, payment)
+	err := service.AddTransaction(id, payment, "pending", &models.RiskAssessment{Decision: models.RiskAllow}, origin)
 
 	// Assert
 	assert.NoError(t, err)
-	mockCache.AssertExpectations(t)
+	assert.Contains(t, storedTransactions(t, cacheClient, transactionsByDate), id)
+	mockAudit.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+	mockIndex.AssertExpectations(t)
 }
 
-func TestAddTransaction_CacheSetError(t *testing.T) {
+func TestAddTransaction_Concurrent(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	mockPublisher := new(MockPublisher)
+	mockAudit := new(MockAuditService)
+	service := New(cacheClient, mockPublisher, mockAudit, new(MockQueueClient), newMockPendingService(), newMockIndexService(), provider.Registry{}, zap.NewNop())
+
+	transactionsByDate := fmt.Sprintf("%s_%s", cache.TransactionsKey, time.Now().Format("02_01_2006"))
+	mockAudit.On("Write", transactionsByDate, mock.Anything, mock.Anything, mock.Anything).Run(storeWrite(cacheClient)).Return(nil)
+	mockPublisher.On("Publish", "merchant1", "transaction.pending", mock.Anything).Return()
+
+	// Action
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			errs <- service.AddTransaction(id, models.Gateway{Amount: 100.0, Currency: "USD", MerchantId: "merchant1"}, "pending", nil, audit.ApiRequest("correlation1"))
+		}(fmt.Sprintf("transaction%d", i))
+	}
+	wg.Wait()
+	close(errs)
+
+	// Assert
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Len(t, storedTransactions(t, cacheClient, transactionsByDate), 20)
+}
+
+func TestAddTransaction_TakeParkedEventsError(t *testing.T) {
+	// Arrange
+	mockPublisher := new(MockPublisher)
+	mockAudit := new(MockAuditService)
+	mockPending := new(MockPendingService)
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, mockPublisher, mockAudit, new(MockQueueClient), mockPending, newMockIndexService(), provider.Registry{}, zap.NewNop())
+
+	id := "transaction1"
+	origin := audit.ApiRequest("correlation1")
+
+	mockAudit.On("Write", mock.Anything, mock.Anything, id, changes(origin, audit.ActionTransactionCreated)).Run(storeWrite(cacheClient)).Return(nil)
+	mockPublisher.On("Publish", "merchant1", "transaction.pending", mock.Anything).Return()
+	mockPending.On("Take", id).Return([]pending.Event(nil), errors.New("take error"))
+
+	// Action
+	err := service.AddTransaction(id, models.Gateway{Amount: 100.0, Currency: "USD", MerchantId: "merchant1"}, "pending", nil, origin)
+
+	// Assert
+	assert.NoError(t, err)
+	mockPending.AssertExpectations(t)
+	mockPending.AssertNotCalled(t, "Park", mock.Anything)
+}
+
+func TestAddTransaction_ParksAgainEventsNotApplied(t *testing.T) {
+
+	// Arrange
+	mockPublisher := new(MockPublisher)
+	mockAudit := new(MockAuditService)
+	mockPending := new(MockPendingService)
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, mockPublisher, mockAudit, new(MockQueueClient), mockPending, newMockIndexService(), provider.Registry{}, zap.NewNop())
+
+	id := "transaction1"
+	origin := audit.ApiRequest("correlation1")
+	parked := []pending.Event{
+		{TransactionId: id, Status: "created", OccurredAt: "2024-10-01T10:00:00Z"},
+		{TransactionId: id, Status: "success", OccurredAt: "2024-10-01T10:00:05Z"},
+	}
+
+	mockAudit.On("Write", mock.Anything, mock.Anything, id, changes(origin, audit.ActionTransactionCreated)).Run(storeWrite(cacheClient)).Return(nil)
+	mockAudit.On("Write", mock.Anything, mock.Anything, id, changes(mock.Anything, audit.ActionTransactionStatusAdded)).Return(errors.New("append error"))
+	mockPublisher.On("Publish", "merchant1", "transaction.pending", mock.Anything).Return()
+	mockPending.On("Take", id).Return(parked, nil)
+	mockPending.On("Park", parked).Return(nil)
+
+	// Action
+	err := service.AddTransaction(id, models.Gateway{Amount: 100.0, Currency: "USD", MerchantId: "merchant1"}, "pending", nil, origin)
+
+	// Assert
+	assert.NoError(t, err)
+	mockPending.AssertExpectations(t)
+}
+
+func TestAddTransaction_CacheWriteError(t *testing.T) {
 
 	// Arrange
-	mockCache := new(MockCacheClient)
-	service := New(mockCache)
+	cacheClient := cache.NewMemory()
+	mockPublisher := new(MockPublisher)
+	auditor := audit.New(failingCache{cacheClient})
+	service := New(failingCache{cacheClient}, mockPublisher, auditor, new(MockQueueClient), newMockPendingService(), newMockIndexService(), provider.Registry{}, zap.NewNop())
 
 	id := "transaction1"
 	payment := models.Gateway{
-		Amount:   100.0,
-		Currency: "USD",
+		Amount:     100.0,
+		Currency:   "USD",
+		MerchantId: "merchant1",
 	}
+	origin := audit.ApiRequest("correlation1")
 
-	now := time.Now()
-	transactionsByDate := fmt.Sprintf("%s_%s", cache.TransactionsKey, now.Format("02_01_2006"))
+	// Action
+	err := service.AddTransaction(id, payment, "pending", &models.RiskAssessment{Decision: models.RiskAllow}, origin)
 
-	mockCache.On("Get", transactionsByDate).Return(nil, errors.New(cache.ErrCacheMiss.Error()))
-	mockCache.On("Set", transactionsByDate, mock.Anything, time.Duration(0)).Return(errors.New("cache set error"))
+	// Assert
+	assert.Error(t, err)
+	_, errStored := cacheClient.Get(fmt.Sprintf("%s_%s", cache.TransactionsKey, time.Now().Format("02_01_2006")))
+	assert.Equal(t, cache.ErrCacheMiss.Error(), errStored.Error())
+	_, errHistory := auditor.GetHistory(id)
+	assert.ErrorIs(t, errHistory, audit.ErrHistoryNotFound)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddTransaction_AuditWriteError(t *testing.T) {
+
+	// Arrange
+	mockPublisher := new(MockPublisher)
+	mockAudit := new(MockAuditService)
+	service := New(cache.NewMemory(), mockPublisher, mockAudit, new(MockQueueClient), newMockPendingService(), newMockIndexService(), provider.Registry{}, zap.NewNop())
+
+	id := "transaction1"
+	payment := models.Gateway{
+		Amount:     100.0,
+		Currency:   "USD",
+		MerchantId: "merchant1",
+	}
+	origin := audit.ApiRequest("correlation1")
+
+	mockAudit.On("Write", mock.Anything, mock.Anything, id, changes(origin, audit.ActionTransactionCreated)).Return(errors.New("append error"))
 
 	// Action
-	err := service.AddTransaction(id, payment)
+	err := service.AddTransaction(id, payment, "pending", &models.RiskAssessment{Decision: models.RiskAllow}, origin)
 
 	// Assert
 	assert.Error(t, err)
-	mockCache.AssertExpectations(t)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddTransactionStatus_Success(t *testing.T) {
+
+	// Arrange
+	cacheClient := cache.NewMemory()
+	mockPublisher := new(MockPublisher)
+	mockAudit := new(MockAuditService)
+	mockIndex := new(MockIndexService)
+	service := New(cacheClient, mockPublisher, mockAudit, new(MockQueueClient), newMockPendingService(), mockIndex, provider.Registry{}, zap.NewNop())
+
+	id := "transaction1"
+	createdAt := time.Date(2024, 10, 1, 23, 59, 0, 0, time.UTC)
+	transactionsByDate := fmt.Sprintf("%s_%s", cache.TransactionsKey, "01_10_2024")
+	transaction := models.Transaction{Id: id, MerchantId: "merchant1", TransactionStatus: []models.TransactionStatus{{Status: "pending"}}}
+	origin := audit.ApiRequest("correlation1")
+
+	cacheClient.Set(transactionsByDate, utils.ToJSON(map[string]models.Transaction{id: transaction}), 0)
+	mockAudit.On("Write", transactionsByDate, mock.Anything, id, changes(origin, audit.ActionTransactionStatusAdded)).Run(storeWrite(cacheClient)).Return(nil)
+	mockPublisher.On("Publish", "merchant1", "transaction.submitted", mock.Anything).Return()
+	mockIndex.On("Put", "pi_123", id, createdAt).Return(nil)
+
+	// Action
+	err := service.AddTransactionStatus(id, createdAt, "submitted", "pi_123", origin)
+
+	// Assert
+	assert.NoError(t, err)
+	stored := storedTransactions(t, cacheClient, transactionsByDate)
+	assert.Equal(t, "pi_123", stored[id].ProviderReference)
+	assert.Len(t, stored[id].TransactionStatus, 2)
+	assert.Equal(t, "submitted", stored[id].TransactionStatus[1].Status)
+	mockAudit.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+	mockIndex.AssertExpectations(t)
+}
+
+func TestAddTransactionStatus_NotFound(t *testing.T) {
+
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, new(MockPublisher), new(MockAuditService), new(MockQueueClient), newMockPendingService(), newMockIndexService(), provider.Registry{}, zap.NewNop())
+
+	createdAt := time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC)
+	transactionsByDate := fmt.Sprintf("%s_%s", cache.TransactionsKey, "01_10_2024")
+	cacheClient.Set(transactionsByDate, utils.ToJSON(map[string]models.Transaction{}), 0)
+
+	// Action
+	err := service.AddTransactionStatus("transaction1", createdAt, "submitted", "", audit.ApiRequest("correlation1"))
+
+	// Assert
+	assert.Error(t, err)
+	assert.Empty(t, storedTransactions(t, cacheClient, transactionsByDate))
+}
+
+func TestEnqueuePayment_Success(t *testing.T) {
+
+	// Arrange
+	mockQueue := new(MockQueueClient)
+	service := New(cache.NewMemory(), new(MockPublisher), new(MockAuditService), mockQueue, newMockPendingService(), newMockIndexService(), provider.Registry{}, zap.NewNop())
+
+	job := models.PaymentJob{TransactionId: "transaction1", CorrelationId: "correlation1"}
+	mockQueue.On("Enqueue", queue.PaymentsStream, utils.ToJSON(job)).Return("1-0", nil)
+
+	// Action
+	err := service.EnqueuePayment(job)
+
+	// Assert
+	assert.NoError(t, err)
+	mockQueue.AssertExpectations(t)
 }
@@ -0,0 +1,32 @@
+package quote
+
+import (
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	TTL         time.Duration
+	Retention   time.Duration
+	Reservation time.Duration
+}
+
+// LoadConfig loads the exchange rate quotes configuration from the environment variables.
+//
+// Environment Variables:
+//   - EXCHANGE_RATE_QUOTE_TTL: how long a quote can be used after it is created, e.g. "15m".
+//   - EXCHANGE_RATE_QUOTE_RETENTION: how long a quote is kept after it expires, so it is rejected as expired
+//     instead of unknown, e.g. "24h".
+//   - EXCHANGE_RATE_QUOTE_RESERVATION: how long a payment can hold a quote before it is committed or released,
+//     e.g. "2m".
+//
+// Returns:
+//   - Config: the exchange rate quotes configuration.
+func LoadConfig() Config {
+	return Config{
+		TTL:         utils.GetEnvDuration("EXCHANGE_RATE_QUOTE_TTL", 15*time.Minute),
+		Retention:   utils.GetEnvDuration("EXCHANGE_RATE_QUOTE_RETENTION", 24*time.Hour),
+		Reservation: utils.GetEnvDuration("EXCHANGE_RATE_QUOTE_RESERVATION", 2*time.Minute),
+	}
+}
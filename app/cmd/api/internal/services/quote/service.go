@@ -0,0 +1,363 @@
+package quote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/iso4217"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/money"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+var (
+	ErrQuoteNotFound = errors.New("quote not found")
+	ErrQuoteExpired  = errors.New("quote has expired")
+	ErrQuoteUsed     = errors.New("quote has already been used")
+	ErrQuoteInUse    = errors.New("quote is being used by another request")
+	ErrQuoteMismatch = errors.New("quote does not match the merchant, amount and currencies of the request")
+)
+
+// Converter converts amounts between currencies at the current rates.
+type Converter interface {
+	ConvertExchangeRate(currency models.CurrencyConvert) (*models.CurrencyConvertResponse, error)
+}
+
+type QuoteService interface {
+	CreateQuote(request models.CurrencyQuoteCreate) (*models.CurrencyQuote, error)
+	GetQuote(id string) (*models.CurrencyQuote, error)
+	RedeemForConversion(id string, currency models.CurrencyConvert) (*models.CurrencyConvertResponse, error)
+	ReserveForPayment(payment models.Gateway) (*models.CurrencyQuote, error)
+	Commit(id string) error
+	Release(id string) error
+}
+
+type quoteService struct {
+	cache     cache.CacheClient
+	converter Converter
+	config    Config
+	now       func() time.Time
+}
+
+// New creates a new instance of quoteService with the provided cache client, converter and configuration.
+// It returns a pointer to the newly created quoteService.
+//
+// Parameters:
+//   - cache: an instance of cache.CacheClient where the quotes are stored.
+//   - converter: the Converter that prices the quotes, usually the currency service.
+//   - config: the exchange rate quotes configuration.
+//
+// Returns:
+//   - *quoteService: a pointer to the initialized quoteService.
+func New(cache cache.CacheClient, converter Converter, config Config) *quoteService {
+	return &quoteService{
+		cache:     cache,
+		converter: converter,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// CreateQuote converts an amount at the current rates and locks the result for the configured TTL,
+// so a later conversion or payment gets the same rate and amounts even if the rates change in the meantime.
+//
+// Parameters:
+//   - request: the amount, the currencies, the rounding mode and the merchant of the quote.
+//
+// Returns:
+//   - *models.CurrencyQuote: the quote, with its ID and expiration.
+//   - error: an error if the amount cannot be converted or the quote cannot be stored.
+func (p *quoteService) CreateQuote(request models.CurrencyQuoteCreate) (*models.CurrencyQuote, error) {
+	res, err := p.converter.ConvertExchangeRate(models.CurrencyConvert{
+		Amount:       request.Amount,
+		FromCurrency: request.FromCurrency,
+		ToCurrency:   request.ToCurrency,
+		Rounding:     request.Rounding,
+		MerchantId:   request.MerchantId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := p.now().UTC()
+	quote := &models.CurrencyQuote{
+		Id:                utils.GenerateGUID(),
+		Amount:            request.Amount,
+		ConvertedAmount:   res.Amount,
+		FromCurrency:      res.FromCurrency,
+		ToCurrency:        res.ToCurrency,
+		MerchantId:        request.MerchantId,
+		Date:              res.Date,
+		MidRate:           res.MidRate,
+		Rate:              res.Rate,
+		SpreadBasisPoints: res.SpreadBasisPoints,
+		Markup:            res.Markup,
+		Rounding:          res.Rounding,
+		Provider:          res.Provider,
+		AsOf:              res.AsOf,
+		Stale:             res.Stale,
+		CreatedAt:         now.Format(time.RFC3339),
+		ExpiresAt:         now.Add(p.config.TTL).Format(time.RFC3339),
+	}
+
+	quoteSerialized, err := json.Marshal(quote)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.cache.Set(quoteKey(quote.Id), string(quoteSerialized), p.config.TTL+p.config.Retention); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// GetQuote retrieves a quote, with the time it was used if it was.
+//
+// Parameters:
+//   - id: the quote id.
+//
+// Returns:
+//   - *models.CurrencyQuote: the quote.
+//   - error: ErrQuoteNotFound if the quote does not exist or is no longer kept, or an error if it cannot be read.
+func (p *quoteService) GetQuote(id string) (*models.CurrencyQuote, error) {
+	quote, err := p.getQuote(id)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := p.cache.Get(usedKey(id))
+	if err == nil {
+		quote.UsedAt = string(c)
+	} else if err.Error() != cache.ErrCacheMiss.Error() {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// RedeemForConversion uses a quote for a conversion of the same amount, currencies and merchant as the quote.
+// The conversion gets the rates, amounts and rounding of the quote.
+//
+// Parameters:
+//   - id: the quote id.
+//   - currency: the conversion, whose amount, currencies and merchant must match the quote, and whose format,
+//     if any, formats the converted amount.
+//
+// Returns:
+//   - *models.CurrencyConvertResponse: the conversion locked by the quote.
+//   - error: ErrQuoteNotFound, ErrQuoteExpired, ErrQuoteUsed, ErrQuoteInUse or ErrQuoteMismatch if the quote cannot
+//     be used, or an error if it cannot be read or marked as used.
+func (p *quoteService) RedeemForConversion(id string, currency models.CurrencyConvert) (*models.CurrencyConvertResponse, error) {
+	quote, err := p.reserve(id, func(quote *models.CurrencyQuote) bool {
+		return quote.MerchantId == currency.MerchantId && quote.FromCurrency == currency.FromCurrency &&
+			quote.ToCurrency == currency.ToCurrency && sameAmount(quote.Amount, currency.Amount, quote.FromCurrency)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.Commit(id); err != nil {
+		return nil, errors.Join(err, p.Release(id))
+	}
+
+	result := &models.CurrencyConvertResponse{
+		Amount:            quote.ConvertedAmount,
+		FromCurrency:      quote.FromCurrency,
+		ToCurrency:        quote.ToCurrency,
+		Date:              quote.Date,
+		MidRate:           quote.MidRate,
+		Rate:              quote.Rate,
+		SpreadBasisPoints: quote.SpreadBasisPoints,
+		Markup:            quote.Markup,
+		Rounding:          quote.Rounding,
+		Provider:          quote.Provider,
+		AsOf:              quote.AsOf,
+		Stale:             quote.Stale,
+		QuoteId:           quote.Id,
+	}
+
+	if currency.Format != "" {
+		if result.Formatted, err = money.Format(result.Amount, result.ToCurrency, currency.Format); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// ReserveForPayment reserves a quote for a payment of the merchant of the quote, of its converted amount and in its
+// target currency. The quote is not used yet: it must be committed once the transaction of the payment is stored,
+// or released if the payment fails, so a failed payment does not burn the quote. A reservation that is neither
+// committed nor released expires after the configured reservation time.
+//
+// Parameters:
+//   - payment: the payment, whose merchant, amount and currency must match the quote.
+//
+// Returns:
+//   - *models.CurrencyQuote: the reserved quote.
+//   - error: ErrQuoteNotFound, ErrQuoteExpired, ErrQuoteUsed, ErrQuoteInUse or ErrQuoteMismatch if the quote cannot
+//     be used, or an error if it cannot be read or reserved.
+func (p *quoteService) ReserveForPayment(payment models.Gateway) (*models.CurrencyQuote, error) {
+	return p.reserve(payment.QuoteId, func(quote *models.CurrencyQuote) bool {
+		return quote.MerchantId == payment.MerchantId && quote.ToCurrency == payment.Currency &&
+			sameAmount(quote.ConvertedAmount, payment.Amount, quote.ToCurrency)
+	})
+}
+
+// Commit marks a reserved quote as used and ends its reservation. The quote is kept as used for as long as it is
+// kept, so it is never accepted again.
+//
+// Parameters:
+//   - id: the quote id.
+//
+// Returns:
+//   - error: ErrQuoteNotFound if the quote no longer exists, ErrQuoteUsed if it was used while its reservation had
+//     expired, or an error if it cannot be marked as used.
+func (p *quoteService) Commit(id string) error {
+	quote, err := p.getQuote(id)
+	if err != nil {
+		return err
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, quote.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	now := p.now().UTC()
+	expiration := p.config.Retention
+	if expiresAt.After(now) {
+		expiration += expiresAt.Sub(now)
+	}
+
+	marked, err := p.cache.SetNX(usedKey(id), now.Format(time.RFC3339), expiration)
+	if err != nil {
+		return err
+	}
+
+	if !marked {
+		return ErrQuoteUsed
+	}
+
+	_, err = p.cache.Delete(reservationKey(id))
+	return err
+}
+
+// Release ends the reservation of a quote that was not used, so it can be used by a later request.
+// Quotes already used are left untouched.
+//
+// Parameters:
+//   - id: the quote id.
+//
+// Returns:
+//   - error: an error if the reservation cannot be removed.
+func (p *quoteService) Release(id string) error {
+	used, err := p.used(id)
+	if err != nil || used {
+		return err
+	}
+
+	_, err = p.cache.Delete(reservationKey(id))
+	return err
+}
+
+// reserve reserves a quote that has not expired, is not used and matches the request. The quote is reserved with
+// SetNX, so concurrent requests with the same quote, even on different instances, cannot both use it.
+func (p *quoteService) reserve(id string, matches func(quote *models.CurrencyQuote) bool) (*models.CurrencyQuote, error) {
+	quote, err := p.getQuote(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := p.now().UTC()
+	expiresAt, err := time.Parse(time.RFC3339, quote.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if !now.Before(expiresAt) {
+		return nil, ErrQuoteExpired
+	}
+
+	if !matches(quote) {
+		return nil, ErrQuoteMismatch
+	}
+
+	reserved, err := p.cache.SetNX(reservationKey(id), now.Format(time.RFC3339), p.config.Reservation)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reserved {
+		return nil, ErrQuoteInUse
+	}
+
+	// The quote may have been used before it was reserved, so it is checked while holding the reservation.
+	used, err := p.used(id)
+	if err == nil && used {
+		err = ErrQuoteUsed
+	}
+
+	if err != nil {
+		_, releaseErr := p.cache.Delete(reservationKey(id))
+		return nil, errors.Join(err, releaseErr)
+	}
+
+	return quote, nil
+}
+
+// used reports whether a quote was already used.
+func (p *quoteService) used(id string) (bool, error) {
+	_, err := p.cache.Get(usedKey(id))
+	if err == nil {
+		return true, nil
+	}
+	if err.Error() != cache.ErrCacheMiss.Error() {
+		return false, err
+	}
+	return false, nil
+}
+
+// sameAmount reports whether two amounts of a currency are the same once rounded to the minor units of the
+// currency, so amounts are compared as the cents they are charged in rather than as floats.
+func sameAmount(a float64, b float64, code string) bool {
+	minorUnits := 2
+	if currency, ok := iso4217.Get(code); ok {
+		minorUnits = currency.MinorUnits
+	}
+
+	return money.Round(money.Rat(a), minorUnits, money.HalfEven).Cmp(money.Round(money.Rat(b), minorUnits, money.HalfEven)) == 0
+}
+
+func (p *quoteService) getQuote(id string) (*models.CurrencyQuote, error) {
+	c, err := p.cache.Get(quoteKey(id))
+	if err != nil {
+		if err.Error() == cache.ErrCacheMiss.Error() {
+			return nil, ErrQuoteNotFound
+		}
+		return nil, err
+	}
+
+	var quote *models.CurrencyQuote
+	if err := json.Unmarshal(c, &quote); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+func quoteKey(id string) string {
+	return fmt.Sprintf("%s_%s", cache.ExchangeRateQuoteKey, id)
+}
+
+func usedKey(id string) string {
+	return fmt.Sprintf("%s_%s", cache.ExchangeRateQuoteUsedKey, id)
+}
+
+func reservationKey(id string) string {
+	return fmt.Sprintf("%s_%s", cache.ExchangeRateQuoteReservedKey, id)
+}
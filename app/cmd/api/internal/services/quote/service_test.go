@@ -0,0 +1,286 @@
+package quote
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeConverter struct {
+	result *models.CurrencyConvertResponse
+	err    error
+	calls  int
+}
+
+func (c *fakeConverter) ConvertExchangeRate(currency models.CurrencyConvert) (*models.CurrencyConvertResponse, error) {
+	c.calls++
+	return c.result, c.err
+}
+
+func newService(converter Converter, now *time.Time) *quoteService {
+	service := New(cache.NewMemory(), converter, Config{TTL: 15 * time.Minute, Retention: time.Hour, Reservation: time.Minute})
+	service.now = func() time.Time { return *now }
+	return service
+}
+
+func converter() *fakeConverter {
+	return &fakeConverter{result: &models.CurrencyConvertResponse{
+		Amount: 541.75, FromCurrency: "USD", ToCurrency: "BRL", MidRate: "5.5", Rate: "5.4175",
+		SpreadBasisPoints: 150, Markup: 8.25, Rounding: "half_even", Provider: "ecb",
+	}}
+}
+
+var quoteRequest = models.CurrencyQuoteCreate{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL", MerchantId: "merchant-1"}
+
+func payment(id string, amount float64, currency string) models.Gateway {
+	return models.Gateway{Amount: amount, Currency: currency, MerchantId: "merchant-1", QuoteId: id}
+}
+
+func TestCreateQuote(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	service := newService(converter(), &now)
+
+	// Action
+	quote, err := service.CreateQuote(quoteRequest)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, quote.Id)
+	assert.Equal(t, 100.0, quote.Amount)
+	assert.Equal(t, 541.75, quote.ConvertedAmount)
+	assert.Equal(t, "5.4175", quote.Rate)
+	assert.Equal(t, "2024-10-01T12:15:00Z", quote.ExpiresAt)
+
+	stored, err := service.GetQuote(quote.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, quote, stored)
+}
+
+func TestCreateQuote_ConversionFailure(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	service := newService(&fakeConverter{err: errors.New("missing or unavailable currency keys: [BRL]")}, &now)
+
+	// Action
+	quote, err := service.CreateQuote(quoteRequest)
+
+	// Assert
+	assert.EqualError(t, err, "missing or unavailable currency keys: [BRL]")
+	assert.Nil(t, quote)
+}
+
+func TestRedeemForConversion(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	rates := converter()
+	service := newService(rates, &now)
+	quote, _ := service.CreateQuote(quoteRequest)
+	now = now.Add(10 * time.Minute)
+
+	// Action
+	result, err := service.RedeemForConversion(quote.Id, models.CurrencyConvert{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL", MerchantId: "merchant-1", Format: "pt_BR"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 541.75, result.Amount)
+	assert.Equal(t, "5.4175", result.Rate)
+	assert.Equal(t, 8.25, result.Markup)
+	assert.Equal(t, "R$\u00a0541,75", result.Formatted)
+	assert.Equal(t, quote.Id, result.QuoteId)
+	assert.Equal(t, 1, rates.calls)
+
+	stored, err := service.GetQuote(quote.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-10-01T12:10:00Z", stored.UsedAt)
+}
+
+func TestReserveForPayment(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	service := newService(converter(), &now)
+	quote, _ := service.CreateQuote(quoteRequest)
+
+	// Action
+	reserved, err := service.ReserveForPayment(payment(quote.Id, 541.75, "BRL"))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, quote.Id, reserved.Id)
+
+	stored, err := service.GetQuote(quote.Id)
+	assert.NoError(t, err)
+	assert.Empty(t, stored.UsedAt)
+
+	_, err = service.ReserveForPayment(payment(quote.Id, 541.75, "BRL"))
+	assert.ErrorIs(t, err, ErrQuoteInUse)
+}
+
+func TestReserveForPayment_Commit(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	service := newService(converter(), &now)
+	quote, _ := service.CreateQuote(quoteRequest)
+	_, err := service.ReserveForPayment(payment(quote.Id, 541.75, "BRL"))
+	assert.NoError(t, err)
+	now = now.Add(time.Minute)
+
+	// Action
+	commitErr := service.Commit(quote.Id)
+	releaseErr := service.Release(quote.Id)
+
+	// Assert
+	assert.NoError(t, commitErr)
+	assert.NoError(t, releaseErr)
+
+	stored, err := service.GetQuote(quote.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-10-01T12:01:00Z", stored.UsedAt)
+
+	_, err = service.ReserveForPayment(payment(quote.Id, 541.75, "BRL"))
+	assert.ErrorIs(t, err, ErrQuoteUsed)
+}
+
+func TestReserveForPayment_Release(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	service := newService(converter(), &now)
+	quote, _ := service.CreateQuote(quoteRequest)
+	_, err := service.ReserveForPayment(payment(quote.Id, 541.75, "BRL"))
+	assert.NoError(t, err)
+
+	// Action
+	releaseErr := service.Release(quote.Id)
+	reserved, err := service.ReserveForPayment(payment(quote.Id, 541.75, "BRL"))
+
+	// Assert
+	assert.NoError(t, releaseErr)
+	assert.NoError(t, err)
+	assert.Equal(t, quote.Id, reserved.Id)
+
+	stored, err := service.GetQuote(quote.Id)
+	assert.NoError(t, err)
+	assert.Empty(t, stored.UsedAt)
+}
+
+func TestRedeem_Rejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		redeem      func(service *quoteService, id string, now *time.Time) error
+		expectedErr error
+	}{
+		{"Unknown quote", func(service *quoteService, id string, now *time.Time) error {
+			_, err := service.ReserveForPayment(payment("unknown", 541.75, "BRL"))
+			return err
+		}, ErrQuoteNotFound},
+		{"Expired quote", func(service *quoteService, id string, now *time.Time) error {
+			*now = now.Add(15 * time.Minute)
+			_, err := service.ReserveForPayment(payment(id, 541.75, "BRL"))
+			return err
+		}, ErrQuoteExpired},
+		{"Used quote", func(service *quoteService, id string, now *time.Time) error {
+			if _, err := service.ReserveForPayment(payment(id, 541.75, "BRL")); err != nil {
+				return err
+			}
+			if err := service.Commit(id); err != nil {
+				return err
+			}
+			_, err := service.RedeemForConversion(id, models.CurrencyConvert{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL", MerchantId: "merchant-1"})
+			return err
+		}, ErrQuoteUsed},
+		{"Reserved quote", func(service *quoteService, id string, now *time.Time) error {
+			if _, err := service.ReserveForPayment(payment(id, 541.75, "BRL")); err != nil {
+				return err
+			}
+			_, err := service.RedeemForConversion(id, models.CurrencyConvert{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL", MerchantId: "merchant-1"})
+			return err
+		}, ErrQuoteInUse},
+		{"Payment amount does not match", func(service *quoteService, id string, now *time.Time) error {
+			_, err := service.ReserveForPayment(payment(id, 541.74, "BRL"))
+			return err
+		}, ErrQuoteMismatch},
+		{"Payment merchant does not match", func(service *quoteService, id string, now *time.Time) error {
+			other := payment(id, 541.75, "BRL")
+			other.MerchantId = "merchant-2"
+			_, err := service.ReserveForPayment(other)
+			return err
+		}, ErrQuoteMismatch},
+		{"Conversion merchant does not match", func(service *quoteService, id string, now *time.Time) error {
+			_, err := service.RedeemForConversion(id, models.CurrencyConvert{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL"})
+			return err
+		}, ErrQuoteMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+			service := newService(converter(), &now)
+			quote, _ := service.CreateQuote(quoteRequest)
+
+			// Action
+			err := tt.redeem(service, quote.Id, &now)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestRedeem_MismatchDoesNotReserveQuote(t *testing.T) {
+	// Arrange
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	service := newService(converter(), &now)
+	quote, _ := service.CreateQuote(quoteRequest)
+	_, mismatchErr := service.ReserveForPayment(payment(quote.Id, 541.75, "USD"))
+
+	// Action
+	reserved, err := service.ReserveForPayment(payment(quote.Id, 541.75, "BRL"))
+
+	// Assert
+	assert.ErrorIs(t, mismatchErr, ErrQuoteMismatch)
+	assert.NoError(t, err)
+	assert.Equal(t, quote.Id, reserved.Id)
+}
+
+func TestSameAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        float64
+		b        float64
+		currency string
+		expected bool
+	}{
+		{"Sum of floats", 0.1 + 0.2, 0.3, "USD", true},
+		{"Same cents", 541.75, 541.7500001, "BRL", true},
+		{"Different cents", 541.75, 541.74, "BRL", false},
+		{"Currency without minor units", 1000, 1000.4, "JPY", true},
+		{"Currency with three minor units", 1.001, 1.002, "KWD", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Action
+			result := sameAmount(tt.a, tt.b, tt.currency)
+
+			// Assert
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Arrange
+	t.Setenv("EXCHANGE_RATE_QUOTE_TTL", "30s")
+	t.Setenv("EXCHANGE_RATE_QUOTE_RETENTION", "invalid")
+
+	// Action
+	config := LoadConfig()
+
+	// Assert
+	assert.Equal(t, Config{TTL: 30 * time.Second, Retention: 24 * time.Hour, Reservation: 2 * time.Minute}, config)
+}
@@ -0,0 +1,91 @@
+package risk
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	VelocityWindow        time.Duration
+	CardVelocityLimit     int64
+	IpVelocityLimit       int64
+	CustomerVelocityLimit int64
+	AmountThresholds      map[string]float64
+	BlockedCountries      map[string]bool
+	BlockedBins           map[string]bool
+	BinCountries          map[string]string
+	ReviewScore           int
+	BlockScore            int
+}
+
+// LoadConfig loads the risk rules configuration from the environment variables.
+//
+// Environment Variables:
+//   - RISK_VELOCITY_WINDOW: the window used to count payment attempts, e.g. "1h".
+//   - RISK_VELOCITY_CARD_LIMIT: the maximum attempts per card in the window.
+//   - RISK_VELOCITY_IP_LIMIT: the maximum attempts per IP address in the window.
+//   - RISK_VELOCITY_CUSTOMER_LIMIT: the maximum attempts per customer in the window.
+//   - RISK_AMOUNT_THRESHOLDS: the amount thresholds per currency, e.g. "USD:5000,BRL:25000".
+//   - RISK_BLOCKED_COUNTRIES: the blocked ISO 3166 country codes, e.g. "KP,IR".
+//   - RISK_BLOCKED_BINS: the blocked card BINs, e.g. "400000,510510".
+//   - RISK_BIN_COUNTRIES: the issuing country of known card BINs, e.g. "424242:US".
+//   - RISK_REVIEW_SCORE: the minimum score to send a payment to review.
+//   - RISK_BLOCK_SCORE: the minimum score to block a payment.
+//
+// Returns:
+//   - Config: the risk rules configuration.
+func LoadConfig() Config {
+	return Config{
+		VelocityWindow:        utils.GetEnvDuration("RISK_VELOCITY_WINDOW", time.Hour),
+		CardVelocityLimit:     int64(utils.GetEnvInt("RISK_VELOCITY_CARD_LIMIT", 5)),
+		IpVelocityLimit:       int64(utils.GetEnvInt("RISK_VELOCITY_IP_LIMIT", 10)),
+		CustomerVelocityLimit: int64(utils.GetEnvInt("RISK_VELOCITY_CUSTOMER_LIMIT", 10)),
+		AmountThresholds:      getAmounts("RISK_AMOUNT_THRESHOLDS", "USD:5000,EUR:5000,BRL:25000"),
+		BlockedCountries:      getSet("RISK_BLOCKED_COUNTRIES"),
+		BlockedBins:           getSet("RISK_BLOCKED_BINS"),
+		BinCountries:          getPairs("RISK_BIN_COUNTRIES"),
+		ReviewScore:           utils.GetEnvInt("RISK_REVIEW_SCORE", 50),
+		BlockScore:            utils.GetEnvInt("RISK_BLOCK_SCORE", 80),
+	}
+}
+
+func getSet(key string) map[string]bool {
+	set := map[string]bool{}
+	for _, value := range utils.GetEnvList(key, nil) {
+		set[strings.ToUpper(value)] = true
+	}
+	return set
+}
+
+func getPairs(key string) map[string]string {
+	pairs := map[string]string{}
+	for bin, country := range parsePairs(os.Getenv(key)) {
+		pairs[bin] = strings.ToUpper(country)
+	}
+	return pairs
+}
+
+func getAmounts(key string, valueDefault string) map[string]float64 {
+	amounts := map[string]float64{}
+	for currency, amount := range parsePairs(utils.GetEnvString(key, valueDefault)) {
+		if threshold, err := strconv.ParseFloat(amount, 64); err == nil {
+			amounts[currency] = threshold
+		}
+	}
+	return amounts
+}
+
+func parsePairs(value string) map[string]string {
+	pairs := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.Split(pair, ":")
+		if len(parts) == 2 && !utils.IsEmptyOrNull(parts[0]) && !utils.IsEmptyOrNull(parts[1]) {
+			pairs[strings.ToUpper(strings.TrimSpace(parts[0]))] = strings.TrimSpace(parts[1])
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,169 @@
+package risk
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+const (
+	velocityCardScore       = 40
+	velocityIpScore         = 30
+	velocityCustomerScore   = 30
+	amountThresholdScore    = 30
+	binCountryMismatchScore = 25
+	ipCountryMismatchScore  = 20
+	blockedScore            = 100
+)
+
+type RiskService interface {
+	Assess(payment models.Gateway, ip string, ipCountry string) (*models.RiskAssessment, error)
+}
+
+type riskService struct {
+	cache             cache.CacheClient
+	config            Config
+	fingerprintSecret []byte
+}
+
+// New creates a new instance of riskService with the provided cache client and rules configuration.
+// It returns a pointer to the newly created riskService.
+//
+// Parameters:
+//   - cache: an instance of cache.CacheClient used to count payment attempts.
+//   - config: the risk rules configuration.
+//   - fingerprintSecret: the secret the counted values are fingerprinted with.
+//
+// Returns:
+//   - *riskService: a pointer to the initialized riskService.
+func New(cache cache.CacheClient, config Config, fingerprintSecret []byte) *riskService {
+	return &riskService{
+		cache:             cache,
+		config:            config,
+		fingerprintSecret: fingerprintSecret,
+	}
+}
+
+// Assess screens a payment before it is sent to the provider and returns its risk assessment.
+// Each rule that matches adds to the risk score and records the reason, and the final score
+// decides whether the payment is allowed, sent to review or blocked.
+//
+// The following rules are applied:
+// 1. Blocklists: the card BIN, its issuing country, the billing country and the IP country.
+// 2. Velocity: payment attempts per card, IP address and customer in the configured window.
+// 3. Amount: the amount threshold configured for the payment currency.
+// 4. Mismatch: the billing country against the card issuing country and the IP country.
+//
+// Parameters:
+//   - payment: the payment to be screened.
+//   - ip: the IP address of the client that requested the payment.
+//   - ipCountry: the country of the client IP address, if known.
+//
+// Returns:
+//   - *models.RiskAssessment: the score, decision and reasons of the assessment.
+//   - error: an error if the payment attempts could not be counted.
+func (p *riskService) Assess(payment models.Gateway, ip string, ipCountry string) (*models.RiskAssessment, error) {
+	assessment := &models.RiskAssessment{Reasons: []string{}}
+
+	bin := getBin(payment.CardDetails.Number)
+	binCountry := p.config.BinCountries[bin]
+	billingCountry := strings.ToUpper(payment.Country)
+	ipCountry = strings.ToUpper(ipCountry)
+
+	if p.config.BlockedBins[bin] {
+		add(assessment, blockedScore, fmt.Sprintf("card BIN %s is blocked", bin))
+	}
+
+	for _, country := range []string{binCountry, billingCountry, ipCountry} {
+		if p.config.BlockedCountries[country] {
+			add(assessment, blockedScore, fmt.Sprintf("country %s is blocked", country))
+			break
+		}
+	}
+
+	velocities := []struct {
+		dimension string
+		value     string
+		limit     int64
+		score     int
+	}{
+		{"card", payment.CardDetails.Number, p.config.CardVelocityLimit, velocityCardScore},
+		{"ip", ip, p.config.IpVelocityLimit, velocityIpScore},
+		{"customer", payment.CustomerId, p.config.CustomerVelocityLimit, velocityCustomerScore},
+	}
+
+	for _, velocity := range velocities {
+		if utils.IsEmptyOrNull(velocity.value) {
+			continue
+		}
+
+		attempts, err := p.countAttempt(velocity.dimension, velocity.value)
+		if err != nil {
+			return nil, err
+		}
+
+		if attempts > velocity.limit {
+			add(assessment, velocity.score, fmt.Sprintf("%s velocity exceeded: %d attempts in %s", velocity.dimension, attempts, p.config.VelocityWindow))
+		}
+	}
+
+	if threshold, exists := p.config.AmountThresholds[strings.ToUpper(payment.Currency)]; exists && payment.Amount > threshold {
+		add(assessment, amountThresholdScore, fmt.Sprintf("amount %.2f %s exceeds threshold %.2f", payment.Amount, payment.Currency, threshold))
+	}
+
+	if !utils.IsEmptyOrNull(billingCountry) {
+		if !utils.IsEmptyOrNull(binCountry) && binCountry != billingCountry {
+			add(assessment, binCountryMismatchScore, fmt.Sprintf("billing country %s does not match card country %s", billingCountry, binCountry))
+		}
+
+		if !utils.IsEmptyOrNull(ipCountry) && ipCountry != billingCountry {
+			add(assessment, ipCountryMismatchScore, fmt.Sprintf("billing country %s does not match IP country %s", billingCountry, ipCountry))
+		}
+	}
+
+	assessment.Decision = p.decide(assessment.Score)
+	return assessment, nil
+}
+
+// countAttempt increments the payment attempts of the given dimension in the current
+// velocity window and returns the number of attempts so far. The value is fingerprinted
+// with the server secret so card numbers are never stored in, nor recoverable from, the cache.
+func (p *riskService) countAttempt(dimension, value string) (int64, error) {
+	window := time.Now().UnixNano() / int64(p.config.VelocityWindow)
+	key := fmt.Sprintf("%s_%s_%s_%d", cache.RiskVelocityKey, dimension, utils.Fingerprint(p.fingerprintSecret, value), window)
+
+	return p.cache.Increment(key, p.config.VelocityWindow)
+}
+
+// decide returns the decision for the given risk score.
+func (p *riskService) decide(score int) models.RiskDecision {
+	switch {
+	case score >= p.config.BlockScore:
+		return models.RiskBlock
+	case score >= p.config.ReviewScore:
+		return models.RiskReview
+	default:
+		return models.RiskAllow
+	}
+}
+
+// add adds the score of a matched rule to the assessment, capped at 100, and records its reason.
+func add(assessment *models.RiskAssessment, score int, reason string) {
+	assessment.Score += score
+	if assessment.Score > 100 {
+		assessment.Score = 100
+	}
+	assessment.Reasons = append(assessment.Reasons, reason)
+}
+
+// getBin returns the bank identification number, the first six digits of the card number.
+func getBin(cardNumber string) string {
+	if len(cardNumber) < 6 {
+		return cardNumber
+	}
+	return cardNumber[:6]
+}
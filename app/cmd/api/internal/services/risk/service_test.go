@@ -0,0 +1,167 @@
+package risk
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingCache struct {
+	cache.CacheClient
+}
+
+func (c failingCache) Increment(key string, expiration time.Duration) (int64, error) {
+	return 0, errors.New("cache error")
+}
+
+func testConfig() Config {
+	return Config{
+		VelocityWindow:        time.Hour,
+		CardVelocityLimit:     5,
+		IpVelocityLimit:       10,
+		CustomerVelocityLimit: 10,
+		AmountThresholds:      map[string]float64{"USD": 5000},
+		BlockedCountries:      map[string]bool{"KP": true},
+		BlockedBins:           map[string]bool{"400000": true},
+		BinCountries:          map[string]string{"424242": "US"},
+		ReviewScore:           50,
+		BlockScore:            80,
+	}
+}
+
+func testPayment() models.Gateway {
+	return models.Gateway{
+		Amount:   100,
+		Currency: "USD",
+		Country:  "US",
+		CardDetails: models.CardDetails{
+			Number: "4242424242424242",
+		},
+	}
+}
+
+func TestAssess_Allow(t *testing.T) {
+	// Arrange
+	service := New(cache.NewMemory(), testConfig(), []byte("test-secret"))
+
+	// Action
+	assessment, err := service.Assess(testPayment(), "10.0.0.1", "US")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.RiskAllow, assessment.Decision)
+	assert.Equal(t, 0, assessment.Score)
+	assert.Empty(t, assessment.Reasons)
+}
+
+func TestAssess_ReviewOnVelocityAndAmount(t *testing.T) {
+	// Arrange
+	service := New(cache.NewMemory(), testConfig(), []byte("test-secret"))
+	for i := 0; i < 5; i++ {
+		_, err := service.Assess(testPayment(), "10.0.0.1", "")
+		assert.NoError(t, err)
+	}
+
+	payment := testPayment()
+	payment.Amount = 6000
+
+	// Action
+	assessment, err := service.Assess(payment, "10.0.0.1", "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.RiskReview, assessment.Decision)
+	assert.Equal(t, velocityCardScore+amountThresholdScore, assessment.Score)
+	assert.Len(t, assessment.Reasons, 2)
+}
+
+func TestAssess_BlockOnBlockedCountry(t *testing.T) {
+	// Arrange
+	service := New(cache.NewMemory(), testConfig(), []byte("test-secret"))
+
+	// Action
+	assessment, err := service.Assess(testPayment(), "10.0.0.1", "kp")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.RiskBlock, assessment.Decision)
+	assert.Equal(t, 100, assessment.Score)
+	assert.Contains(t, assessment.Reasons, "country KP is blocked")
+	assert.Contains(t, assessment.Reasons, "billing country US does not match IP country KP")
+}
+
+func TestAssess_BlockOnBlockedBin(t *testing.T) {
+	// Arrange
+	service := New(cache.NewMemory(), testConfig(), []byte("test-secret"))
+
+	payment := testPayment()
+	payment.CardDetails.Number = "4000000000000002"
+
+	// Action
+	assessment, err := service.Assess(payment, "10.0.0.1", "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.RiskBlock, assessment.Decision)
+	assert.Contains(t, assessment.Reasons, "card BIN 400000 is blocked")
+}
+
+func TestAssess_CardCountryMismatch(t *testing.T) {
+	// Arrange
+	service := New(cache.NewMemory(), testConfig(), []byte("test-secret"))
+
+	payment := testPayment()
+	payment.Country = "BR"
+	payment.CustomerId = "customer1"
+
+	// Action
+	assessment, err := service.Assess(payment, "10.0.0.1", "BR")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, models.RiskAllow, assessment.Decision)
+	assert.Equal(t, binCountryMismatchScore, assessment.Score)
+	assert.Equal(t, []string{"billing country BR does not match card country US"}, assessment.Reasons)
+}
+
+func TestAssess_Failure_Increment(t *testing.T) {
+	// Arrange
+	service := New(failingCache{cache.NewMemory()}, testConfig(), []byte("test-secret"))
+
+	// Action
+	assessment, err := service.Assess(testPayment(), "10.0.0.1", "")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, assessment)
+}
+
+func TestLoadConfig(t *testing.T) {
+	// Arrange
+	os.Setenv("RISK_VELOCITY_WINDOW", "30m")
+	os.Setenv("RISK_AMOUNT_THRESHOLDS", "usd:100, BRL:500")
+	os.Setenv("RISK_BLOCKED_COUNTRIES", "kp, IR")
+	os.Setenv("RISK_BIN_COUNTRIES", "424242:us")
+	defer os.Unsetenv("RISK_VELOCITY_WINDOW")
+	defer os.Unsetenv("RISK_AMOUNT_THRESHOLDS")
+	defer os.Unsetenv("RISK_BLOCKED_COUNTRIES")
+	defer os.Unsetenv("RISK_BIN_COUNTRIES")
+
+	// Action
+	config := LoadConfig()
+
+	// Assert
+	assert.Equal(t, 30*time.Minute, config.VelocityWindow)
+	assert.Equal(t, int64(5), config.CardVelocityLimit)
+	assert.Equal(t, map[string]float64{"USD": 100, "BRL": 500}, config.AmountThresholds)
+	assert.Equal(t, map[string]bool{"KP": true, "IR": true}, config.BlockedCountries)
+	assert.Equal(t, map[string]string{"424242": "US"}, config.BinCountries)
+	assert.Empty(t, config.BlockedBins)
+	assert.Equal(t, 50, config.ReviewScore)
+	assert.Equal(t, 80, config.BlockScore)
+}
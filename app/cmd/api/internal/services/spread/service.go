@@ -0,0 +1,341 @@
+package spread
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+var (
+	ErrSpreadNotFound = errors.New("spread not found")
+	ErrSpreadConflict = errors.New("a spread already exists for this merchant and currency pair")
+	ErrSpreadLocked   = errors.New("the spread is being changed by another request, please try again")
+	ErrIncompletePair = errors.New("from_currency and to_currency must be set together")
+)
+
+// lockTTL bounds how long a spread stays locked by a change whose instance stopped before releasing it.
+const lockTTL = 10 * time.Second
+
+type SpreadService interface {
+	CreateSpread(spread models.SpreadCreate, origin audit.Origin) (*models.Spread, error)
+	GetAllSpreads() ([]models.Spread, error)
+	UpdateSpread(id string, update models.SpreadUpdate, origin audit.Origin) (*models.Spread, error)
+	DeleteSpread(id string, origin audit.Origin) error
+	GetHistory(id string) ([]audit.Entry, error)
+	Resolve(merchantId string, fromCurrency string, toCurrency string) (*models.Spread, error)
+}
+
+type spreadService struct {
+	cache   cache.CacheClient
+	auditor audit.AuditService
+	now     func() time.Time
+}
+
+// New creates a new instance of spreadService with the provided cache client and audit service.
+// It returns a pointer to the newly created spreadService.
+//
+// Parameters:
+//   - cache: an instance of cache.CacheClient where the spreads are stored.
+//   - auditor: an instance of audit.AuditService that records every change of the spreads.
+//
+// Returns:
+//   - *spreadService: a pointer to the initialized spreadService.
+func New(cache cache.CacheClient, auditor audit.AuditService) *spreadService {
+	return &spreadService{
+		cache:   cache,
+		auditor: auditor,
+		now:     time.Now,
+	}
+}
+
+// CreateSpread configures the spread of a scope: a merchant, a currency pair, both, or neither for the default spread.
+// Each scope has at most one spread, claimed with SetNX, so concurrent requests for the same scope cannot both
+// create a spread, even on different instances. The spread is removed and its scope freed if the change cannot be
+// audited, so a spread is never stored without its audit trail.
+//
+// Parameters:
+//   - spread: the merchant, the currency pair and the basis points of the spread.
+//   - origin: the actor and correlation ID responsible for the change.
+//
+// Returns:
+//   - *models.Spread: the created spread.
+//   - error: ErrIncompletePair if only one currency of the pair is set, ErrSpreadConflict if the scope already
+//     has a spread, or an error if the spread cannot be stored or audited.
+func (p *spreadService) CreateSpread(spread models.SpreadCreate, origin audit.Origin) (*models.Spread, error) {
+	if (spread.FromCurrency == "") != (spread.ToCurrency == "") {
+		return nil, ErrIncompletePair
+	}
+
+	now := p.now().UTC().Format(time.RFC3339)
+	created := models.Spread{
+		Id:           utils.GenerateGUID(),
+		MerchantId:   spread.MerchantId,
+		FromCurrency: spread.FromCurrency,
+		ToCurrency:   spread.ToCurrency,
+		BasisPoints:  *spread.BasisPoints,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	claimed, err := p.cache.SetNX(scopeKey(created), created.Id, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if !claimed {
+		return nil, ErrSpreadConflict
+	}
+
+	if err := p.setSpread(created); err != nil {
+		_, releaseErr := p.cache.Delete(scopeKey(created))
+		return nil, errors.Join(err, releaseErr)
+	}
+
+	if err := p.auditor.Record(created.Id, audit.ActionSpreadCreated, origin, nil, created); err != nil {
+		_, removeErr := p.cache.HDelete(cache.ExchangeRateSpreadsKey, created.Id)
+		_, releaseErr := p.cache.Delete(scopeKey(created))
+		return nil, errors.Join(err, removeErr, releaseErr)
+	}
+
+	return &created, nil
+}
+
+// GetAllSpreads retrieves all configured spreads, sorted by creation date.
+//
+// Returns:
+//   - []models.Spread: the configured spreads.
+//   - error: an error if the spreads cannot be retrieved.
+func (p *spreadService) GetAllSpreads() ([]models.Spread, error) {
+	result, err := p.getSpreads()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CreatedAt == result[j].CreatedAt {
+			return result[i].Id < result[j].Id
+		}
+		return result[i].CreatedAt < result[j].CreatedAt
+	})
+
+	return result, nil
+}
+
+// UpdateSpread changes the basis points of a spread. The scope of a spread cannot be changed.
+// The spread is locked while it is changed, so a concurrent change or removal of the same spread is rejected, and
+// the spread is restored if the change cannot be audited.
+//
+// Parameters:
+//   - id: the spread id.
+//   - update: the new basis points of the spread.
+//   - origin: the actor and correlation ID responsible for the change.
+//
+// Returns:
+//   - *models.Spread: the updated spread.
+//   - error: ErrSpreadNotFound if the spread does not exist, ErrSpreadLocked if it is being changed,
+//     or an error if it cannot be stored or audited.
+func (p *spreadService) UpdateSpread(id string, update models.SpreadUpdate, origin audit.Origin) (result *models.Spread, err error) {
+	if err := p.lock(id); err != nil {
+		return nil, err
+	}
+	defer func() { err = errors.Join(err, p.unlock(id)) }()
+
+	before, err := p.getSpread(id)
+	if err != nil {
+		return nil, err
+	}
+
+	after := *before
+	after.BasisPoints = *update.BasisPoints
+	after.UpdatedAt = p.now().UTC().Format(time.RFC3339)
+
+	if err := p.setSpread(after); err != nil {
+		return nil, err
+	}
+
+	if err := p.auditor.Record(id, audit.ActionSpreadUpdated, origin, before, after); err != nil {
+		return nil, errors.Join(err, p.setSpread(*before))
+	}
+
+	return &after, nil
+}
+
+// DeleteSpread removes a spread and frees its scope. Its audit trail is kept.
+// The spread is locked while it is removed, so a concurrent change of the same spread is rejected, and the spread
+// is restored if its removal cannot be audited.
+//
+// Parameters:
+//   - id: the spread id.
+//   - origin: the actor and correlation ID responsible for the change.
+//
+// Returns:
+//   - error: ErrSpreadNotFound if the spread does not exist, ErrSpreadLocked if it is being changed,
+//     or an error if it cannot be removed or audited.
+func (p *spreadService) DeleteSpread(id string, origin audit.Origin) (err error) {
+	if err := p.lock(id); err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, p.unlock(id)) }()
+
+	before, err := p.getSpread(id)
+	if err != nil {
+		return err
+	}
+
+	removed, err := p.cache.HDelete(cache.ExchangeRateSpreadsKey, id)
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		return ErrSpreadNotFound
+	}
+
+	if _, err := p.cache.Delete(scopeKey(*before)); err != nil {
+		return errors.Join(err, p.setSpread(*before))
+	}
+
+	if err := p.auditor.Record(id, audit.ActionSpreadDeleted, origin, before, nil); err != nil {
+		_, claimErr := p.cache.SetNX(scopeKey(*before), id, 0)
+		return errors.Join(err, claimErr, p.setSpread(*before))
+	}
+
+	return nil
+}
+
+// GetHistory retrieves every recorded change of a spread, oldest first, including the changes of deleted spreads.
+//
+// Parameters:
+//   - id: the spread id.
+//
+// Returns:
+//   - []audit.Entry: the entries of the audit trail of the spread.
+//   - error: ErrSpreadNotFound if the spread never existed, or an error if the entries cannot be read.
+func (p *spreadService) GetHistory(id string) ([]audit.Entry, error) {
+	entries, err := p.auditor.GetHistory(id)
+	if errors.Is(err, audit.ErrHistoryNotFound) {
+		return nil, ErrSpreadNotFound
+	}
+
+	return entries, err
+}
+
+// Resolve finds the spread that applies to a conversion. The most specific spread wins: the spread of the merchant
+// and the pair, then the spread of the merchant, then the spread of the pair and finally the default spread.
+//
+// Parameters:
+//   - merchantId: the merchant the conversion is quoted to, or empty for none.
+//   - fromCurrency: the original currency of the conversion.
+//   - toCurrency: the target currency of the conversion.
+//
+// Returns:
+//   - *models.Spread: the spread that applies, or nil if no spread applies.
+//   - error: an error if the spreads cannot be retrieved.
+func (p *spreadService) Resolve(merchantId string, fromCurrency string, toCurrency string) (*models.Spread, error) {
+	spreads, err := p.getSpreads()
+	if err != nil {
+		return nil, err
+	}
+
+	var result *models.Spread
+	best := -1
+	for _, spread := range spreads {
+		specificity := 0
+
+		if spread.MerchantId != "" {
+			if spread.MerchantId != merchantId {
+				continue
+			}
+			specificity += 2
+		}
+
+		if spread.FromCurrency != "" {
+			if spread.FromCurrency != fromCurrency || spread.ToCurrency != toCurrency {
+				continue
+			}
+			specificity++
+		}
+
+		if specificity > best {
+			spread := spread
+			result, best = &spread, specificity
+		}
+	}
+
+	return result, nil
+}
+
+func (p *spreadService) lock(id string) error {
+	locked, err := p.cache.SetNX(lockKey(id), p.now().UTC().Format(time.RFC3339), lockTTL)
+	if err != nil {
+		return err
+	}
+
+	if !locked {
+		return ErrSpreadLocked
+	}
+
+	return nil
+}
+
+func (p *spreadService) unlock(id string) error {
+	_, err := p.cache.Delete(lockKey(id))
+	return err
+}
+
+func (p *spreadService) getSpread(id string) (*models.Spread, error) {
+	c, err := p.cache.HGet(cache.ExchangeRateSpreadsKey, id)
+	if err != nil {
+		if err.Error() == cache.ErrCacheMiss.Error() {
+			return nil, ErrSpreadNotFound
+		}
+		return nil, err
+	}
+
+	var spread models.Spread
+	if err := json.Unmarshal(c, &spread); err != nil {
+		return nil, err
+	}
+
+	return &spread, nil
+}
+
+func (p *spreadService) getSpreads() ([]models.Spread, error) {
+	values, err := p.cache.HGetAll(cache.ExchangeRateSpreadsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	spreads := make([]models.Spread, 0, len(values))
+	for _, value := range values {
+		var spread models.Spread
+		if err := json.Unmarshal(value, &spread); err != nil {
+			return nil, err
+		}
+		spreads = append(spreads, spread)
+	}
+
+	return spreads, nil
+}
+
+func (p *spreadService) setSpread(spread models.Spread) error {
+	return p.cache.HSet(cache.ExchangeRateSpreadsKey, spread.Id, utils.ToJSON(spread))
+}
+
+// scopeKey returns the cache key claimed by the spread of a scope. The currencies come first because they are
+// either both empty or both three letters long, so a merchant ID cannot make two scopes share a key.
+func scopeKey(spread models.Spread) string {
+	return fmt.Sprintf("%s_%s_%s_%s", cache.ExchangeRateSpreadScopeKey, spread.FromCurrency, spread.ToCurrency, spread.MerchantId)
+}
+
+// lockKey returns the cache key locking a spread while it is changed.
+func lockKey(id string) string {
+	return fmt.Sprintf("%s_%s", cache.ExchangeRateSpreadLockKey, id)
+}
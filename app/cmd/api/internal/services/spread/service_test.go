@@ -0,0 +1,255 @@
+package spread
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingAuditor fails to record the changes of the spreads once failing is set.
+type failingAuditor struct {
+	audit.AuditService
+	failing bool
+}
+
+func (a *failingAuditor) Record(transactionId string, action string, origin audit.Origin, before interface{}, after interface{}) error {
+	if a.failing {
+		return errors.New("audit error")
+	}
+	return a.AuditService.Record(transactionId, action, origin, before, after)
+}
+
+func newService() *spreadService {
+	memory := cache.NewMemory()
+	service := New(memory, audit.NewLog(memory, cache.SpreadAuditLogKey))
+	service.now = func() time.Time { return time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC) }
+	return service
+}
+
+func basisPoints(value int) *int {
+	return &value
+}
+
+func TestCreateSpread(t *testing.T) {
+	// Arrange
+	service := newService()
+	origin := audit.Admin("ops", "correlation-1")
+
+	// Action
+	spread, err := service.CreateSpread(models.SpreadCreate{MerchantId: "merchant-1", FromCurrency: "USD", ToCurrency: "BRL", BasisPoints: basisPoints(150)}, origin)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, spread.Id)
+	assert.Equal(t, 150, spread.BasisPoints)
+	assert.Equal(t, "2024-10-01T12:00:00Z", spread.CreatedAt)
+
+	spreads, err := service.GetAllSpreads()
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Spread{*spread}, spreads)
+
+	history, err := service.GetHistory(spread.Id)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, audit.ActionSpreadCreated, history[0].Action)
+	assert.Equal(t, audit.Actor{Type: audit.ActorAdmin, Id: "ops"}, history[0].Actor)
+	assert.Equal(t, "correlation-1", history[0].CorrelationId)
+
+	_, err = audit.New(service.cache).GetHistory(spread.Id)
+	assert.ErrorIs(t, err, audit.ErrHistoryNotFound)
+}
+
+func TestCreateSpread_Failure(t *testing.T) {
+	tests := []struct {
+		name        string
+		spread      models.SpreadCreate
+		expectedErr error
+	}{
+		{"Same scope", models.SpreadCreate{MerchantId: "merchant-1", BasisPoints: basisPoints(50)}, ErrSpreadConflict},
+		{"Incomplete pair", models.SpreadCreate{FromCurrency: "USD", BasisPoints: basisPoints(50)}, ErrIncompletePair},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := newService()
+			_, err := service.CreateSpread(models.SpreadCreate{MerchantId: "merchant-1", BasisPoints: basisPoints(100)}, audit.ApiRequest("correlation-1"))
+			assert.NoError(t, err)
+
+			// Action
+			spread, err := service.CreateSpread(tt.spread, audit.ApiRequest("correlation-2"))
+
+			// Assert
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Nil(t, spread)
+		})
+	}
+}
+
+func TestUpdateAndDeleteSpread(t *testing.T) {
+	// Arrange
+	service := newService()
+	created, _ := service.CreateSpread(models.SpreadCreate{BasisPoints: basisPoints(100)}, audit.ApiRequest("correlation-1"))
+
+	// Action
+	updated, updateErr := service.UpdateSpread(created.Id, models.SpreadUpdate{BasisPoints: basisPoints(80)}, audit.ApiRequest("correlation-2"))
+	deleteErr := service.DeleteSpread(created.Id, audit.ApiRequest("correlation-3"))
+
+	// Assert
+	assert.NoError(t, updateErr)
+	assert.Equal(t, 80, updated.BasisPoints)
+	assert.NoError(t, deleteErr)
+
+	spreads, err := service.GetAllSpreads()
+	assert.NoError(t, err)
+	assert.Empty(t, spreads)
+
+	history, err := service.GetHistory(created.Id)
+	assert.NoError(t, err)
+	assert.Len(t, history, 3)
+	assert.Equal(t, audit.ActionSpreadUpdated, history[1].Action)
+	assert.JSONEq(t, `{"id":"`+created.Id+`","basis_points":100,"created_at":"2024-10-01T12:00:00Z","updated_at":"2024-10-01T12:00:00Z"}`, string(history[1].Before))
+	assert.Equal(t, audit.ActionSpreadDeleted, history[2].Action)
+	assert.Equal(t, "null", string(history[2].After))
+}
+
+func TestDeleteSpread_FreesScope(t *testing.T) {
+	// Arrange
+	service := newService()
+	scope := models.SpreadCreate{MerchantId: "merchant-1", FromCurrency: "USD", ToCurrency: "BRL", BasisPoints: basisPoints(100)}
+	created, _ := service.CreateSpread(scope, audit.ApiRequest("correlation-1"))
+	assert.NoError(t, service.DeleteSpread(created.Id, audit.ApiRequest("correlation-2")))
+
+	// Action
+	recreated, err := service.CreateSpread(scope, audit.ApiRequest("correlation-3"))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEqual(t, created.Id, recreated.Id)
+}
+
+func TestSpread_AuditFailure(t *testing.T) {
+	// Arrange
+	service := newService()
+	auditor := &failingAuditor{AuditService: service.auditor}
+	service.auditor = auditor
+	scope := models.SpreadCreate{MerchantId: "merchant-1", FromCurrency: "USD", ToCurrency: "BRL", BasisPoints: basisPoints(100)}
+	existing, _ := service.CreateSpread(models.SpreadCreate{BasisPoints: basisPoints(50)}, audit.ApiRequest("correlation-1"))
+	auditor.failing = true
+
+	// Action
+	created, createErr := service.CreateSpread(scope, audit.ApiRequest("correlation-2"))
+	updated, updateErr := service.UpdateSpread(existing.Id, models.SpreadUpdate{BasisPoints: basisPoints(80)}, audit.ApiRequest("correlation-3"))
+	deleteErr := service.DeleteSpread(existing.Id, audit.ApiRequest("correlation-4"))
+
+	// Assert
+	assert.EqualError(t, createErr, "audit error")
+	assert.Nil(t, created)
+	assert.EqualError(t, updateErr, "audit error")
+	assert.Nil(t, updated)
+	assert.EqualError(t, deleteErr, "audit error")
+
+	spreads, err := service.GetAllSpreads()
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Spread{*existing}, spreads)
+
+	auditor.failing = false
+	_, err = service.CreateSpread(scope, audit.ApiRequest("correlation-5"))
+	assert.NoError(t, err)
+	_, err = service.CreateSpread(models.SpreadCreate{BasisPoints: basisPoints(60)}, audit.ApiRequest("correlation-6"))
+	assert.ErrorIs(t, err, ErrSpreadConflict)
+}
+
+func TestSpread_Locked(t *testing.T) {
+	// Arrange
+	service := newService()
+	created, _ := service.CreateSpread(models.SpreadCreate{BasisPoints: basisPoints(100)}, audit.ApiRequest("correlation-1"))
+	_, err := service.cache.SetNX(lockKey(created.Id), "2024-10-01T12:00:00Z", lockTTL)
+	assert.NoError(t, err)
+
+	// Action
+	updated, updateErr := service.UpdateSpread(created.Id, models.SpreadUpdate{BasisPoints: basisPoints(80)}, audit.ApiRequest("correlation-2"))
+	deleteErr := service.DeleteSpread(created.Id, audit.ApiRequest("correlation-3"))
+
+	// Assert
+	assert.ErrorIs(t, updateErr, ErrSpreadLocked)
+	assert.Nil(t, updated)
+	assert.ErrorIs(t, deleteErr, ErrSpreadLocked)
+
+	spreads, err := service.GetAllSpreads()
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Spread{*created}, spreads)
+}
+
+func TestSpread_NotFound(t *testing.T) {
+	// Arrange
+	service := newService()
+
+	// Action
+	updated, updateErr := service.UpdateSpread("unknown", models.SpreadUpdate{BasisPoints: basisPoints(80)}, audit.ApiRequest("correlation-1"))
+	deleteErr := service.DeleteSpread("unknown", audit.ApiRequest("correlation-1"))
+	history, historyErr := service.GetHistory("unknown")
+
+	// Assert
+	assert.ErrorIs(t, updateErr, ErrSpreadNotFound)
+	assert.Nil(t, updated)
+	assert.ErrorIs(t, deleteErr, ErrSpreadNotFound)
+	assert.ErrorIs(t, historyErr, ErrSpreadNotFound)
+	assert.Nil(t, history)
+}
+
+func TestResolve(t *testing.T) {
+	// Arrange
+	service := newService()
+	for _, spread := range []models.SpreadCreate{
+		{BasisPoints: basisPoints(100)},
+		{FromCurrency: "USD", ToCurrency: "BRL", BasisPoints: basisPoints(200)},
+		{MerchantId: "merchant-1", BasisPoints: basisPoints(50)},
+		{MerchantId: "merchant-1", FromCurrency: "USD", ToCurrency: "BRL", BasisPoints: basisPoints(0)},
+	} {
+		_, err := service.CreateSpread(spread, audit.ApiRequest("correlation-1"))
+		assert.NoError(t, err)
+	}
+
+	tests := []struct {
+		name        string
+		merchantId  string
+		from        string
+		to          string
+		basisPoints int
+	}{
+		{"Merchant and pair", "merchant-1", "USD", "BRL", 0},
+		{"Merchant", "merchant-1", "USD", "EUR", 50},
+		{"Pair", "merchant-2", "USD", "BRL", 200},
+		{"Reversed pair falls back to the default", "merchant-2", "BRL", "USD", 100},
+		{"Default", "", "EUR", "JPY", 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Action
+			spread, err := service.Resolve(tt.merchantId, tt.from, tt.to)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.basisPoints, spread.BasisPoints)
+		})
+	}
+}
+
+func TestResolve_NoSpread(t *testing.T) {
+	// Arrange
+	service := newService()
+
+	// Action
+	spread, err := service.Resolve("merchant-1", "USD", "BRL")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, spread)
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"os"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	Consumer        string
+	BatchSize       int64
+	Block           time.Duration
+	MaxAttempts     int
+	BaseBackoff     time.Duration
+	ReclaimIdle     time.Duration
+	ReclaimInterval time.Duration
+}
+
+// LoadConfig loads the payment worker configuration from the environment variables.
+//
+// Environment Variables:
+//   - WORKER_CONSUMER: the name of the worker in the consumer group, defaulting to the host name.
+//   - WORKER_BATCH_SIZE: the maximum number of jobs read at once.
+//   - WORKER_BLOCK: the maximum time to wait for new jobs on each read, e.g. "5s".
+//   - WORKER_MAX_ATTEMPTS: the maximum attempts of a payment failing with transient errors.
+//   - WORKER_BASE_BACKOFF: the wait before the first retry, doubled on each retry, e.g. "1s".
+//   - WORKER_RECLAIM_IDLE: the time after which jobs left unacknowledged by another worker are reclaimed, e.g. "1m".
+//   - WORKER_RECLAIM_INTERVAL: the interval between checks for jobs to reclaim, e.g. "30s".
+//
+// Returns:
+//   - Config: the payment worker configuration.
+func LoadConfig() Config {
+	hostname, _ := os.Hostname()
+
+	return Config{
+		Consumer:        utils.GetEnvString("WORKER_CONSUMER", hostname),
+		BatchSize:       int64(utils.GetEnvInt("WORKER_BATCH_SIZE", 10)),
+		Block:           utils.GetEnvDuration("WORKER_BLOCK", 5*time.Second),
+		MaxAttempts:     utils.GetEnvInt("WORKER_MAX_ATTEMPTS", 3),
+		BaseBackoff:     utils.GetEnvDuration("WORKER_BASE_BACKOFF", time.Second),
+		ReclaimIdle:     utils.GetEnvDuration("WORKER_RECLAIM_IDLE", time.Minute),
+		ReclaimInterval: utils.GetEnvDuration("WORKER_RECLAIM_INTERVAL", 30*time.Second),
+	}
+}
@@ -0,0 +1,170 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	gatewayService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/queue"
+	"go.uber.org/zap"
+)
+
+const (
+	submittedStatus = "submitted"
+	failedStatus    = "failed"
+	workerActor     = "payment_worker"
+)
+
+type PaymentWorker struct {
+	logger         *zap.Logger
+	queue          queue.QueueClient
+	gatewayService gatewayService.GatewayService
+	config         Config
+	sleep          func(time.Duration)
+}
+
+// New creates a new instance of PaymentWorker with the provided logger, queue client, gateway service and configuration.
+// Parameters:
+//   - logger: an instance of zap.Logger used for logging within the worker.
+//   - queue: an instance of queue.QueueClient the payment jobs are consumed from.
+//   - gatewayService: an instance of gatewayService.GatewayService used to record the outcome of the payments.
+//   - config: the payment worker configuration.
+//
+// Returns:
+//   - A pointer to a newly created PaymentWorker.
+func New(logger *zap.Logger, queue queue.QueueClient, gatewayService gatewayService.GatewayService, config Config) *PaymentWorker {
+	return &PaymentWorker{
+		logger:         logger,
+		queue:          queue,
+		gatewayService: gatewayService,
+		config:         config,
+		sleep:          time.Sleep,
+	}
+}
+
+// Run consumes the payment jobs of the payments stream as a member of the payment workers consumer group until
+// the context is canceled. Jobs left unacknowledged by stopped workers are periodically reclaimed.
+//
+// Parameters:
+//   - ctx: the context whose cancellation stops the worker.
+//
+// Returns:
+//   - error: an error if the consumer group cannot be created.
+func (w *PaymentWorker) Run(ctx context.Context) error {
+	if err := w.queue.CreateGroup(queue.PaymentsStream, queue.PaymentsGroup); err != nil {
+		return err
+	}
+
+	w.logger.Info("Payment worker started", zap.String("consumer", w.config.Consumer))
+	lastReclaim := time.Time{}
+
+	for ctx.Err() == nil {
+		if time.Since(lastReclaim) >= w.config.ReclaimInterval {
+			w.reclaim()
+			lastReclaim = time.Now()
+		}
+
+		messages, err := w.queue.Read(queue.PaymentsStream, queue.PaymentsGroup, w.config.Consumer, w.config.BatchSize, w.config.Block)
+		if err != nil {
+			w.logger.Error("Failed to read payment jobs", zap.Error(err))
+			w.sleep(w.config.BaseBackoff)
+			continue
+		}
+
+		for _, message := range messages {
+			w.Handle(message)
+		}
+	}
+
+	w.logger.Info("Payment worker stopped", zap.String("consumer", w.config.Consumer))
+	return nil
+}
+
+// Handle processes a payment job and acknowledges it once its outcome is recorded.
+// Jobs whose outcome cannot be recorded are left pending, to be reclaimed later.
+//
+// Parameters:
+//   - message: the message carrying the payment job.
+func (w *PaymentWorker) Handle(message queue.Message) {
+	var job models.PaymentJob
+	if err := json.Unmarshal(message.Payload, &job); err != nil {
+		w.logger.Error("Discarding invalid payment job", zap.String("message_id", message.Id), zap.Error(err))
+		w.ack(message)
+		return
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, job.CreatedAt)
+	if err != nil {
+		w.logger.Error("Discarding invalid payment job", zap.String("message_id", message.Id), zap.Error(err))
+		w.ack(message)
+		return
+	}
+
+	w.logger.Info("Processing payment job", zap.String("correlation_id", job.CorrelationId), zap.String("transaction_id", job.TransactionId))
+
+	status := submittedStatus
+	reference, err := w.process(job)
+	if err != nil {
+		w.logger.Error("Payment processing failed", zap.String("correlation_id", job.CorrelationId), zap.String("transaction_id", job.TransactionId), zap.Error(err))
+		status = failedStatus
+	}
+
+	origin := audit.Origin{Actor: audit.Actor{Type: workerActor, Id: w.config.Consumer}, CorrelationId: job.CorrelationId}
+	if err := w.gatewayService.AddTransactionStatus(job.TransactionId, createdAt, status, reference, origin); err != nil {
+		w.logger.Error("Failed to record payment outcome", zap.String("correlation_id", job.CorrelationId), zap.String("transaction_id", job.TransactionId), zap.Error(err))
+		return
+	}
+
+	w.ack(message)
+	w.logger.Info("Payment job completed", zap.String("correlation_id", job.CorrelationId), zap.String("transaction_id", job.TransactionId), zap.String("status", status))
+}
+
+// process sends the payment to its provider, retrying with exponential backoff while the provider fails with transient errors.
+// It returns the reference of the payment at the provider.
+func (w *PaymentWorker) process(job models.PaymentJob) (string, error) {
+	paymentGateway, err := w.gatewayService.GetProvider(job.Gateway)
+	if err != nil {
+		return "", err
+	}
+
+	payment := job.Payment()
+
+	for attempt := 1; ; attempt++ {
+		res, err := paymentGateway.ProcessPayment(payment, job.CorrelationId)
+		if err == nil {
+			return *res, nil
+		}
+
+		if !provider.IsTransient(err) || attempt >= w.config.MaxAttempts {
+			return "", err
+		}
+
+		w.logger.Warn("Retrying payment after transient error", zap.String("correlation_id", job.CorrelationId), zap.Int("attempt", attempt), zap.Error(err))
+		w.sleep(w.config.BaseBackoff * time.Duration(1<<(attempt-1)))
+	}
+}
+
+// reclaim claims and processes the jobs left unacknowledged by other workers for longer than the reclaim idle time.
+func (w *PaymentWorker) reclaim() {
+	messages, err := w.queue.Reclaim(queue.PaymentsStream, queue.PaymentsGroup, w.config.Consumer, w.config.ReclaimIdle, w.config.BatchSize)
+	if err != nil {
+		w.logger.Error("Failed to reclaim payment jobs", zap.Error(err))
+		return
+	}
+
+	for _, message := range messages {
+		w.logger.Warn("Reclaimed stuck payment job", zap.String("message_id", message.Id))
+		w.Handle(message)
+	}
+}
+
+// ack acknowledges a processed message, logging failures.
+func (w *PaymentWorker) ack(message queue.Message) {
+	if err := w.queue.Ack(queue.PaymentsStream, queue.PaymentsGroup, message.Id); err != nil {
+		w.logger.Error("Failed to acknowledge payment job", zap.String("message_id", message.Id), zap.Error(err))
+	}
+}
@@ -0,0 +1,212 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/queue"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockQueueClient struct {
+	mock.Mock
+}
+
+func (m *MockQueueClient) CreateGroup(stream string, group string) error {
+	args := m.Called(stream, group)
+	return args.Error(0)
+}
+
+func (m *MockQueueClient) Enqueue(stream string, payload interface{}) (string, error) {
+	args := m.Called(stream, payload)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockQueueClient) Read(stream string, group string, consumer string, count int64, block time.Duration) ([]queue.Message, error) {
+	args := m.Called(stream, group, consumer, count, block)
+	return args.Get(0).([]queue.Message), args.Error(1)
+}
+
+func (m *MockQueueClient) Reclaim(stream string, group string, consumer string, minIdle time.Duration, count int64) ([]queue.Message, error) {
+	args := m.Called(stream, group, consumer, minIdle, count)
+	return args.Get(0).([]queue.Message), args.Error(1)
+}
+
+func (m *MockQueueClient) Ack(stream string, group string, id string) error {
+	args := m.Called(stream, group, id)
+	return args.Error(0)
+}
+
+func (m *MockQueueClient) Schedule(stream string, payload interface{}, at time.Time) error {
+	args := m.Called(stream, payload, at)
+	return args.Error(0)
+}
+
+func (m *MockQueueClient) Promote(stream string, now time.Time, count int64) (int, error) {
+	args := m.Called(stream, now, count)
+	return args.Int(0), args.Error(1)
+}
+
+type GatewayServiceMock struct {
+	mock.Mock
+}
+
+func (m *GatewayServiceMock) GetAllAvaiablesGateways() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *GatewayServiceMock) GetProvider(gateway string) (provider.PaymentGateway, error) {
+	args := m.Called(gateway)
+	var result provider.PaymentGateway
+	if args.Get(0) != nil {
+		result = args.Get(0).(provider.PaymentGateway)
+	}
+	return result, args.Error(1)
+}
+
+func (m *GatewayServiceMock) GetAllTransactionsByDate(date string) (*[]models.Transaction, error) {
+	args := m.Called(date)
+	return args.Get(0).(*[]models.Transaction), args.Error(1)
+}
+
+func (m *GatewayServiceMock) AddTransaction(id string, payment models.Gateway, status string, risk *models.RiskAssessment, origin audit.Origin) error {
+	args := m.Called(id, payment, status, risk, origin)
+	return args.Error(0)
+}
+
+func (m *GatewayServiceMock) AddTransactionStatus(id string, createdAt time.Time, status string, providerReference string, origin audit.Origin) error {
+	args := m.Called(id, createdAt, status, providerReference, origin)
+	return args.Error(0)
+}
+
+func (m *GatewayServiceMock) EnqueuePayment(job models.PaymentJob) error {
+	args := m.Called(job)
+	return args.Error(0)
+}
+
+type PaymentGatewayMock struct {
+	mock.Mock
+}
+
+func (m *PaymentGatewayMock) ProcessPayment(payment models.Gateway, correlationId string) (*string, error) {
+	args := m.Called(payment, correlationId)
+	var result *string
+	if args.Get(0) != nil {
+		result = args.Get(0).(*string)
+	}
+	return result, args.Error(1)
+}
+
+func (m *PaymentGatewayMock) Tokenize(payment models.Gateway) (string, error) {
+	args := m.Called(payment)
+	return args.String(0), args.Error(1)
+}
+
+func testConfig() Config {
+	return Config{
+		Consumer:        "worker1",
+		BatchSize:       10,
+		Block:           time.Millisecond,
+		MaxAttempts:     3,
+		BaseBackoff:     time.Millisecond,
+		ReclaimIdle:     time.Minute,
+		ReclaimInterval: time.Minute,
+	}
+}
+
+func testMessage(gateway string) queue.Message {
+	job := models.PaymentJob{
+		TransactionId: "transaction1",
+		CorrelationId: "correlation1",
+		Gateway:       gateway,
+		PaymentMethod: "card",
+		PaymentToken:  "pm_123",
+		Amount:        100,
+		Currency:      "USD",
+		CreatedAt:     "2024-10-01T10:00:00Z",
+	}
+	return queue.Message{Id: "1-0", Payload: []byte(utils.ToJSON(job))}
+}
+
+func TestHandle_RecordsFailedPayment(t *testing.T) {
+	// Arrange
+	mockQueue := new(MockQueueClient)
+	mockGatewayService := new(GatewayServiceMock)
+	worker := New(zap.NewNop(), mockQueue, mockGatewayService, testConfig())
+
+	createdAt := time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC)
+	origin := audit.Origin{Actor: audit.Actor{Type: workerActor, Id: "worker1"}, CorrelationId: "correlation1"}
+	mockGateway := new(PaymentGatewayMock)
+	mockGateway.On("ProcessPayment", mock.MatchedBy(func(payment models.Gateway) bool {
+		return payment.PaymentToken == "pm_123" && payment.TransactionId == "transaction1" && payment.CardDetails == models.CardDetails{}
+	}), "correlation1").Return(nil, errors.New("card declined"))
+	mockGatewayService.On("GetProvider", "PayPal").Return(mockGateway, nil)
+	mockGatewayService.On("AddTransactionStatus", "transaction1", createdAt, failedStatus, "", origin).Return(nil)
+	mockQueue.On("Ack", queue.PaymentsStream, queue.PaymentsGroup, "1-0").Return(nil)
+
+	// Action
+	worker.Handle(testMessage("PayPal"))
+
+	// Assert
+	mockGatewayService.AssertExpectations(t)
+	mockQueue.AssertExpectations(t)
+}
+
+func TestHandle_KeepsJobPendingWhenOutcomeIsNotRecorded(t *testing.T) {
+	// Arrange
+	mockQueue := new(MockQueueClient)
+	mockGatewayService := new(GatewayServiceMock)
+	worker := New(zap.NewNop(), mockQueue, mockGatewayService, testConfig())
+
+	mockGatewayService.On("GetProvider", "Unknown").Return(nil, errors.New("unsupported payment gateway type"))
+	mockGatewayService.On("AddTransactionStatus", "transaction1", mock.Anything, failedStatus, "", mock.Anything).Return(errors.New("cache error"))
+
+	// Action
+	worker.Handle(testMessage("Unknown"))
+
+	// Assert
+	mockGatewayService.AssertExpectations(t)
+	mockQueue.AssertNotCalled(t, "Ack", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandle_DiscardsInvalidJob(t *testing.T) {
+	// Arrange
+	mockQueue := new(MockQueueClient)
+	mockGatewayService := new(GatewayServiceMock)
+	worker := New(zap.NewNop(), mockQueue, mockGatewayService, testConfig())
+
+	mockQueue.On("Ack", queue.PaymentsStream, queue.PaymentsGroup, "1-0").Return(nil)
+
+	// Action
+	worker.Handle(queue.Message{Id: "1-0", Payload: []byte("invalid")})
+
+	// Assert
+	mockQueue.AssertExpectations(t)
+	mockGatewayService.AssertNotCalled(t, "AddTransactionStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReclaim_HandlesStuckJobs(t *testing.T) {
+	// Arrange
+	mockQueue := new(MockQueueClient)
+	mockGatewayService := new(GatewayServiceMock)
+	worker := New(zap.NewNop(), mockQueue, mockGatewayService, testConfig())
+
+	mockQueue.On("Reclaim", queue.PaymentsStream, queue.PaymentsGroup, "worker1", time.Minute, int64(10)).Return([]queue.Message{testMessage("PayPal")}, nil)
+	mockGatewayService.On("GetProvider", "PayPal").Return(nil, errors.New("unsupported payment gateway type"))
+	mockGatewayService.On("AddTransactionStatus", "transaction1", mock.Anything, failedStatus, "", mock.Anything).Return(nil)
+	mockQueue.On("Ack", queue.PaymentsStream, queue.PaymentsGroup, "1-0").Return(nil)
+
+	// Action
+	worker.reclaim()
+
+	// Assert
+	mockQueue.AssertExpectations(t)
+	mockGatewayService.AssertExpectations(t)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	gatewayService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider/paypal"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/services/gateway/provider/stripe"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/api/internal/worker"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/index"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/notification"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/pending"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/queue"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	logger.Info("Start payment worker application")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cacheConfig := cache.LoadConfig()
+	if cacheConfig.Driver == cache.MemoryDriver {
+		logger.Fatal("The payment worker uses Redis Streams and cannot run with the memory cache driver")
+	}
+
+	cacheClient, err := cache.NewClient(cacheConfig)
+	if err != nil {
+		logger.Fatal("Error creating cache client", zap.Error(err))
+	}
+	queueClient := queue.New()
+
+	notificationService := notification.New(cacheClient, queueClient, logger, notification.LoadConfig())
+	auditService := audit.New(cacheClient)
+	providers := provider.NewRegistry(stripe.LoadConfig(), paypal.LoadConfig())
+	gatewayService := gatewayService.New(cacheClient, notificationService, auditService, queueClient, pending.New(cacheClient, logger, pending.LoadConfig()), index.New(cacheClient, index.LoadConfig()), providers, logger)
+
+	paymentWorker := worker.New(logger, queueClient, gatewayService, worker.LoadConfig())
+	if err := paymentWorker.Run(ctx); err != nil {
+		logger.Fatal("Error starting payment worker application", zap.Error(err))
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+const usage = `Usage: dlqctl [-url URL] [-key API_KEY] <command> [arguments]
+
+Commands:
+  list [-provider NAME]     list the failed webhook events
+  inspect <id>              show a failed webhook event
+  replay <id>...            replay failed webhook events
+  replay -all               replay every failed webhook event
+  discard <id>              discard a failed webhook event
+
+The dead-letter endpoints require an admin API key, read from -key or DLQCTL_API_KEY.
+`
+
+type client struct {
+	baseUrl string
+	apiKey  string
+	http    *http.Client
+	out     io.Writer
+}
+
+func main() {
+	baseUrl := flag.String("url", utils.GetEnvString("WEBHOOK_URL", "http://localhost:8081"), "base URL of the webhook application")
+	apiKey := flag.String("key", utils.GetEnvString("DLQCTL_API_KEY", ""), "admin API key of the webhook application")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	c := &client{
+		baseUrl: strings.TrimSuffix(*baseUrl, "/") + "/api/v1/dead-letters",
+		apiKey:  *apiKey,
+		http:    &http.Client{Timeout: 30 * time.Second},
+		out:     os.Stdout,
+	}
+
+	if err := run(c, flag.Arg(0), flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(c *client, command string, args []string) error {
+	switch command {
+	case "list":
+		flags := flag.NewFlagSet("list", flag.ContinueOnError)
+		provider := flags.String("provider", "", "provider to filter by")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		path := ""
+		if !utils.IsEmptyOrNull(*provider) {
+			path = "?provider=" + url.QueryEscape(*provider)
+		}
+		return c.do(http.MethodGet, path, nil)
+	case "inspect":
+		if len(args) != 1 {
+			return fmt.Errorf("inspect expects a dead letter ID")
+		}
+		return c.do(http.MethodGet, "/"+url.PathEscape(args[0]), nil)
+	case "replay":
+		flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+		all := flags.Bool("all", false, "replay every failed webhook event")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		if !*all && flags.NArg() == 0 {
+			return fmt.Errorf("replay expects dead letter IDs or -all")
+		}
+		return c.do(http.MethodPost, "/replay", models.ReplayRequest{Ids: flags.Args(), All: *all})
+	case "discard":
+		if len(args) != 1 {
+			return fmt.Errorf("discard expects a dead letter ID")
+		}
+		return c.do(http.MethodDelete, "/"+url.PathEscape(args[0]), nil)
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// do sends a request to the dead-letter endpoints and prints the indented response body.
+func (c *client) do(method string, path string, payload interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewBufferString(utils.ToJSON(payload))
+	}
+
+	req, err := http.NewRequest(method, c.baseUrl+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+	if !utils.IsEmptyOrNull(c.apiKey) {
+		req.Header.Set(middleware.APIKeyHeader, c.apiKey)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if len(data) > 0 {
+		var out bytes.Buffer
+		if json.Indent(&out, data, "", "  ") == nil {
+			data = out.Bytes()
+		}
+		fmt.Fprintln(c.out, string(data))
+	}
+
+	if res.StatusCode >= 400 {
+		return fmt.Errorf("request failed with status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+// request is a request received by the test server.
+type request struct {
+	method string
+	uri    string
+	apiKey string
+	body   string
+}
+
+func newClient(t *testing.T, status int, response string) (*client, *request, *bytes.Buffer) {
+	received := &request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*received = request{
+			method: r.Method,
+			uri:    r.URL.RequestURI(),
+			apiKey: r.Header.Get(middleware.APIKeyHeader),
+			body:   string(body),
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	out := &bytes.Buffer{}
+	return &client{
+		baseUrl: server.URL + "/api/v1/dead-letters",
+		apiKey:  "admin-key",
+		http:    server.Client(),
+		out:     out,
+	}, received, out
+}
+
+func TestRun_List(t *testing.T) {
+	// Arrange
+	c, received, out := newClient(t, http.StatusOK, `[{"id":"letter1"}]`)
+
+	// Action
+	err := run(c, "list", []string{"-provider", "stripe"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodGet, received.method)
+	assert.Equal(t, "/api/v1/dead-letters?provider=stripe", received.uri)
+	assert.Equal(t, "admin-key", received.apiKey)
+	assert.Equal(t, "[\n  {\n    \"id\": \"letter1\"\n  }\n]\n", out.String())
+}
+
+func TestRun_Inspect(t *testing.T) {
+	// Arrange
+	c, received, _ := newClient(t, http.StatusOK, `{"id":"letter1"}`)
+
+	// Action
+	err := run(c, "inspect", []string{"letter1"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodGet, received.method)
+	assert.Equal(t, "/api/v1/dead-letters/letter1", received.uri)
+}
+
+func TestRun_ReplayAll(t *testing.T) {
+	// Arrange
+	c, received, _ := newClient(t, http.StatusOK, `[]`)
+
+	// Action
+	err := run(c, "replay", []string{"-all"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, received.method)
+	assert.Equal(t, "/api/v1/dead-letters/replay", received.uri)
+
+	var payload models.ReplayRequest
+	assert.NoError(t, json.Unmarshal([]byte(received.body), &payload))
+	assert.True(t, payload.All)
+	assert.Empty(t, payload.Ids)
+}
+
+func TestRun_ReplayIds(t *testing.T) {
+	// Arrange
+	c, received, _ := newClient(t, http.StatusOK, `[]`)
+
+	// Action
+	err := run(c, "replay", []string{"letter1", "letter2"})
+
+	// Assert
+	assert.NoError(t, err)
+
+	var payload models.ReplayRequest
+	assert.NoError(t, json.Unmarshal([]byte(received.body), &payload))
+	assert.False(t, payload.All)
+	assert.Equal(t, []string{"letter1", "letter2"}, payload.Ids)
+}
+
+func TestRun_Discard(t *testing.T) {
+	// Arrange
+	c, received, out := newClient(t, http.StatusNoContent, "")
+
+	// Action
+	err := run(c, "discard", []string{"letter1"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodDelete, received.method)
+	assert.Equal(t, "/api/v1/dead-letters/letter1", received.uri)
+	assert.Empty(t, out.String())
+}
+
+func TestRun_RequestFailed(t *testing.T) {
+	// Arrange
+	c, _, out := newClient(t, http.StatusUnauthorized, `{"message":"invalid API key"}`)
+
+	// Action
+	err := run(c, "list", nil)
+
+	// Assert
+	assert.EqualError(t, err, "request failed with status 401")
+	assert.Contains(t, out.String(), "invalid API key")
+}
+
+func TestRun_InvalidArguments(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		err     string
+	}{
+		{"inspect without ID", "inspect", nil, "inspect expects a dead letter ID"},
+		{"discard without ID", "discard", nil, "discard expects a dead letter ID"},
+		{"replay without IDs", "replay", nil, "replay expects dead letter IDs or -all"},
+		{"unknown command", "purge", nil, `unknown command "purge"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			c, received, _ := newClient(t, http.StatusOK, "")
+
+			// Action
+			err := run(c, tt.command, tt.args)
+
+			// Assert
+			assert.EqualError(t, err, tt.err)
+			assert.Empty(t, received.method)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package deadletter
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	deadLetterService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/deadletter"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type DeadLetterHandler struct {
+	logger            *zap.Logger
+	deadLetterService deadLetterService.DeadLetterService
+}
+
+// New creates a new instance of DeadLetterHandler with the provided logger and dead-letter service.
+// Parameters:
+//   - logger: an instance of zap.Logger used for logging within the handler.
+//   - deadLetterService: an instance of deadLetterService.DeadLetterService that manages the failed webhook events.
+//
+// Returns:
+//   - A pointer to a newly created DeadLetterHandler.
+func New(logger *zap.Logger, deadLetterService deadLetterService.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		logger:            logger,
+		deadLetterService: deadLetterService,
+	}
+}
+
+// GetAllHandler handles the request to list the failed webhook events, oldest first.
+// It expects an optional query parameter "provider" to filter the events of a provider.
+//
+// @Summary List the failed webhook events
+// @Tags dead-letters
+// @Produce json
+// @Param provider query string false "Provider name"
+// @Success 200 {object} []models.DeadLetter
+// @Failure 500 {object} string
+// @Router /dead-letters [get]
+func (c *DeadLetterHandler) GetAllHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.logger.Info("Starting request to list dead letters", zap.String("correlation_id", correlationId))
+
+	result, err := c.deadLetterService.GetAll(ctx.Query("provider"))
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to list dead letters", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully listed dead letters", zap.String("correlation_id", correlationId), zap.Int("dead_letter_count", len(result)))
+}
+
+// GetHandler handles the request to inspect a failed webhook event.
+//
+// @Summary Inspect a failed webhook event
+// @Tags dead-letters
+// @Produce json
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} models.DeadLetter
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /dead-letters/{id} [get]
+func (c *DeadLetterHandler) GetHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := ctx.Param("id")
+	c.logger.Info("Starting request to get dead letter", zap.String("correlation_id", correlationId), zap.String("dead_letter_id", id))
+
+	result, err := c.deadLetterService.Get(id)
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to get dead letter", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully retrieved dead letter", zap.String("correlation_id", correlationId), zap.String("dead_letter_id", id))
+}
+
+// ReplayHandler handles the request to process a failed webhook event again. The event is removed once replayed.
+//
+// @Summary Replay a failed webhook event
+// @Tags dead-letters
+// @Produce json
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} models.ReplayResult
+// @Failure 404 {object} string
+// @Failure 422 {object} models.ReplayResult
+// @Failure 500 {object} string
+// @Router /dead-letters/{id}/replay [post]
+func (c *DeadLetterHandler) ReplayHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := ctx.Param("id")
+	c.logger.Info("Starting request to replay dead letter", zap.String("correlation_id", correlationId), zap.String("dead_letter_id", id))
+
+	results, err := c.deadLetterService.ReplayMany([]string{id}, false)
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to replay dead letter", err)
+		return
+	}
+
+	result := results[0]
+	if !result.Replayed {
+		if result.Error == deadLetterService.ErrDeadLetterNotFound.Error() {
+			c.errorResponse(ctx, correlationId, "Failed to replay dead letter", deadLetterService.ErrDeadLetterNotFound)
+			return
+		}
+
+		c.logger.Error("Dead letter replay failed", zap.String("correlation_id", correlationId), zap.String("dead_letter_id", id), zap.String("error", result.Error))
+		utils.ApiResponse(ctx, http.StatusUnprocessableEntity, result)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully replayed dead letter", zap.String("correlation_id", correlationId), zap.String("dead_letter_id", id))
+}
+
+// ReplayManyHandler handles the request to process several failed webhook events again, either the ones
+// listed in the request body or all of them. A failed replay does not stop the others.
+//
+// @Summary Replay failed webhook events in bulk
+// @Tags dead-letters
+// @Accept json
+// @Produce json
+// @Param request body models.ReplayRequest true "Dead letters to replay"
+// @Success 200 {object} []models.ReplayResult
+// @Failure 400 {object} []utils.Errors
+// @Failure 500 {object} string
+// @Router /dead-letters/replay [post]
+func (c *DeadLetterHandler) ReplayManyHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request models.ReplayRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		c.logger.Error("Invalid request body", zap.String("correlation_id", correlationId), zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !request.All && len(request.Ids) == 0 {
+		c.logger.Error("No dead letters to replay", zap.String("correlation_id", correlationId))
+		utils.ApiResponse(ctx, http.StatusBadRequest, []utils.Errors{{Field: "ids", Message: "ids must not be empty unless all is set"}})
+		return
+	}
+
+	c.logger.Info("Starting request to replay dead letters", zap.String("correlation_id", correlationId), zap.Bool("all", request.All), zap.Int("dead_letter_count", len(request.Ids)))
+
+	results, err := c.deadLetterService.ReplayMany(request.Ids, request.All)
+	if err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to replay dead letters", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, results)
+	c.logger.Info("Successfully replayed dead letters", zap.String("correlation_id", correlationId), zap.Int("dead_letter_count", len(results)))
+}
+
+// DiscardHandler handles the request to remove a failed webhook event without processing it.
+//
+// @Summary Discard a failed webhook event
+// @Tags dead-letters
+// @Param id path string true "Dead letter ID"
+// @Success 204
+// @Failure 404 {object} string
+// @Failure 500 {object} string
+// @Router /dead-letters/{id} [delete]
+func (c *DeadLetterHandler) DiscardHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := ctx.Param("id")
+	c.logger.Info("Starting request to discard dead letter", zap.String("correlation_id", correlationId), zap.String("dead_letter_id", id))
+
+	if err := c.deadLetterService.Discard(id); err != nil {
+		c.errorResponse(ctx, correlationId, "Failed to discard dead letter", err)
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusNoContent, nil)
+	c.logger.Info("Successfully discarded dead letter", zap.String("correlation_id", correlationId), zap.String("dead_letter_id", id))
+}
+
+// errorResponse logs the error and responds with 404 Not Found for unknown dead letters, or 500 Internal Server Error otherwise.
+func (c *DeadLetterHandler) errorResponse(ctx *gin.Context, correlationId string, message string, err error) {
+	c.logger.Error(message, zap.String("correlation_id", correlationId), zap.Error(err))
+
+	if errors.Is(err, deadLetterService.ErrDeadLetterNotFound) {
+		utils.ApiResponse(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+}
@@ -0,0 +1,95 @@
+package eventstore
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	eventStoreService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/eventstore"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type WebhookEventHandler struct {
+	logger            *zap.Logger
+	eventStoreService eventStoreService.EventStoreService
+}
+
+// New creates a new instance of WebhookEventHandler with the provided logger and event store service.
+// Parameters:
+//   - logger: an instance of zap.Logger used for logging within the handler.
+//   - eventStoreService: an instance of eventStoreService.EventStoreService that keeps the received webhook events.
+//
+// Returns:
+//   - A pointer to a newly created WebhookEventHandler.
+func New(logger *zap.Logger, eventStoreService eventStoreService.EventStoreService) *WebhookEventHandler {
+	return &WebhookEventHandler{
+		logger:            logger,
+		eventStoreService: eventStoreService,
+	}
+}
+
+// GetAllHandler handles the request to list the received webhook events, newest first.
+// It expects optional query parameters to filter the events: "provider", "type" (the event type of the provider,
+// such as "charge.refunded", or the normalized type, such as "refund"), "transaction_id", "from" and "to" (RFC 3339
+// times) and "limit".
+//
+// @Summary List the received webhook events
+// @Tags webhook-events
+// @Produce json
+// @Param provider query string false "Provider name"
+// @Param type query string false "Event type"
+// @Param transaction_id query string false "Transaction ID"
+// @Param from query string false "Received at or after, RFC 3339"
+// @Param to query string false "Received at or before, RFC 3339"
+// @Param limit query int false "Maximum number of events"
+// @Success 200 {object} []models.WebhookEvent
+// @Failure 400 {object} string
+// @Failure 500 {object} string
+// @Router /webhook-events [get]
+func (c *WebhookEventHandler) GetAllHandler(ctx *gin.Context) {
+
+	correlationId, err := utils.GetCorrelationId(ctx)
+	if err != nil {
+		c.logger.Error("Failed to get correlation ID", zap.Error(err))
+		utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.logger.Info("Starting request to list webhook events", zap.String("correlation_id", correlationId))
+
+	filter := models.WebhookEventFilter{
+		Provider:      ctx.Query("provider"),
+		Type:          ctx.Query("type"),
+		TransactionId: ctx.Query("transaction_id"),
+		From:          ctx.Query("from"),
+		To:            ctx.Query("to"),
+	}
+
+	if limit := ctx.Query("limit"); !utils.IsEmptyOrNull(limit) {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit <= 0 {
+			c.logger.Error("Invalid webhook events limit", zap.String("correlation_id", correlationId), zap.String("limit", limit))
+			utils.ApiResponse(ctx, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+	}
+
+	result, err := c.eventStoreService.Find(filter)
+	if err != nil {
+		c.logger.Error("Failed to list webhook events", zap.String("correlation_id", correlationId), zap.Error(err))
+
+		if errors.Is(err, eventStoreService.ErrInvalidFilter) {
+			utils.ApiResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.ApiResponse(ctx, http.StatusInternalServerError, "Unable to process your request, please try again later")
+		return
+	}
+
+	utils.ApiResponse(ctx, http.StatusOK, result)
+	c.logger.Info("Successfully listed webhook events", zap.String("correlation_id", correlationId), zap.Int("webhook_event_count", len(result)))
+}
@@ -0,0 +1,325 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/metrics"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/providers"
+	deadLetterService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/deadletter"
+	dedupeService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/dedupe"
+	eventStoreService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/eventstore"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/transaction"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	MaxBodyBytes = int64(65536)
+
+	redactedValue = "[REDACTED]"
+)
+
+// redactedHeaders are the headers whose values are redacted in the webhook event store and the dead-letter queue,
+// in canonical form.
+var redactedHeaders = []string{"Authorization", "Cookie", "Stripe-Signature", "Paypal-Transmission-Sig", "X-Mgc-Apikey"}
+
+var ErrNoTransactionRef = errors.New("webhook event has no transaction reference")
+
+type WebhookHandler struct {
+	logger             *zap.Logger
+	providers          providers.Registry
+	transactionService transaction.TransactionService
+	dedupeService      dedupeService.DedupeService
+	deadLetterService  deadLetterService.DeadLetterService
+	eventStoreService  eventStoreService.EventStoreService
+}
+
+// New creates a new instance of WebhookHandler with the provided logger, providers, transactionService,
+// dedupeService, deadLetterService and eventStoreService.
+// It returns a pointer to the newly created WebhookHandler.
+//
+// Parameters:
+//   - logger: A zap.Logger instance used for logging.
+//   - providers: The providers webhooks are received from, verifying and translating their payloads.
+//   - transactionService: An instance of transaction.TransactionService applying payment events to transactions.
+//   - dedupeService: An instance of dedupeService.DedupeService used to skip events delivered more than once.
+//   - deadLetterService: An instance of deadLetterService.DeadLetterService where failed events are stored for replay.
+//   - eventStoreService: An instance of eventStoreService.EventStoreService where every received webhook is stored.
+//
+// Returns:
+//   - A pointer to a WebhookHandler instance.
+func New(logger *zap.Logger, providers providers.Registry, transactionService transaction.TransactionService, dedupeService dedupeService.DedupeService, deadLetterService deadLetterService.DeadLetterService, eventStoreService eventStoreService.EventStoreService) *WebhookHandler {
+	return &WebhookHandler{
+		logger:             logger,
+		providers:          providers,
+		transactionService: transactionService,
+		dedupeService:      dedupeService,
+		deadLetterService:  deadLetterService,
+		eventStoreService:  eventStoreService,
+	}
+}
+
+// WebhookHandler handles incoming webhook requests from the provider named in the path, as in
+// "/api/v1/webhooks/:provider".
+// The provider verifies the signature of the request and translates its payload into a payment event, which is
+// deduplicated and applied to its transaction. Events of unhandled types are acknowledged as ignored, and events
+// failing processing are stored in the dead-letter queue. Requests failing signature verification are rejected and
+// counted, never dead-lettered, since they cannot be trusted or replayed. Every request is stored in the webhook
+// event store with its outcome and latency.
+//
+// Parameters:
+// - ctx: The Gin context for the request.
+//
+// Responses:
+// - 400 Bad Request: If there is an error reading the request body, verifying its signature or parsing the event, or
+// the event has no transaction reference.
+// - 404 Not Found: If the provider is unknown.
+// - 409 Conflict: If the same event is being processed by a concurrent delivery.
+// - 500 Internal Server Error: If there is an error processing the event.
+// - 202 Accepted: If the transaction of the event does not exist yet. The event is parked and applied once the
+// transaction is created, without the provider retrying it.
+// - 200 OK: If the event is successfully processed, was already processed, or its type is not handled.
+func (c *WebhookHandler) WebhookHandler(ctx *gin.Context) {
+	c.ingest(ctx, ctx.Param("provider"))
+}
+
+// Handler returns a handler of the webhook requests of a single provider, for the endpoints registered at the
+// provider before the generic endpoint, such as "/api/v1/stripe/webhook".
+//
+// Parameters:
+//   - name: The name of the provider, such as "stripe".
+//
+// Returns:
+//   - gin.HandlerFunc: The handler of the webhook requests of the provider.
+func (c *WebhookHandler) Handler(name string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		c.ingest(ctx, name)
+	}
+}
+
+// Replay processes again an event stored in the dead-letter queue. Only events whose signature was verified when
+// they were received are stored, and their signature headers are redacted, so the stored body is not verified again.
+//
+// Parameters:
+//   - letter: the stored failed event.
+//
+// Returns:
+//   - error: an error if the provider is unknown or the event could not be processed.
+func (c *WebhookHandler) Replay(letter models.DeadLetter) error {
+	provider, err := c.providers.Get(letter.Provider)
+	if err != nil {
+		return err
+	}
+
+	event, err := provider.Translate([]byte(letter.Body))
+	if event.EventId == "" {
+		if err == nil {
+			err = errors.New("webhook event has no ID")
+		}
+		return err
+	}
+
+	c.logger.Info("Replaying webhook event", zap.String("provider", letter.Provider), zap.String("event_id", event.EventId), zap.String("event_type", event.EventType), zap.String("dead_letter_id", letter.Id))
+
+	_, _, err = c.handleEvent(event, err)
+	return err
+}
+
+// ingest reads, verifies and processes a webhook request of a provider, storing it in the webhook event store.
+func (c *WebhookHandler) ingest(ctx *gin.Context, name string) {
+	start := time.Now()
+	record := models.WebhookEvent{Provider: name, ReceivedAt: start.UTC().Format(time.RFC3339Nano)}
+	defer c.record(&record, start)
+
+	provider, err := c.providers.Get(name)
+	if err != nil {
+		c.logger.Error("Webhook received for unknown provider", zap.String("provider", name))
+		c.respond(ctx, &record, models.OutcomeRejected, http.StatusNotFound, err)
+		return
+	}
+
+	req := ctx.Request
+	if req.Body == nil {
+		req.Body = http.NoBody
+	}
+	req.Body = http.MaxBytesReader(ctx.Writer, req.Body, MaxBodyBytes)
+
+	body, err := io.ReadAll(req.Body)
+	record.Headers = redact(headers(req.Header))
+	if err != nil {
+		c.logger.Error("Error reading request body", zap.String("provider", name), zap.Error(err))
+		c.respond(ctx, &record, models.OutcomeRejected, http.StatusBadRequest, err)
+		return
+	}
+	record.BodySize = len(body)
+	record.BodySha256 = utils.Hash(string(body))
+
+	if err := provider.Verify(req.Header, body, false); err != nil {
+		c.logger.Error("Error verifying webhook signature", zap.String("provider", name), zap.Error(err))
+		record.VerificationError = err.Error()
+		metrics.RejectSignature(name)
+		c.respond(ctx, &record, models.OutcomeRejected, http.StatusBadRequest, err)
+		return
+	}
+	record.Verified = true
+
+	event, err := provider.Translate(body)
+	record.EventId = event.EventId
+	record.EventType = event.EventType
+	record.Type = event.Type
+	record.TransactionRef = event.TransactionRef
+	if event.EventId == "" {
+		if err == nil {
+			err = errors.New("webhook event has no ID")
+		}
+		c.logger.Error("Error parsing webhook event", zap.String("provider", name), zap.Error(err))
+		c.respond(ctx, &record, models.OutcomeRejected, http.StatusBadRequest, err)
+		return
+	}
+
+	c.logger.Info("Webhook event received", zap.String("provider", name), zap.String("event_id", event.EventId), zap.String("event_type", event.EventType))
+
+	status, outcome, err := c.handleEvent(event, err)
+	if status == http.StatusInternalServerError {
+		c.deadLetter(models.DeadLetter{
+			Provider:  name,
+			EventId:   event.EventId,
+			EventType: event.EventType,
+			Body:      string(body),
+			Headers:   record.Headers,
+			Error:     err.Error(),
+		})
+	}
+
+	c.respond(ctx, &record, outcome, status, err)
+}
+
+// respond sends the response of a webhook request, setting its outcome in the stored event.
+func (c *WebhookHandler) respond(ctx *gin.Context, record *models.WebhookEvent, outcome string, status int, err error) {
+	record.Outcome = outcome
+	record.StatusCode = status
+
+	if err != nil {
+		record.Error = err.Error()
+		utils.ApiResponse(ctx, status, err.Error())
+		return
+	}
+
+	utils.ApiResponse(ctx, status, nil)
+}
+
+// record stores a webhook request in the webhook event store with its latency, logging failures.
+func (c *WebhookHandler) record(record *models.WebhookEvent, start time.Time) {
+	record.LatencyMs = time.Since(start).Milliseconds()
+
+	if err := c.eventStoreService.Record(*record); err != nil {
+		c.logger.Error("Error storing webhook event", zap.String("provider", record.Provider), zap.String("event_id", record.EventId), zap.Error(err))
+	}
+}
+
+// handleEvent deduplicates and applies a verified payment event, given the error of its translation, returning the
+// outcome and its HTTP status. Events without a transaction reference are rejected, as they could never be applied.
+func (c *WebhookHandler) handleEvent(event models.PaymentEvent, translateErr error) (int, string, error) {
+	logger := c.logger.With(zap.String("provider", event.Provider), zap.String("event_id", event.EventId), zap.String("event_type", event.EventType))
+
+	if translateErr == nil && event.TransactionRef == "" {
+		logger.Error("Webhook event has no transaction reference")
+		return http.StatusBadRequest, models.OutcomeRejected, ErrNoTransactionRef
+	}
+
+	decision, err := c.dedupeService.Acquire(event.Provider, event.EventId)
+	if err != nil {
+		logger.Error("Error checking webhook event deduplication", zap.Error(err))
+		return http.StatusInternalServerError, models.OutcomeFailed, err
+	}
+
+	logger.Info("Webhook event deduplication decision", zap.String("dedupe_decision", string(decision)))
+
+	switch decision {
+	case dedupeService.DecisionDuplicate:
+		return http.StatusOK, models.OutcomeDuplicate, nil
+	case dedupeService.DecisionInProgress:
+		return http.StatusConflict, models.OutcomeInProgress, errors.New("event is already being processed")
+	}
+
+	if errors.Is(translateErr, providers.ErrUnhandledEvent) {
+		logger.Info("Ignoring unhandled webhook event type")
+		metrics.Ignore(event.Provider, event.EventType)
+		c.complete(event)
+		return http.StatusOK, models.OutcomeIgnored, nil
+	}
+
+	if translateErr != nil {
+		logger.Error("Error translating webhook event", zap.Error(translateErr))
+		c.release(event)
+		return http.StatusInternalServerError, models.OutcomeFailed, translateErr
+	}
+
+	err = c.transactionService.ApplyEvent(event)
+	if errors.Is(err, transaction.ErrTransactionNotFound) {
+		logger.Warn("Transaction of webhook event not found, event parked until it is created", zap.String("transaction_ref", event.TransactionRef))
+		c.complete(event)
+		return http.StatusAccepted, models.OutcomeParked, nil
+	}
+	if err != nil {
+		logger.Error("Error processing payment", zap.String("transaction_ref", event.TransactionRef), zap.Error(err))
+		c.release(event)
+		return http.StatusInternalServerError, models.OutcomeFailed, err
+	}
+
+	c.complete(event)
+
+	logger.Info("Successfully processed webhook event", zap.String("transaction_ref", event.TransactionRef), zap.String("status", event.Status))
+	return http.StatusOK, models.OutcomeProcessed, nil
+}
+
+// deadLetter stores a failed event in the dead-letter queue, logging failures.
+func (c *WebhookHandler) deadLetter(letter models.DeadLetter) {
+	stored, err := c.deadLetterService.Store(letter)
+	if err != nil {
+		c.logger.Error("Error storing failed webhook event", zap.String("provider", letter.Provider), zap.String("event_id", letter.EventId), zap.Error(err))
+		return
+	}
+
+	c.logger.Warn("Failed webhook event stored in the dead-letter queue", zap.String("provider", letter.Provider), zap.String("event_id", letter.EventId), zap.String("dead_letter_id", stored.Id), zap.Int("attempts", stored.Attempts))
+}
+
+// complete marks an event as processed, so later deliveries of the event are skipped.
+func (c *WebhookHandler) complete(event models.PaymentEvent) {
+	if err := c.dedupeService.Complete(event.Provider, event.EventId); err != nil {
+		c.logger.Error("Error marking webhook event as processed", zap.String("provider", event.Provider), zap.String("event_id", event.EventId), zap.Error(err))
+	}
+}
+
+// release releases the deduplication lock of an event that could not be processed, so a retried delivery can process it.
+func (c *WebhookHandler) release(event models.PaymentEvent) {
+	if err := c.dedupeService.Release(event.Provider, event.EventId); err != nil {
+		c.logger.Error("Error releasing webhook event lock", zap.String("provider", event.Provider), zap.String("event_id", event.EventId), zap.Error(err))
+	}
+}
+
+// headers flattens the request headers, keeping the first value of each header.
+func headers(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for key := range header {
+		result[key] = header.Get(key)
+	}
+	return result
+}
+
+// redact replaces the values of the signature and credential headers, which are not kept in the webhook event store
+// nor in the dead-letter queue.
+func redact(headers map[string]string) map[string]string {
+	for key := range headers {
+		if slices.Contains(redactedHeaders, http.CanonicalHeaderKey(key)) {
+			headers[key] = redactedValue
+		}
+	}
+	return headers
+}
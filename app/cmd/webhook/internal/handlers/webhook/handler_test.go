@@ -0,0 +1,351 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/metrics"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/providers"
+	deadLetterService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/deadletter"
+	dedupeService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/dedupe"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/transaction"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/pending"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type ProviderMock struct {
+	mock.Mock
+}
+
+func (m *ProviderMock) Name() string {
+	return "test"
+}
+
+func (m *ProviderMock) Verify(header http.Header, body []byte, ignoreTolerance bool) error {
+	args := m.Called(header, body, ignoreTolerance)
+	return args.Error(0)
+}
+
+func (m *ProviderMock) Translate(body []byte) (models.PaymentEvent, error) {
+	args := m.Called(body)
+	return args.Get(0).(models.PaymentEvent), args.Error(1)
+}
+
+type TransactionServiceMock struct {
+	mock.Mock
+}
+
+func (m *TransactionServiceMock) AddTransaction(id string, status models.TransactionStatus, origin audit.Origin) error {
+	args := m.Called(id, status, origin)
+	return args.Error(0)
+}
+
+func (m *TransactionServiceMock) ApplyEvent(event models.PaymentEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *TransactionServiceMock) ApplyPending(id string, events []pending.Event) (bool, error) {
+	args := m.Called(id, events)
+	return args.Bool(0), args.Error(1)
+}
+
+type DedupeServiceMock struct {
+	mock.Mock
+}
+
+func (m *DedupeServiceMock) Acquire(provider string, eventId string) (dedupeService.Decision, error) {
+	args := m.Called(provider, eventId)
+	return args.Get(0).(dedupeService.Decision), args.Error(1)
+}
+
+func (m *DedupeServiceMock) Complete(provider string, eventId string) error {
+	args := m.Called(provider, eventId)
+	return args.Error(0)
+}
+
+func (m *DedupeServiceMock) Release(provider string, eventId string) error {
+	args := m.Called(provider, eventId)
+	return args.Error(0)
+}
+
+type DeadLetterServiceMock struct {
+	mock.Mock
+}
+
+func (m *DeadLetterServiceMock) Register(provider string, replayer deadLetterService.Replayer) {
+	m.Called(provider, replayer)
+}
+
+func (m *DeadLetterServiceMock) Store(letter models.DeadLetter) (*models.DeadLetter, error) {
+	args := m.Called(letter)
+	return args.Get(0).(*models.DeadLetter), args.Error(1)
+}
+
+func (m *DeadLetterServiceMock) GetAll(provider string) ([]models.DeadLetter, error) {
+	args := m.Called(provider)
+	return args.Get(0).([]models.DeadLetter), args.Error(1)
+}
+
+func (m *DeadLetterServiceMock) Get(id string) (*models.DeadLetter, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.DeadLetter), args.Error(1)
+}
+
+func (m *DeadLetterServiceMock) Replay(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *DeadLetterServiceMock) ReplayMany(ids []string, all bool) ([]models.ReplayResult, error) {
+	args := m.Called(ids, all)
+	return args.Get(0).([]models.ReplayResult), args.Error(1)
+}
+
+func (m *DeadLetterServiceMock) Discard(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type EventStoreServiceMock struct {
+	mock.Mock
+}
+
+func (m *EventStoreServiceMock) Record(event models.WebhookEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *EventStoreServiceMock) Find(filter models.WebhookEventFilter) ([]models.WebhookEvent, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.WebhookEvent), args.Error(1)
+}
+
+func (m *EventStoreServiceMock) Purge() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *EventStoreServiceMock) Run(ctx context.Context) {
+	m.Called(ctx)
+}
+
+type testHandler struct {
+	handler      *WebhookHandler
+	provider     *ProviderMock
+	transactions *TransactionServiceMock
+	dedupe       *DedupeServiceMock
+	deadLetters  *DeadLetterServiceMock
+	events       *EventStoreServiceMock
+}
+
+func newTestHandler() testHandler {
+	h := testHandler{
+		provider:     new(ProviderMock),
+		transactions: new(TransactionServiceMock),
+		dedupe:       new(DedupeServiceMock),
+		deadLetters:  new(DeadLetterServiceMock),
+		events:       new(EventStoreServiceMock),
+	}
+	h.events.On("Record", mock.Anything).Return(nil).Maybe()
+	h.handler = New(zap.NewNop(), providers.NewRegistry(h.provider), h.transactions, h.dedupe, h.deadLetters, h.events)
+	return h
+}
+
+func (h testHandler) serve(provider string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/webhooks/:provider", h.handler.WebhookHandler)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/webhooks/"+provider, bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Stripe-Signature", "t=1727776740,v1=signature")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// recorded returns the webhook event stored in the event store.
+func (h testHandler) recorded(t *testing.T) models.WebhookEvent {
+	h.events.AssertNumberOfCalls(t, "Record", 1)
+	return h.events.Calls[0].Arguments.Get(0).(models.WebhookEvent)
+}
+
+func testEvent() models.PaymentEvent {
+	return models.PaymentEvent{Provider: "test", EventId: "evt_123", EventType: "payment.succeeded", TransactionRef: "transaction1", Status: "success"}
+}
+
+func TestWebhookHandler_UnknownProvider(t *testing.T) {
+	// Arrange
+	h := newTestHandler()
+
+	// Action
+	w := h.serve("unknown")
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhookHandler_Success(t *testing.T) {
+	// Arrange
+	h := newTestHandler()
+	h.provider.On("Verify", mock.Anything, []byte(`{}`), false).Return(nil)
+	h.provider.On("Translate", []byte(`{}`)).Return(testEvent(), nil)
+	h.dedupe.On("Acquire", "test", "evt_123").Return(dedupeService.DecisionNew, nil)
+	h.dedupe.On("Complete", "test", "evt_123").Return(nil)
+	h.transactions.On("ApplyEvent", testEvent()).Return(nil)
+
+	// Action
+	w := h.serve("test")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	h.transactions.AssertExpectations(t)
+	h.dedupe.AssertExpectations(t)
+
+	recorded := h.recorded(t)
+	assert.Equal(t, models.OutcomeProcessed, recorded.Outcome)
+	assert.Equal(t, http.StatusOK, recorded.StatusCode)
+	assert.Equal(t, "evt_123", recorded.EventId)
+	assert.Equal(t, "transaction1", recorded.TransactionRef)
+	assert.Equal(t, 2, recorded.BodySize)
+	assert.Equal(t, utils.Hash(`{}`), recorded.BodySha256)
+	assert.Equal(t, "[REDACTED]", recorded.Headers["Stripe-Signature"])
+	assert.Equal(t, "application/json", recorded.Headers["Content-Type"])
+	assert.True(t, recorded.Verified)
+	assert.NotEmpty(t, recorded.ReceivedAt)
+}
+
+func TestWebhookHandler_UnhandledEvent(t *testing.T) {
+	// Arrange
+	h := newTestHandler()
+	h.provider.On("Verify", mock.Anything, mock.Anything, false).Return(nil)
+	h.provider.On("Translate", mock.Anything).Return(testEvent(), providers.ErrUnhandledEvent)
+	h.dedupe.On("Acquire", "test", "evt_123").Return(dedupeService.DecisionNew, nil)
+	h.dedupe.On("Complete", "test", "evt_123").Return(nil)
+
+	// Action
+	w := h.serve("test")
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	h.transactions.AssertNotCalled(t, "ApplyEvent", mock.Anything)
+	h.dedupe.AssertExpectations(t)
+}
+
+// counter returns the value of a metric counter, which is nil until it is first incremented.
+func counter(value expvar.Var) int64 {
+	if value == nil {
+		return 0
+	}
+	return value.(*expvar.Int).Value()
+}
+
+func TestWebhookHandler_InvalidSignature(t *testing.T) {
+	// Arrange
+	h := newTestHandler()
+	h.provider.On("Verify", mock.Anything, mock.Anything, false).Return(errors.New("invalid signature"))
+	rejected := metrics.RejectedSignatures.Get("test")
+
+	// Action
+	w := h.serve("test")
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	h.deadLetters.AssertNotCalled(t, "Store", mock.Anything)
+	h.provider.AssertNotCalled(t, "Translate", mock.Anything)
+	assert.Equal(t, counter(rejected)+1, counter(metrics.RejectedSignatures.Get("test")))
+
+	recorded := h.recorded(t)
+	assert.Equal(t, models.OutcomeRejected, recorded.Outcome)
+	assert.False(t, recorded.Verified)
+	assert.Equal(t, "invalid signature", recorded.VerificationError)
+}
+
+func TestWebhookHandler_TransactionNotFound(t *testing.T) {
+	// Arrange
+	h := newTestHandler()
+	h.provider.On("Verify", mock.Anything, mock.Anything, false).Return(nil)
+	h.provider.On("Translate", mock.Anything).Return(testEvent(), nil)
+	h.dedupe.On("Acquire", "test", "evt_123").Return(dedupeService.DecisionNew, nil)
+	h.dedupe.On("Complete", "test", "evt_123").Return(nil)
+	h.transactions.On("ApplyEvent", testEvent()).Return(transaction.ErrTransactionNotFound)
+
+	// Action
+	w := h.serve("test")
+
+	// Assert
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	h.dedupe.AssertExpectations(t)
+	h.deadLetters.AssertNotCalled(t, "Store", mock.Anything)
+	assert.Equal(t, models.OutcomeParked, h.recorded(t).Outcome)
+}
+
+func TestWebhookHandler_NoTransactionRef(t *testing.T) {
+	// Arrange
+	h := newTestHandler()
+	event := testEvent()
+	event.TransactionRef = ""
+	h.provider.On("Verify", mock.Anything, mock.Anything, false).Return(nil)
+	h.provider.On("Translate", mock.Anything).Return(event, nil)
+
+	// Action
+	w := h.serve("test")
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	h.dedupe.AssertNotCalled(t, "Acquire", mock.Anything, mock.Anything)
+	h.transactions.AssertNotCalled(t, "ApplyEvent", mock.Anything)
+	h.deadLetters.AssertNotCalled(t, "Store", mock.Anything)
+	assert.Equal(t, models.OutcomeRejected, h.recorded(t).Outcome)
+}
+
+func TestWebhookHandler_ProcessingError(t *testing.T) {
+	// Arrange
+	h := newTestHandler()
+	h.provider.On("Verify", mock.Anything, mock.Anything, false).Return(nil)
+	h.provider.On("Translate", mock.Anything).Return(testEvent(), nil)
+	h.dedupe.On("Acquire", "test", "evt_123").Return(dedupeService.DecisionNew, nil)
+	h.dedupe.On("Release", "test", "evt_123").Return(nil)
+	h.transactions.On("ApplyEvent", testEvent()).Return(errors.New("cache error"))
+	h.deadLetters.On("Store", mock.MatchedBy(func(letter models.DeadLetter) bool {
+		return letter.Provider == "test" && letter.EventId == "evt_123" && letter.Body == `{}` && letter.Headers["Stripe-Signature"] == "[REDACTED]"
+	})).Return(&models.DeadLetter{Id: "dl1", Attempts: 1}, nil)
+
+	// Action
+	w := h.serve("test")
+
+	// Assert
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	h.dedupe.AssertExpectations(t)
+	h.deadLetters.AssertExpectations(t)
+}
+
+func TestReplay(t *testing.T) {
+	// Arrange
+	h := newTestHandler()
+	letter := models.DeadLetter{Id: "dl1", Provider: "test", Body: `{}`, Headers: map[string]string{"Stripe-Signature": "[REDACTED]"}}
+	h.provider.On("Translate", []byte(`{}`)).Return(testEvent(), nil)
+	h.dedupe.On("Acquire", "test", "evt_123").Return(dedupeService.DecisionNew, nil)
+	h.dedupe.On("Complete", "test", "evt_123").Return(nil)
+	h.transactions.On("ApplyEvent", testEvent()).Return(nil)
+
+	// Action
+	err := h.handler.Replay(letter)
+
+	// Assert
+	assert.NoError(t, err)
+	h.provider.AssertNotCalled(t, "Verify", mock.Anything, mock.Anything, mock.Anything)
+	h.transactions.AssertExpectations(t)
+}
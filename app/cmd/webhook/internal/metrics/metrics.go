@@ -0,0 +1,27 @@
+package metrics
+
+import "expvar"
+
+// IgnoredEvents counts the webhook events acknowledged without processing, per provider and event type,
+// published in /debug/vars.
+var IgnoredEvents = expvar.NewMap("webhook_ignored_events")
+
+// RejectedSignatures counts the webhook requests failing signature verification, per provider, published in /debug/vars.
+var RejectedSignatures = expvar.NewMap("webhook_rejected_signatures")
+
+// Ignore records a webhook event acknowledged without processing because its type is not handled.
+//
+// Parameters:
+//   - provider: the name of the provider, such as "stripe".
+//   - eventType: the type of the event.
+func Ignore(provider string, eventType string) {
+	IgnoredEvents.Add(provider+"."+eventType, 1)
+}
+
+// RejectSignature records a webhook request rejected because its signature could not be verified.
+//
+// Parameters:
+//   - provider: the name of the provider, such as "stripe".
+func RejectSignature(provider string) {
+	RejectedSignatures.Add(provider, 1)
+}
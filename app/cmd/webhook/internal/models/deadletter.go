@@ -0,0 +1,25 @@
+package models
+
+type DeadLetter struct {
+	Id            string            `json:"id"`
+	Provider      string            `json:"provider"`
+	EventId       string            `json:"event_id,omitempty"`
+	EventType     string            `json:"event_type,omitempty"`
+	Body          string            `json:"body"`
+	Headers       map[string]string `json:"headers"`
+	Error         string            `json:"error"`
+	Attempts      int               `json:"attempts"`
+	FirstFailedAt string            `json:"first_failed_at"`
+	LastFailedAt  string            `json:"last_failed_at"`
+}
+
+type ReplayRequest struct {
+	Ids []string `json:"ids"`
+	All bool     `json:"all"`
+}
+
+type ReplayResult struct {
+	Id       string `json:"id"`
+	Replayed bool   `json:"replayed"`
+	Error    string `json:"error,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "encoding/json"
+
+const (
+	PaymentEventPayment = "payment"
+	PaymentEventRefund  = "refund"
+	PaymentEventDispute = "dispute"
+)
+
+// PaymentEvent is a provider webhook event translated into the changes it makes to a transaction.
+type PaymentEvent struct {
+	Provider       string          `json:"provider"`
+	EventId        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	TransactionRef string          `json:"transaction_ref"`
+	Type           string          `json:"type"`
+	Amount         int64           `json:"amount,omitempty"`
+	Currency       string          `json:"currency,omitempty"`
+	Status         string          `json:"status"`
+	Code           string          `json:"code,omitempty"`
+	Message        string          `json:"message,omitempty"`
+	OccurredAt     string          `json:"occurred_at"`
+	CorrelationId  string          `json:"correlation_id,omitempty"`
+	Raw            json.RawMessage `json:"raw,omitempty"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "encoding/json"
+
+type PayPalEvent struct {
+	Id           string          `json:"id"`
+	EventType    string          `json:"event_type"`
+	CreateTime   string          `json:"create_time"`
+	ResourceType string          `json:"resource_type"`
+	Summary      string          `json:"summary"`
+	Resource     json.RawMessage `json:"resource"`
+}
+
+type PayPalAmount struct {
+	CurrencyCode string `json:"currency_code"`
+	Value        string `json:"value"`
+}
+
+type PayPalLink struct {
+	Href string `json:"href"`
+	Rel  string `json:"rel"`
+}
+
+type PayPalCapture struct {
+	Id            string       `json:"id"`
+	Status        string       `json:"status"`
+	Amount        PayPalAmount `json:"amount"`
+	CustomId      string       `json:"custom_id"`
+	StatusDetails struct {
+		Reason string `json:"reason"`
+	} `json:"status_details"`
+	Links []PayPalLink `json:"links"`
+}
+
+type PayPalOrder struct {
+	Id            string `json:"id"`
+	Status        string `json:"status"`
+	PurchaseUnits []struct {
+		ReferenceId string `json:"reference_id"`
+		CustomId    string `json:"custom_id"`
+	} `json:"purchase_units"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type RiskAssessment struct {
+	Score    int      `json:"score"`
+	Decision string   `json:"decision"`
+	Reasons  []string `json:"reasons"`
+}
@@ -0,0 +1,43 @@
+package models
+
+const (
+	OutcomeProcessed  = "processed"
+	OutcomeDuplicate  = "duplicate"
+	OutcomeInProgress = "in_progress"
+	OutcomeIgnored    = "ignored"
+	OutcomeParked     = "parked"
+	OutcomeFailed     = "failed"
+	OutcomeRejected   = "rejected"
+)
+
+// WebhookEvent is the metadata of a webhook request received from a provider, with the outcome of its processing.
+// The body is not kept, only its size and SHA-256 hash, and the values of the signature and credential headers are
+// redacted.
+type WebhookEvent struct {
+	Id                string            `json:"id"`
+	Provider          string            `json:"provider"`
+	EventId           string            `json:"event_id,omitempty"`
+	EventType         string            `json:"event_type,omitempty"`
+	Type              string            `json:"type,omitempty"`
+	TransactionRef    string            `json:"transaction_ref,omitempty"`
+	BodySize          int               `json:"body_size"`
+	BodySha256        string            `json:"body_sha256,omitempty"`
+	Headers           map[string]string `json:"headers"`
+	Verified          bool              `json:"verified"`
+	VerificationError string            `json:"verification_error,omitempty"`
+	Outcome           string            `json:"outcome"`
+	StatusCode        int               `json:"status_code"`
+	Error             string            `json:"error,omitempty"`
+	ReceivedAt        string            `json:"received_at"`
+	LatencyMs         int64             `json:"latency_ms"`
+}
+
+// WebhookEventFilter selects the stored webhook events. Empty fields match every event.
+type WebhookEventFilter struct {
+	Provider      string
+	Type          string
+	TransactionId string
+	From          string
+	To            string
+	Limit         int
+}
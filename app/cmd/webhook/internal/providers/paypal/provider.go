@@ -0,0 +1,82 @@
+package paypal
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/providers"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/providers/paypal/verifier"
+)
+
+const Name = "paypal"
+
+type paypalProvider struct {
+	verifier verifier.Verifier
+}
+
+// New creates a new instance of paypalProvider with the provided verifier.
+// It returns a pointer to the newly created paypalProvider.
+//
+// Parameters:
+//   - verifier: an instance of verifier.Verifier used to verify the signature of PayPal transmissions.
+//
+// Returns:
+//   - *paypalProvider: a pointer to the newly created paypalProvider.
+func New(verifier verifier.Verifier) *paypalProvider {
+	return &paypalProvider{
+		verifier: verifier,
+	}
+}
+
+// Name returns the name of the provider, "paypal".
+func (p *paypalProvider) Name() string {
+	return Name
+}
+
+// Verify verifies the signature of a PayPal webhook transmission.
+//
+// Parameters:
+//   - header: the headers of the transmission.
+//   - body: the raw body of the transmission.
+//   - ignoreTolerance: whether transmissions older than the tolerance are accepted, as when replaying stored events.
+//
+// Returns:
+//   - error: an error if the transmission is not signed by PayPal for the configured webhook.
+func (p *paypalProvider) Verify(header http.Header, body []byte, ignoreTolerance bool) error {
+	return p.verifier.Verify(header, body, ignoreTolerance)
+}
+
+// Translate translates a PayPal event into a payment event.
+//
+// Parameters:
+//   - body: the raw body of a verified transmission.
+//
+// Returns:
+//   - models.PaymentEvent: the payment event. Its event ID and type are set when the body is a PayPal event, even
+//     if it could not be translated.
+//   - error: providers.ErrUnhandledEvent if the event type is not handled, or an error if the body is invalid.
+func (p *paypalProvider) Translate(body []byte) (models.PaymentEvent, error) {
+	var event models.PayPalEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return models.PaymentEvent{}, err
+	}
+
+	paymentEvent := models.PaymentEvent{
+		Provider:   Name,
+		EventId:    event.Id,
+		EventType:  event.EventType,
+		OccurredAt: event.CreateTime,
+		Raw:        event.Resource,
+	}
+
+	translate, exists := translators[event.EventType]
+	if !exists {
+		return paymentEvent, providers.ErrUnhandledEvent
+	}
+
+	if err := translate(event, &paymentEvent); err != nil {
+		return paymentEvent, err
+	}
+	return paymentEvent, nil
+}
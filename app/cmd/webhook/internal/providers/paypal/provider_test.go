@@ -0,0 +1,113 @@
+package paypal
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type VerifierMock struct {
+	mock.Mock
+}
+
+func (m *VerifierMock) Verify(header http.Header, body []byte, ignoreTolerance bool) error {
+	args := m.Called(header, body, ignoreTolerance)
+	return args.Error(0)
+}
+
+func testEvent(eventType string, summary string, resource string) []byte {
+	return []byte(fmt.Sprintf(`{"id":"WH-EVT-1","event_type":%q,"create_time":"2024-10-01T10:00:00Z","summary":%q,"resource":%s}`, eventType, summary, resource))
+}
+
+func TestVerify(t *testing.T) {
+	// Arrange
+	mockVerifier := new(VerifierMock)
+	provider := New(mockVerifier)
+	header := http.Header{}
+	body := []byte(`{}`)
+	mockVerifier.On("Verify", header, body, true).Return(nil)
+
+	// Action
+	err := provider.Verify(header, body, true)
+
+	// Assert
+	assert.NoError(t, err)
+	mockVerifier.AssertExpectations(t)
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		summary   string
+		resource  string
+		expected  models.PaymentEvent
+	}{
+		{
+			"Capture completed",
+			PaymentCaptureCompleted,
+			"",
+			`{"id":"CAP-1","status":"COMPLETED","custom_id":"transaction1","amount":{"currency_code":"USD","value":"10.00"}}`,
+			models.PaymentEvent{TransactionRef: "transaction1", Type: models.PaymentEventPayment, Amount: 1000, Currency: "USD", Status: "success"},
+		},
+		{
+			"Capture denied",
+			PaymentCaptureDenied,
+			"Payment denied",
+			`{"id":"CAP-1","status":"DECLINED","custom_id":"transaction1","status_details":{"reason":"BUYER_COMPLAINT"}}`,
+			models.PaymentEvent{TransactionRef: "transaction1", Type: models.PaymentEventPayment, Status: "failed", Code: "BUYER_COMPLAINT", Message: "Payment denied"},
+		},
+		{
+			"Capture refunded without custom ID",
+			PaymentCaptureRefunded,
+			"",
+			`{"id":"REF-1","amount":{"currency_code":"USD","value":"10.00"},"links":[{"rel":"up","href":"https://api.paypal.com/v2/payments/captures/CAP-1"}]}`,
+			models.PaymentEvent{TransactionRef: "CAP-1", Type: models.PaymentEventRefund, Amount: 1000, Currency: "USD", Status: "refunded", Message: "amount refunded: 10.00 USD"},
+		},
+		{
+			"Order approved",
+			CheckoutOrderApproved,
+			"",
+			`{"id":"ORDER-1","purchase_units":[{"custom_id":"transaction1"}]}`,
+			models.PaymentEvent{TransactionRef: "transaction1", Type: models.PaymentEventPayment, Status: "approved"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			provider := New(new(VerifierMock))
+
+			// Action
+			event, err := provider.Translate(testEvent(tt.eventType, tt.summary, tt.resource))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, Name, event.Provider)
+			assert.Equal(t, "WH-EVT-1", event.EventId)
+			assert.Equal(t, tt.eventType, event.EventType)
+			assert.Equal(t, "2024-10-01T10:00:00Z", event.OccurredAt)
+			assert.JSONEq(t, tt.resource, string(event.Raw))
+
+			event.Provider, event.EventId, event.EventType, event.OccurredAt, event.Raw = "", "", "", "", nil
+			assert.Equal(t, tt.expected, event)
+		})
+	}
+}
+
+func TestTranslate_UnhandledEvent(t *testing.T) {
+	// Arrange
+	provider := New(new(VerifierMock))
+
+	// Action
+	event, err := provider.Translate(testEvent("BILLING.PLAN.CREATED", "", `{}`))
+
+	// Assert
+	assert.ErrorIs(t, err, providers.ErrUnhandledEvent)
+	assert.Equal(t, "WH-EVT-1", event.EventId)
+}
@@ -0,0 +1,117 @@
+package paypal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+)
+
+// translator sets the transaction changes of a PayPal event in the payment event.
+type translator func(event models.PayPalEvent, paymentEvent *models.PaymentEvent) error
+
+const (
+	PaymentCaptureCompleted = "PAYMENT.CAPTURE.COMPLETED"
+	PaymentCaptureDenied    = "PAYMENT.CAPTURE.DENIED"
+	PaymentCaptureRefunded  = "PAYMENT.CAPTURE.REFUNDED"
+	CheckoutOrderApproved   = "CHECKOUT.ORDER.APPROVED"
+)
+
+var translators = map[string]translator{
+	PaymentCaptureCompleted: captureCompleted,
+	PaymentCaptureDenied:    captureDenied,
+	PaymentCaptureRefunded:  captureRefunded,
+	CheckoutOrderApproved:   orderApproved,
+}
+
+// captureCompleted translates "PAYMENT.CAPTURE.COMPLETED" into the status "success".
+func captureCompleted(event models.PayPalEvent, paymentEvent *models.PaymentEvent) error {
+	_, err := captureOf(event, paymentEvent, models.PaymentEventPayment, "success")
+	return err
+}
+
+// captureDenied translates "PAYMENT.CAPTURE.DENIED" into the status "failed", with the reason of the capture status
+// as code and the event summary as message.
+func captureDenied(event models.PayPalEvent, paymentEvent *models.PaymentEvent) error {
+	capture, err := captureOf(event, paymentEvent, models.PaymentEventPayment, "failed")
+	if err != nil {
+		return err
+	}
+
+	paymentEvent.Code = capture.StatusDetails.Reason
+	paymentEvent.Message = event.Summary
+	return nil
+}
+
+// captureRefunded translates "PAYMENT.CAPTURE.REFUNDED", whose resource is the refund, into the status "refunded",
+// with the refunded amount.
+func captureRefunded(event models.PayPalEvent, paymentEvent *models.PaymentEvent) error {
+	refund, err := captureOf(event, paymentEvent, models.PaymentEventRefund, "refunded")
+	if err != nil {
+		return err
+	}
+
+	paymentEvent.Message = fmt.Sprintf("amount refunded: %s %s", refund.Amount.Value, refund.Amount.CurrencyCode)
+	return nil
+}
+
+// orderApproved translates "CHECKOUT.ORDER.APPROVED" into the status "approved". Orders carry the ID of their
+// transaction as custom ID of their purchase unit, and the order ID is used otherwise.
+func orderApproved(event models.PayPalEvent, paymentEvent *models.PaymentEvent) error {
+	var order models.PayPalOrder
+	if err := json.Unmarshal(event.Resource, &order); err != nil {
+		return err
+	}
+
+	paymentEvent.TransactionRef = order.Id
+	if len(order.PurchaseUnits) > 0 && order.PurchaseUnits[0].CustomId != "" {
+		paymentEvent.TransactionRef = order.PurchaseUnits[0].CustomId
+	}
+	paymentEvent.Type = models.PaymentEventPayment
+	paymentEvent.Status = "approved"
+	return nil
+}
+
+// captureOf unmarshals the capture or refund of an event, setting the transaction, amount and status of the
+// payment event.
+func captureOf(event models.PayPalEvent, paymentEvent *models.PaymentEvent, eventType string, status string) (models.PayPalCapture, error) {
+	var capture models.PayPalCapture
+	if err := json.Unmarshal(event.Resource, &capture); err != nil {
+		return capture, err
+	}
+
+	paymentEvent.TransactionRef = captureTransactionId(capture)
+	paymentEvent.Type = eventType
+	paymentEvent.Amount = minorUnits(capture.Amount.Value)
+	paymentEvent.Currency = capture.Amount.CurrencyCode
+	paymentEvent.Status = status
+	return capture, nil
+}
+
+// captureTransactionId returns the ID of the transaction a capture or refund belongs to. Payments carry the ID of
+// their transaction as custom ID, and the capture ID is used otherwise. Refunds without custom ID use the ID of the
+// refunded capture, linked as "up".
+func captureTransactionId(capture models.PayPalCapture) string {
+	if capture.CustomId != "" {
+		return capture.CustomId
+	}
+
+	for _, link := range capture.Links {
+		if link.Rel == "up" && strings.Contains(link.Href, "/captures/") {
+			return link.Href[strings.LastIndex(link.Href, "/")+1:]
+		}
+	}
+	return capture.Id
+}
+
+// minorUnits converts a PayPal amount, such as "10.00", to the minor units of its currency, such as 1000. PayPal
+// formats amounts with the decimal digits of their currency, so the digits are kept and the point is dropped.
+func minorUnits(value string) int64 {
+	amount, err := strconv.ParseInt(strings.Replace(value, ".", "", 1), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return amount
+}
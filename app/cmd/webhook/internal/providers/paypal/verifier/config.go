@@ -0,0 +1,36 @@
+package verifier
+
+import (
+	"os"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	WebhookId  string
+	TrustStore string
+	CertHosts  []string
+	Tolerance  time.Duration
+}
+
+// LoadConfig loads the PayPal webhook verification configuration from the environment variables.
+//
+// Environment Variables:
+//   - PAYPAL_WEBHOOK_ID: the ID of the webhook registered at PayPal, part of the signed message. Every transmission
+//     is rejected while it is not set.
+//   - PAYPAL_TRUST_STORE: the path of a PEM file with the root certificates trusted to issue PayPal certificates.
+//     The system root certificates are used when it is not set.
+//   - PAYPAL_CERT_HOSTS: the comma-separated hosts certificates are downloaded from, e.g. "api.paypal.com".
+//   - PAYPAL_WEBHOOK_TOLERANCE: how old a transmission can be, e.g. "5m".
+//
+// Returns:
+//   - Config: the PayPal webhook verification configuration.
+func LoadConfig() Config {
+	return Config{
+		WebhookId:  os.Getenv("PAYPAL_WEBHOOK_ID"),
+		TrustStore: os.Getenv("PAYPAL_TRUST_STORE"),
+		CertHosts:  utils.GetEnvList("PAYPAL_CERT_HOSTS", []string{"api.paypal.com", "api.sandbox.paypal.com"}),
+		Tolerance:  utils.GetEnvDuration("PAYPAL_WEBHOOK_TOLERANCE", 5*time.Minute),
+	}
+}
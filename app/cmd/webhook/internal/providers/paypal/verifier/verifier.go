@@ -0,0 +1,274 @@
+package verifier
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TransmissionIdHeader   = "Paypal-Transmission-Id"
+	TransmissionTimeHeader = "Paypal-Transmission-Time"
+	TransmissionSigHeader  = "Paypal-Transmission-Sig"
+	CertUrlHeader          = "Paypal-Cert-Url"
+	AuthAlgoHeader         = "Paypal-Auth-Algo"
+
+	authAlgo = "SHA256withRSA"
+
+	// maxCerts is the maximum number of signing certificates kept in memory.
+	maxCerts = 32
+)
+
+var (
+	ErrMissingHeaders = errors.New("missing paypal transmission headers")
+	ErrInvalidSig     = errors.New("invalid paypal transmission signature")
+	ErrNoWebhookId    = errors.New("paypal webhook ID is not configured")
+)
+
+type Verifier interface {
+	Verify(header http.Header, body []byte, ignoreTolerance bool) error
+}
+
+// download is a download of a certificate chain in progress, shared by the transmissions signed with it.
+type download struct {
+	done  chan struct{}
+	chain []*x509.Certificate
+	err   error
+}
+
+type verifier struct {
+	config    Config
+	roots     *x509.CertPool
+	certs     map[string][]*x509.Certificate
+	downloads map[string]*download
+	mutex     sync.Mutex
+	now       func() time.Time
+	fetch     func(certUrl string) ([]byte, error)
+}
+
+// New creates a new instance of verifier with the provided configuration, loading the trust store.
+//
+// Parameters:
+//   - config: the PayPal webhook verification configuration.
+//
+// Returns:
+//   - *verifier: a pointer to the newly created verifier.
+//   - error: an error if the trust store could not be loaded.
+func New(config Config) (*verifier, error) {
+	roots, err := loadTrustStore(config.TrustStore)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	return &verifier{
+		config:    config,
+		roots:     roots,
+		certs:     map[string][]*x509.Certificate{},
+		downloads: map[string]*download{},
+		now:       time.Now,
+		fetch: func(certUrl string) ([]byte, error) {
+			res, err := client.Get(certUrl)
+			if err != nil {
+				return nil, err
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("error downloading paypal certificate: status %d", res.StatusCode)
+			}
+			return io.ReadAll(res.Body)
+		},
+	}, nil
+}
+
+// Verify verifies the signature of a PayPal webhook transmission. The signed message is
+// "<transmission id>|<transmission time>|<webhook id>|<crc32 of the body>", signed with the certificate at the
+// certificate URL, whose chain must be issued by the trust store. Every transmission is rejected while the webhook
+// ID is not configured.
+//
+// Parameters:
+//   - header: the headers of the transmission.
+//   - body: the raw body of the transmission.
+//   - ignoreTolerance: whether transmissions older than the tolerance are accepted, as when replaying stored events.
+//
+// Returns:
+//   - error: ErrNoWebhookId if the webhook ID is not configured, or an error if the transmission is not signed by
+//     PayPal for the configured webhook.
+func (v *verifier) Verify(header http.Header, body []byte, ignoreTolerance bool) error {
+	if v.config.WebhookId == "" {
+		return ErrNoWebhookId
+	}
+
+	transmissionId := header.Get(TransmissionIdHeader)
+	transmissionTime := header.Get(TransmissionTimeHeader)
+	transmissionSig := header.Get(TransmissionSigHeader)
+	certUrl := header.Get(CertUrlHeader)
+
+	if transmissionId == "" || transmissionTime == "" || transmissionSig == "" || certUrl == "" {
+		return ErrMissingHeaders
+	}
+
+	if algo := header.Get(AuthAlgoHeader); algo != authAlgo {
+		return fmt.Errorf("unsupported paypal auth algorithm %q", algo)
+	}
+
+	sentAt, err := time.Parse(time.RFC3339, transmissionTime)
+	if err != nil {
+		return fmt.Errorf("invalid paypal transmission time: %w", err)
+	}
+
+	if !ignoreTolerance && v.now().Sub(sentAt).Abs() > v.config.Tolerance {
+		return fmt.Errorf("paypal transmission time %s is outside the tolerance", transmissionTime)
+	}
+
+	cert, err := v.certificate(certUrl)
+	if err != nil {
+		return err
+	}
+
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("paypal certificate has no rsa public key")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(transmissionSig)
+	if err != nil {
+		return ErrInvalidSig
+	}
+
+	message := fmt.Sprintf("%s|%s|%s|%d", transmissionId, transmissionTime, v.config.WebhookId, crc32.ChecksumIEEE(body))
+	digest := sha256.Sum256([]byte(message))
+
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return ErrInvalidSig
+	}
+
+	return nil
+}
+
+// certificate returns the signing certificate at the URL, downloading it and verifying its chain on first use.
+func (v *verifier) certificate(certUrl string) (*x509.Certificate, error) {
+	parsed, err := url.Parse(certUrl)
+	if err != nil || parsed.Scheme != "https" || !slices.Contains(v.config.CertHosts, parsed.Hostname()) {
+		return nil, fmt.Errorf("untrusted paypal certificate url %q", certUrl)
+	}
+
+	chain, err := v.chain(certUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	// The chain is verified on every use, so expired certificates are rejected.
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	leaf := chain[0]
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: v.roots, Intermediates: intermediates, CurrentTime: v.now()}); err != nil {
+		return nil, fmt.Errorf("untrusted paypal certificate: %w", err)
+	}
+
+	if !strings.HasPrefix(leaf.Subject.CommonName, "messageverificationcerts.") || !strings.HasSuffix(leaf.Subject.CommonName, ".paypal.com") {
+		return nil, fmt.Errorf("unexpected paypal certificate subject %q", leaf.Subject.CommonName)
+	}
+
+	return leaf, nil
+}
+
+// chain returns the certificate chain at the URL, downloading it on first use. The download runs without holding the
+// lock, and concurrent transmissions signed with the same certificate wait for a single download.
+func (v *verifier) chain(certUrl string) ([]*x509.Certificate, error) {
+	v.mutex.Lock()
+	if chain, cached := v.certs[certUrl]; cached {
+		v.mutex.Unlock()
+		return chain, nil
+	}
+
+	if current, downloading := v.downloads[certUrl]; downloading {
+		v.mutex.Unlock()
+		<-current.done
+		return current.chain, current.err
+	}
+
+	current := &download{done: make(chan struct{})}
+	v.downloads[certUrl] = current
+	v.mutex.Unlock()
+
+	data, err := v.fetch(certUrl)
+	if err == nil {
+		current.chain, err = parseChain(data)
+	}
+	current.err = err
+
+	v.mutex.Lock()
+	delete(v.downloads, certUrl)
+	if err == nil {
+		// PayPal rotates its certificates rarely, so dropping any cached one keeps the cache bounded.
+		for cachedUrl := range v.certs {
+			if len(v.certs) < maxCerts {
+				break
+			}
+			delete(v.certs, cachedUrl)
+		}
+		v.certs[certUrl] = current.chain
+	}
+	v.mutex.Unlock()
+	close(current.done)
+
+	return current.chain, current.err
+}
+
+func parseChain(data []byte) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid paypal certificate: %w", err)
+		}
+		chain = append(chain, cert)
+	}
+
+	if len(chain) == 0 {
+		return nil, errors.New("no paypal certificate found")
+	}
+	return chain, nil
+}
+
+func loadTrustStore(path string) (*x509.CertPool, error) {
+	if path == "" {
+		return x509.SystemCertPool()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading paypal trust store: %w", err)
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(data) {
+		return nil, errors.New("no certificate found in paypal trust store")
+	}
+	return roots, nil
+}
@@ -0,0 +1,212 @@
+package verifier
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	certUrl   = "https://api.paypal.com/v1/notifications/certs/CERT-360caa42"
+	webhookId = "WH-123"
+)
+
+var now = time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC)
+
+type testPki struct {
+	rootPem  []byte
+	chainPem []byte
+	key      *rsa.PrivateKey
+}
+
+func newTestPki(t *testing.T) testPki {
+	rootKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDer, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	assert.NoError(t, err)
+	root, _ := x509.ParseCertificate(rootDer)
+
+	leafKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "messageverificationcerts.paypal.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	leafDer, err := x509.CreateCertificate(rand.Reader, leafTemplate, root, &leafKey.PublicKey, rootKey)
+	assert.NoError(t, err)
+
+	return testPki{
+		rootPem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootDer}),
+		chainPem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDer}),
+		key:      leafKey,
+	}
+}
+
+func (p testPki) header(t *testing.T, body []byte, transmissionTime string) http.Header {
+	message := fmt.Sprintf("tx-1|%s|%s|%d", transmissionTime, webhookId, crc32.ChecksumIEEE(body))
+	digest := sha256.Sum256([]byte(message))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	assert.NoError(t, err)
+
+	header := http.Header{}
+	header.Set(TransmissionIdHeader, "tx-1")
+	header.Set(TransmissionTimeHeader, transmissionTime)
+	header.Set(TransmissionSigHeader, base64.StdEncoding.EncodeToString(signature))
+	header.Set(CertUrlHeader, certUrl)
+	header.Set(AuthAlgoHeader, "SHA256withRSA")
+	return header
+}
+
+func newTestVerifier(t *testing.T, pki testPki, trusted []byte) *verifier {
+	trustStore := filepath.Join(t.TempDir(), "roots.pem")
+	assert.NoError(t, os.WriteFile(trustStore, trusted, 0o600))
+
+	v, err := New(Config{
+		WebhookId:  webhookId,
+		TrustStore: trustStore,
+		CertHosts:  []string{"api.paypal.com"},
+		Tolerance:  5 * time.Minute,
+	})
+	assert.NoError(t, err)
+
+	v.now = func() time.Time { return now }
+	v.fetch = func(string) ([]byte, error) { return pki.chainPem, nil }
+	return v
+}
+
+func TestVerify(t *testing.T) {
+	pki := newTestPki(t)
+	otherPki := newTestPki(t)
+	body := []byte(`{"id":"WH-EVT-1","event_type":"PAYMENT.CAPTURE.COMPLETED"}`)
+
+	tests := []struct {
+		name            string
+		trusted         []byte
+		header          func() http.Header
+		body            []byte
+		ignoreTolerance bool
+		wantErr         bool
+	}{
+		{"Valid transmission", pki.rootPem, func() http.Header { return pki.header(t, body, "2024-10-01T09:59:00Z") }, body, false, false},
+		{"Tampered body", pki.rootPem, func() http.Header { return pki.header(t, body, "2024-10-01T09:59:00Z") }, []byte(`{"id":"WH-EVT-2"}`), false, true},
+		{"Outside tolerance", pki.rootPem, func() http.Header { return pki.header(t, body, "2024-10-01T09:00:00Z") }, body, false, true},
+		{"Outside tolerance on replay", pki.rootPem, func() http.Header { return pki.header(t, body, "2024-10-01T09:00:00Z") }, body, true, false},
+		{"Untrusted root", otherPki.rootPem, func() http.Header { return pki.header(t, body, "2024-10-01T09:59:00Z") }, body, false, true},
+		{"Untrusted certificate host", pki.rootPem, func() http.Header {
+			header := pki.header(t, body, "2024-10-01T09:59:00Z")
+			header.Set(CertUrlHeader, "https://attacker.example.com/cert.pem")
+			return header
+		}, body, false, true},
+		{"Missing headers", pki.rootPem, func() http.Header { return http.Header{} }, body, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			v := newTestVerifier(t, pki, tt.trusted)
+
+			// Action
+			err := v.Verify(tt.header(), tt.body, tt.ignoreTolerance)
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestVerify_WithoutWebhookId(t *testing.T) {
+	// Arrange
+	pki := newTestPki(t)
+	body := []byte(`{"id":"WH-EVT-1"}`)
+	v := newTestVerifier(t, pki, pki.rootPem)
+	v.config.WebhookId = ""
+
+	// Action
+	err := v.Verify(pki.header(t, body, "2024-10-01T09:59:00Z"), body, false)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNoWebhookId)
+}
+
+func TestVerify_DownloadsCertificateOnce(t *testing.T) {
+	// Arrange
+	pki := newTestPki(t)
+	body := []byte(`{"id":"WH-EVT-1"}`)
+	v := newTestVerifier(t, pki, pki.rootPem)
+
+	var downloads atomic.Int32
+	release := make(chan struct{})
+	v.fetch = func(string) ([]byte, error) {
+		downloads.Add(1)
+		<-release
+		return pki.chainPem, nil
+	}
+
+	// Action
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = v.Verify(pki.header(t, body, "2024-10-01T09:59:00Z"), body, false)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Assert
+	assert.Equal(t, int32(1), downloads.Load())
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+func TestCertificate_BoundsCache(t *testing.T) {
+	// Arrange
+	pki := newTestPki(t)
+	v := newTestVerifier(t, pki, pki.rootPem)
+
+	// Action
+	for i := 0; i < maxCerts+10; i++ {
+		_, err := v.certificate(fmt.Sprintf("%s-%d", certUrl, i))
+		assert.NoError(t, err)
+	}
+
+	// Assert
+	assert.Len(t, v.certs, maxCerts)
+	assert.Empty(t, v.downloads)
+}
@@ -0,0 +1,70 @@
+package providers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+)
+
+var (
+	// ErrUnknownProvider is returned for providers not registered.
+	ErrUnknownProvider = errors.New("unknown webhook provider")
+
+	// ErrUnhandledEvent is returned by Translate for event types without a translation.
+	ErrUnhandledEvent = errors.New("unhandled webhook event type")
+)
+
+// Provider verifies the webhooks of a payment provider and translates their payloads into payment events.
+type Provider interface {
+	Name() string
+	Verify(header http.Header, body []byte, ignoreTolerance bool) error
+	Translate(body []byte) (models.PaymentEvent, error)
+}
+
+// Registry holds the providers webhooks are received from, by name.
+type Registry map[string]Provider
+
+// NewRegistry creates a registry of the provided providers.
+//
+// Parameters:
+//   - providers: the providers webhooks are received from.
+//
+// Returns:
+//   - Registry: the providers by name.
+func NewRegistry(providers ...Provider) Registry {
+	registry := make(Registry, len(providers))
+	for _, provider := range providers {
+		registry[provider.Name()] = provider
+	}
+	return registry
+}
+
+// Get returns the provider with the name.
+//
+// Parameters:
+//   - name: the name of the provider, such as "stripe".
+//
+// Returns:
+//   - Provider: the provider.
+//   - error: ErrUnknownProvider if no provider is registered with the name.
+func (r Registry) Get(name string) (Provider, error) {
+	if provider, exists := r[name]; exists {
+		return provider, nil
+	}
+	return nil, ErrUnknownProvider
+}
+
+// Names returns the names of the registered providers, sorted.
+//
+// Returns:
+//   - []string: the names of the providers.
+func (r Registry) Names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
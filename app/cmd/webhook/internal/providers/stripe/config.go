@@ -0,0 +1,20 @@
+package stripe
+
+import "os"
+
+type Config struct {
+	WebhookSecret string
+}
+
+// LoadConfig loads the Stripe webhook configuration from the environment variables.
+//
+// Environment Variables:
+//   - STRIPE_WEBHOOK_KEY: the signing secret of the webhook endpoint registered at Stripe.
+//
+// Returns:
+//   - Config: the Stripe webhook configuration.
+func LoadConfig() Config {
+	return Config{
+		WebhookSecret: os.Getenv("STRIPE_WEBHOOK_KEY"),
+	}
+}
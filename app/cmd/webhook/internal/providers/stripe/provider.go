@@ -0,0 +1,101 @@
+package stripe
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/providers"
+	"github.com/stripe/stripe-go"
+	"github.com/stripe/stripe-go/webhook"
+)
+
+const (
+	Name = "stripe"
+
+	SignatureHeader = "Stripe-Signature"
+)
+
+type stripeProvider struct {
+	config Config
+}
+
+// New creates a new instance of stripeProvider with the provided configuration.
+// It returns a pointer to the newly created stripeProvider.
+//
+// Parameters:
+//   - config: the Stripe webhook configuration.
+//
+// Returns:
+//   - *stripeProvider: a pointer to the newly created stripeProvider.
+func New(config Config) *stripeProvider {
+	return &stripeProvider{
+		config: config,
+	}
+}
+
+// Name returns the name of the provider, "stripe".
+func (p *stripeProvider) Name() string {
+	return Name
+}
+
+// Verify verifies the Stripe-Signature header of a webhook request against the signing secret.
+//
+// Parameters:
+//   - header: the headers of the request.
+//   - body: the raw body of the request.
+//   - ignoreTolerance: whether requests older than the Stripe timestamp tolerance are accepted, as when replaying
+//     stored events.
+//
+// Returns:
+//   - error: an error if the request is not signed by Stripe.
+func (p *stripeProvider) Verify(header http.Header, body []byte, ignoreTolerance bool) error {
+	var err error
+	if ignoreTolerance {
+		_, err = webhook.ConstructEventIgnoringTolerance(body, header.Get(SignatureHeader), p.config.WebhookSecret)
+	} else {
+		_, err = webhook.ConstructEvent(body, header.Get(SignatureHeader), p.config.WebhookSecret)
+	}
+	return err
+}
+
+// Translate translates a Stripe event into a payment event.
+//
+// Parameters:
+//   - body: the raw body of a verified request.
+//
+// Returns:
+//   - models.PaymentEvent: the payment event. Its event ID and type are set when the body is a Stripe event, even
+//     if it could not be translated.
+//   - error: providers.ErrUnhandledEvent if the event type is not handled, or an error if the body is invalid.
+func (p *stripeProvider) Translate(body []byte) (models.PaymentEvent, error) {
+	var event stripe.Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return models.PaymentEvent{}, err
+	}
+
+	paymentEvent := models.PaymentEvent{
+		Provider:   Name,
+		EventId:    event.ID,
+		EventType:  event.Type,
+		OccurredAt: occurredAt(event),
+	}
+	if event.Data != nil {
+		paymentEvent.Raw = event.Data.Raw
+	}
+
+	translate, exists := translators[event.Type]
+	if !exists {
+		return paymentEvent, providers.ErrUnhandledEvent
+	}
+
+	if event.Data == nil {
+		return paymentEvent, errors.New("stripe event has no data")
+	}
+
+	if err := translate(event, &paymentEvent); err != nil {
+		return paymentEvent, err
+	}
+	return paymentEvent, nil
+}
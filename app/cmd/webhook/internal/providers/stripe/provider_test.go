@@ -0,0 +1,126 @@
+package stripe
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stripe/stripe-go/webhook"
+)
+
+func testEvent(eventType string, data string) []byte {
+	return []byte(fmt.Sprintf(`{"id":"evt_123","type":%q,"created":1727776800,"data":{"object":%s}}`, eventType, data))
+}
+
+func TestVerify(t *testing.T) {
+	body := testEvent(PaymentIntentSucceeded, `{"id":"pi_123"}`)
+	signature := func(signedAt time.Time, secret string) http.Header {
+		header := http.Header{}
+		header.Set(SignatureHeader, fmt.Sprintf("t=%d,v1=%s", signedAt.Unix(), hex.EncodeToString(webhook.ComputeSignature(signedAt, body, secret))))
+		return header
+	}
+
+	tests := []struct {
+		name            string
+		header          http.Header
+		ignoreTolerance bool
+		wantErr         bool
+	}{
+		{"Valid signature", signature(time.Now(), "whsec_123"), false, false},
+		{"Wrong secret", signature(time.Now(), "whsec_other"), false, true},
+		{"Outside tolerance", signature(time.Now().Add(-time.Hour), "whsec_123"), false, true},
+		{"Outside tolerance on replay", signature(time.Now().Add(-time.Hour), "whsec_123"), true, false},
+		{"Missing signature", http.Header{}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			provider := New(Config{WebhookSecret: "whsec_123"})
+
+			// Action
+			err := provider.Verify(tt.header, body, tt.ignoreTolerance)
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	occurredAt := time.Unix(1727776800, 0).Format(time.RFC3339)
+	tests := []struct {
+		name      string
+		eventType string
+		data      string
+		expected  models.PaymentEvent
+	}{
+		{
+			"Payment failed with decline code",
+			PaymentIntentPaymentFailed,
+			`{"id":"pi_123","amount":1000,"currency":"usd","metadata":{"transaction_id":"transaction1","correlation_id":"correlation1"},"last_payment_error":{"code":"card_declined","decline_code":"insufficient_funds","message":"Your card has insufficient funds."}}`,
+			models.PaymentEvent{TransactionRef: "transaction1", Type: models.PaymentEventPayment, Amount: 1000, Currency: "usd", Status: "failed", Code: "insufficient_funds", Message: "Your card has insufficient funds.", CorrelationId: "correlation1"},
+		},
+		{
+			"Payment canceled",
+			PaymentIntentCanceled,
+			`{"id":"pi_123","cancellation_reason":"abandoned"}`,
+			models.PaymentEvent{TransactionRef: "pi_123", Type: models.PaymentEventPayment, Status: "canceled", Code: "abandoned"},
+		},
+		{
+			"Charge partially refunded",
+			ChargeRefunded,
+			`{"id":"ch_123","payment_intent":"pi_123","amount":1000,"currency":"usd","refunded":false,"amount_refunded":500}`,
+			models.PaymentEvent{TransactionRef: "pi_123", Type: models.PaymentEventRefund, Amount: 500, Currency: "usd", Status: "partially_refunded", Message: "amount refunded: 500"},
+		},
+		{
+			"Dispute won",
+			ChargeDisputeClosed,
+			`{"id":"dp_123","payment_intent":"pi_123","status":"won"}`,
+			models.PaymentEvent{TransactionRef: "pi_123", Type: models.PaymentEventDispute, Status: "dispute_won", Code: "won"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			provider := New(Config{})
+
+			// Action
+			event, err := provider.Translate(testEvent(tt.eventType, tt.data))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, Name, event.Provider)
+			assert.Equal(t, "evt_123", event.EventId)
+			assert.Equal(t, tt.eventType, event.EventType)
+			assert.Equal(t, occurredAt, event.OccurredAt)
+			assert.JSONEq(t, tt.data, string(event.Raw))
+
+			event.Provider, event.EventId, event.EventType, event.OccurredAt, event.Raw = "", "", "", "", nil
+			assert.Equal(t, tt.expected, event)
+		})
+	}
+}
+
+func TestTranslate_UnhandledEvent(t *testing.T) {
+	// Arrange
+	provider := New(Config{})
+
+	// Action
+	event, err := provider.Translate(testEvent("customer.created", `{"id":"cus_123"}`))
+
+	// Assert
+	assert.ErrorIs(t, err, providers.ErrUnhandledEvent)
+	assert.Equal(t, "evt_123", event.EventId)
+	assert.Equal(t, "customer.created", event.EventType)
+}
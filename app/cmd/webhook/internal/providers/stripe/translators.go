@@ -0,0 +1,243 @@
+package stripe
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/stripe/stripe-go"
+)
+
+// translator sets the transaction changes of a Stripe event in the payment event.
+type translator func(event stripe.Event, paymentEvent *models.PaymentEvent) error
+
+const (
+	PaymentIntentCreated                 = "payment_intent.created"
+	PaymentIntentSucceeded               = "payment_intent.succeeded"
+	PaymentIntentPaymentFailed           = "payment_intent.payment_failed"
+	PaymentIntentProcessing              = "payment_intent.processing"
+	PaymentIntentCanceled                = "payment_intent.canceled"
+	PaymentIntentRequiresAction          = "payment_intent.requires_action"
+	PaymentIntentAmountCapturableUpdated = "payment_intent.amount_capturable_updated"
+	ChargeRefunded                       = "charge.refunded"
+	ChargeCaptured                       = "charge.captured"
+	ChargeDisputeCreated                 = "charge.dispute.created"
+	ChargeDisputeClosed                  = "charge.dispute.closed"
+)
+
+var translators = map[string]translator{
+	PaymentIntentCreated:                 paymentIntentStatus("created"),
+	PaymentIntentSucceeded:               paymentIntentStatus("success"),
+	PaymentIntentPaymentFailed:           paymentFailed,
+	PaymentIntentProcessing:              paymentIntentStatus("processing"),
+	PaymentIntentCanceled:                paymentCanceled,
+	PaymentIntentRequiresAction:          paymentRequiresAction,
+	PaymentIntentAmountCapturableUpdated: paymentCapturable,
+	ChargeRefunded:                       chargeRefunded,
+	ChargeCaptured:                       chargeCaptured,
+	ChargeDisputeCreated:                 disputeCreated,
+	ChargeDisputeClosed:                  disputeClosed,
+}
+
+// paymentIntentStatus returns a translator of payment intent events setting the status to the transaction.
+func paymentIntentStatus(status string) translator {
+	return func(event stripe.Event, paymentEvent *models.PaymentEvent) error {
+		_, err := paymentIntentOf(event, paymentEvent, status)
+		return err
+	}
+}
+
+// paymentFailed translates "payment_intent.payment_failed" into the status "failed", with the decline code and
+// message of the last payment error.
+func paymentFailed(event stripe.Event, paymentEvent *models.PaymentEvent) error {
+	paymentIntent, err := paymentIntentOf(event, paymentEvent, "failed")
+	if err != nil {
+		return err
+	}
+
+	if paymentError := paymentIntent.LastPaymentError; paymentError != nil {
+		paymentEvent.Code = string(paymentError.DeclineCode)
+		if paymentEvent.Code == "" {
+			paymentEvent.Code = string(paymentError.Code)
+		}
+		paymentEvent.Message = paymentError.Msg
+	}
+	return nil
+}
+
+// paymentCanceled translates "payment_intent.canceled" into the status "canceled", with the cancellation reason.
+func paymentCanceled(event stripe.Event, paymentEvent *models.PaymentEvent) error {
+	paymentIntent, err := paymentIntentOf(event, paymentEvent, "canceled")
+	if err != nil {
+		return err
+	}
+
+	paymentEvent.Code = string(paymentIntent.CancellationReason)
+	return nil
+}
+
+// paymentRequiresAction translates "payment_intent.requires_action" into the status "requires_action", with the
+// type of the next action the customer must take.
+func paymentRequiresAction(event stripe.Event, paymentEvent *models.PaymentEvent) error {
+	paymentIntent, err := paymentIntentOf(event, paymentEvent, "requires_action")
+	if err != nil {
+		return err
+	}
+
+	if paymentIntent.NextAction != nil {
+		paymentEvent.Code = string(paymentIntent.NextAction.Type)
+	}
+	return nil
+}
+
+// paymentCapturable translates "payment_intent.amount_capturable_updated" into the status "authorized", with the
+// amount that can be captured.
+func paymentCapturable(event stripe.Event, paymentEvent *models.PaymentEvent) error {
+	paymentIntent, err := paymentIntentOf(event, paymentEvent, "authorized")
+	if err != nil {
+		return err
+	}
+
+	paymentEvent.Amount = paymentIntent.AmountCapturable
+	paymentEvent.Message = fmt.Sprintf("amount capturable: %d", paymentIntent.AmountCapturable)
+	return nil
+}
+
+// chargeRefunded translates "charge.refunded" into the status "refunded", or "partially_refunded" when only part
+// of the amount was refunded, with the refunded amount.
+func chargeRefunded(event stripe.Event, paymentEvent *models.PaymentEvent) error {
+	charge, err := chargeOf(event, paymentEvent, "refunded")
+	if err != nil {
+		return err
+	}
+
+	if !charge.Refunded {
+		paymentEvent.Status = "partially_refunded"
+	}
+	paymentEvent.Type = models.PaymentEventRefund
+	paymentEvent.Amount = charge.AmountRefunded
+	paymentEvent.Message = fmt.Sprintf("amount refunded: %d", charge.AmountRefunded)
+	return nil
+}
+
+// chargeCaptured translates "charge.captured" into the status "captured".
+func chargeCaptured(event stripe.Event, paymentEvent *models.PaymentEvent) error {
+	_, err := chargeOf(event, paymentEvent, "captured")
+	return err
+}
+
+// disputeCreated translates "charge.dispute.created" into the status "disputed", with the reason of the dispute.
+func disputeCreated(event stripe.Event, paymentEvent *models.PaymentEvent) error {
+	dispute, err := disputeOf(event, paymentEvent, "disputed")
+	if err != nil {
+		return err
+	}
+
+	paymentEvent.Code = string(dispute.Reason)
+	return nil
+}
+
+// disputeClosed translates "charge.dispute.closed" into the status "dispute_won" or "dispute_lost", or
+// "dispute_closed" for other outcomes, with the status of the dispute.
+func disputeClosed(event stripe.Event, paymentEvent *models.PaymentEvent) error {
+	dispute, err := disputeOf(event, paymentEvent, "dispute_closed")
+	if err != nil {
+		return err
+	}
+
+	switch dispute.Status {
+	case stripe.DisputeStatusWon:
+		paymentEvent.Status = "dispute_won"
+	case stripe.DisputeStatusLost:
+		paymentEvent.Status = "dispute_lost"
+	}
+	paymentEvent.Code = string(dispute.Status)
+	return nil
+}
+
+// paymentIntentOf unmarshals the payment intent of an event, setting the transaction, amount and status of the
+// payment event.
+func paymentIntentOf(event stripe.Event, paymentEvent *models.PaymentEvent, status string) (stripe.PaymentIntent, error) {
+	var paymentIntent stripe.PaymentIntent
+	if err := json.Unmarshal(event.Data.Raw, &paymentIntent); err != nil {
+		return paymentIntent, err
+	}
+
+	paymentEvent.TransactionRef = transactionId(paymentIntent)
+	paymentEvent.Type = models.PaymentEventPayment
+	paymentEvent.Amount = paymentIntent.Amount
+	paymentEvent.Currency = string(paymentIntent.Currency)
+	paymentEvent.Status = status
+	paymentEvent.CorrelationId = paymentIntent.Metadata["correlation_id"]
+	return paymentIntent, nil
+}
+
+// chargeOf unmarshals the charge of an event, setting the transaction, amount and status of the payment event.
+func chargeOf(event stripe.Event, paymentEvent *models.PaymentEvent, status string) (stripe.Charge, error) {
+	var charge stripe.Charge
+	if err := json.Unmarshal(event.Data.Raw, &charge); err != nil {
+		return charge, err
+	}
+
+	paymentEvent.TransactionRef = chargeTransactionId(charge)
+	paymentEvent.Type = models.PaymentEventPayment
+	paymentEvent.Amount = charge.Amount
+	paymentEvent.Currency = string(charge.Currency)
+	paymentEvent.Status = status
+	paymentEvent.CorrelationId = charge.Metadata["correlation_id"]
+	return charge, nil
+}
+
+// disputeOf unmarshals the dispute of an event, setting the transaction, amount and status of the payment event.
+func disputeOf(event stripe.Event, paymentEvent *models.PaymentEvent, status string) (stripe.Dispute, error) {
+	var dispute stripe.Dispute
+	if err := json.Unmarshal(event.Data.Raw, &dispute); err != nil {
+		return dispute, err
+	}
+
+	paymentEvent.TransactionRef = disputeTransactionId(dispute)
+	paymentEvent.Type = models.PaymentEventDispute
+	paymentEvent.Amount = dispute.Amount
+	paymentEvent.Currency = string(dispute.Currency)
+	paymentEvent.Status = status
+	paymentEvent.CorrelationId = dispute.Metadata["correlation_id"]
+	return dispute, nil
+}
+
+// transactionId returns the ID of the transaction a payment intent belongs to. Payments processed asynchronously
+// carry the ID of their transaction in the payment intent metadata, while payments processed during the api request
+// are stored under the payment intent ID.
+func transactionId(paymentIntent stripe.PaymentIntent) string {
+	if id := paymentIntent.Metadata["transaction_id"]; id != "" {
+		return id
+	}
+	return paymentIntent.ID
+}
+
+// chargeTransactionId returns the ID of the transaction a charge belongs to, from the charge metadata or its payment intent.
+func chargeTransactionId(charge stripe.Charge) string {
+	if id := charge.Metadata["transaction_id"]; id != "" {
+		return id
+	}
+	return charge.PaymentIntent
+}
+
+// disputeTransactionId returns the ID of the transaction a dispute belongs to.
+func disputeTransactionId(dispute stripe.Dispute) string {
+	if id := dispute.Metadata["transaction_id"]; id != "" {
+		return id
+	}
+	if dispute.PaymentIntent != nil {
+		return dispute.PaymentIntent.ID
+	}
+	if dispute.Charge != nil {
+		return chargeTransactionId(*dispute.Charge)
+	}
+	return ""
+}
+
+// occurredAt returns the time a Stripe event was created.
+func occurredAt(event stripe.Event) string {
+	return time.Unix(event.Created, 0).Format(time.RFC3339)
+}
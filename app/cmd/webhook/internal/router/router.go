@@ -1,40 +1,108 @@
 package router
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/index"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/notification"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/pending"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/queue"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
-	paypalHandler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/handlers/paypal"
-	stripeHandler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/handlers/stripe"
+	deadLetterHandler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/handlers/deadletter"
+	eventStoreHandler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/handlers/eventstore"
+	webhookHandler "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/handlers/webhook"
 
-	paypalService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/paypal"
-	stripeService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/stripe"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/providers"
+	paypalProvider "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/providers/paypal"
+	paypalVerifier "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/providers/paypal/verifier"
+	stripeProvider "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/providers/stripe"
+
+	deadLetterService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/deadletter"
+	dedupeService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/dedupe"
+	eventStoreService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/eventstore"
+	transactionService "github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/services/transaction"
 )
 
 func Init(route *gin.Engine, logger *zap.Logger) {
-	cacheClient := cache.New()
+	cacheConfig := cache.LoadConfig()
+	cacheClient, err := cache.NewClient(cacheConfig)
+	if err != nil {
+		logger.Fatal("Error creating cache client", zap.Error(err))
+	}
+
+	apiKeys, err := middleware.LoadAPIKeys()
+	if err != nil {
+		logger.Fatal("Error loading API keys", zap.Error(err))
+	}
+	adminAuth := middleware.Authenticate(apiKeys, middleware.RoleAdmin)
+
+	notificationService := notification.New(cacheClient, queue.NewClient(cacheConfig), logger, notification.LoadConfig())
+	go notificationService.Run(context.Background())
+
+	auditService := audit.New(cacheClient)
+
+	pendingService := pending.New(cacheClient, logger, pending.LoadConfig())
+
+	indexService := index.New(cacheClient, index.LoadConfig())
+
+	transactionService := transactionService.New(cacheClient, notificationService, auditService, pendingService, indexService)
+	go pendingService.Run(context.Background(), transactionService.ApplyPending)
+
+	dedupeService := dedupeService.New(cacheClient, dedupeService.LoadConfig())
+	deadLetterService := deadLetterService.New(cacheClient, deadLetterService.LoadConfig())
 
-	stripeService := stripeService.New(cacheClient)
-	stripeHandler := stripeHandler.New(logger, stripeService)
+	eventStoreService := eventStoreService.New(cacheClient, logger, eventStoreService.LoadConfig())
+	go eventStoreService.Run(context.Background())
 
-	paypalService := paypalService.New(cacheClient)
-	paypalHandler := paypalHandler.New(logger, paypalService)
+	paypalVerifier, err := paypalVerifier.New(paypalVerifier.LoadConfig())
+	if err != nil {
+		logger.Fatal("Error loading PayPal webhook verification", zap.Error(err))
+	}
+
+	providers := providers.NewRegistry(
+		stripeProvider.New(stripeProvider.LoadConfig()),
+		paypalProvider.New(paypalVerifier),
+	)
+
+	webhookHandler := webhookHandler.New(logger, providers, transactionService, dedupeService, deadLetterService, eventStoreService)
+	for _, name := range providers.Names() {
+		deadLetterService.Register(name, webhookHandler)
+	}
+
+	deadLetterHandler := deadLetterHandler.New(logger, deadLetterService)
+	eventStoreHandler := eventStoreHandler.New(logger, eventStoreService)
 
 	groupRoute := route.Group("/api/v1")
 
+	groupRoute.POST("/webhooks/:provider", webhookHandler.WebhookHandler)
+
 	stripeGroup := groupRoute.Group("/stripe")
 	{
-		stripeGroup.POST("/webhook", stripeHandler.WebhookHandler)
+		stripeGroup.POST("/webhook", webhookHandler.Handler(stripeProvider.Name))
 	}
 
 	gatewayGroup := groupRoute.Group("/paypal")
 	{
-		gatewayGroup.POST("/webhook", paypalHandler.WebhookHandler)
+		gatewayGroup.POST("/webhook", webhookHandler.Handler(paypalProvider.Name))
 	}
 
+	deadLetterGroup := groupRoute.Group("/dead-letters", adminAuth)
+	{
+		deadLetterGroup.GET("", deadLetterHandler.GetAllHandler)
+		deadLetterGroup.GET("/:id", deadLetterHandler.GetHandler)
+		deadLetterGroup.POST("/replay", deadLetterHandler.ReplayManyHandler)
+		deadLetterGroup.POST("/:id/replay", deadLetterHandler.ReplayHandler)
+		deadLetterGroup.DELETE("/:id", deadLetterHandler.DiscardHandler)
+	}
+
+	groupRoute.GET("/webhook-events", adminAuth, eventStoreHandler.GetAllHandler)
+
 	route.GET("/ping", func(ctx *gin.Context) {
 		ctx.String(http.StatusOK, "pong")
 	})
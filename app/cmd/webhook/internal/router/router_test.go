@@ -5,6 +5,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -13,6 +15,8 @@ import (
 func TestInit(t *testing.T) {
 	// Arrange
 	gin.SetMode(gin.TestMode)
+	t.Setenv("CACHE_DRIVER", "memory")
+	t.Setenv("ADMIN_API_KEYS", "ops1:admin-key")
 	router := gin.Default()
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
@@ -22,15 +26,28 @@ func TestInit(t *testing.T) {
 	tests := []struct {
 		method   string
 		endpoint string
+		apiKey   string
 		expected int
 	}{
-		{"POST", "/api/v1/paypal/webhook", http.StatusBadRequest},
-		{"POST", "/api/v1/stripe/webhook", http.StatusInternalServerError},
+		{"POST", "/api/v1/paypal/webhook", "", http.StatusBadRequest},
+		{"POST", "/api/v1/stripe/webhook", "", http.StatusBadRequest},
+		{"POST", "/api/v1/webhooks/paypal", "", http.StatusBadRequest},
+		{"POST", "/api/v1/webhooks/stripe", "", http.StatusBadRequest},
+		{"POST", "/api/v1/webhooks/unknown", "", http.StatusNotFound},
+		{"GET", "/api/v1/webhook-events", "", http.StatusUnauthorized},
+		{"GET", "/api/v1/webhook-events", "admin-key", http.StatusOK},
+		{"GET", "/api/v1/dead-letters", "", http.StatusUnauthorized},
+		{"GET", "/api/v1/dead-letters", "admin-key", http.StatusOK},
+		{"GET", "/debug/vars", "", http.StatusNotFound},
 	}
 
 	for _, tt := range tests {
 		// Action
 		req, _ := http.NewRequest(tt.method, tt.endpoint, nil)
+		req.Header.Set("x-mgc-correlationId", utils.GenerateGUID())
+		if tt.apiKey != "" {
+			req.Header.Set(middleware.APIKeyHeader, tt.apiKey)
+		}
 		w := httptest.NewRecorder()
 
 		router.ServeHTTP(w, req)
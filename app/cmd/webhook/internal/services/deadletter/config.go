@@ -0,0 +1,28 @@
+package deadletter
+
+import (
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	Retention  time.Duration
+	MaxLetters int
+}
+
+// LoadConfig loads the dead-letter queue configuration from the environment variables.
+//
+// Environment Variables:
+//   - WEBHOOK_DLQ_RETENTION: how long a failed event is kept after its last failure, e.g. "168h".
+//   - WEBHOOK_DLQ_MAX_LETTERS: the maximum number of failed events kept. New failed events are refused when
+//     the queue is full, while failures of events already kept are still recorded.
+//
+// Returns:
+//   - Config: the dead-letter queue configuration.
+func LoadConfig() Config {
+	return Config{
+		Retention:  utils.GetEnvDuration("WEBHOOK_DLQ_RETENTION", 7*24*time.Hour),
+		MaxLetters: utils.GetEnvInt("WEBHOOK_DLQ_MAX_LETTERS", 10000),
+	}
+}
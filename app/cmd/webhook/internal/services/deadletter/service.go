@@ -0,0 +1,283 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrReplayUnsupported  = errors.New("replay is not supported for the provider")
+	ErrEventIdRequired    = errors.New("dead letter has no event ID")
+	ErrQueueFull          = errors.New("dead-letter queue is full")
+)
+
+// Replayer processes again a failed event of a provider from its stored request.
+type Replayer interface {
+	Replay(letter models.DeadLetter) error
+}
+
+type DeadLetterService interface {
+	Register(provider string, replayer Replayer)
+	Store(letter models.DeadLetter) (*models.DeadLetter, error)
+	GetAll(provider string) ([]models.DeadLetter, error)
+	Get(id string) (*models.DeadLetter, error)
+	Replay(id string) error
+	ReplayMany(ids []string, all bool) ([]models.ReplayResult, error)
+	Discard(id string) error
+}
+
+type deadLetterService struct {
+	cache     cache.CacheClient
+	config    Config
+	replayers map[string]Replayer
+}
+
+// New creates a new instance of deadLetterService with the provided cache client and configuration.
+// It returns a pointer to the newly created deadLetterService.
+//
+// Parameters:
+//   - cache: an instance of cache.CacheClient where failed webhook events are kept, each in its own key expiring
+//     after the retention, with an index of the kept events.
+//   - config: the dead-letter queue configuration.
+//
+// Returns:
+//   - *deadLetterService: a pointer to the newly created deadLetterService.
+func New(cache cache.CacheClient, config Config) *deadLetterService {
+	return &deadLetterService{
+		cache:     cache,
+		config:    config,
+		replayers: map[string]Replayer{},
+	}
+}
+
+// Register sets the replayer used to replay the failed events of a provider.
+//
+// Parameters:
+//   - provider: the name of the provider, such as "stripe".
+//   - replayer: the replayer of the provider events.
+func (s *deadLetterService) Register(provider string, replayer Replayer) {
+	s.replayers[provider] = replayer
+}
+
+// Store records a failed webhook event for the retention window. The ID of a stored event is derived from its
+// provider and event ID, so a new failure of an event already stored updates the stored event and increments its
+// attempt count.
+//
+// Parameters:
+//   - letter: the failed event, with its raw body, headers and processing error.
+//
+// Returns:
+//   - *models.DeadLetter: the stored event.
+//   - error: ErrEventIdRequired if the event has no ID, ErrQueueFull if the queue holds the maximum number of
+//     events, or an error if the event could not be stored.
+func (s *deadLetterService) Store(letter models.DeadLetter) (*models.DeadLetter, error) {
+	if utils.IsEmptyOrNull(letter.EventId) {
+		return nil, ErrEventIdRequired
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	letter.Id = letterId(letter.Provider, letter.EventId)
+	letter.Attempts = 1
+	letter.FirstFailedAt = now
+	letter.LastFailedAt = now
+
+	existing, err := s.Get(letter.Id)
+	switch {
+	case err == nil:
+		letter.Attempts = existing.Attempts + 1
+		letter.FirstFailedAt = existing.FirstFailedAt
+	case errors.Is(err, ErrDeadLetterNotFound):
+		ids, err := s.cache.HGetAll(cache.WebhookDeadLetterIndexKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) >= s.config.MaxLetters {
+			return nil, ErrQueueFull
+		}
+	default:
+		return nil, err
+	}
+
+	if err := s.cache.Set(letterKey(letter.Id), utils.ToJSON(letter), s.config.Retention); err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.HSet(cache.WebhookDeadLetterIndexKey, letter.Id, letter.Provider); err != nil {
+		return nil, err
+	}
+
+	return &letter, nil
+}
+
+// GetAll retrieves the stored failed events, oldest first.
+//
+// Parameters:
+//   - provider: the name of the provider to filter by, or an empty string for all providers.
+//
+// Returns:
+//   - []models.DeadLetter: the stored failed events.
+//   - error: an error if the events could not be retrieved.
+func (s *deadLetterService) GetAll(provider string) ([]models.DeadLetter, error) {
+	ids, err := s.cache.HGetAll(cache.WebhookDeadLetterIndexKey)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.DeadLetter, 0, len(ids))
+	for id, letterProvider := range ids {
+		if !utils.IsEmptyOrNull(provider) && string(letterProvider) != provider {
+			continue
+		}
+
+		letter, err := s.Get(id)
+		if errors.Is(err, ErrDeadLetterNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *letter)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FirstFailedAt < result[j].FirstFailedAt
+	})
+
+	return result, nil
+}
+
+// Get retrieves a stored failed event.
+//
+// Parameters:
+//   - id: the ID of the stored event.
+//
+// Returns:
+//   - *models.DeadLetter: the stored event.
+//   - error: ErrDeadLetterNotFound if the event does not exist, or an error if it could not be retrieved.
+func (s *deadLetterService) Get(id string) (*models.DeadLetter, error) {
+	c, err := s.cache.Get(letterKey(id))
+	if err != nil {
+		if err.Error() == cache.ErrCacheMiss.Error() {
+			if _, err := s.cache.HDelete(cache.WebhookDeadLetterIndexKey, id); err != nil {
+				return nil, err
+			}
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+
+	var letter models.DeadLetter
+	if err := json.Unmarshal(c, &letter); err != nil {
+		return nil, fmt.Errorf("invalid dead letter: %w", err)
+	}
+
+	return &letter, nil
+}
+
+// Replay processes a stored failed event again with the replayer of its provider. Replayed events are removed,
+// and events failing again are kept with their new error and an incremented attempt count.
+//
+// Parameters:
+//   - id: the ID of the stored event.
+//
+// Returns:
+//   - error: ErrDeadLetterNotFound if the event does not exist, ErrReplayUnsupported if its provider has no replayer,
+//     or the error of the replay.
+func (s *deadLetterService) Replay(id string) error {
+	letter, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+
+	replayer, exists := s.replayers[letter.Provider]
+	if !exists {
+		return ErrReplayUnsupported
+	}
+
+	if replayErr := replayer.Replay(*letter); replayErr != nil {
+		letter.Error = replayErr.Error()
+		if _, err := s.Store(*letter); err != nil {
+			return err
+		}
+		return replayErr
+	}
+
+	return s.Discard(id)
+}
+
+// ReplayMany replays several stored failed events, continuing when one of them fails.
+//
+// Parameters:
+//   - ids: the IDs of the stored events.
+//   - all: whether every stored event is replayed, in which case ids is ignored.
+//
+// Returns:
+//   - []models.ReplayResult: the result of the replay of each event.
+//   - error: an error if the stored events could not be retrieved.
+func (s *deadLetterService) ReplayMany(ids []string, all bool) ([]models.ReplayResult, error) {
+	if all {
+		letters, err := s.GetAll("")
+		if err != nil {
+			return nil, err
+		}
+
+		ids = make([]string, 0, len(letters))
+		for _, letter := range letters {
+			ids = append(ids, letter.Id)
+		}
+	}
+
+	results := make([]models.ReplayResult, 0, len(ids))
+	for _, id := range ids {
+		result := models.ReplayResult{Id: id, Replayed: true}
+		if err := s.Replay(id); err != nil {
+			result.Replayed = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Discard removes a stored failed event without processing it.
+//
+// Parameters:
+//   - id: the ID of the stored event.
+//
+// Returns:
+//   - error: ErrDeadLetterNotFound if the event does not exist, or an error if it could not be removed.
+func (s *deadLetterService) Discard(id string) error {
+	deleted, err := s.cache.Delete(letterKey(id))
+	if err != nil {
+		return err
+	}
+
+	indexed, err := s.cache.HDelete(cache.WebhookDeadLetterIndexKey, id)
+	if err != nil {
+		return err
+	}
+
+	if *deleted == 0 && !indexed {
+		return ErrDeadLetterNotFound
+	}
+	return nil
+}
+
+// letterId returns the ID of the stored event of a provider event.
+func letterId(provider string, eventId string) string {
+	return utils.Hash(provider + ":" + eventId)[:32]
+}
+
+// letterKey returns the cache key of a stored event.
+func letterKey(id string) string {
+	return fmt.Sprintf("%s_%s", cache.WebhookDeadLetterKey, id)
+}
@@ -0,0 +1,211 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReplayer struct {
+	mock.Mock
+}
+
+func (m *MockReplayer) Replay(letter models.DeadLetter) error {
+	args := m.Called(letter)
+	return args.Error(0)
+}
+
+// storedId is the ID of the event stored by storeLetters.
+var storedId = letterId("stripe", "evt_123")
+
+func testConfig() Config {
+	return Config{
+		Retention:  time.Hour,
+		MaxLetters: 2,
+	}
+}
+
+func storeLetters(cacheClient cache.CacheClient) {
+	cacheClient.Set(letterKey(storedId), utils.ToJSON(models.DeadLetter{
+		Id:            storedId,
+		Provider:      "stripe",
+		EventId:       "evt_123",
+		Body:          `{"id":"evt_123"}`,
+		Error:         "cache error",
+		Attempts:      1,
+		FirstFailedAt: "2024-10-01T10:00:00Z",
+		LastFailedAt:  "2024-10-01T10:00:00Z",
+	}), time.Hour)
+	cacheClient.HSet(cache.WebhookDeadLetterIndexKey, storedId, "stripe")
+}
+
+func savedLetter(t *testing.T, cacheClient cache.CacheClient, id string) models.DeadLetter {
+	var letter models.DeadLetter
+	c, err := cacheClient.Get(letterKey(id))
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(c, &letter))
+	return letter
+}
+
+func TestStore_NewEvent(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, testConfig())
+
+	// Action
+	letter, err := service.Store(models.DeadLetter{Provider: "stripe", EventId: "evt_123", Error: "cache error"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, letterId("stripe", "evt_123"), letter.Id)
+	assert.Equal(t, 1, letter.Attempts)
+	assert.Equal(t, "cache error", savedLetter(t, cacheClient, letter.Id).Error)
+
+	ids, _ := cacheClient.HGetAll(cache.WebhookDeadLetterIndexKey)
+	assert.Contains(t, ids, letter.Id)
+}
+
+func TestStore_KnownEventIncrementsAttempts(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, testConfig())
+	first, _ := service.Store(models.DeadLetter{Provider: "stripe", EventId: "evt_123", Error: "cache error"})
+
+	// Action
+	letter, err := service.Store(models.DeadLetter{Provider: "stripe", EventId: "evt_123", Error: "timeout"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, first.Id, letter.Id)
+	assert.Equal(t, 2, letter.Attempts)
+	assert.Equal(t, first.FirstFailedAt, letter.FirstFailedAt)
+	assert.Equal(t, "timeout", savedLetter(t, cacheClient, letter.Id).Error)
+}
+
+func TestStore_WithoutEventId(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, testConfig())
+
+	// Action
+	letter, err := service.Store(models.DeadLetter{Provider: "stripe", Body: "{}", Error: "invalid signature"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrEventIdRequired)
+	assert.Nil(t, letter)
+}
+
+func TestStore_QueueFull(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, testConfig())
+	service.Store(models.DeadLetter{Provider: "stripe", EventId: "evt_1"})
+	service.Store(models.DeadLetter{Provider: "stripe", EventId: "evt_2"})
+
+	// Action
+	letter, err := service.Store(models.DeadLetter{Provider: "stripe", EventId: "evt_3"})
+	known, errKnown := service.Store(models.DeadLetter{Provider: "stripe", EventId: "evt_1"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrQueueFull)
+	assert.Nil(t, letter)
+	assert.NoError(t, errKnown)
+	assert.Equal(t, 2, known.Attempts)
+}
+
+func TestGetAll_SkipsExpiredEvents(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, testConfig())
+	storeLetters(cacheClient)
+	cacheClient.HSet(cache.WebhookDeadLetterIndexKey, "letter2", "stripe")
+
+	// Action
+	letters, err := service.GetAll("stripe")
+	others, errOthers := service.GetAll("paypal")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, letters, 1)
+	assert.Equal(t, storedId, letters[0].Id)
+	assert.NoError(t, errOthers)
+	assert.Empty(t, others)
+
+	ids, _ := cacheClient.HGetAll(cache.WebhookDeadLetterIndexKey)
+	assert.NotContains(t, ids, "letter2")
+}
+
+func TestGet_NotFound(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, testConfig())
+	storeLetters(cacheClient)
+
+	// Action
+	letter, err := service.Get("letter2")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+	assert.Nil(t, letter)
+}
+
+func TestReplay_Success(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	mockReplayer := new(MockReplayer)
+	service := New(cacheClient, testConfig())
+	service.Register("stripe", mockReplayer)
+	storeLetters(cacheClient)
+	mockReplayer.On("Replay", mock.Anything).Return(nil)
+
+	// Action
+	err := service.Replay(storedId)
+
+	// Assert
+	assert.NoError(t, err)
+	_, errGet := service.Get(storedId)
+	assert.ErrorIs(t, errGet, ErrDeadLetterNotFound)
+}
+
+func TestReplay_FailureKeepsEvent(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	mockReplayer := new(MockReplayer)
+	service := New(cacheClient, testConfig())
+	service.Register("stripe", mockReplayer)
+	storeLetters(cacheClient)
+	mockReplayer.On("Replay", mock.Anything).Return(errors.New("timeout"))
+
+	// Action
+	err := service.Replay(storedId)
+
+	// Assert
+	assert.EqualError(t, err, "timeout")
+	letter := savedLetter(t, cacheClient, storedId)
+	assert.Equal(t, 2, letter.Attempts)
+	assert.Equal(t, "timeout", letter.Error)
+}
+
+func TestReplayMany_ContinuesAfterFailure(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, testConfig())
+	storeLetters(cacheClient)
+
+	// Action
+	results, err := service.ReplayMany([]string{"letter2", storedId}, false)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ReplayResult{
+		{Id: "letter2", Error: ErrDeadLetterNotFound.Error()},
+		{Id: storedId, Error: ErrReplayUnsupported.Error()},
+	}, results)
+}
@@ -0,0 +1,27 @@
+package dedupe
+
+import (
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	Retention time.Duration
+	LockTTL   time.Duration
+}
+
+// LoadConfig loads the webhook deduplication configuration from the environment variables.
+//
+// Environment Variables:
+//   - WEBHOOK_DEDUPE_RETENTION: how long processed event IDs are kept, e.g. "72h". Stripe retries events for up to three days.
+//   - WEBHOOK_DEDUPE_LOCK_TTL: how long an event being processed is locked against concurrent duplicates, e.g. "30s".
+//
+// Returns:
+//   - Config: the webhook deduplication configuration.
+func LoadConfig() Config {
+	return Config{
+		Retention: utils.GetEnvDuration("WEBHOOK_DEDUPE_RETENTION", 72*time.Hour),
+		LockTTL:   utils.GetEnvDuration("WEBHOOK_DEDUPE_LOCK_TTL", 30*time.Second),
+	}
+}
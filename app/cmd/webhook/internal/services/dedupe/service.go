@@ -0,0 +1,146 @@
+package dedupe
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+)
+
+type Decision string
+
+const (
+	DecisionNew        Decision = "new"
+	DecisionDuplicate  Decision = "duplicate"
+	DecisionInProgress Decision = "in_progress"
+)
+
+// decisions counts the deduplication decisions per provider and decision, published in /debug/vars.
+var decisions = expvar.NewMap("webhook_dedupe_decisions")
+
+type DedupeService interface {
+	Acquire(provider string, eventId string) (Decision, error)
+	Complete(provider string, eventId string) error
+	Release(provider string, eventId string) error
+}
+
+type dedupeService struct {
+	cache  cache.CacheClient
+	config Config
+}
+
+// New creates a new instance of dedupeService with the provided cache client and configuration.
+// It returns a pointer to the newly created dedupeService.
+//
+// Parameters:
+//   - cache: an instance of cache.CacheClient where processed event IDs and processing locks are kept.
+//   - config: the webhook deduplication configuration.
+//
+// Returns:
+//   - *dedupeService: a pointer to the newly created dedupeService.
+func New(cache cache.CacheClient, config Config) *dedupeService {
+	return &dedupeService{
+		cache:  cache,
+		config: config,
+	}
+}
+
+// Acquire decides whether a provider event must be processed. Events already processed are duplicates, and events
+// locked by a concurrent delivery are in progress. Otherwise the event is locked for the lock TTL and must be processed,
+// then completed or released. The event is checked again once locked, so an event completed concurrently is never
+// processed twice.
+//
+// Parameters:
+//   - provider: the name of the provider that sent the event, such as "stripe".
+//   - eventId: the ID of the event at the provider.
+//
+// Returns:
+//   - Decision: DecisionNew, DecisionDuplicate or DecisionInProgress.
+//   - error: an error if the cache cannot be read or written.
+func (s *dedupeService) Acquire(provider string, eventId string) (Decision, error) {
+	processed, err := s.processed(provider, eventId)
+	if err != nil {
+		return "", err
+	}
+	if processed {
+		return s.decide(provider, DecisionDuplicate), nil
+	}
+
+	locked, err := s.cache.SetNX(lockKey(provider, eventId), time.Now().Format(time.RFC3339), s.config.LockTTL)
+	if err != nil {
+		return "", err
+	}
+	if !locked {
+		return s.decide(provider, DecisionInProgress), nil
+	}
+
+	// A concurrent delivery may have completed the event, releasing its lock, between the check above and
+	// taking the lock, so the event is checked again while holding the lock.
+	processed, err = s.processed(provider, eventId)
+	if err != nil {
+		return "", errors.Join(err, s.Release(provider, eventId))
+	}
+	if processed {
+		return s.decide(provider, DecisionDuplicate), s.Release(provider, eventId)
+	}
+
+	return s.decide(provider, DecisionNew), nil
+}
+
+// Complete marks a provider event as processed for the retention window and releases its lock.
+//
+// Parameters:
+//   - provider: the name of the provider that sent the event.
+//   - eventId: the ID of the event at the provider.
+//
+// Returns:
+//   - error: an error if the event cannot be marked as processed.
+func (s *dedupeService) Complete(provider string, eventId string) error {
+	if err := s.cache.Set(processedKey(provider, eventId), time.Now().Format(time.RFC3339), s.config.Retention); err != nil {
+		return err
+	}
+	return s.Release(provider, eventId)
+}
+
+// Release releases the lock of a provider event that could not be processed, so a retried delivery can process it.
+//
+// Parameters:
+//   - provider: the name of the provider that sent the event.
+//   - eventId: the ID of the event at the provider.
+//
+// Returns:
+//   - error: an error if the lock cannot be removed.
+func (s *dedupeService) Release(provider string, eventId string) error {
+	_, err := s.cache.Delete(lockKey(provider, eventId))
+	return err
+}
+
+// processed reports whether a provider event was already processed.
+func (s *dedupeService) processed(provider string, eventId string) (bool, error) {
+	_, err := s.cache.Get(processedKey(provider, eventId))
+	if err == nil {
+		return true, nil
+	}
+	if err.Error() != cache.ErrCacheMiss.Error() {
+		return false, err
+	}
+	return false, nil
+}
+
+// decide counts the decision in the deduplication metrics and returns it.
+func (s *dedupeService) decide(provider string, decision Decision) Decision {
+	decisions.Add(fmt.Sprintf("%s_%s", provider, decision), 1)
+	return decision
+}
+
+// processedKey returns the cache key marking a provider event as processed.
+func processedKey(provider string, eventId string) string {
+	return fmt.Sprintf("%s_%s_%s", cache.WebhookProcessedKey, provider, eventId)
+}
+
+// lockKey returns the cache key locking a provider event while it is processed.
+func lockKey(provider string, eventId string) string {
+	return fmt.Sprintf("%s_%s_%s", cache.WebhookLockKey, provider, eventId)
+}
@@ -0,0 +1,120 @@
+package dedupe
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingCache struct {
+	cache.CacheClient
+}
+
+func (c failingCache) Get(key string) ([]byte, error) {
+	return nil, errors.New("connection refused")
+}
+
+// completingCache completes the event, as a concurrent delivery would, right before the lock is taken.
+type completingCache struct {
+	cache.CacheClient
+}
+
+func (c completingCache) SetNX(key string, item interface{}, expiration time.Duration) (bool, error) {
+	c.CacheClient.Set("webhook_processed_key_stripe_evt_123", "2024-10-01T10:00:00Z", time.Hour)
+	return c.CacheClient.SetNX(key, item, expiration)
+}
+
+func testConfig() Config {
+	return Config{
+		Retention: time.Hour,
+		LockTTL:   time.Second,
+	}
+}
+
+func TestAcquire(t *testing.T) {
+	tests := []struct {
+		name      string
+		processed bool
+		locked    bool
+		want      Decision
+	}{
+		{"New event", false, false, DecisionNew},
+		{"Processed event", true, false, DecisionDuplicate},
+		{"Event being processed", false, true, DecisionInProgress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cacheClient := cache.NewMemory()
+			service := New(cacheClient, testConfig())
+
+			if tt.processed {
+				cacheClient.Set("webhook_processed_key_stripe_evt_123", "2024-10-01T10:00:00Z", time.Hour)
+			}
+			if tt.locked {
+				cacheClient.Set("webhook_lock_key_stripe_evt_123", "2024-10-01T10:00:00Z", time.Second)
+			}
+
+			// Action
+			decision, err := service.Acquire("stripe", "evt_123")
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, decision)
+		})
+	}
+}
+
+func TestAcquire_CompletedWhileLocking(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(completingCache{cacheClient}, testConfig())
+
+	// Action
+	decision, err := service.Acquire("stripe", "evt_123")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, DecisionDuplicate, decision)
+
+	_, err = cacheClient.Get("webhook_lock_key_stripe_evt_123")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+}
+
+func TestAcquire_Failure_Get(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(failingCache{cacheClient}, testConfig())
+
+	// Action
+	decision, err := service.Acquire("stripe", "evt_123")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Empty(t, decision)
+
+	_, err = cacheClient.Get("webhook_lock_key_stripe_evt_123")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+}
+
+func TestComplete_Success(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, testConfig())
+	cacheClient.Set("webhook_lock_key_stripe_evt_123", "2024-10-01T10:00:00Z", time.Second)
+
+	// Action
+	err := service.Complete("stripe", "evt_123")
+
+	// Assert
+	assert.NoError(t, err)
+
+	_, err = cacheClient.Get("webhook_processed_key_stripe_evt_123")
+	assert.NoError(t, err)
+	_, err = cacheClient.Get("webhook_lock_key_stripe_evt_123")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+}
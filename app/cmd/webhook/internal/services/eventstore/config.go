@@ -0,0 +1,27 @@
+package eventstore
+
+import (
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+// LoadConfig loads the webhook event store configuration from the environment variables.
+//
+// Environment Variables:
+//   - WEBHOOK_EVENT_RETENTION: how long received webhook events are kept, e.g. "720h". Events are purged by day.
+//   - WEBHOOK_EVENT_PURGE_INTERVAL: how often the events older than the retention are purged, e.g. "1h".
+//
+// Returns:
+//   - Config: the webhook event store configuration.
+func LoadConfig() Config {
+	return Config{
+		Retention:     utils.GetEnvDuration("WEBHOOK_EVENT_RETENTION", 30*24*time.Hour),
+		PurgeInterval: utils.GetEnvDuration("WEBHOOK_EVENT_PURGE_INTERVAL", time.Hour),
+	}
+}
@@ -0,0 +1,339 @@
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+
+	// dayFormat is the format of the days events are grouped by, sortable as text.
+	dayFormat = "2006_01_02"
+)
+
+var ErrInvalidFilter = errors.New("invalid webhook event filter")
+
+type EventStoreService interface {
+	Record(event models.WebhookEvent) error
+	Find(filter models.WebhookEventFilter) ([]models.WebhookEvent, error)
+	Purge() error
+	Run(ctx context.Context)
+}
+
+type eventStoreService struct {
+	cache   cache.CacheClient
+	logger  *zap.Logger
+	config  Config
+	mutex   sync.Mutex
+	lastDay string
+	now     func() time.Time
+}
+
+// New creates a new instance of eventStoreService with the provided cache client, logger and configuration.
+// It returns a pointer to the newly created eventStoreService.
+//
+// Parameters:
+//   - cache: an instance of cache.CacheClient where received webhook events are kept.
+//   - logger: an instance of zap.Logger used to log purge failures.
+//   - config: the webhook event store configuration.
+//
+// Returns:
+//   - *eventStoreService: a pointer to the newly created eventStoreService.
+func New(cache cache.CacheClient, logger *zap.Logger, config Config) *eventStoreService {
+	return &eventStoreService{
+		cache:  cache,
+		logger: logger,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Record stores a received webhook event, grouped by the day it was received. The event is also indexed by its
+// provider and day and by its transaction, and the indexes expire after the retention period.
+//
+// Parameters:
+//   - event: the metadata of the received event, with its verification result, processing outcome and latency.
+//
+// Returns:
+//   - error: an error if the event could not be stored.
+func (s *eventStoreService) Record(event models.WebhookEvent) error {
+	if utils.IsEmptyOrNull(event.Id) {
+		event.Id = utils.GenerateGUID()
+	}
+
+	receivedAt, err := time.Parse(time.RFC3339Nano, event.ReceivedAt)
+	if err != nil {
+		receivedAt = s.now()
+		event.ReceivedAt = receivedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	day := receivedAt.UTC().Format(dayFormat)
+	if err := s.addDay(day); err != nil {
+		return err
+	}
+
+	eventSerialized, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if err := s.cache.Append(key(day), string(eventSerialized)); err != nil {
+		return err
+	}
+
+	// Provider indexes are kept for a day longer, so an index expires after every event of its day is purged.
+	if err := s.cache.AppendExpiring(providerKey(day, event.Provider), s.config.Retention+24*time.Hour, string(eventSerialized)); err != nil {
+		return err
+	}
+
+	if utils.IsEmptyOrNull(event.TransactionRef) {
+		return nil
+	}
+	return s.cache.AppendExpiring(transactionKey(event.TransactionRef), s.config.Retention, string(eventSerialized))
+}
+
+// Find retrieves the stored webhook events matching a filter, newest first.
+// The time range defaults to the retention period, and the number of events to DefaultLimit. Events of a transaction
+// are read from its index, and events of a provider from the indexes of the provider for each day.
+//
+// Parameters:
+//   - filter: the provider, event type (of the provider or normalized), transaction ID and time range of the events.
+//
+// Returns:
+//   - []models.WebhookEvent: the matching events.
+//   - error: ErrInvalidFilter if the time range is invalid, or an error if the events could not be retrieved.
+func (s *eventStoreService) Find(filter models.WebhookEventFilter) ([]models.WebhookEvent, error) {
+	now := s.now().UTC()
+	from := now.Add(-s.config.Retention)
+	to := now
+
+	if !utils.IsEmptyOrNull(filter.From) {
+		value, err := time.Parse(time.RFC3339, filter.From)
+		if err != nil {
+			return nil, fmt.Errorf("%w: from must be an RFC 3339 time", ErrInvalidFilter)
+		}
+		from = maxTime(from, value.UTC())
+	}
+
+	if !utils.IsEmptyOrNull(filter.To) {
+		value, err := time.Parse(time.RFC3339, filter.To)
+		if err != nil {
+			return nil, fmt.Errorf("%w: to must be an RFC 3339 time", ErrInvalidFilter)
+		}
+		to = value.UTC()
+	}
+
+	if to.Before(from) {
+		return []models.WebhookEvent{}, nil
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+
+	keys := []string{transactionKey(filter.TransactionId)}
+	if utils.IsEmptyOrNull(filter.TransactionId) {
+		keys = []string{}
+		for day := truncateDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+			if utils.IsEmptyOrNull(filter.Provider) {
+				keys = append(keys, key(day.Format(dayFormat)))
+				continue
+			}
+			keys = append(keys, providerKey(day.Format(dayFormat), filter.Provider))
+		}
+	}
+
+	result := []models.WebhookEvent{}
+	for _, listKey := range keys {
+		items, err := s.cache.GetList(listKey)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			var event models.WebhookEvent
+			if err := json.Unmarshal(item, &event); err != nil {
+				continue
+			}
+
+			receivedAt, err := time.Parse(time.RFC3339Nano, event.ReceivedAt)
+			if err != nil || receivedAt.Before(from) || receivedAt.After(to) {
+				continue
+			}
+
+			if matches(event, filter) {
+				result = append(result, event)
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ReceivedAt > result[j].ReceivedAt
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+// Purge deletes the days of events received before the retention period.
+//
+// Returns:
+//   - error: an error if the days could not be retrieved or deleted.
+func (s *eventStoreService) Purge() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	days, err := s.getDays()
+	if err != nil {
+		return err
+	}
+
+	cutoff := s.now().UTC().Add(-s.config.Retention).Format(dayFormat)
+
+	kept := make([]string, 0, len(days))
+	for _, day := range days {
+		if day >= cutoff {
+			kept = append(kept, day)
+			continue
+		}
+
+		if _, err := s.cache.Delete(key(day)); err != nil {
+			return err
+		}
+	}
+
+	if len(kept) == len(days) {
+		return nil
+	}
+
+	s.lastDay = ""
+	return s.setDays(kept)
+}
+
+// Run purges the events older than the retention period at the configured interval until the context is canceled.
+//
+// Parameters:
+//   - ctx: the context whose cancellation stops the purges.
+func (s *eventStoreService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Purge(); err != nil {
+				s.logger.Error("Failed to purge webhook events", zap.Error(err))
+			}
+		}
+	}
+}
+
+// addDay adds a day to the days with stored events, so it is purged once it is older than the retention period.
+func (s *eventStoreService) addDay(day string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.lastDay == day {
+		return nil
+	}
+
+	days, err := s.getDays()
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(days, day) {
+		days = append(days, day)
+		sort.Strings(days)
+		if err := s.setDays(days); err != nil {
+			return err
+		}
+	}
+
+	s.lastDay = day
+	return nil
+}
+
+// matches reports whether an event matches the provider, type and transaction of a filter.
+func matches(event models.WebhookEvent, filter models.WebhookEventFilter) bool {
+	if !utils.IsEmptyOrNull(filter.Provider) && event.Provider != filter.Provider {
+		return false
+	}
+	if !utils.IsEmptyOrNull(filter.Type) && event.EventType != filter.Type && event.Type != filter.Type {
+		return false
+	}
+	if !utils.IsEmptyOrNull(filter.TransactionId) && event.TransactionRef != filter.TransactionId {
+		return false
+	}
+	return true
+}
+
+func truncateDay(value time.Time) time.Time {
+	return time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func maxTime(a time.Time, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func key(day string) string {
+	return fmt.Sprintf("%s_%s", cache.WebhookEventsKey, day)
+}
+
+func providerKey(day string, provider string) string {
+	return fmt.Sprintf("%s_%s_%s", cache.WebhookEventsKey, day, provider)
+}
+
+func transactionKey(transactionRef string) string {
+	return fmt.Sprintf("%s_transaction_%s", cache.WebhookEventsKey, transactionRef)
+}
+
+func (s *eventStoreService) getDays() ([]string, error) {
+	days := []string{}
+
+	c, err := s.cache.Get(cache.WebhookEventDaysKey)
+	if err != nil {
+		if err.Error() == cache.ErrCacheMiss.Error() {
+			return days, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(c, &days); err != nil {
+		return nil, fmt.Errorf("invalid webhook event days: %w", err)
+	}
+
+	return days, nil
+}
+
+func (s *eventStoreService) setDays(days []string) error {
+	daysSerialized, err := json.Marshal(days)
+	if err != nil {
+		return err
+	}
+
+	return s.cache.Set(cache.WebhookEventDaysKey, string(daysSerialized), 0)
+}
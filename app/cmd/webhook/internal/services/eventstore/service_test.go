@@ -0,0 +1,133 @@
+package eventstore
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+var now = time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC)
+
+func newTestService(cacheClient cache.CacheClient) *eventStoreService {
+	service := New(cacheClient, zap.NewNop(), Config{Retention: 30 * 24 * time.Hour, PurgeInterval: time.Hour})
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func testEvents() []models.WebhookEvent {
+	return []models.WebhookEvent{
+		{Id: "1", Provider: "stripe", EventType: "payment_intent.succeeded", Type: models.PaymentEventPayment, TransactionRef: "pi_123", ReceivedAt: "2024-10-01T08:00:00Z"},
+		{Id: "2", Provider: "stripe", EventType: "charge.refunded", Type: models.PaymentEventRefund, TransactionRef: "pi_123", ReceivedAt: "2024-10-01T09:00:00Z"},
+		{Id: "3", Provider: "paypal", EventType: "PAYMENT.CAPTURE.COMPLETED", Type: models.PaymentEventPayment, TransactionRef: "transaction1", ReceivedAt: "2024-10-01T09:30:00Z"},
+	}
+}
+
+func TestRecord(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := newTestService(cacheClient)
+
+	// Action
+	err := service.Record(models.WebhookEvent{Provider: "stripe", EventId: "evt_123", TransactionRef: "pi_123", ReceivedAt: "2024-10-01T09:59:00Z"})
+	errAgain := service.Record(models.WebhookEvent{Provider: "stripe", EventId: "evt_456", ReceivedAt: "2024-10-01T09:59:30Z"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, errAgain)
+
+	days, _ := cacheClient.Get(cache.WebhookEventDaysKey)
+	assert.JSONEq(t, `["2024_10_01"]`, string(days))
+
+	items, _ := cacheClient.GetList(cache.WebhookEventsKey + "_2024_10_01")
+	assert.Len(t, items, 2)
+
+	var stored models.WebhookEvent
+	assert.NoError(t, json.Unmarshal(items[0], &stored))
+	assert.Equal(t, "evt_123", stored.EventId)
+	assert.NotEmpty(t, stored.Id)
+
+	byProvider, _ := cacheClient.GetList(cache.WebhookEventsKey + "_2024_10_01_stripe")
+	byTransaction, _ := cacheClient.GetList(cache.WebhookEventsKey + "_transaction_pi_123")
+	assert.Len(t, byProvider, 2)
+	assert.Len(t, byTransaction, 1)
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   models.WebhookEventFilter
+		expected []string
+	}{
+		{"Newest first", models.WebhookEventFilter{}, []string{"3", "2", "1"}},
+		{"By provider", models.WebhookEventFilter{Provider: "paypal"}, []string{"3"}},
+		{"By provider event type", models.WebhookEventFilter{Type: "charge.refunded"}, []string{"2"}},
+		{"By normalized type", models.WebhookEventFilter{Type: models.PaymentEventPayment}, []string{"3", "1"}},
+		{"By transaction", models.WebhookEventFilter{TransactionId: "pi_123"}, []string{"2", "1"}},
+		{"By provider and transaction", models.WebhookEventFilter{Provider: "paypal", TransactionId: "pi_123"}, []string{}},
+		{"By time range", models.WebhookEventFilter{From: "2024-10-01T08:30:00Z", To: "2024-10-01T09:15:00Z"}, []string{"2"}},
+		{"Limited", models.WebhookEventFilter{Limit: 1}, []string{"3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cacheClient := cache.NewMemory()
+			service := newTestService(cacheClient)
+			for _, event := range testEvents() {
+				assert.NoError(t, service.Record(event))
+			}
+
+			// Action
+			result, err := service.Find(tt.filter)
+
+			// Assert
+			assert.NoError(t, err)
+			ids := []string{}
+			for _, event := range result {
+				ids = append(ids, event.Id)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestFind_InvalidFilter(t *testing.T) {
+	// Arrange
+	service := newTestService(cache.NewMemory())
+
+	// Action
+	_, err := service.Find(models.WebhookEventFilter{From: "yesterday"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestPurge(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := newTestService(cacheClient)
+	cacheClient.Set(cache.WebhookEventDaysKey, `["2024_08_30","2024_09_01","2024_10_01"]`, 0)
+	for _, day := range []string{"2024_08_30", "2024_09_01"} {
+		cacheClient.Append(cache.WebhookEventsKey+"_"+day, utils.ToJSON(models.WebhookEvent{Id: day}))
+	}
+
+	// Action
+	err := service.Purge()
+
+	// Assert
+	assert.NoError(t, err)
+
+	days, _ := cacheClient.Get(cache.WebhookEventDaysKey)
+	assert.JSONEq(t, `["2024_09_01","2024_10_01"]`, string(days))
+
+	purged, _ := cacheClient.GetList(cache.WebhookEventsKey + "_2024_08_30")
+	kept, _ := cacheClient.GetList(cache.WebhookEventsKey + "_2024_09_01")
+	assert.Empty(t, purged)
+	assert.Len(t, kept, 1)
+}
@@ -1,7 +0,0 @@
-package processor
-
-type PayPalProcessType string
-
-const (
-	createdAction PayPalProcessType = "created"
-)
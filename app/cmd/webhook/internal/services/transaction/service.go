@@ -0,0 +1,268 @@
+package transaction
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/index"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/notification"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/pending"
+)
+
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+type TransactionService interface {
+	AddTransaction(id string, status models.TransactionStatus, origin audit.Origin) error
+	ApplyEvent(event models.PaymentEvent) error
+	ApplyPending(id string, events []pending.Event) (bool, error)
+}
+
+type transactionService struct {
+	cache     cache.CacheClient
+	publisher notification.Publisher
+	auditor   audit.AuditService
+	pending   pending.PendingService
+	index     index.IndexService
+}
+
+// New creates a new instance of transactionService with the provided cache client, notification publisher, audit service,
+// pending events service and transaction index.
+// It returns a pointer to the newly created transactionService.
+//
+// Parameters:
+//   - cache: an instance of cache.CacheClient to be used by the transactionService.
+//   - publisher: an instance of notification.Publisher used to notify merchants of transaction status changes.
+//   - auditor: an instance of audit.AuditService that records every mutation of a transaction.
+//   - pending: an instance of pending.PendingService where events for transactions not created yet are parked.
+//   - index: an instance of index.IndexService used to find transactions regardless of the date they were created.
+//
+// Returns:
+//   - *transactionService: a pointer to the newly created transactionService.
+func New(cache cache.CacheClient, publisher notification.Publisher, auditor audit.AuditService, pending pending.PendingService, index index.IndexService) *transactionService {
+	return &transactionService{
+		cache:     cache,
+		publisher: publisher,
+		auditor:   auditor,
+		pending:   pending,
+		index:     index,
+	}
+}
+
+// AddTransaction adds a new transaction status to an existing transaction in the cache, whatever the day it was created.
+// Statuses are ordered by the time their events occurred at the provider rather than the time they arrived, and
+// statuses of events already applied are skipped, so retried deliveries do not duplicate them.
+// Events for transactions that do not exist yet are parked, to be applied once the transaction is created, and
+// ErrTransactionNotFound is returned once the event is parked, so callers can tell it was not applied yet.
+// Once the new status is stored, the change is recorded in the audit log and merchants are notified of it.
+//
+// Parameters:
+//   - id: The unique identifier of the transaction.
+//   - status: The status to be added to the transaction, with the time the event occurred at the provider and, for
+//     failures, the code and message of the provider.
+//   - origin: The actor and correlation ID responsible for the new status.
+//
+// Returns:
+//   - error: ErrTransactionNotFound if the transaction does not exist, or an error if there is an issue with unmarshalling
+//     the cache data, setting the updated transactions in the cache, parking the event or recording the audit entry.
+func (p *transactionService) AddTransaction(id string, status models.TransactionStatus, origin audit.Origin) error {
+	event := pending.Event{
+		TransactionId: id,
+		Status:        status.Status,
+		OccurredAt:    status.DateTime,
+		Code:          status.Code,
+		Message:       status.Message,
+		Origin:        origin,
+	}
+
+	applied, err := p.ApplyPending(id, []pending.Event{event})
+	if err != nil || applied {
+		return err
+	}
+
+	if err := p.pending.Park(event); err != nil {
+		return err
+	}
+
+	// The transaction may have been created after it was looked up, without seeing the parked event.
+	parked, err := p.pending.Take(id)
+	if err != nil {
+		return err
+	}
+
+	applied, err = p.ApplyPending(id, parked)
+	if err != nil || !applied {
+		if err := errors.Join(err, p.pending.Park(parked...)); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %s", ErrTransactionNotFound, id)
+	}
+
+	return nil
+}
+
+// ApplyEvent adds the status of a payment event translated from a provider webhook to its transaction, as
+// AddTransaction does, recording the provider event as the origin of the change.
+//
+// Parameters:
+//   - event: The payment event.
+//
+// Returns:
+//   - error: ErrTransactionNotFound if the transaction does not exist, or an error if the status could not be added.
+func (p *transactionService) ApplyEvent(event models.PaymentEvent) error {
+	status := models.TransactionStatus{
+		Status:   event.Status,
+		DateTime: event.OccurredAt,
+		Code:     event.Code,
+		Message:  event.Message,
+	}
+
+	return p.AddTransaction(event.TransactionRef, status, audit.WebhookEvent(event.EventId, event.CorrelationId))
+}
+
+// ApplyPending adds the statuses of provider events to a transaction, if the transaction exists.
+// The transaction is looked up by the transaction index, so transactions created on any day are found. Transactions
+// created before they were indexed are looked up among the transactions of the current day. The transactions of the
+// day are locked while they are read and written back, so concurrent changes never overwrite each other.
+//
+// Parameters:
+//   - id: The unique identifier of the transaction.
+//   - events: The events whose statuses are added to the transaction.
+//
+// Returns:
+//   - bool: Whether the transaction exists and the statuses were added.
+//   - error: An error if there is an issue reading or setting the transactions, or recording the audit entries.
+func (p *transactionService) ApplyPending(id string, events []pending.Event) (bool, error) {
+	if len(events) == 0 {
+		return true, nil
+	}
+
+	entry, err := p.index.Get(id)
+	if err != nil {
+		if !errors.Is(err, index.ErrNotIndexed) {
+			return false, err
+		}
+		entry = &index.Entry{TransactionId: id, Date: time.Now().Format(index.DateFormat)}
+	}
+
+	transactionsByDate := entry.TransactionsKey()
+
+	var (
+		found   bool
+		changes []change
+	)
+	err = cache.WithLock(p.cache, transactionsByDate, func() error {
+		c, err := p.cache.Get(transactionsByDate)
+		if err != nil {
+			if err.Error() == cache.ErrCacheMiss.Error() {
+				return nil
+			}
+			return err
+		}
+
+		var transactions = make(map[string]*models.Transaction)
+		if err := json.Unmarshal(c, &transactions); err != nil {
+			return err
+		}
+
+		transaction := transactions[entry.TransactionId]
+		if transaction == nil {
+			return nil
+		}
+		found = true
+
+		changes = make([]change, 0, len(events))
+		for _, event := range events {
+			status := models.TransactionStatus{
+				Status:   event.Status,
+				DateTime: event.OccurredAt,
+				Code:     event.Code,
+				Message:  event.Message,
+				EventId:  eventId(event.Origin),
+			}
+			if hasStatus(transaction.TransactionStatus, status) {
+				continue
+			}
+
+			before := *transaction
+			transaction.TransactionStatus = insertStatus(before.TransactionStatus, status)
+			changes = append(changes, change{status: event.Status, origin: event.Origin, before: before, after: *transaction})
+		}
+
+		if len(changes) == 0 {
+			return nil
+		}
+
+		updatedTransactions, err := json.Marshal(transactions)
+		if err != nil {
+			return err
+		}
+
+		auditChanges := make([]audit.Change, 0, len(changes))
+		for _, change := range changes {
+			auditChanges = append(auditChanges, audit.Change{Action: audit.ActionTransactionStatusAdded, Origin: change.origin, Before: change.before, After: change.after})
+		}
+
+		return p.auditor.Write(transactionsByDate, updatedTransactions, entry.TransactionId, auditChanges...)
+	})
+	if err != nil || !found {
+		return false, err
+	}
+
+	for _, change := range changes {
+		p.publisher.Publish(change.after.MerchantId, notification.TransactionEventType(change.status), change.after)
+	}
+
+	return true, nil
+}
+
+// change is a status added to a transaction by a provider event.
+type change struct {
+	status string
+	origin audit.Origin
+	before models.Transaction
+	after  models.Transaction
+}
+
+// insertStatus returns a copy of the statuses with the new status inserted in the order of the time it occurred.
+func insertStatus(statuses []models.TransactionStatus, status models.TransactionStatus) []models.TransactionStatus {
+	result := append(append([]models.TransactionStatus{}, statuses...), status)
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return statusTime(result[i]).Before(statusTime(result[j]))
+	})
+
+	return result
+}
+
+// eventId returns the ID of the provider event responsible for a status, or an empty string for other origins.
+func eventId(origin audit.Origin) string {
+	if origin.Actor.Type != audit.ActorWebhookEvent {
+		return ""
+	}
+	return origin.Actor.Id
+}
+
+// hasStatus reports whether the status of a provider event was already added to the transaction.
+func hasStatus(statuses []models.TransactionStatus, status models.TransactionStatus) bool {
+	if status.EventId == "" {
+		return false
+	}
+
+	for _, current := range statuses {
+		if current.EventId == status.EventId && current.Status == status.Status {
+			return true
+		}
+	}
+	return false
+}
+
+func statusTime(status models.TransactionStatus) time.Time {
+	value, _ := time.Parse(time.RFC3339, status.DateTime)
+	return value
+}
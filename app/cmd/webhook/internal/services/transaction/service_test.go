@@ -0,0 +1,171 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/models"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/index"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/pending"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPublisher struct {
+	mock.Mock
+}
+
+func (m *MockPublisher) Publish(merchantId string, eventType string, data interface{}) {
+	m.Called(merchantId, eventType, data)
+}
+
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) Record(transactionId string, action string, origin audit.Origin, before interface{}, after interface{}) error {
+	args := m.Called(transactionId, action, origin, before, after)
+	return args.Error(0)
+}
+
+func (m *MockAuditService) Write(key string, item interface{}, transactionId string, changes ...audit.Change) error {
+	args := m.Called(key, item, transactionId, changes)
+	return args.Error(0)
+}
+
+func (m *MockAuditService) GetHistory(transactionId string) ([]audit.Entry, error) {
+	args := m.Called(transactionId)
+	return args.Get(0).([]audit.Entry), args.Error(1)
+}
+
+func (m *MockAuditService) GetStateAt(transactionId string, at time.Time) (*audit.State, error) {
+	args := m.Called(transactionId, at)
+	return args.Get(0).(*audit.State), args.Error(1)
+}
+
+type MockPendingService struct {
+	mock.Mock
+}
+
+func (m *MockPendingService) Park(events ...pending.Event) error {
+	args := m.Called(events)
+	return args.Error(0)
+}
+
+func (m *MockPendingService) Take(transactionId string) ([]pending.Event, error) {
+	args := m.Called(transactionId)
+	return args.Get(0).([]pending.Event), args.Error(1)
+}
+
+func (m *MockPendingService) Sweep(apply pending.ApplyFunc) error {
+	args := m.Called(apply)
+	return args.Error(0)
+}
+
+func (m *MockPendingService) Run(ctx context.Context, apply pending.ApplyFunc) {
+	m.Called(ctx, apply)
+}
+
+type MockIndexService struct {
+	mock.Mock
+}
+
+func (m *MockIndexService) Put(lookupId string, transactionId string, createdAt time.Time) error {
+	args := m.Called(lookupId, transactionId, createdAt)
+	return args.Error(0)
+}
+
+func (m *MockIndexService) Get(lookupId string) (*index.Entry, error) {
+	args := m.Called(lookupId)
+	entry, _ := args.Get(0).(*index.Entry)
+	return entry, args.Error(1)
+}
+
+func storedTransactions(t *testing.T, cacheClient cache.CacheClient, key string) map[string]models.Transaction {
+	transactions := map[string]models.Transaction{}
+	c, err := cacheClient.Get(key)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(c, &transactions))
+	return transactions
+}
+
+func TestAddTransaction_FindsTransactionOfAnotherDay(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	mockPublisher := new(MockPublisher)
+	mockAudit := new(MockAuditService)
+	mockIndex := new(MockIndexService)
+	service := New(cacheClient, mockPublisher, mockAudit, new(MockPendingService), mockIndex)
+
+	transactionsByDate := cache.TransactionsKey + "_01_10_2024"
+	origin := audit.WebhookEvent("evt_123", "correlation1")
+	transaction := models.Transaction{Id: "transaction1", MerchantId: "merchant1", TransactionStatus: []models.TransactionStatus{{Status: "pending", DateTime: "2024-10-01T23:59:00Z"}}}
+
+	mockIndex.On("Get", "transaction1").Return(&index.Entry{TransactionId: "transaction1", Date: "01_10_2024"}, nil)
+	cacheClient.Set(transactionsByDate, utils.ToJSON(map[string]models.Transaction{"transaction1": transaction}), 0)
+	mockAudit.On("Write", transactionsByDate, mock.Anything, "transaction1", mock.MatchedBy(func(changes []audit.Change) bool {
+		return len(changes) == 1 && changes[0].Action == audit.ActionTransactionStatusAdded && changes[0].Origin == origin
+	})).Run(func(args mock.Arguments) {
+		cacheClient.Set(args.String(0), args.Get(1), 0)
+	}).Return(nil)
+	mockPublisher.On("Publish", "merchant1", "transaction.refunded", mock.Anything).Return()
+
+	// Action
+	err := service.AddTransaction("transaction1", models.TransactionStatus{Status: "refunded", DateTime: "2024-10-04T10:00:00Z"}, origin)
+
+	// Assert
+	assert.NoError(t, err)
+	statuses := storedTransactions(t, cacheClient, transactionsByDate)["transaction1"].TransactionStatus
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "refunded", statuses[1].Status)
+	assert.Equal(t, "evt_123", statuses[1].EventId)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestAddTransaction_SkipsEventAlreadyApplied(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	mockPublisher := new(MockPublisher)
+	mockIndex := new(MockIndexService)
+	service := New(cacheClient, mockPublisher, new(MockAuditService), new(MockPendingService), mockIndex)
+
+	transactionsByDate := cache.TransactionsKey + "_01_10_2024"
+	transaction := models.Transaction{Id: "transaction1", MerchantId: "merchant1", TransactionStatus: []models.TransactionStatus{
+		{Status: "pending", DateTime: "2024-10-01T23:59:00Z"},
+		{Status: "success", DateTime: "2024-10-02T00:01:00Z", EventId: "evt_123"},
+	}}
+
+	mockIndex.On("Get", "transaction1").Return(&index.Entry{TransactionId: "transaction1", Date: "01_10_2024"}, nil)
+	cacheClient.Set(transactionsByDate, utils.ToJSON(map[string]models.Transaction{"transaction1": transaction}), 0)
+
+	// Action
+	err := service.AddTransaction("transaction1", models.TransactionStatus{Status: "success", DateTime: "2024-10-02T00:01:00Z"}, audit.WebhookEvent("evt_123", ""))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, storedTransactions(t, cacheClient, transactionsByDate)["transaction1"].TransactionStatus, 2)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddTransaction_NotFound(t *testing.T) {
+	// Arrange
+	mockPending := new(MockPendingService)
+	mockIndex := new(MockIndexService)
+	service := New(cache.NewMemory(), new(MockPublisher), new(MockAuditService), mockPending, mockIndex)
+
+	mockIndex.On("Get", "transaction1").Return(nil, index.ErrNotIndexed)
+	mockPending.On("Park", mock.Anything).Return(nil)
+	mockPending.On("Take", "transaction1").Return([]pending.Event{{TransactionId: "transaction1", Status: "success"}}, nil)
+
+	// Action
+	err := service.AddTransaction("transaction1", models.TransactionStatus{Status: "success", DateTime: "2024-10-02T00:01:00Z"}, audit.WebhookEvent("evt_123", ""))
+
+	// Assert
+	assert.ErrorIs(t, err, ErrTransactionNotFound)
+	mockPending.AssertNumberOfCalls(t, "Park", 2)
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"expvar"
 	"fmt"
+	"net/http"
 
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/cmd/webhook/internal/router"
 	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
@@ -17,6 +19,8 @@ func main() {
 	logger.Info("Start webhook application")
 	engine := setupServer(logger)
 
+	go serveDebug(logger, utils.GetEnvString("DEBUG_ADDRESS", "127.0.0.1:8091"))
+
 	port := utils.GetEnvPortOrDefault("8081")
 	if err := engine.Run(fmt.Sprintf(":%s", port)); err != nil {
 		logger.Fatal("Error starting webhook application", zap.Error(err))
@@ -38,3 +42,15 @@ func setupServer(logger *zap.Logger) *gin.Engine {
 
 	return engine
 }
+
+// serveDebug serves the metrics published with expvar at /debug/vars on an internal listener, separate from the
+// public webhook routes, so they are only reachable from the host or the private network it is bound to.
+func serveDebug(logger *zap.Logger, address string) {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	logger.Info("Serving debug metrics", zap.String("address", address))
+	if err := http.ListenAndServe(address, mux); err != nil {
+		logger.Error("Error serving debug metrics", zap.String("address", address), zap.Error(err))
+	}
+}
@@ -0,0 +1,51 @@
+package audit
+
+import "encoding/json"
+
+const (
+	ActorApiRequest   = "api_request"
+	ActorWebhookEvent = "webhook_event"
+	ActorAdmin        = "admin"
+)
+
+const (
+	ActionTransactionCreated     = "transaction.created"
+	ActionTransactionStatusAdded = "transaction.status_added"
+	ActionSpreadCreated          = "spread.created"
+	ActionSpreadUpdated          = "spread.updated"
+	ActionSpreadDeleted          = "spread.deleted"
+)
+
+type Actor struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+type Origin struct {
+	Actor         Actor  `json:"actor"`
+	CorrelationId string `json:"correlation_id,omitempty"`
+}
+
+type Change struct {
+	Action string
+	Origin Origin
+	Before interface{}
+	After  interface{}
+}
+
+type Entry struct {
+	Id            string          `json:"id"`
+	TransactionId string          `json:"transaction_id"`
+	Action        string          `json:"action"`
+	Actor         Actor           `json:"actor"`
+	CorrelationId string          `json:"correlation_id,omitempty"`
+	Before        json.RawMessage `json:"before"`
+	After         json.RawMessage `json:"after"`
+	Timestamp     string          `json:"timestamp"`
+}
+
+type State struct {
+	TransactionId string          `json:"transaction_id"`
+	At            string          `json:"at"`
+	State         json.RawMessage `json:"state"`
+}
@@ -0,0 +1,262 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+var (
+	ErrHistoryNotFound = errors.New("transaction history not found")
+	ErrNoStateAt       = errors.New("transaction did not exist at the requested time")
+)
+
+type AuditService interface {
+	Record(transactionId string, action string, origin Origin, before interface{}, after interface{}) error
+	Write(key string, item interface{}, transactionId string, changes ...Change) error
+	GetHistory(transactionId string) ([]Entry, error)
+	GetStateAt(transactionId string, at time.Time) (*State, error)
+}
+
+type auditService struct {
+	cache  cache.CacheClient
+	logKey string
+	now    func() time.Time
+}
+
+// New creates a new instance of auditService with the provided cache client, keeping the logs of the transactions.
+// It returns a pointer to the newly created auditService.
+//
+// Parameters:
+//   - cache: an instance of cache.CacheClient where the append-only log of each transaction is kept.
+//
+// Returns:
+//   - *auditService: a pointer to the newly created auditService.
+func New(client cache.CacheClient) *auditService {
+	return NewLog(client, cache.AuditLogKey)
+}
+
+// NewLog creates a new instance of auditService keeping its logs under the given cache key, so the logs of other
+// entities, such as spreads, are kept apart from the logs of the transactions.
+//
+// Parameters:
+//   - cache: an instance of cache.CacheClient where the append-only log of each entity is kept.
+//   - logKey: the cache key the logs are kept under, suffixed with the ID of each entity.
+//
+// Returns:
+//   - *auditService: a pointer to the newly created auditService.
+func NewLog(cache cache.CacheClient, logKey string) *auditService {
+	return &auditService{
+		cache:  cache,
+		logKey: logKey,
+		now:    time.Now,
+	}
+}
+
+// ApiRequest returns the origin of a mutation made while serving an api request.
+//
+// Parameters:
+//   - correlationId: the correlation ID of the request, which also identifies the actor.
+//
+// Returns:
+//   - Origin: the origin of the mutation.
+func ApiRequest(correlationId string) Origin {
+	return Origin{
+		Actor:         Actor{Type: ActorApiRequest, Id: correlationId},
+		CorrelationId: correlationId,
+	}
+}
+
+// Admin returns the origin of a mutation made by an authenticated administrator while serving an api request.
+//
+// Parameters:
+//   - adminId: the ID of the administrator, as authenticated by its API key.
+//   - correlationId: the correlation ID of the request.
+//
+// Returns:
+//   - Origin: the origin of the mutation.
+func Admin(adminId string, correlationId string) Origin {
+	return Origin{
+		Actor:         Actor{Type: ActorAdmin, Id: adminId},
+		CorrelationId: correlationId,
+	}
+}
+
+// WebhookEvent returns the origin of a mutation made while processing a provider webhook event.
+//
+// Parameters:
+//   - eventId: the ID of the provider event.
+//   - correlationId: the correlation ID of the payment the event refers to, if known.
+//
+// Returns:
+//   - Origin: the origin of the mutation.
+func WebhookEvent(eventId string, correlationId string) Origin {
+	return Origin{
+		Actor:         Actor{Type: ActorWebhookEvent, Id: eventId},
+		CorrelationId: correlationId,
+	}
+}
+
+// Record appends an entry describing a mutation of a transaction to its audit log.
+// Entries are only ever appended, so a bad write to the transaction itself never loses its history.
+//
+// Parameters:
+//   - transactionId: the ID of the mutated transaction.
+//   - action: the mutation performed, such as "transaction.created" or "transaction.status_added".
+//   - origin: the actor and correlation ID responsible for the mutation.
+//   - before: the state of the transaction before the mutation, or nil if it did not exist.
+//   - after: the state of the transaction after the mutation.
+//
+// Returns:
+//   - error: an error if the states cannot be serialized or the entry cannot be appended.
+func (s *auditService) Record(transactionId string, action string, origin Origin, before interface{}, after interface{}) error {
+	entry, err := s.entry(transactionId, Change{Action: action, Origin: origin, Before: before, After: after})
+	if err != nil {
+		return err
+	}
+
+	return s.cache.Append(s.key(transactionId), entry)
+}
+
+// Write stores the item holding a transaction and appends the entries describing its changes to the audit log of
+// the transaction in a single cache transaction, so the transaction is never changed without its history.
+//
+// Parameters:
+//   - key: the cache key of the item holding the transaction.
+//   - item: the item to be stored, which never expires.
+//   - transactionId: the ID of the changed transaction.
+//   - changes: the mutations of the transaction, in the order they were made.
+//
+// Returns:
+//   - error: an error if the states cannot be serialized or the item and the entries cannot be written.
+func (s *auditService) Write(key string, item interface{}, transactionId string, changes ...Change) error {
+	entries := make([]interface{}, 0, len(changes))
+	for _, change := range changes {
+		entry, err := s.entry(transactionId, change)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+
+	return s.cache.SetAndAppend(key, item, 0, s.key(transactionId), entries...)
+}
+
+// entry returns the serialized audit log entry of a change of a transaction.
+func (s *auditService) entry(transactionId string, change Change) (string, error) {
+	beforeSerialized, err := json.Marshal(change.Before)
+	if err != nil {
+		return "", err
+	}
+
+	afterSerialized, err := json.Marshal(change.After)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.ToJSON(Entry{
+		Id:            utils.GenerateGUID(),
+		TransactionId: transactionId,
+		Action:        change.Action,
+		Actor:         change.Origin.Actor,
+		CorrelationId: change.Origin.CorrelationId,
+		Before:        beforeSerialized,
+		After:         afterSerialized,
+		Timestamp:     s.now().UTC().Format(time.RFC3339Nano),
+	}), nil
+}
+
+// GetHistory retrieves every entry of the audit log of a transaction, oldest first.
+//
+// Parameters:
+//   - transactionId: the ID of the transaction.
+//
+// Returns:
+//   - []Entry: the entries of the audit log.
+//   - error: ErrHistoryNotFound if the transaction has no entries, or an error if they cannot be read.
+func (s *auditService) GetHistory(transactionId string) ([]Entry, error) {
+	items, err := s.cache.GetList(s.key(transactionId))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, ErrHistoryNotFound
+	}
+
+	entries := make([]Entry, 0, len(items))
+	for _, item := range items {
+		var entry Entry
+		if err := json.Unmarshal(item, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// GetStateAt reconstructs the state of a transaction at a point in time by replaying its audit log
+// up to that time. Entries are replayed in the order of their timestamps, as entries written by different
+// replicas may be appended out of order.
+//
+// Parameters:
+//   - transactionId: the ID of the transaction.
+//   - at: the point in time to reconstruct.
+//
+// Returns:
+//   - *State: the state of the transaction after the last mutation made at or before the given time.
+//   - error: ErrHistoryNotFound if the transaction has no entries, ErrNoStateAt if it did not exist yet,
+//     or an error if the entries cannot be read.
+func (s *auditService) GetStateAt(transactionId string, at time.Time) (*State, error) {
+	entries, err := s.GetHistory(transactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	type replayed struct {
+		timestamp time.Time
+		after     json.RawMessage
+	}
+
+	replay := make([]replayed, 0, len(entries))
+	for _, entry := range entries {
+		timestamp, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		replay = append(replay, replayed{timestamp: timestamp, after: entry.After})
+	}
+
+	sort.SliceStable(replay, func(i, j int) bool {
+		return replay[i].timestamp.Before(replay[j].timestamp)
+	})
+
+	var state json.RawMessage
+	for _, entry := range replay {
+		if entry.timestamp.After(at) {
+			break
+		}
+		state = entry.after
+	}
+
+	if state == nil {
+		return nil, ErrNoStateAt
+	}
+
+	return &State{
+		TransactionId: transactionId,
+		At:            at.UTC().Format(time.RFC3339Nano),
+		State:         state,
+	}, nil
+}
+
+// key returns the cache key of the audit log of a transaction.
+func (s *auditService) key(transactionId string) string {
+	return fmt.Sprintf("%s_%s", s.logKey, transactionId)
+}
@@ -0,0 +1,197 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingCache struct {
+	cache.CacheClient
+}
+
+func (c failingCache) Append(key string, item interface{}) error {
+	return errors.New("cache error")
+}
+
+func (c failingCache) SetAndAppend(key string, item interface{}, expiration time.Duration, listKey string, listItems ...interface{}) error {
+	return errors.New("cache error")
+}
+
+func testEntries() [][]byte {
+	return [][]byte{
+		[]byte(utils.ToJSON(Entry{
+			Id:        "entry1",
+			Action:    ActionTransactionCreated,
+			Before:    json.RawMessage(`null`),
+			After:     json.RawMessage(`{"status":"pending"}`),
+			Timestamp: "2024-10-01T10:00:00Z",
+		})),
+		[]byte(utils.ToJSON(Entry{
+			Id:        "entry2",
+			Action:    ActionTransactionStatusAdded,
+			Before:    json.RawMessage(`{"status":"pending"}`),
+			After:     json.RawMessage(`{"status":"success"}`),
+			Timestamp: "2024-10-01T10:05:00Z",
+		})),
+	}
+}
+
+func TestRecord_Success(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient)
+	service.now = func() time.Time { return time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC) }
+
+	// Action
+	err := service.Record("transaction1", ActionTransactionCreated, WebhookEvent("evt_123", "correlation1"), nil, map[string]string{"status": "pending"})
+
+	// Assert
+	assert.NoError(t, err)
+
+	items, _ := cacheClient.GetList("audit_log_key_transaction1")
+	assert.Len(t, items, 1)
+
+	var recorded Entry
+	json.Unmarshal(items[0], &recorded)
+	assert.NotEmpty(t, recorded.Id)
+	assert.Equal(t, Actor{Type: ActorWebhookEvent, Id: "evt_123"}, recorded.Actor)
+	assert.Equal(t, "correlation1", recorded.CorrelationId)
+	assert.JSONEq(t, `null`, string(recorded.Before))
+	assert.JSONEq(t, `{"status":"pending"}`, string(recorded.After))
+	assert.Equal(t, "2024-10-01T10:00:00Z", recorded.Timestamp)
+}
+
+func TestNewLog_SeparateLogs(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	transactions := New(cacheClient)
+	spreads := NewLog(cacheClient, cache.SpreadAuditLogKey)
+
+	// Action
+	err := spreads.Record("spread1", ActionSpreadCreated, Admin("ops", "correlation1"), nil, map[string]int{"basis_points": 100})
+
+	// Assert
+	assert.NoError(t, err)
+
+	items, _ := cacheClient.GetList("spread_audit_log_key_spread1")
+	assert.Len(t, items, 1)
+
+	_, err = transactions.GetHistory("spread1")
+	assert.ErrorIs(t, err, ErrHistoryNotFound)
+}
+
+func TestRecord_Failure_Append(t *testing.T) {
+	// Arrange
+	service := New(failingCache{cache.NewMemory()})
+
+	// Action
+	err := service.Record("transaction1", ActionTransactionCreated, ApiRequest("correlation1"), nil, nil)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestGetHistory_NotFound(t *testing.T) {
+	// Arrange
+	service := New(cache.NewMemory())
+
+	// Action
+	entries, err := service.GetHistory("transaction1")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrHistoryNotFound)
+	assert.Nil(t, entries)
+}
+
+func TestGetStateAt(t *testing.T) {
+	tests := []struct {
+		name    string
+		at      time.Time
+		want    string
+		wantErr error
+	}{
+		{"Before creation", time.Date(2024, 10, 1, 9, 59, 0, 0, time.UTC), "", ErrNoStateAt},
+		{"At creation", time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC), `{"status":"pending"}`, nil},
+		{"Between mutations", time.Date(2024, 10, 1, 10, 3, 0, 0, time.UTC), `{"status":"pending"}`, nil},
+		{"After last mutation", time.Date(2024, 10, 2, 0, 0, 0, 0, time.UTC), `{"status":"success"}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cacheClient := cache.NewMemory()
+			service := New(cacheClient)
+			for _, entry := range testEntries() {
+				cacheClient.Append("audit_log_key_transaction1", string(entry))
+			}
+
+			// Action
+			state, err := service.GetStateAt("transaction1", tt.at)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(state.State))
+		})
+	}
+}
+
+func TestGetStateAt_OutOfOrder(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient)
+	entries := testEntries()
+	cacheClient.Append("audit_log_key_transaction1", string(entries[1]))
+	cacheClient.Append("audit_log_key_transaction1", string(entries[0]))
+
+	// Action
+	state, err := service.GetStateAt("transaction1", time.Date(2024, 10, 1, 10, 3, 0, 0, time.UTC))
+
+	// Assert
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"status":"pending"}`, string(state.State))
+}
+
+func TestWrite_Success(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient)
+
+	// Action
+	err := service.Write("transactions_key", `{"transaction1":{"status":"success"}}`, "transaction1",
+		Change{Action: ActionTransactionCreated, Origin: ApiRequest("correlation1"), After: map[string]string{"status": "pending"}},
+		Change{Action: ActionTransactionStatusAdded, Origin: WebhookEvent("evt_123", "correlation1"), Before: map[string]string{"status": "pending"}, After: map[string]string{"status": "success"}},
+	)
+
+	// Assert
+	assert.NoError(t, err)
+
+	stored, _ := cacheClient.Get("transactions_key")
+	assert.JSONEq(t, `{"transaction1":{"status":"success"}}`, string(stored))
+
+	history, _ := service.GetHistory("transaction1")
+	assert.Len(t, history, 2)
+	assert.Equal(t, ActionTransactionCreated, history[0].Action)
+	assert.Equal(t, Actor{Type: ActorWebhookEvent, Id: "evt_123"}, history[1].Actor)
+	assert.JSONEq(t, `{"status":"success"}`, string(history[1].After))
+}
+
+func TestWrite_Failure(t *testing.T) {
+	// Arrange
+	service := New(failingCache{cache.NewMemory()})
+
+	// Action
+	err := service.Write("transactions_key", "{}", "transaction1", Change{Action: ActionTransactionCreated, Origin: ApiRequest("correlation1")})
+
+	// Assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+const (
+	RedisDriver  = "redis"
+	MemoryDriver = "memory"
+)
+
+var ErrUnknownDriver = errors.New("unknown cache driver")
+
+type Config struct {
+	Driver string
+}
+
+// LoadConfig loads the cache configuration from the environment variables.
+//
+// Environment Variables:
+//   - CACHE_DRIVER: the cache implementation, "redis" (default) or "memory" to keep the data of a single node
+//     in the process memory. Asynchronous payments use Redis Streams and still require Redis.
+//
+// Returns:
+//   - Config: the cache configuration.
+func LoadConfig() Config {
+	return Config{
+		Driver: strings.ToLower(utils.GetEnvString("CACHE_DRIVER", RedisDriver)),
+	}
+}
+
+// NewClient creates the CacheClient of the configured driver.
+//
+// Parameters:
+//   - config: the cache configuration.
+//
+// Returns:
+//   - CacheClient: the cache client.
+//   - error: ErrUnknownDriver if the driver is neither "redis" nor "memory".
+func NewClient(config Config) (CacheClient, error) {
+	switch config.Driver {
+	case RedisDriver:
+		return New(), nil
+	case MemoryDriver:
+		return NewMemory(), nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownDriver, config.Driver)
+}
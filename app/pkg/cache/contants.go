@@ -1,7 +1,31 @@
 package cache
 
 const (
-	AvaiableGatewaysKey = "avaiable_gateways_key"
-	TransactionsKey     = "transactions_Key"
-	ExchangeRateKey     = "exchange_rate_key"
+	AvaiableGatewaysKey          = "avaiable_gateways_key"
+	TransactionsKey              = "transactions_Key"
+	ExchangeRateKey              = "exchange_rate_key"
+	ExchangeRateHistoryKey       = "exchange_rate_history_key"
+	ExchangeRateSpreadsKey       = "exchange_rate_spreads_key"
+	ExchangeRateSpreadScopeKey   = "exchange_rate_spread_scope_key"
+	ExchangeRateSpreadLockKey    = "exchange_rate_spread_lock_key"
+	ExchangeRateQuoteKey         = "exchange_rate_quote_key"
+	ExchangeRateQuoteUsedKey     = "exchange_rate_quote_used_key"
+	ExchangeRateQuoteReservedKey = "exchange_rate_quote_reserved_key"
+	RateLimitKey                 = "rate_limit_key"
+	RiskVelocityKey              = "risk_velocity_key"
+	NotificationEndpointsKey     = "notification_endpoints_key"
+	NotificationDeliveriesKey    = "notification_deliveries_key"
+	NotificationFailuresKey      = "notification_failures_key"
+	AuditLogKey                  = "audit_log_key"
+	SpreadAuditLogKey            = "spread_audit_log_key"
+	WebhookProcessedKey          = "webhook_processed_key"
+	WebhookLockKey               = "webhook_lock_key"
+	WebhookDeadLetterKey         = "webhook_dead_letter_key"
+	WebhookDeadLetterIndexKey    = "webhook_dead_letter_index_key"
+	PendingEventsKey             = "pending_events_key"
+	PendingEventsIndexKey        = "pending_events_index_key"
+	TransactionIndexKey          = "transaction_index_key"
+	WebhookEventsKey             = "webhook_events_key"
+	WebhookEventDaysKey          = "webhook_event_days_key"
+	LockKey                      = "lock_key"
 )
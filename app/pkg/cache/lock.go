@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrLocked = errors.New("the key is being changed by another request, please try again")
+
+const (
+	// lockTTL bounds how long a key stays locked by an instance that stopped before releasing it.
+	lockTTL = 10 * time.Second
+	// lockWait bounds how long a change waits for the lock of a key held by another change.
+	lockWait = 5 * time.Second
+	// lockRetryInterval is the interval between attempts to acquire the lock of a key.
+	lockRetryInterval = 10 * time.Millisecond
+)
+
+// WithLock runs a change of the item stored at the specified key while holding the lock of the key, so changes that
+// read, modify and write the item back, from any instance, never overwrite each other.
+// A change waits for the lock while it is held by another change, up to a few seconds.
+//
+// Parameters:
+//
+//	client - The cache client holding the item and its lock.
+//	key - The key of the item to be changed.
+//	change - The change of the item.
+//
+// Returns:
+//
+//	error - ErrLocked if the lock is not released in time, the error of the change, or an error if the lock cannot be
+//	acquired or released.
+func WithLock(client CacheClient, key string, change func() error) (err error) {
+	lockKey := fmt.Sprintf("%s_%s", LockKey, key)
+	deadline := time.Now().Add(lockWait)
+
+	for {
+		locked, err := client.SetNX(lockKey, time.Now().UTC().Format(time.RFC3339), lockTTL)
+		if err != nil {
+			return err
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			return ErrLocked
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	defer func() {
+		_, unlockErr := client.Delete(lockKey)
+		err = errors.Join(err, unlockErr)
+	}()
+
+	return change()
+}
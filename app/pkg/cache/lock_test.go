@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLock_ReleasesLock(t *testing.T) {
+	// Arrange
+	client := NewMemory()
+
+	// Action
+	err := WithLock(client, "key1", func() error { return errors.New("change error") })
+	_, errLock := client.Get(fmt.Sprintf("%s_%s", LockKey, "key1"))
+
+	// Assert
+	assert.EqualError(t, err, "change error")
+	assert.Equal(t, ErrCacheMiss.Error(), errLock.Error())
+}
+
+func TestWithLock_SerializesChanges(t *testing.T) {
+	// Arrange
+	client := NewMemory()
+	client.Set("counter", "0", 0)
+
+	// Action
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			WithLock(client, "counter", func() error {
+				value, err := client.Get("counter")
+				if err != nil {
+					return err
+				}
+				var counter int
+				fmt.Sscan(string(value), &counter)
+				return client.Set("counter", fmt.Sprint(counter+1), 0)
+			})
+		}()
+	}
+	wg.Wait()
+	value, err := client.Get("counter")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "20", string(value))
+}
@@ -0,0 +1,659 @@
+package cache
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrWrongType  = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+	ErrNotInteger = errors.New("ERR value is not an integer or out of range")
+)
+
+// sweepInterval is the minimum interval between removals of the expired items not accessed since they expired.
+const sweepInterval = time.Minute
+
+type itemKind int
+
+const (
+	stringKind itemKind = iota
+	listKind
+	hashKind
+)
+
+type memoryItem struct {
+	kind      itemKind
+	value     []byte
+	list      [][]byte
+	hash      map[string][]byte
+	expiresAt time.Time
+}
+
+func (i *memoryItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && !now.Before(i.expiresAt)
+}
+
+type memoryClient struct {
+	mutex     sync.Mutex
+	items     map[string]*memoryItem
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemory creates a new instance of memoryClient, a CacheClient keeping the items in the process memory.
+// It honors the expiration of the items and answers misses with the same error as Redis, so a single node
+// can run without Redis, e.g. in development and in end-to-end tests. Items are lost when the process stops
+// and are not shared between processes.
+// Returns a pointer to the initialized memoryClient.
+func NewMemory() *memoryClient {
+	return &memoryClient{
+		items: map[string]*memoryItem{},
+		now:   time.Now,
+	}
+}
+
+// CheckCache reports whether the cache is available, which the in-memory cache always is.
+func (c *memoryClient) CheckCache() bool {
+	return true
+}
+
+// Set stores an item in the cache with the specified key and expiration duration,
+// replacing the item and the expiration of the key. Items without expiration never expire.
+//
+// Parameters:
+//
+//	key - the key under which the item will be stored
+//	item - the item to be stored in the cache
+//	expiration - the duration for which the item should remain in the cache
+//
+// Returns:
+//
+//	error - an error if the item cannot be stored as a string, otherwise nil
+func (c *memoryClient) Set(key string, item interface{}, expiration time.Duration) error {
+	value, err := toBytes(item)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	c.sweep(now)
+	c.items[key] = &memoryItem{value: value, expiresAt: expiresAt(now, expiration)}
+	return nil
+}
+
+// SetNX stores an item in the cache with the specified key and expiration duration
+// only if the key does not exist yet.
+//
+// Parameters:
+//
+//	key - the key under which the item will be stored
+//	item - the item to be stored in the cache
+//	expiration - the duration for which the item should remain in the cache
+//
+// Returns:
+//
+//	bool - true if the item was stored, false if the key already exists
+//	error - an error if the item cannot be stored as a string, otherwise nil
+func (c *memoryClient) SetNX(key string, item interface{}, expiration time.Duration) (bool, error) {
+	value, err := toBytes(item)
+	if err != nil {
+		return false, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	if c.get(key, now) != nil {
+		return false, nil
+	}
+
+	c.sweep(now)
+	c.items[key] = &memoryItem{value: value, expiresAt: expiresAt(now, expiration)}
+	return true, nil
+}
+
+// Get retrieves the value associated with the given key from the cache.
+//
+// Parameters:
+//
+//	key - The key for which the value needs to be retrieved.
+//
+// Returns:
+//
+//	[]byte - The value associated with the key.
+//	error  - ErrCacheMiss if the key does not exist or expired, or ErrWrongType if it holds a list or a hash.
+func (c *memoryClient) Get(key string) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item := c.get(key, c.now())
+	if item == nil {
+		return nil, ErrCacheMiss
+	}
+	if item.kind != stringKind {
+		return nil, ErrWrongType
+	}
+
+	return append([]byte(nil), item.value...), nil
+}
+
+// Delete removes the specified key from the cache.
+//
+// Parameters:
+//
+//	key - The key to be deleted from the cache.
+//
+// Returns:
+//
+//	*int64 - A pointer to the number of keys that were removed.
+//	error - Always nil.
+func (c *memoryClient) Delete(key string) (*int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	result := int64(0)
+	if c.get(key, c.now()) != nil {
+		delete(c.items, key)
+		result = 1
+	}
+
+	return &result, nil
+}
+
+// Increment increments the integer stored at the specified key and sets its expiration when
+// the key has none, as the Redis client does.
+//
+// Parameters:
+//
+//	key - The key of the counter to be incremented.
+//	expiration - The duration for which the counter should remain in the cache.
+//
+// Returns:
+//
+//	int64 - The value of the counter after the increment.
+//	error - ErrNotInteger if the key holds a value that is not an integer, or ErrWrongType if it holds a list or a hash.
+func (c *memoryClient) Increment(key string, expiration time.Duration) (int64, error) {
+	return c.IncrementBy(key, 1, expiration)
+}
+
+// IncrementBy increments the integer stored at the specified key by a value and sets its expiration
+// when the key has none, as Increment does.
+//
+// Parameters:
+//
+//	key - The key of the counter to be incremented.
+//	increment - The value added to the counter.
+//	expiration - The duration for which the counter should remain in the cache.
+//
+// Returns:
+//
+//	int64 - The value of the counter after the increment.
+//	error - ErrNotInteger if the key holds a value that is not an integer, or ErrWrongType if it holds a list or a hash.
+func (c *memoryClient) IncrementBy(key string, increment int64, expiration time.Duration) (int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	item := c.get(key, now)
+	if item == nil {
+		c.sweep(now)
+		item = &memoryItem{value: []byte("0")}
+		c.items[key] = item
+	}
+	if item.kind != stringKind {
+		return 0, ErrWrongType
+	}
+
+	value, err := strconv.ParseInt(string(item.value), 10, 64)
+	if err != nil {
+		return 0, ErrNotInteger
+	}
+
+	value += increment
+	item.value = []byte(strconv.FormatInt(value, 10))
+	if item.expiresAt.IsZero() {
+		item.expiresAt = expiresAt(now, expiration)
+	}
+
+	return value, nil
+}
+
+// Append adds an item to the end of the list stored at the specified key,
+// creating the list if it does not exist. Items are never overwritten.
+//
+// Parameters:
+//
+//	key - The key of the list.
+//	item - The item to be appended to the list.
+//
+// Returns:
+//
+//	error - ErrWrongType if the key holds a value that is not a list, or an error if the item cannot be stored as a string.
+func (c *memoryClient) Append(key string, item interface{}) error {
+	value, err := toBytes(item)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	list := c.get(key, now)
+	if list == nil {
+		c.sweep(now)
+		list = &memoryItem{kind: listKind}
+		c.items[key] = list
+	}
+	if list.kind != listKind {
+		return ErrWrongType
+	}
+
+	list.list = append(list.list, value)
+	return nil
+}
+
+// AppendCapped adds an item to the end of the list stored at the specified key, creating the list if it does
+// not exist, and removes the oldest items so the list keeps at most max items.
+//
+// Parameters:
+//
+//	key - The key of the list.
+//	item - The item to be appended to the list.
+//	max - The maximum number of items kept in the list.
+//
+// Returns:
+//
+//	error - ErrWrongType if the key holds a value that is not a list, or an error if the item cannot be stored as a string.
+func (c *memoryClient) AppendCapped(key string, item interface{}, max int64) error {
+	if err := c.Append(key, item); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if list := c.get(key, c.now()); list != nil && int64(len(list.list)) > max {
+		list.list = append([][]byte(nil), list.list[int64(len(list.list))-max:]...)
+	}
+	return nil
+}
+
+// AppendExpiring adds items to the end of the list stored at the specified key, creating the list if it does not
+// exist, and sets the expiration of the list.
+//
+// Parameters:
+//
+//	key - The key of the list.
+//	expiration - The duration for which the list should remain in the cache after the items are added.
+//	items - The items to be appended to the list.
+//
+// Returns:
+//
+//	error - ErrWrongType if the key holds a value that is not a list, or an error if an item cannot be stored as a string.
+func (c *memoryClient) AppendExpiring(key string, expiration time.Duration, items ...interface{}) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	values := make([][]byte, 0, len(items))
+	for _, item := range items {
+		value, err := toBytes(item)
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	list := c.get(key, now)
+	if list == nil {
+		c.sweep(now)
+		list = &memoryItem{kind: listKind}
+		c.items[key] = list
+	}
+	if list.kind != listKind {
+		return ErrWrongType
+	}
+
+	list.list = append(list.list, values...)
+	list.expiresAt = expiresAt(now, expiration)
+	return nil
+}
+
+// SetAndAppend stores an item in the cache with the specified key and expiration duration and adds items to the
+// end of the list stored at another key, holding the lock for both so neither write happens without the other.
+//
+// Parameters:
+//
+//	key - the key under which the item will be stored
+//	item - the item to be stored in the cache
+//	expiration - the duration for which the item should remain in the cache
+//	listKey - The key of the list.
+//	listItems - The items to be appended to the list.
+//
+// Returns:
+//
+//	error - ErrWrongType if the list key holds a value that is not a list, or an error if an item cannot be stored as a string.
+func (c *memoryClient) SetAndAppend(key string, item interface{}, expiration time.Duration, listKey string, listItems ...interface{}) error {
+	value, err := toBytes(item)
+	if err != nil {
+		return err
+	}
+
+	values := make([][]byte, 0, len(listItems))
+	for _, listItem := range listItems {
+		listValue, err := toBytes(listItem)
+		if err != nil {
+			return err
+		}
+		values = append(values, listValue)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	list := c.get(listKey, now)
+	if key == listKey || (list != nil && list.kind != listKind) {
+		return ErrWrongType
+	}
+
+	c.sweep(now)
+	c.items[key] = &memoryItem{value: value, expiresAt: expiresAt(now, expiration)}
+	if len(values) == 0 {
+		return nil
+	}
+
+	if list == nil {
+		list = &memoryItem{kind: listKind}
+		c.items[listKey] = list
+	}
+	list.list = append(list.list, values...)
+	return nil
+}
+
+// GetList retrieves all the items of the list stored at the specified key, in insertion order.
+// It returns an empty slice if the list does not exist.
+//
+// Parameters:
+//
+//	key - The key of the list.
+//
+// Returns:
+//
+//	[][]byte - The items of the list.
+//	error - ErrWrongType if the key holds a value that is not a list.
+func (c *memoryClient) GetList(key string) ([][]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	list := c.get(key, c.now())
+	if list == nil {
+		return [][]byte{}, nil
+	}
+	if list.kind != listKind {
+		return nil, ErrWrongType
+	}
+
+	items := make([][]byte, 0, len(list.list))
+	for _, value := range list.list {
+		items = append(items, append([]byte(nil), value...))
+	}
+
+	return items, nil
+}
+
+// TakeList retrieves and removes all the items of the list stored at the specified key, in insertion order, holding
+// the lock for both so each item is taken once. It returns an empty slice if the list does not exist.
+//
+// Parameters:
+//
+//	key - The key of the list.
+//
+// Returns:
+//
+//	[][]byte - The items of the list.
+//	error - ErrWrongType if the key holds a value that is not a list.
+func (c *memoryClient) TakeList(key string) ([][]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	list := c.get(key, c.now())
+	if list == nil {
+		return [][]byte{}, nil
+	}
+	if list.kind != listKind {
+		return nil, ErrWrongType
+	}
+
+	delete(c.items, key)
+	return list.list, nil
+}
+
+// HSet stores an item in the field of the hash stored at the specified key, creating the hash if it does not exist.
+//
+// Parameters:
+//
+//	key - The key of the hash.
+//	field - The field under which the item will be stored.
+//	item - The item to be stored in the hash.
+//
+// Returns:
+//
+//	error - ErrWrongType if the key holds a value that is not a hash, or an error if the item cannot be stored as a string.
+func (c *memoryClient) HSet(key string, field string, item interface{}) error {
+	value, err := toBytes(item)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	hash := c.get(key, now)
+	if hash == nil {
+		c.sweep(now)
+		hash = &memoryItem{kind: hashKind, hash: map[string][]byte{}}
+		c.items[key] = hash
+	}
+	if hash.kind != hashKind {
+		return ErrWrongType
+	}
+
+	hash.hash[field] = value
+	return nil
+}
+
+// HGet retrieves the value of a field of the hash stored at the specified key.
+//
+// Parameters:
+//
+//	key - The key of the hash.
+//	field - The field of the hash.
+//
+// Returns:
+//
+//	[]byte - The value of the field.
+//	error - ErrCacheMiss if the hash or the field does not exist, or ErrWrongType if the key holds a value that is not a hash.
+func (c *memoryClient) HGet(key string, field string) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	hash := c.get(key, c.now())
+	if hash == nil {
+		return nil, ErrCacheMiss
+	}
+	if hash.kind != hashKind {
+		return nil, ErrWrongType
+	}
+
+	value, exists := hash.hash[field]
+	if !exists {
+		return nil, ErrCacheMiss
+	}
+
+	return append([]byte(nil), value...), nil
+}
+
+// HGetAll retrieves all the fields of the hash stored at the specified key.
+// It returns an empty map if the hash does not exist.
+//
+// Parameters:
+//
+//	key - The key of the hash.
+//
+// Returns:
+//
+//	map[string][]byte - The values of the hash by field.
+//	error - ErrWrongType if the key holds a value that is not a hash.
+func (c *memoryClient) HGetAll(key string) (map[string][]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	values := map[string][]byte{}
+
+	hash := c.get(key, c.now())
+	if hash == nil {
+		return values, nil
+	}
+	if hash.kind != hashKind {
+		return nil, ErrWrongType
+	}
+
+	for field, value := range hash.hash {
+		values[field] = append([]byte(nil), value...)
+	}
+
+	return values, nil
+}
+
+// HDelete removes a field from the hash stored at the specified key, removing the hash when it becomes empty.
+//
+// Parameters:
+//
+//	key - The key of the hash.
+//	field - The field to be removed.
+//
+// Returns:
+//
+//	bool - true if the field was removed, false if it did not exist.
+//	error - ErrWrongType if the key holds a value that is not a hash.
+func (c *memoryClient) HDelete(key string, field string) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	hash := c.get(key, c.now())
+	if hash == nil {
+		return false, nil
+	}
+	if hash.kind != hashKind {
+		return false, ErrWrongType
+	}
+
+	if _, exists := hash.hash[field]; !exists {
+		return false, nil
+	}
+
+	delete(hash.hash, field)
+	if len(hash.hash) == 0 {
+		delete(c.items, key)
+	}
+	return true, nil
+}
+
+// get returns the item of a key, removing it when it expired. It must be called holding the mutex.
+func (c *memoryClient) get(key string, now time.Time) *memoryItem {
+	item, exists := c.items[key]
+	if !exists {
+		return nil
+	}
+	if item.expired(now) {
+		delete(c.items, key)
+		return nil
+	}
+	return item
+}
+
+// sweep removes the expired items at most once per sweepInterval, so items never read again do not pile up.
+// It must be called holding the mutex.
+func (c *memoryClient) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < sweepInterval {
+		return
+	}
+
+	for key, item := range c.items {
+		if item.expired(now) {
+			delete(c.items, key)
+		}
+	}
+	c.lastSweep = now
+}
+
+func expiresAt(now time.Time, expiration time.Duration) time.Time {
+	if expiration <= 0 {
+		return time.Time{}
+	}
+	return now.Add(expiration)
+}
+
+// toBytes converts an item to the bytes stored for it, as the Redis client writes it.
+func toBytes(item interface{}) ([]byte, error) {
+	switch value := item.(type) {
+	case nil:
+		return []byte{}, nil
+	case string:
+		return []byte(value), nil
+	case []byte:
+		return append([]byte(nil), value...), nil
+	case int:
+		return strconv.AppendInt(nil, int64(value), 10), nil
+	case int8:
+		return strconv.AppendInt(nil, int64(value), 10), nil
+	case int16:
+		return strconv.AppendInt(nil, int64(value), 10), nil
+	case int32:
+		return strconv.AppendInt(nil, int64(value), 10), nil
+	case int64:
+		return strconv.AppendInt(nil, value, 10), nil
+	case uint:
+		return strconv.AppendUint(nil, uint64(value), 10), nil
+	case uint8:
+		return strconv.AppendUint(nil, uint64(value), 10), nil
+	case uint16:
+		return strconv.AppendUint(nil, uint64(value), 10), nil
+	case uint32:
+		return strconv.AppendUint(nil, uint64(value), 10), nil
+	case uint64:
+		return strconv.AppendUint(nil, value, 10), nil
+	case float32:
+		return strconv.AppendFloat(nil, float64(value), 'f', -1, 32), nil
+	case float64:
+		return strconv.AppendFloat(nil, value, 'f', -1, 64), nil
+	case bool:
+		if value {
+			return []byte("1"), nil
+		}
+		return []byte("0"), nil
+	case time.Time:
+		return value.AppendFormat(nil, time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.AppendInt(nil, value.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		return value.MarshalBinary()
+	default:
+		return nil, fmt.Errorf("redis: can't marshal %T (implement encoding.BinaryMarshaler)", item)
+	}
+}
@@ -0,0 +1,329 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMemory() (*memoryClient, *time.Time) {
+	now := time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC)
+	client := NewMemory()
+	client.now = func() time.Time { return now }
+	return client, &now
+}
+
+func TestMemory_SetGet(t *testing.T) {
+	// Arrange
+	client, now := newTestMemory()
+
+	// Action
+	errSet := client.Set("key1", `{"id": "1"}`, time.Minute)
+	value, err := client.Get("key1")
+	*now = now.Add(time.Minute)
+	_, errExpired := client.Get("key1")
+
+	// Assert
+	assert.NoError(t, errSet)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id": "1"}`, string(value))
+	assert.Equal(t, ErrCacheMiss.Error(), errExpired.Error())
+}
+
+func TestMemory_Get_Miss(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+
+	// Action
+	value, err := client.Get("key1")
+
+	// Assert
+	assert.Nil(t, value)
+	assert.Equal(t, ErrCacheMiss.Error(), err.Error())
+}
+
+func TestMemory_Set_WithoutExpiration(t *testing.T) {
+	// Arrange
+	client, now := newTestMemory()
+
+	// Action
+	client.Set("key1", []byte("value"), 0)
+	*now = now.Add(365 * 24 * time.Hour)
+	value, err := client.Get("key1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "value", string(value))
+}
+
+func TestMemory_Set_UnsupportedItem(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+
+	// Action
+	err := client.Set("key1", map[string]string{}, 0)
+
+	// Assert
+	assert.Error(t, err)
+}
+
+func TestMemory_SetNX(t *testing.T) {
+	// Arrange
+	client, now := newTestMemory()
+
+	// Action
+	first, _ := client.SetNX("lock1", "owner1", time.Second)
+	second, _ := client.SetNX("lock1", "owner2", time.Second)
+	*now = now.Add(time.Second)
+	afterExpiry, _ := client.SetNX("lock1", "owner3", time.Second)
+	value, _ := client.Get("lock1")
+
+	// Assert
+	assert.True(t, first)
+	assert.False(t, second)
+	assert.True(t, afterExpiry)
+	assert.Equal(t, "owner3", string(value))
+}
+
+func TestMemory_Delete(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+	client.Set("key1", "value", 0)
+
+	// Action
+	deleted, err := client.Delete("key1")
+	deletedAgain, _ := client.Delete("key1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), *deleted)
+	assert.Equal(t, int64(0), *deletedAgain)
+}
+
+func TestMemory_Increment(t *testing.T) {
+	// Arrange
+	client, now := newTestMemory()
+
+	// Action
+	first, _ := client.Increment("counter1", time.Minute)
+	*now = now.Add(30 * time.Second)
+	second, _ := client.Increment("counter1", time.Minute)
+	*now = now.Add(30 * time.Second)
+	afterWindow, _ := client.Increment("counter1", time.Minute)
+
+	// Assert
+	assert.Equal(t, int64(1), first)
+	assert.Equal(t, int64(2), second)
+	assert.Equal(t, int64(1), afterWindow)
+}
+
+func TestMemory_IncrementBy(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+
+	// Action
+	first, _ := client.IncrementBy("counter1", 10, time.Minute)
+	second, err := client.Increment("counter1", time.Minute)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), first)
+	assert.Equal(t, int64(11), second)
+}
+
+func TestMemory_Increment_NotInteger(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+	client.Set("key1", "value", 0)
+
+	// Action
+	_, err := client.Increment("key1", time.Minute)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNotInteger)
+}
+
+func TestMemory_AppendGetList(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+
+	// Action
+	empty, errEmpty := client.GetList("list1")
+	client.Append("list1", "item1")
+	client.Append("list1", []byte("item2"))
+	items, err := client.GetList("list1")
+
+	// Assert
+	assert.NoError(t, errEmpty)
+	assert.Empty(t, empty)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("item1"), []byte("item2")}, items)
+}
+
+func TestMemory_WrongType(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+	client.Set("key1", "value", 0)
+	client.Append("list1", "item1")
+
+	// Action
+	errAppend := client.Append("key1", "item1")
+	_, errGetList := client.GetList("key1")
+	_, errGet := client.Get("list1")
+
+	// Assert
+	assert.ErrorIs(t, errAppend, ErrWrongType)
+	assert.ErrorIs(t, errGetList, ErrWrongType)
+	assert.ErrorIs(t, errGet, ErrWrongType)
+}
+
+func TestMemory_Sweep(t *testing.T) {
+	// Arrange
+	client, now := newTestMemory()
+	client.Set("key1", "value", time.Second)
+	*now = now.Add(sweepInterval)
+
+	// Action
+	client.Set("key2", "value", 0)
+
+	// Assert
+	assert.NotContains(t, client.items, "key1")
+	assert.Contains(t, client.items, "key2")
+}
+
+func TestNewClient(t *testing.T) {
+	// Action
+	memory, memoryErr := NewClient(Config{Driver: MemoryDriver})
+	redis, redisErr := NewClient(Config{Driver: RedisDriver})
+	_, unknownErr := NewClient(Config{Driver: "memcached"})
+
+	// Assert
+	assert.NoError(t, memoryErr)
+	assert.NoError(t, redisErr)
+	assert.IsType(t, &memoryClient{}, memory)
+	assert.IsType(t, &cacheClient{}, redis)
+	assert.ErrorIs(t, unknownErr, ErrUnknownDriver)
+}
+
+func TestMemory_AppendCapped(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+
+	// Action
+	for _, item := range []string{"item1", "item2", "item3"} {
+		client.AppendCapped("list1", item, 2)
+	}
+	items, err := client.GetList("list1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("item2"), []byte("item3")}, items)
+}
+
+func TestMemory_Hash(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+
+	// Action
+	empty, errEmpty := client.HGetAll("hash1")
+	client.HSet("hash1", "field1", "value1")
+	client.HSet("hash1", "field2", "value2")
+	value, err := client.HGet("hash1", "field1")
+	_, errMiss := client.HGet("hash1", "field3")
+	removed, errDelete := client.HDelete("hash1", "field1")
+	removedAgain, _ := client.HDelete("hash1", "field1")
+	values, errAll := client.HGetAll("hash1")
+
+	// Assert
+	assert.NoError(t, errEmpty)
+	assert.Empty(t, empty)
+	assert.NoError(t, err)
+	assert.Equal(t, "value1", string(value))
+	assert.ErrorIs(t, errMiss, ErrCacheMiss)
+	assert.NoError(t, errDelete)
+	assert.True(t, removed)
+	assert.False(t, removedAgain)
+	assert.NoError(t, errAll)
+	assert.Equal(t, map[string][]byte{"field2": []byte("value2")}, values)
+}
+
+func TestMemory_Hash_WrongType(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+	client.Set("key1", "value", 0)
+	client.HSet("hash1", "field1", "value1")
+
+	// Action
+	errSet := client.HSet("key1", "field1", "value1")
+	_, errGet := client.Get("hash1")
+	_, errGetList := client.GetList("hash1")
+
+	// Assert
+	assert.ErrorIs(t, errSet, ErrWrongType)
+	assert.ErrorIs(t, errGet, ErrWrongType)
+	assert.ErrorIs(t, errGetList, ErrWrongType)
+}
+
+func TestMemory_SetAndAppend(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+	_ = client.Append("list1", "entry1")
+	_ = client.Set("hash1", "value", 0)
+
+	// Action
+	err := client.SetAndAppend("key1", "value1", 0, "list1", "entry2", "entry3")
+	value, _ := client.Get("key1")
+	list, _ := client.GetList("list1")
+	errWrongType := client.SetAndAppend("key2", "value2", 0, "hash1", "entry1")
+	_, errNotSet := client.Get("key2")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "value1", string(value))
+	assert.Equal(t, [][]byte{[]byte("entry1"), []byte("entry2"), []byte("entry3")}, list)
+	assert.Equal(t, ErrWrongType, errWrongType)
+	assert.Equal(t, ErrCacheMiss.Error(), errNotSet.Error())
+}
+
+func TestMemory_AppendExpiring(t *testing.T) {
+	// Arrange
+	client, now := newTestMemory()
+
+	// Action
+	errFirst := client.AppendExpiring("list1", time.Minute, "item1")
+	*now = now.Add(30 * time.Second)
+	errSecond := client.AppendExpiring("list1", time.Minute, "item2", "item3")
+	*now = now.Add(45 * time.Second)
+	items, err := client.GetList("list1")
+	*now = now.Add(30 * time.Second)
+	expired, errExpired := client.GetList("list1")
+
+	// Assert
+	assert.NoError(t, errFirst)
+	assert.NoError(t, errSecond)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("item1"), []byte("item2"), []byte("item3")}, items)
+	assert.NoError(t, errExpired)
+	assert.Empty(t, expired)
+}
+
+func TestMemory_TakeList(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+	_ = client.Append("list1", "item1")
+	_ = client.Append("list1", "item2")
+	_ = client.Set("key1", "value1", 0)
+
+	// Action
+	items, err := client.TakeList("list1")
+	again, errAgain := client.TakeList("list1")
+	_, errWrongType := client.TakeList("key1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("item1"), []byte("item2")}, items)
+	assert.NoError(t, errAgain)
+	assert.Empty(t, again)
+	assert.Equal(t, ErrWrongType, errWrongType)
+}
@@ -17,8 +17,21 @@ var (
 type CacheClient interface {
 	CheckCache() bool
 	Set(key string, item interface{}, expiration time.Duration) error
+	SetNX(key string, item interface{}, expiration time.Duration) (bool, error)
 	Get(key string) ([]byte, error)
 	Delete(key string) (*int64, error)
+	Increment(key string, expiration time.Duration) (int64, error)
+	IncrementBy(key string, increment int64, expiration time.Duration) (int64, error)
+	Append(key string, item interface{}) error
+	AppendCapped(key string, item interface{}, max int64) error
+	AppendExpiring(key string, expiration time.Duration, items ...interface{}) error
+	SetAndAppend(key string, item interface{}, expiration time.Duration, listKey string, listItems ...interface{}) error
+	GetList(key string) ([][]byte, error)
+	TakeList(key string) ([][]byte, error)
+	HSet(key string, field string, item interface{}) error
+	HGet(key string, field string) ([]byte, error)
+	HGetAll(key string) (map[string][]byte, error)
+	HDelete(key string, field string) (bool, error)
 }
 
 type cacheClient struct {
@@ -69,6 +82,23 @@ func (c *cacheClient) Set(key string, item interface{}, expiration time.Duration
 	return c.cache.Set(c.context, key, item, expiration).Err()
 }
 
+// SetNX stores an item in the cache with the specified key and expiration duration
+// only if the key does not exist yet.
+//
+// Parameters:
+//
+//	key - the key under which the item will be stored
+//	item - the item to be stored in the cache
+//	expiration - the duration for which the item should remain in the cache
+//
+// Returns:
+//
+//	bool - true if the item was stored, false if the key already exists
+//	error - an error if the operation fails, otherwise nil
+func (c *cacheClient) SetNX(key string, item interface{}, expiration time.Duration) (bool, error) {
+	return c.cache.SetNX(c.context, key, item, expiration).Result()
+}
+
 // Get retrieves the value associated with the given key from the cache.
 // It returns the value as a byte slice and an error if the operation fails.
 //
@@ -104,3 +134,261 @@ func (c *cacheClient) Delete(key string) (*int64, error) {
 
 	return &result, nil
 }
+
+// Increment atomically increments the integer stored at the specified key and
+// sets its expiration when the key is created. It is used to keep counters
+// that are shared between replicas, such as rate limit windows.
+//
+// Parameters:
+//
+//	key - The key of the counter to be incremented.
+//	expiration - The duration for which the counter should remain in the cache.
+//
+// Returns:
+//
+//	int64 - The value of the counter after the increment.
+//	error - An error if the increment operation fails.
+func (c *cacheClient) Increment(key string, expiration time.Duration) (int64, error) {
+	return c.IncrementBy(key, 1, expiration)
+}
+
+// IncrementBy atomically increments the integer stored at the specified key by a value and
+// sets its expiration when the key is created, as Increment does.
+//
+// Parameters:
+//
+//	key - The key of the counter to be incremented.
+//	increment - The value added to the counter.
+//	expiration - The duration for which the counter should remain in the cache.
+//
+// Returns:
+//
+//	int64 - The value of the counter after the increment.
+//	error - An error if the increment operation fails.
+func (c *cacheClient) IncrementBy(key string, increment int64, expiration time.Duration) (int64, error) {
+	pipe := c.cache.TxPipeline()
+	incr := pipe.IncrBy(c.context, key, increment)
+	pipe.ExpireNX(c.context, key, expiration)
+
+	if _, err := pipe.Exec(c.context); err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+// Append adds an item to the end of the list stored at the specified key,
+// creating the list if it does not exist. Items are never overwritten.
+//
+// Parameters:
+//
+//	key - The key of the list.
+//	item - The item to be appended to the list.
+//
+// Returns:
+//
+//	error - An error if the append operation fails.
+func (c *cacheClient) Append(key string, item interface{}) error {
+	return c.cache.RPush(c.context, key, item).Err()
+}
+
+// AppendCapped adds an item to the end of the list stored at the specified key, creating the list if it does
+// not exist, and removes the oldest items so the list keeps at most max items. Both run in a single transaction.
+//
+// Parameters:
+//
+//	key - The key of the list.
+//	item - The item to be appended to the list.
+//	max - The maximum number of items kept in the list.
+//
+// Returns:
+//
+//	error - An error if the append operation fails.
+func (c *cacheClient) AppendCapped(key string, item interface{}, max int64) error {
+	pipe := c.cache.TxPipeline()
+	pipe.RPush(c.context, key, item)
+	pipe.LTrim(c.context, key, -max, -1)
+
+	_, err := pipe.Exec(c.context)
+	return err
+}
+
+// AppendExpiring adds items to the end of the list stored at the specified key, creating the list if it does not
+// exist, and sets the expiration of the list. Both run in a single transaction.
+//
+// Parameters:
+//
+//	key - The key of the list.
+//	expiration - The duration for which the list should remain in the cache after the items are added.
+//	items - The items to be appended to the list.
+//
+// Returns:
+//
+//	error - An error if the append operation fails.
+func (c *cacheClient) AppendExpiring(key string, expiration time.Duration, items ...interface{}) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	pipe := c.cache.TxPipeline()
+	pipe.RPush(c.context, key, items...)
+	if expiration > 0 {
+		pipe.Expire(c.context, key, expiration)
+	}
+
+	_, err := pipe.Exec(c.context)
+	return err
+}
+
+// SetAndAppend stores an item in the cache with the specified key and expiration duration and adds items to the
+// end of the list stored at another key in a single transaction, so neither write happens without the other.
+//
+// Parameters:
+//
+//	key - the key under which the item will be stored
+//	item - the item to be stored in the cache
+//	expiration - the duration for which the item should remain in the cache
+//	listKey - The key of the list.
+//	listItems - The items to be appended to the list.
+//
+// Returns:
+//
+//	error - An error if the transaction fails.
+func (c *cacheClient) SetAndAppend(key string, item interface{}, expiration time.Duration, listKey string, listItems ...interface{}) error {
+	pipe := c.cache.TxPipeline()
+	pipe.Set(c.context, key, item, expiration)
+	if len(listItems) > 0 {
+		pipe.RPush(c.context, listKey, listItems...)
+	}
+
+	_, err := pipe.Exec(c.context)
+	return err
+}
+
+// GetList retrieves all the items of the list stored at the specified key, in insertion order.
+// It returns an empty slice if the list does not exist.
+//
+// Parameters:
+//
+//	key - The key of the list.
+//
+// Returns:
+//
+//	[][]byte - The items of the list.
+//	error - An error if the retrieval fails.
+func (c *cacheClient) GetList(key string) ([][]byte, error) {
+	values, err := c.cache.LRange(c.context, key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([][]byte, 0, len(values))
+	for _, value := range values {
+		items = append(items, []byte(value))
+	}
+
+	return items, nil
+}
+
+// TakeList retrieves and removes all the items of the list stored at the specified key, in insertion order, in a
+// single transaction, so each item is taken once. It returns an empty slice if the list does not exist.
+//
+// Parameters:
+//
+//	key - The key of the list.
+//
+// Returns:
+//
+//	[][]byte - The items of the list.
+//	error - An error if the retrieval fails.
+func (c *cacheClient) TakeList(key string) ([][]byte, error) {
+	pipe := c.cache.TxPipeline()
+	lrange := pipe.LRange(c.context, key, 0, -1)
+	pipe.Del(c.context, key)
+
+	if _, err := pipe.Exec(c.context); err != nil {
+		return nil, err
+	}
+
+	items := make([][]byte, 0, len(lrange.Val()))
+	for _, value := range lrange.Val() {
+		items = append(items, []byte(value))
+	}
+
+	return items, nil
+}
+
+// HSet stores an item in the field of the hash stored at the specified key, creating the hash if it does not exist.
+//
+// Parameters:
+//
+//	key - The key of the hash.
+//	field - The field under which the item will be stored.
+//	item - The item to be stored in the hash.
+//
+// Returns:
+//
+//	error - An error if the item cannot be stored.
+func (c *cacheClient) HSet(key string, field string, item interface{}) error {
+	return c.cache.HSet(c.context, key, field, item).Err()
+}
+
+// HGet retrieves the value of a field of the hash stored at the specified key.
+//
+// Parameters:
+//
+//	key - The key of the hash.
+//	field - The field of the hash.
+//
+// Returns:
+//
+//	[]byte - The value of the field.
+//	error - ErrCacheMiss if the hash or the field does not exist, or an error if the retrieval fails.
+func (c *cacheClient) HGet(key string, field string) ([]byte, error) {
+	return c.cache.HGet(c.context, key, field).Bytes()
+}
+
+// HGetAll retrieves all the fields of the hash stored at the specified key.
+// It returns an empty map if the hash does not exist.
+//
+// Parameters:
+//
+//	key - The key of the hash.
+//
+// Returns:
+//
+//	map[string][]byte - The values of the hash by field.
+//	error - An error if the retrieval fails.
+func (c *cacheClient) HGetAll(key string) (map[string][]byte, error) {
+	values, err := c.cache.HGetAll(c.context, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[string][]byte, len(values))
+	for field, value := range values {
+		items[field] = []byte(value)
+	}
+
+	return items, nil
+}
+
+// HDelete removes a field from the hash stored at the specified key.
+//
+// Parameters:
+//
+//	key - The key of the hash.
+//	field - The field to be removed.
+//
+// Returns:
+//
+//	bool - true if the field was removed, false if it did not exist.
+//	error - An error if the removal fails.
+func (c *cacheClient) HDelete(key string, field string) (bool, error) {
+	removed, err := c.cache.HDel(c.context, key, field).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return removed > 0, nil
+}
@@ -0,0 +1,25 @@
+package index
+
+import (
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	Retention time.Duration
+}
+
+// LoadConfig loads the transaction index configuration from the environment variables.
+//
+// Environment Variables:
+//   - TRANSACTION_INDEX_RETENTION: how long after its creation a transaction can be found by provider events, e.g.
+//     "4320h". It should cover the refund and dispute windows of the providers.
+//
+// Returns:
+//   - Config: the transaction index configuration.
+func LoadConfig() Config {
+	return Config{
+		Retention: utils.GetEnvDuration("TRANSACTION_INDEX_RETENTION", 180*24*time.Hour),
+	}
+}
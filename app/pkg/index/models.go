@@ -0,0 +1,6 @@
+package index
+
+type Entry struct {
+	TransactionId string `json:"transaction_id"`
+	Date          string `json:"date"`
+}
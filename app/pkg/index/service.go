@@ -0,0 +1,107 @@
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+)
+
+// DateFormat is the format of the date transactions are grouped by, as in "transactions_Key_02_01_2006".
+const DateFormat = "02_01_2006"
+
+var ErrNotIndexed = errors.New("transaction not indexed")
+
+type IndexService interface {
+	Put(lookupId string, transactionId string, createdAt time.Time) error
+	Get(lookupId string) (*Entry, error)
+}
+
+type indexService struct {
+	cache  cache.CacheClient
+	config Config
+	now    func() time.Time
+}
+
+// New creates a new instance of indexService with the provided cache client and configuration.
+// It returns a pointer to the newly created indexService.
+//
+// Parameters:
+//   - cache: an instance of cache.CacheClient where the index is kept.
+//   - config: the transaction index configuration.
+//
+// Returns:
+//   - *indexService: a pointer to the newly created indexService.
+func New(cache cache.CacheClient, config Config) *indexService {
+	return &indexService{
+		cache:  cache,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Put indexes a transaction under an ID it can be looked up by, such as its own ID or its reference at the provider,
+// so it can be found regardless of the date it is grouped by. Every entry of a transaction expires once the retention
+// has passed since the transaction was created, and transactions older than the retention are not indexed.
+//
+// Parameters:
+//   - lookupId: the ID the transaction is looked up by.
+//   - transactionId: the ID of the transaction.
+//   - createdAt: the creation time of the transaction, which sets the date it is grouped by.
+//
+// Returns:
+//   - error: an error if the index could not be stored.
+func (s *indexService) Put(lookupId string, transactionId string, createdAt time.Time) error {
+	expiration := createdAt.Add(s.config.Retention).Sub(s.now())
+	if expiration <= 0 {
+		return nil
+	}
+
+	entry := Entry{TransactionId: transactionId, Date: createdAt.Format(DateFormat)}
+
+	entrySerialized, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return s.cache.Set(key(lookupId), string(entrySerialized), expiration)
+}
+
+// Get looks up the transaction indexed under an ID.
+//
+// Parameters:
+//   - lookupId: the ID the transaction is looked up by.
+//
+// Returns:
+//   - *Entry: the ID of the transaction and the date it is grouped by.
+//   - error: ErrNotIndexed if no transaction is indexed under the ID, or an error if the index could not be read.
+func (s *indexService) Get(lookupId string) (*Entry, error) {
+	c, err := s.cache.Get(key(lookupId))
+	if err != nil {
+		if err.Error() == cache.ErrCacheMiss.Error() {
+			return nil, ErrNotIndexed
+		}
+		return nil, err
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(c, &entry); err != nil {
+		return nil, fmt.Errorf("invalid transaction index: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// TransactionsKey returns the key of the transactions grouped by the date of the entry.
+//
+// Returns:
+//   - string: the key of the transactions of the date.
+func (e Entry) TransactionsKey() string {
+	return fmt.Sprintf("%s_%s", cache.TransactionsKey, e.Date)
+}
+
+func key(lookupId string) string {
+	return fmt.Sprintf("%s_%s", cache.TransactionIndexKey, lookupId)
+}
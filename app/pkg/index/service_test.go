@@ -0,0 +1,57 @@
+package index
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC)
+
+func newTestService() *indexService {
+	service := New(cache.NewMemory(), Config{Retention: 24 * time.Hour})
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func TestPutGet(t *testing.T) {
+	// Arrange
+	service := newTestService()
+
+	// Action
+	err := service.Put("pi_123", "transaction1", now.Add(-time.Hour))
+	entry, errGet := service.Get("pi_123")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, errGet)
+	assert.Equal(t, &Entry{TransactionId: "transaction1", Date: "01_10_2024"}, entry)
+	assert.Equal(t, "transactions_Key_01_10_2024", entry.TransactionsKey())
+}
+
+func TestPut_OutsideRetention(t *testing.T) {
+	// Arrange
+	service := newTestService()
+
+	// Action
+	err := service.Put("pi_123", "transaction1", now.Add(-25*time.Hour))
+	_, errGet := service.Get("pi_123")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.ErrorIs(t, errGet, ErrNotIndexed)
+}
+
+func TestGet_NotIndexed(t *testing.T) {
+	// Arrange
+	service := newTestService()
+
+	// Action
+	entry, err := service.Get("pi_123")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrNotIndexed)
+	assert.Nil(t, entry)
+}
@@ -0,0 +1,11 @@
+package iso4217
+
+// Currency is the ISO 4217 metadata of a currency. Countries are ISO 3166-1 alpha-2 codes.
+type Currency struct {
+	Code        string   `json:"code"`
+	Name        string   `json:"name,omitempty"`
+	NumericCode string   `json:"numeric_code,omitempty"`
+	Symbol      string   `json:"symbol,omitempty"`
+	MinorUnits  int      `json:"minor_units"`
+	Countries   []string `json:"countries,omitempty"`
+}
@@ -0,0 +1,197 @@
+package iso4217
+
+import "sort"
+
+// currencies are the active currencies of ISO 4217, sorted by code.
+var currencies = []Currency{
+	{Code: "AED", Name: "UAE Dirham", NumericCode: "784", Symbol: "د.إ", MinorUnits: 2, Countries: []string{"AE"}},
+	{Code: "AFN", Name: "Afghani", NumericCode: "971", Symbol: "؋", MinorUnits: 2, Countries: []string{"AF"}},
+	{Code: "ALL", Name: "Lek", NumericCode: "008", Symbol: "L", MinorUnits: 2, Countries: []string{"AL"}},
+	{Code: "AMD", Name: "Armenian Dram", NumericCode: "051", Symbol: "֏", MinorUnits: 2, Countries: []string{"AM"}},
+	{Code: "AOA", Name: "Kwanza", NumericCode: "973", Symbol: "Kz", MinorUnits: 2, Countries: []string{"AO"}},
+	{Code: "ARS", Name: "Argentine Peso", NumericCode: "032", Symbol: "$", MinorUnits: 2, Countries: []string{"AR"}},
+	{Code: "AUD", Name: "Australian Dollar", NumericCode: "036", Symbol: "A$", MinorUnits: 2, Countries: []string{"AU", "CC", "CX", "HM", "KI", "NF", "NR", "TV"}},
+	{Code: "AWG", Name: "Aruban Florin", NumericCode: "533", Symbol: "ƒ", MinorUnits: 2, Countries: []string{"AW"}},
+	{Code: "AZN", Name: "Azerbaijan Manat", NumericCode: "944", Symbol: "₼", MinorUnits: 2, Countries: []string{"AZ"}},
+	{Code: "BAM", Name: "Convertible Mark", NumericCode: "977", Symbol: "KM", MinorUnits: 2, Countries: []string{"BA"}},
+	{Code: "BBD", Name: "Barbados Dollar", NumericCode: "052", Symbol: "Bds$", MinorUnits: 2, Countries: []string{"BB"}},
+	{Code: "BDT", Name: "Taka", NumericCode: "050", Symbol: "৳", MinorUnits: 2, Countries: []string{"BD"}},
+	{Code: "BHD", Name: "Bahraini Dinar", NumericCode: "048", Symbol: ".د.ب", MinorUnits: 3, Countries: []string{"BH"}},
+	{Code: "BIF", Name: "Burundi Franc", NumericCode: "108", Symbol: "FBu", MinorUnits: 0, Countries: []string{"BI"}},
+	{Code: "BMD", Name: "Bermudian Dollar", NumericCode: "060", Symbol: "$", MinorUnits: 2, Countries: []string{"BM"}},
+	{Code: "BND", Name: "Brunei Dollar", NumericCode: "096", Symbol: "B$", MinorUnits: 2, Countries: []string{"BN"}},
+	{Code: "BOB", Name: "Boliviano", NumericCode: "068", Symbol: "Bs", MinorUnits: 2, Countries: []string{"BO"}},
+	{Code: "BRL", Name: "Brazilian Real", NumericCode: "986", Symbol: "R$", MinorUnits: 2, Countries: []string{"BR"}},
+	{Code: "BSD", Name: "Bahamian Dollar", NumericCode: "044", Symbol: "$", MinorUnits: 2, Countries: []string{"BS"}},
+	{Code: "BTN", Name: "Ngultrum", NumericCode: "064", Symbol: "Nu.", MinorUnits: 2, Countries: []string{"BT"}},
+	{Code: "BWP", Name: "Pula", NumericCode: "072", Symbol: "P", MinorUnits: 2, Countries: []string{"BW"}},
+	{Code: "BYN", Name: "Belarusian Ruble", NumericCode: "933", Symbol: "Br", MinorUnits: 2, Countries: []string{"BY"}},
+	{Code: "BZD", Name: "Belize Dollar", NumericCode: "084", Symbol: "BZ$", MinorUnits: 2, Countries: []string{"BZ"}},
+	{Code: "CAD", Name: "Canadian Dollar", NumericCode: "124", Symbol: "CA$", MinorUnits: 2, Countries: []string{"CA"}},
+	{Code: "CDF", Name: "Congolese Franc", NumericCode: "976", Symbol: "FC", MinorUnits: 2, Countries: []string{"CD"}},
+	{Code: "CHF", Name: "Swiss Franc", NumericCode: "756", Symbol: "CHF", MinorUnits: 2, Countries: []string{"CH", "LI"}},
+	{Code: "CLP", Name: "Chilean Peso", NumericCode: "152", Symbol: "$", MinorUnits: 0, Countries: []string{"CL"}},
+	{Code: "CNY", Name: "Yuan Renminbi", NumericCode: "156", Symbol: "¥", MinorUnits: 2, Countries: []string{"CN"}},
+	{Code: "COP", Name: "Colombian Peso", NumericCode: "170", Symbol: "$", MinorUnits: 2, Countries: []string{"CO"}},
+	{Code: "CRC", Name: "Costa Rican Colon", NumericCode: "188", Symbol: "₡", MinorUnits: 2, Countries: []string{"CR"}},
+	{Code: "CUP", Name: "Cuban Peso", NumericCode: "192", Symbol: "$", MinorUnits: 2, Countries: []string{"CU"}},
+	{Code: "CVE", Name: "Cabo Verde Escudo", NumericCode: "132", Symbol: "Esc", MinorUnits: 2, Countries: []string{"CV"}},
+	{Code: "CZK", Name: "Czech Koruna", NumericCode: "203", Symbol: "Kč", MinorUnits: 2, Countries: []string{"CZ"}},
+	{Code: "DJF", Name: "Djibouti Franc", NumericCode: "262", Symbol: "Fdj", MinorUnits: 0, Countries: []string{"DJ"}},
+	{Code: "DKK", Name: "Danish Krone", NumericCode: "208", Symbol: "kr", MinorUnits: 2, Countries: []string{"DK", "FO", "GL"}},
+	{Code: "DOP", Name: "Dominican Peso", NumericCode: "214", Symbol: "RD$", MinorUnits: 2, Countries: []string{"DO"}},
+	{Code: "DZD", Name: "Algerian Dinar", NumericCode: "012", Symbol: "د.ج", MinorUnits: 2, Countries: []string{"DZ"}},
+	{Code: "EGP", Name: "Egyptian Pound", NumericCode: "818", Symbol: "E£", MinorUnits: 2, Countries: []string{"EG"}},
+	{Code: "ERN", Name: "Nakfa", NumericCode: "232", Symbol: "Nfk", MinorUnits: 2, Countries: []string{"ER"}},
+	{Code: "ETB", Name: "Ethiopian Birr", NumericCode: "230", Symbol: "Br", MinorUnits: 2, Countries: []string{"ET"}},
+	{Code: "EUR", Name: "Euro", NumericCode: "978", Symbol: "€", MinorUnits: 2, Countries: []string{"AD", "AT", "AX", "BE", "BG", "BL", "CY", "DE", "EE", "ES", "FI", "FR", "GF", "GP", "GR", "HR", "IE", "IT", "LT", "LU", "LV", "MC", "ME", "MF", "MQ", "MT", "NL", "PM", "PT", "RE", "SI", "SK", "SM", "TF", "VA", "YT"}},
+	{Code: "FJD", Name: "Fiji Dollar", NumericCode: "242", Symbol: "FJ$", MinorUnits: 2, Countries: []string{"FJ"}},
+	{Code: "FKP", Name: "Falkland Islands Pound", NumericCode: "238", Symbol: "£", MinorUnits: 2, Countries: []string{"FK"}},
+	{Code: "GBP", Name: "Pound Sterling", NumericCode: "826", Symbol: "£", MinorUnits: 2, Countries: []string{"GB", "GG", "IM", "JE"}},
+	{Code: "GEL", Name: "Lari", NumericCode: "981", Symbol: "₾", MinorUnits: 2, Countries: []string{"GE"}},
+	{Code: "GHS", Name: "Ghana Cedi", NumericCode: "936", Symbol: "GH₵", MinorUnits: 2, Countries: []string{"GH"}},
+	{Code: "GIP", Name: "Gibraltar Pound", NumericCode: "292", Symbol: "£", MinorUnits: 2, Countries: []string{"GI"}},
+	{Code: "GMD", Name: "Dalasi", NumericCode: "270", Symbol: "D", MinorUnits: 2, Countries: []string{"GM"}},
+	{Code: "GNF", Name: "Guinean Franc", NumericCode: "324", Symbol: "FG", MinorUnits: 0, Countries: []string{"GN"}},
+	{Code: "GTQ", Name: "Quetzal", NumericCode: "320", Symbol: "Q", MinorUnits: 2, Countries: []string{"GT"}},
+	{Code: "GYD", Name: "Guyana Dollar", NumericCode: "328", Symbol: "G$", MinorUnits: 2, Countries: []string{"GY"}},
+	{Code: "HKD", Name: "Hong Kong Dollar", NumericCode: "344", Symbol: "HK$", MinorUnits: 2, Countries: []string{"HK"}},
+	{Code: "HNL", Name: "Lempira", NumericCode: "340", Symbol: "L", MinorUnits: 2, Countries: []string{"HN"}},
+	{Code: "HTG", Name: "Gourde", NumericCode: "332", Symbol: "G", MinorUnits: 2, Countries: []string{"HT"}},
+	{Code: "HUF", Name: "Forint", NumericCode: "348", Symbol: "Ft", MinorUnits: 2, Countries: []string{"HU"}},
+	{Code: "IDR", Name: "Rupiah", NumericCode: "360", Symbol: "Rp", MinorUnits: 2, Countries: []string{"ID"}},
+	{Code: "ILS", Name: "New Israeli Sheqel", NumericCode: "376", Symbol: "₪", MinorUnits: 2, Countries: []string{"IL"}},
+	{Code: "INR", Name: "Indian Rupee", NumericCode: "356", Symbol: "₹", MinorUnits: 2, Countries: []string{"BT", "IN"}},
+	{Code: "IQD", Name: "Iraqi Dinar", NumericCode: "368", Symbol: "ع.د", MinorUnits: 3, Countries: []string{"IQ"}},
+	{Code: "IRR", Name: "Iranian Rial", NumericCode: "364", Symbol: "﷼", MinorUnits: 2, Countries: []string{"IR"}},
+	{Code: "ISK", Name: "Iceland Krona", NumericCode: "352", Symbol: "kr", MinorUnits: 0, Countries: []string{"IS"}},
+	{Code: "JMD", Name: "Jamaican Dollar", NumericCode: "388", Symbol: "J$", MinorUnits: 2, Countries: []string{"JM"}},
+	{Code: "JOD", Name: "Jordanian Dinar", NumericCode: "400", Symbol: "د.ا", MinorUnits: 3, Countries: []string{"JO"}},
+	{Code: "JPY", Name: "Yen", NumericCode: "392", Symbol: "¥", MinorUnits: 0, Countries: []string{"JP"}},
+	{Code: "KES", Name: "Kenyan Shilling", NumericCode: "404", Symbol: "KSh", MinorUnits: 2, Countries: []string{"KE"}},
+	{Code: "KGS", Name: "Som", NumericCode: "417", Symbol: "с", MinorUnits: 2, Countries: []string{"KG"}},
+	{Code: "KHR", Name: "Riel", NumericCode: "116", Symbol: "៛", MinorUnits: 2, Countries: []string{"KH"}},
+	{Code: "KMF", Name: "Comorian Franc", NumericCode: "174", Symbol: "CF", MinorUnits: 0, Countries: []string{"KM"}},
+	{Code: "KPW", Name: "North Korean Won", NumericCode: "408", Symbol: "₩", MinorUnits: 2, Countries: []string{"KP"}},
+	{Code: "KRW", Name: "Won", NumericCode: "410", Symbol: "₩", MinorUnits: 0, Countries: []string{"KR"}},
+	{Code: "KWD", Name: "Kuwaiti Dinar", NumericCode: "414", Symbol: "د.ك", MinorUnits: 3, Countries: []string{"KW"}},
+	{Code: "KYD", Name: "Cayman Islands Dollar", NumericCode: "136", Symbol: "CI$", MinorUnits: 2, Countries: []string{"KY"}},
+	{Code: "KZT", Name: "Tenge", NumericCode: "398", Symbol: "₸", MinorUnits: 2, Countries: []string{"KZ"}},
+	{Code: "LAK", Name: "Lao Kip", NumericCode: "418", Symbol: "₭", MinorUnits: 2, Countries: []string{"LA"}},
+	{Code: "LBP", Name: "Lebanese Pound", NumericCode: "422", Symbol: "ل.ل", MinorUnits: 2, Countries: []string{"LB"}},
+	{Code: "LKR", Name: "Sri Lanka Rupee", NumericCode: "144", Symbol: "Rs", MinorUnits: 2, Countries: []string{"LK"}},
+	{Code: "LRD", Name: "Liberian Dollar", NumericCode: "430", Symbol: "L$", MinorUnits: 2, Countries: []string{"LR"}},
+	{Code: "LSL", Name: "Loti", NumericCode: "426", Symbol: "L", MinorUnits: 2, Countries: []string{"LS"}},
+	{Code: "LYD", Name: "Libyan Dinar", NumericCode: "434", Symbol: "ل.د", MinorUnits: 3, Countries: []string{"LY"}},
+	{Code: "MAD", Name: "Moroccan Dirham", NumericCode: "504", Symbol: "د.م.", MinorUnits: 2, Countries: []string{"EH", "MA"}},
+	{Code: "MDL", Name: "Moldovan Leu", NumericCode: "498", Symbol: "L", MinorUnits: 2, Countries: []string{"MD"}},
+	{Code: "MGA", Name: "Malagasy Ariary", NumericCode: "969", Symbol: "Ar", MinorUnits: 2, Countries: []string{"MG"}},
+	{Code: "MKD", Name: "Denar", NumericCode: "807", Symbol: "ден", MinorUnits: 2, Countries: []string{"MK"}},
+	{Code: "MMK", Name: "Kyat", NumericCode: "104", Symbol: "K", MinorUnits: 2, Countries: []string{"MM"}},
+	{Code: "MNT", Name: "Tugrik", NumericCode: "496", Symbol: "₮", MinorUnits: 2, Countries: []string{"MN"}},
+	{Code: "MOP", Name: "Pataca", NumericCode: "446", Symbol: "MOP$", MinorUnits: 2, Countries: []string{"MO"}},
+	{Code: "MRU", Name: "Ouguiya", NumericCode: "929", Symbol: "UM", MinorUnits: 2, Countries: []string{"MR"}},
+	{Code: "MUR", Name: "Mauritius Rupee", NumericCode: "480", Symbol: "₨", MinorUnits: 2, Countries: []string{"MU"}},
+	{Code: "MVR", Name: "Rufiyaa", NumericCode: "462", Symbol: "Rf", MinorUnits: 2, Countries: []string{"MV"}},
+	{Code: "MWK", Name: "Malawi Kwacha", NumericCode: "454", Symbol: "MK", MinorUnits: 2, Countries: []string{"MW"}},
+	{Code: "MXN", Name: "Mexican Peso", NumericCode: "484", Symbol: "MX$", MinorUnits: 2, Countries: []string{"MX"}},
+	{Code: "MYR", Name: "Malaysian Ringgit", NumericCode: "458", Symbol: "RM", MinorUnits: 2, Countries: []string{"MY"}},
+	{Code: "MZN", Name: "Mozambique Metical", NumericCode: "943", Symbol: "MT", MinorUnits: 2, Countries: []string{"MZ"}},
+	{Code: "NAD", Name: "Namibia Dollar", NumericCode: "516", Symbol: "N$", MinorUnits: 2, Countries: []string{"NA"}},
+	{Code: "NGN", Name: "Naira", NumericCode: "566", Symbol: "₦", MinorUnits: 2, Countries: []string{"NG"}},
+	{Code: "NIO", Name: "Cordoba Oro", NumericCode: "558", Symbol: "C$", MinorUnits: 2, Countries: []string{"NI"}},
+	{Code: "NOK", Name: "Norwegian Krone", NumericCode: "578", Symbol: "kr", MinorUnits: 2, Countries: []string{"BV", "NO", "SJ"}},
+	{Code: "NPR", Name: "Nepalese Rupee", NumericCode: "524", Symbol: "Rs", MinorUnits: 2, Countries: []string{"NP"}},
+	{Code: "NZD", Name: "New Zealand Dollar", NumericCode: "554", Symbol: "NZ$", MinorUnits: 2, Countries: []string{"CK", "NU", "NZ", "PN", "TK"}},
+	{Code: "OMR", Name: "Rial Omani", NumericCode: "512", Symbol: "ر.ع.", MinorUnits: 3, Countries: []string{"OM"}},
+	{Code: "PAB", Name: "Balboa", NumericCode: "590", Symbol: "B/.", MinorUnits: 2, Countries: []string{"PA"}},
+	{Code: "PEN", Name: "Sol", NumericCode: "604", Symbol: "S/", MinorUnits: 2, Countries: []string{"PE"}},
+	{Code: "PGK", Name: "Kina", NumericCode: "598", Symbol: "K", MinorUnits: 2, Countries: []string{"PG"}},
+	{Code: "PHP", Name: "Philippine Peso", NumericCode: "608", Symbol: "₱", MinorUnits: 2, Countries: []string{"PH"}},
+	{Code: "PKR", Name: "Pakistan Rupee", NumericCode: "586", Symbol: "Rs", MinorUnits: 2, Countries: []string{"PK"}},
+	{Code: "PLN", Name: "Zloty", NumericCode: "985", Symbol: "zł", MinorUnits: 2, Countries: []string{"PL"}},
+	{Code: "PYG", Name: "Guarani", NumericCode: "600", Symbol: "₲", MinorUnits: 0, Countries: []string{"PY"}},
+	{Code: "QAR", Name: "Qatari Rial", NumericCode: "634", Symbol: "ر.ق", MinorUnits: 2, Countries: []string{"QA"}},
+	{Code: "RON", Name: "Romanian Leu", NumericCode: "946", Symbol: "lei", MinorUnits: 2, Countries: []string{"RO"}},
+	{Code: "RSD", Name: "Serbian Dinar", NumericCode: "941", Symbol: "дин.", MinorUnits: 2, Countries: []string{"RS"}},
+	{Code: "RUB", Name: "Russian Ruble", NumericCode: "643", Symbol: "₽", MinorUnits: 2, Countries: []string{"RU"}},
+	{Code: "RWF", Name: "Rwanda Franc", NumericCode: "646", Symbol: "FRw", MinorUnits: 0, Countries: []string{"RW"}},
+	{Code: "SAR", Name: "Saudi Riyal", NumericCode: "682", Symbol: "ر.س", MinorUnits: 2, Countries: []string{"SA"}},
+	{Code: "SBD", Name: "Solomon Islands Dollar", NumericCode: "090", Symbol: "SI$", MinorUnits: 2, Countries: []string{"SB"}},
+	{Code: "SCR", Name: "Seychelles Rupee", NumericCode: "690", Symbol: "₨", MinorUnits: 2, Countries: []string{"SC"}},
+	{Code: "SDG", Name: "Sudanese Pound", NumericCode: "938", Symbol: "ج.س.", MinorUnits: 2, Countries: []string{"SD"}},
+	{Code: "SEK", Name: "Swedish Krona", NumericCode: "752", Symbol: "kr", MinorUnits: 2, Countries: []string{"SE"}},
+	{Code: "SGD", Name: "Singapore Dollar", NumericCode: "702", Symbol: "S$", MinorUnits: 2, Countries: []string{"SG"}},
+	{Code: "SHP", Name: "Saint Helena Pound", NumericCode: "654", Symbol: "£", MinorUnits: 2, Countries: []string{"SH"}},
+	{Code: "SLE", Name: "Leone", NumericCode: "925", Symbol: "Le", MinorUnits: 2, Countries: []string{"SL"}},
+	{Code: "SOS", Name: "Somali Shilling", NumericCode: "706", Symbol: "Sh", MinorUnits: 2, Countries: []string{"SO"}},
+	{Code: "SRD", Name: "Surinam Dollar", NumericCode: "968", Symbol: "$", MinorUnits: 2, Countries: []string{"SR"}},
+	{Code: "SSP", Name: "South Sudanese Pound", NumericCode: "728", Symbol: "£", MinorUnits: 2, Countries: []string{"SS"}},
+	{Code: "STN", Name: "Dobra", NumericCode: "930", Symbol: "Db", MinorUnits: 2, Countries: []string{"ST"}},
+	{Code: "SVC", Name: "El Salvador Colon", NumericCode: "222", Symbol: "₡", MinorUnits: 2, Countries: []string{"SV"}},
+	{Code: "SYP", Name: "Syrian Pound", NumericCode: "760", Symbol: "£S", MinorUnits: 2, Countries: []string{"SY"}},
+	{Code: "SZL", Name: "Lilangeni", NumericCode: "748", Symbol: "E", MinorUnits: 2, Countries: []string{"SZ"}},
+	{Code: "THB", Name: "Baht", NumericCode: "764", Symbol: "฿", MinorUnits: 2, Countries: []string{"TH"}},
+	{Code: "TJS", Name: "Somoni", NumericCode: "972", Symbol: "SM", MinorUnits: 2, Countries: []string{"TJ"}},
+	{Code: "TMT", Name: "Turkmenistan New Manat", NumericCode: "934", Symbol: "m", MinorUnits: 2, Countries: []string{"TM"}},
+	{Code: "TND", Name: "Tunisian Dinar", NumericCode: "788", Symbol: "د.ت", MinorUnits: 3, Countries: []string{"TN"}},
+	{Code: "TOP", Name: "Pa'anga", NumericCode: "776", Symbol: "T$", MinorUnits: 2, Countries: []string{"TO"}},
+	{Code: "TRY", Name: "Turkish Lira", NumericCode: "949", Symbol: "₺", MinorUnits: 2, Countries: []string{"TR"}},
+	{Code: "TTD", Name: "Trinidad and Tobago Dollar", NumericCode: "780", Symbol: "TT$", MinorUnits: 2, Countries: []string{"TT"}},
+	{Code: "TWD", Name: "New Taiwan Dollar", NumericCode: "901", Symbol: "NT$", MinorUnits: 2, Countries: []string{"TW"}},
+	{Code: "TZS", Name: "Tanzanian Shilling", NumericCode: "834", Symbol: "TSh", MinorUnits: 2, Countries: []string{"TZ"}},
+	{Code: "UAH", Name: "Hryvnia", NumericCode: "980", Symbol: "₴", MinorUnits: 2, Countries: []string{"UA"}},
+	{Code: "UGX", Name: "Uganda Shilling", NumericCode: "800", Symbol: "USh", MinorUnits: 0, Countries: []string{"UG"}},
+	{Code: "USD", Name: "US Dollar", NumericCode: "840", Symbol: "$", MinorUnits: 2, Countries: []string{"AS", "BQ", "EC", "FM", "GU", "IO", "MH", "MP", "PA", "PR", "PW", "SV", "TC", "TL", "UM", "US", "VG", "VI"}},
+	{Code: "UYU", Name: "Peso Uruguayo", NumericCode: "858", Symbol: "$U", MinorUnits: 2, Countries: []string{"UY"}},
+	{Code: "UZS", Name: "Uzbekistan Sum", NumericCode: "860", Symbol: "soʻm", MinorUnits: 2, Countries: []string{"UZ"}},
+	{Code: "VED", Name: "Bolívar Soberano", NumericCode: "926", Symbol: "Bs.D", MinorUnits: 2, Countries: []string{"VE"}},
+	{Code: "VES", Name: "Bolívar Soberano", NumericCode: "928", Symbol: "Bs.S", MinorUnits: 2, Countries: []string{"VE"}},
+	{Code: "VND", Name: "Dong", NumericCode: "704", Symbol: "₫", MinorUnits: 0, Countries: []string{"VN"}},
+	{Code: "VUV", Name: "Vatu", NumericCode: "548", Symbol: "VT", MinorUnits: 0, Countries: []string{"VU"}},
+	{Code: "WST", Name: "Tala", NumericCode: "882", Symbol: "WS$", MinorUnits: 2, Countries: []string{"WS"}},
+	{Code: "XAF", Name: "CFA Franc BEAC", NumericCode: "950", Symbol: "FCFA", MinorUnits: 0, Countries: []string{"CF", "CG", "CM", "GA", "GQ", "TD"}},
+	{Code: "XCD", Name: "East Caribbean Dollar", NumericCode: "951", Symbol: "EC$", MinorUnits: 2, Countries: []string{"AG", "AI", "DM", "GD", "KN", "LC", "MS", "VC"}},
+	{Code: "XCG", Name: "Caribbean Guilder", NumericCode: "532", Symbol: "Cg", MinorUnits: 2, Countries: []string{"CW", "SX"}},
+	{Code: "XOF", Name: "CFA Franc BCEAO", NumericCode: "952", Symbol: "CFA", MinorUnits: 0, Countries: []string{"BF", "BJ", "CI", "GW", "ML", "NE", "SN", "TG"}},
+	{Code: "XPF", Name: "CFP Franc", NumericCode: "953", Symbol: "₣", MinorUnits: 0, Countries: []string{"NC", "PF", "WF"}},
+	{Code: "YER", Name: "Yemeni Rial", NumericCode: "886", Symbol: "﷼", MinorUnits: 2, Countries: []string{"YE"}},
+	{Code: "ZAR", Name: "Rand", NumericCode: "710", Symbol: "R", MinorUnits: 2, Countries: []string{"LS", "NA", "ZA"}},
+	{Code: "ZMW", Name: "Zambian Kwacha", NumericCode: "967", Symbol: "ZK", MinorUnits: 2, Countries: []string{"ZM"}},
+	{Code: "ZWG", Name: "Zimbabwe Gold", NumericCode: "924", Symbol: "ZiG", MinorUnits: 2, Countries: []string{"ZW"}},
+}
+
+var registry = func() map[string]Currency {
+	registry := make(map[string]Currency, len(currencies))
+	for _, currency := range currencies {
+		registry[currency.Code] = currency
+	}
+	return registry
+}()
+
+// Get returns the metadata of a currency.
+//
+// Parameters:
+//   - code: the alphabetic code of the currency, in upper case.
+//
+// Returns:
+//   - Currency: the metadata of the currency.
+//   - bool: false if the code is not an active ISO 4217 currency.
+func Get(code string) (Currency, bool) {
+	currency, ok := registry[code]
+	return currency, ok
+}
+
+// IsValid reports whether the code is the alphabetic code of an active ISO 4217 currency, in upper case.
+func IsValid(code string) bool {
+	_, ok := registry[code]
+	return ok
+}
+
+// All returns the metadata of every currency, sorted by code.
+func All() []Currency {
+	all := make([]Currency, len(currencies))
+	copy(all, currencies)
+	sort.Slice(all, func(i, j int) bool { return all[i].Code < all[j].Code })
+	return all
+}
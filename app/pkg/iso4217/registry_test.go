@@ -0,0 +1,67 @@
+package iso4217
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	// Action
+	currency, ok := Get("BRL")
+
+	// Assert
+	assert.True(t, ok)
+	assert.Equal(t, Currency{Code: "BRL", Name: "Brazilian Real", NumericCode: "986", Symbol: "R$", MinorUnits: 2, Countries: []string{"BR"}}, currency)
+}
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected bool
+	}{
+		{"USD", true},
+		{"JPY", true},
+		{"usd", false},
+		{"XYZ", false},
+		{"US", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			// Action & Assert
+			assert.Equal(t, tt.expected, IsValid(tt.code))
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	// Arrange
+	alphabetic := regexp.MustCompile(`^[A-Z]{3}$`)
+	numeric := regexp.MustCompile(`^[0-9]{3}$`)
+	country := regexp.MustCompile(`^[A-Z]{2}$`)
+
+	// Action
+	all := All()
+
+	// Assert
+	numericCodes := map[string]bool{}
+	for i, currency := range all {
+		if i > 0 {
+			assert.Less(t, all[i-1].Code, currency.Code, "currencies must be sorted and unique")
+		}
+		assert.Regexp(t, alphabetic, currency.Code)
+		assert.Regexp(t, numeric, currency.NumericCode, currency.Code)
+		assert.False(t, numericCodes[currency.NumericCode], "duplicated numeric code of %s", currency.Code)
+		numericCodes[currency.NumericCode] = true
+		assert.NotEmpty(t, currency.Name, currency.Code)
+		assert.NotEmpty(t, currency.Symbol, currency.Code)
+		assert.Contains(t, []int{0, 2, 3}, currency.MinorUnits, currency.Code)
+		assert.NotEmpty(t, currency.Countries, currency.Code)
+		for _, code := range currency.Countries {
+			assert.Regexp(t, country, code, currency.Code)
+		}
+	}
+}
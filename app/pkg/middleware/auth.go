@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RoleMerchant = "merchant"
+	RoleAdmin    = "admin"
+
+	APIKeyHeader = "x-mgc-apiKey"
+	identityKey  = "mgc_identity"
+)
+
+// Identity is the authenticated caller of a request: a merchant, or an administrator operating the api.
+type Identity struct {
+	Id   string
+	Role string
+}
+
+// APIKeys maps the SHA-256 hash of each API key to the identity it authenticates, so the keys are never kept in memory.
+type APIKeys map[string]Identity
+
+// LoadAPIKeys loads the API keys from the environment variables, each a comma-separated list of "<id>:<key>" items.
+//
+// Environment Variables:
+//   - MERCHANT_API_KEYS: the API keys of the merchants, e.g. "merchant-1:sk_live_123,merchant-2:sk_live_456".
+//   - ADMIN_API_KEYS: the API keys of the administrators, e.g. "ops:sk_admin_789".
+//
+// Returns:
+//   - APIKeys: the identities by API key hash.
+//   - error: an error if an item is not in the "<id>:<key>" format or a key is used twice.
+func LoadAPIKeys() (APIKeys, error) {
+	keys := APIKeys{}
+	for role, env := range map[string]string{RoleMerchant: "MERCHANT_API_KEYS", RoleAdmin: "ADMIN_API_KEYS"} {
+		for _, item := range utils.GetEnvList(env, nil) {
+			id, key, found := strings.Cut(item, ":")
+			id, key = strings.TrimSpace(id), strings.TrimSpace(key)
+			if !found || id == "" || key == "" {
+				return nil, fmt.Errorf("invalid API key in %s: expected \"<id>:<key>\"", env)
+			}
+
+			hash := utils.Hash(key)
+			if _, exists := keys[hash]; exists {
+				return nil, fmt.Errorf("duplicated API key in %s for %q", env, id)
+			}
+			keys[hash] = Identity{Id: id, Role: role}
+		}
+	}
+
+	return keys, nil
+}
+
+// Authenticate returns a middleware that authenticates the request by its "x-mgc-apiKey" header and stores the
+// identity in the context, to be read by the handlers with GetIdentity. Requests without a known API key are rejected
+// with 401 Unauthorized, and requests of an identity without one of the given roles with 403 Forbidden.
+//
+// Parameters:
+//   - keys: the known API keys.
+//   - roles: the roles allowed to make the request.
+//
+// Returns:
+//   - gin.HandlerFunc: the authentication middleware.
+func Authenticate(keys APIKeys, roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		apiKey := ctx.GetHeader(APIKeyHeader)
+		identity, exists := keys[utils.Hash(apiKey)]
+		if utils.IsEmptyOrNull(apiKey) || !exists {
+			utils.ApiResponse(ctx, http.StatusUnauthorized, []utils.Errors{
+				{
+					Field:   APIKeyHeader,
+					Message: "a valid API key is required",
+				},
+			})
+			return
+		}
+
+		if !hasRole(identity, roles) {
+			utils.ApiResponse(ctx, http.StatusForbidden, []utils.Errors{
+				{
+					Field:   APIKeyHeader,
+					Message: "the API key is not allowed to access this resource",
+				},
+			})
+			return
+		}
+
+		SetIdentity(ctx, identity)
+		ctx.Next()
+	}
+}
+
+// GetIdentity returns the identity authenticated by the Authenticate middleware.
+//
+// Parameters:
+//   - ctx: the request context.
+//
+// Returns:
+//   - Identity: the authenticated identity.
+//   - bool: false if the request was not authenticated.
+func GetIdentity(ctx *gin.Context) (Identity, bool) {
+	value, exists := ctx.Get(identityKey)
+	if !exists {
+		return Identity{}, false
+	}
+
+	identity, ok := value.(Identity)
+	return identity, ok
+}
+
+// RequireIdentity returns the identity authenticated by the Authenticate middleware, answering 401 Unauthorized when
+// the request was not authenticated, so a route registered without the middleware fails closed.
+//
+// Parameters:
+//   - ctx: the request context.
+//
+// Returns:
+//   - Identity: the authenticated identity.
+//   - bool: false if the request was not authenticated and the response was written.
+func RequireIdentity(ctx *gin.Context) (Identity, bool) {
+	identity, ok := GetIdentity(ctx)
+	if !ok || utils.IsEmptyOrNull(identity.Id) {
+		utils.ApiResponse(ctx, http.StatusUnauthorized, []utils.Errors{
+			{
+				Field:   APIKeyHeader,
+				Message: "a valid API key is required",
+			},
+		})
+		return Identity{}, false
+	}
+
+	return identity, true
+}
+
+// SetIdentity stores the authenticated identity in the request context.
+//
+// Parameters:
+//   - ctx: the request context.
+//   - identity: the authenticated identity.
+func SetIdentity(ctx *gin.Context, identity Identity) {
+	ctx.Set(identityKey, identity)
+}
+
+func hasRole(identity Identity, roles []string) bool {
+	for _, role := range roles {
+		if identity.Role == role {
+			return true
+		}
+	}
+	return false
+}
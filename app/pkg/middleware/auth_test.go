@@ -0,0 +1,83 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate(t *testing.T) {
+	keys := middleware.APIKeys{
+		utils.Hash("merchant-key"): {Id: "merchant-1", Role: middleware.RoleMerchant},
+		utils.Hash("admin-key"):    {Id: "ops", Role: middleware.RoleAdmin},
+	}
+
+	tests := []struct {
+		name         string
+		apiKey       string
+		expectedCode int
+		expectedBody string
+	}{
+		{"Merchant", "merchant-key", http.StatusOK, "merchant-1"},
+		{"Wrong role", "admin-key", http.StatusForbidden, ""},
+		{"Unknown key", "other-key", http.StatusUnauthorized, ""},
+		{"Missing key", "", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/", middleware.Authenticate(keys, middleware.RoleMerchant), func(ctx *gin.Context) {
+				identity, _ := middleware.GetIdentity(ctx)
+				ctx.String(http.StatusOK, identity.Id)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.apiKey != "" {
+				req.Header.Set("x-mgc-apiKey", tt.apiKey)
+			}
+
+			// Action
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	// Arrange
+	t.Setenv("MERCHANT_API_KEYS", "merchant-1:key1, merchant-2:key2")
+	t.Setenv("ADMIN_API_KEYS", "ops:key3")
+
+	// Action
+	keys, err := middleware.LoadAPIKeys()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, middleware.APIKeys{
+		utils.Hash("key1"): {Id: "merchant-1", Role: middleware.RoleMerchant},
+		utils.Hash("key2"): {Id: "merchant-2", Role: middleware.RoleMerchant},
+		utils.Hash("key3"): {Id: "ops", Role: middleware.RoleAdmin},
+	}, keys)
+
+	t.Setenv("ADMIN_API_KEYS", "ops")
+	_, err = middleware.LoadAPIKeys()
+	assert.Error(t, err)
+
+	t.Setenv("ADMIN_API_KEYS", "ops:key1")
+	_, err = middleware.LoadAPIKeys()
+	assert.Error(t, err)
+}
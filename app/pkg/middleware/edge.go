@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// LoadTrustedProxies loads the proxies trusted to report the client IP address and to set the edge headers from
+// the environment variable TRUSTED_PROXIES, a comma-separated list of IP addresses and CIDR ranges,
+// e.g. "10.0.0.0/8,192.168.1.10". No proxy is trusted when it is not set.
+//
+// Returns:
+//   - []netip.Prefix: the trusted proxies, single addresses as prefixes of their full length.
+//   - error: an error if an item is neither an IP address nor a CIDR range.
+func LoadTrustedProxies() ([]netip.Prefix, error) {
+	var proxies []netip.Prefix
+	for _, item := range utils.GetEnvList("TRUSTED_PROXIES", nil) {
+		if strings.Contains(item, "/") {
+			prefix, err := netip.ParsePrefix(item)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", item, err)
+			}
+			proxies = append(proxies, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", item, err)
+		}
+		proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return proxies, nil
+}
+
+// EdgeHeaders returns a middleware that removes the given headers from the requests that do not come directly
+// from one of the trusted proxies. Edge headers, such as the country of the client IP address, are set by the
+// proxy in front of the api, so a client could forge them by sending them itself.
+//
+// Parameters:
+//   - trustedProxies: the proxies trusted to set the headers.
+//   - headers: the headers set by the trusted proxies.
+//
+// Returns:
+//   - gin.HandlerFunc: the edge headers middleware.
+func EdgeHeaders(trustedProxies []netip.Prefix, headers ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !isTrustedProxy(trustedProxies, ctx.RemoteIP()) {
+			for _, header := range headers {
+				ctx.Request.Header.Del(header)
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+func isTrustedProxy(trustedProxies []netip.Prefix, remoteIP string) bool {
+	addr, err := netip.ParseAddr(remoteIP)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdgeHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		expected   string
+	}{
+		{"Trusted proxy", "10.1.2.3:443", "BR"},
+		{"Client", "203.0.113.7:443", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/", middleware.EdgeHeaders([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, "x-mgc-ipCountry"), func(ctx *gin.Context) {
+				ctx.String(http.StatusOK, ctx.GetHeader("x-mgc-ipCountry"))
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("x-mgc-ipCountry", "BR")
+
+			// Action
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expected, w.Body.String())
+		})
+	}
+}
+
+func TestLoadTrustedProxies(t *testing.T) {
+	// Arrange
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.10")
+
+	// Action
+	proxies, err := middleware.LoadTrustedProxies()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.1.10/32")}, proxies)
+
+	t.Setenv("TRUSTED_PROXIES", "not-an-ip")
+	_, err = middleware.LoadTrustedProxies()
+	assert.Error(t, err)
+}
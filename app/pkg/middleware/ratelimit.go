@@ -0,0 +1,270 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// KeyFunc extracts the identity used to count requests from the incoming request.
+// It returns false when the request carries no such identity, in which case the
+// limiter is skipped for that request.
+type KeyFunc func(ctx *gin.Context) (string, bool)
+
+// CostFunc returns how many units of the limit a request consumes, such as the number of items of a batch request.
+type CostFunc func(ctx *gin.Context) int64
+
+type RateLimit struct {
+	Name   string
+	Limit  int64
+	Window time.Duration
+	Key    KeyFunc
+	// Cost weighs the requests. Each request consumes one unit when it is not set.
+	Cost CostFunc
+}
+
+// LoadRateLimit returns the given rate limit overridden by the environment variable
+// RATE_LIMIT_<NAME>, where NAME is the upper-cased rate limit name. The variable
+// must be in the format "<limit>/<window>", for example "100/1m".
+// If the variable is not set or is invalid, the default rate limit is returned.
+//
+// Parameters:
+//   - rateLimit: the default rate limit.
+//
+// Returns:
+//   - RateLimit: the rate limit to be applied.
+func LoadRateLimit(rateLimit RateLimit) RateLimit {
+	value := os.Getenv(fmt.Sprintf("RATE_LIMIT_%s", strings.ToUpper(rateLimit.Name)))
+	if utils.IsEmptyOrNull(value) {
+		return rateLimit
+	}
+
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 {
+		return rateLimit
+	}
+
+	limit, err := strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64)
+	if err != nil || limit <= 0 {
+		return rateLimit
+	}
+
+	window, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || window <= 0 {
+		return rateLimit
+	}
+
+	rateLimit.Limit = limit
+	rateLimit.Window = window
+	return rateLimit
+}
+
+// RateLimiter returns a middleware that limits the number of requests per key using
+// a sliding window counter stored in the cache, so the limit is shared by all replicas.
+// The request count is the sum of the current window count and the previous window
+// count weighted by how much of the previous window still overlaps the sliding window.
+// Each request counts as many units as its cost, such as the number of items of a batch.
+//
+// Every response carries the RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and
+// RateLimit-Policy headers. Requests over the limit are rejected with 429 Too Many
+// Requests and a Retry-After header. If the cache is unavailable the request is allowed.
+//
+// Parameters:
+//   - cacheClient: the cache client used to store the window counters.
+//   - logger: an instance of zap.Logger used for logging.
+//   - rateLimit: the rate limit to be applied.
+//
+// Returns:
+//   - gin.HandlerFunc: the rate limit middleware.
+func RateLimiter(cacheClient cache.CacheClient, logger *zap.Logger, rateLimit RateLimit) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key, ok := rateLimit.Key(ctx)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		now := time.Now()
+		window := now.UnixNano() / int64(rateLimit.Window)
+		elapsed := time.Duration(now.UnixNano() % int64(rateLimit.Window))
+
+		cost := int64(1)
+		if rateLimit.Cost != nil {
+			cost = rateLimit.Cost(ctx)
+		}
+
+		count, err := cacheClient.IncrementBy(windowKey(rateLimit.Name, key, window), cost, rateLimit.Window*2)
+		if err != nil {
+			logger.Warn("Rate limit unavailable, allowing request", zap.String("rate_limit", rateLimit.Name), zap.Error(err))
+			ctx.Next()
+			return
+		}
+
+		previous := previousCount(cacheClient, windowKey(rateLimit.Name, key, window-1))
+		weight := 1 - float64(elapsed)/float64(rateLimit.Window)
+		estimated := int64(math.Ceil(float64(previous)*weight)) + count
+
+		reset := int64(math.Ceil((rateLimit.Window - elapsed).Seconds()))
+		remaining := rateLimit.Limit - estimated
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		ctx.Header("RateLimit-Limit", strconv.FormatInt(rateLimit.Limit, 10))
+		ctx.Header("RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+		ctx.Header("RateLimit-Reset", strconv.FormatInt(reset, 10))
+		ctx.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", rateLimit.Limit, int64(rateLimit.Window.Seconds())))
+
+		if estimated > rateLimit.Limit {
+			logger.Warn("Rate limit exceeded", zap.String("rate_limit", rateLimit.Name), zap.Int64("count", estimated))
+			ctx.Header("Retry-After", strconv.FormatInt(reset, 10))
+			utils.ApiResponse(ctx, http.StatusTooManyRequests, []utils.Errors{
+				{
+					Field:   "rate_limit",
+					Message: fmt.Sprintf("too many requests, please retry in %d seconds", reset),
+				},
+			})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// ByAPIKey identifies the request by the "x-mgc-apiKey" header.
+func ByAPIKey(ctx *gin.Context) (string, bool) {
+	apiKey := ctx.GetHeader(APIKeyHeader)
+	if utils.IsEmptyOrNull(apiKey) {
+		return "", false
+	}
+
+	return fmt.Sprintf("key_%s", utils.Hash(apiKey)), true
+}
+
+// ByIP identifies the request by the client IP address.
+func ByIP(ctx *gin.Context) (string, bool) {
+	ip := ctx.ClientIP()
+	if utils.IsEmptyOrNull(ip) {
+		return "", false
+	}
+
+	return fmt.Sprintf("ip_%s", ip), true
+}
+
+// MaxFingerprintBodyBytes is the maximum size of the request body read to fingerprint the card.
+const MaxFingerprintBodyBytes = 1 << 20
+
+// ByCardFingerprint returns a key function that identifies the request by an HMAC fingerprint of the card number
+// in the JSON payload, keyed with the secret, so the card number can neither be read from nor brute-forced out of
+// the cache keys. At most MaxFingerprintBodyBytes of the body are read, and the body is restored after being read
+// so it can be bound by the handler.
+//
+// Parameters:
+//   - secret: the secret of the fingerprints, shared by every replica.
+//
+// Returns:
+//   - KeyFunc: the key function.
+func ByCardFingerprint(secret []byte) KeyFunc {
+	return func(ctx *gin.Context) (string, bool) {
+		if ctx.Request.Body == nil {
+			return "", false
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, MaxFingerprintBodyBytes))
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return "", false
+		}
+
+		var payload struct {
+			CardDetails struct {
+				Number string `json:"number"`
+			} `json:"card_details"`
+		}
+
+		if err := json.Unmarshal(body, &payload); err != nil || utils.IsEmptyOrNull(payload.CardDetails.Number) {
+			return "", false
+		}
+
+		return fmt.Sprintf("card_%s", utils.Fingerprint(secret, payload.CardDetails.Number)), true
+	}
+}
+
+// MaxCostBodyBytes is the maximum size of the request body read to count its items.
+const MaxCostBodyBytes = 4 << 20
+
+// ByItemCount returns a cost function that weighs the request by the number of items of an array field of the JSON
+// payload, so a batch request consumes as much of the limit as the single requests it replaces. Requests without
+// items cost one unit. At most MaxCostBodyBytes of the body are read, and the body is restored after being read so
+// it can be bound by the handler.
+//
+// Parameters:
+//   - field: the name of the array field in the JSON payload, such as "items".
+//
+// Returns:
+//   - CostFunc: the cost function.
+func ByItemCount(field string) CostFunc {
+	return func(ctx *gin.Context) int64 {
+		if ctx.Request.Body == nil {
+			return 1
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, MaxCostBodyBytes))
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return 1
+		}
+
+		var payload map[string]json.RawMessage
+		var items []json.RawMessage
+		if json.Unmarshal(body, &payload) != nil || json.Unmarshal(payload[field], &items) != nil || len(items) == 0 {
+			return 1
+		}
+
+		return int64(len(items))
+	}
+}
+
+// FirstOf combines key functions, identifying the request by the first one that
+// returns a key.
+func FirstOf(keyFuncs ...KeyFunc) KeyFunc {
+	return func(ctx *gin.Context) (string, bool) {
+		for _, keyFunc := range keyFuncs {
+			if key, ok := keyFunc(ctx); ok {
+				return key, true
+			}
+		}
+
+		return "", false
+	}
+}
+
+func windowKey(name, key string, window int64) string {
+	return fmt.Sprintf("%s_%s_%s_%d", cache.RateLimitKey, name, key, window)
+}
+
+func previousCount(cacheClient cache.CacheClient, key string) int64 {
+	c, err := cacheClient.Get(key)
+	if err != nil {
+		return 0
+	}
+
+	count, err := strconv.ParseInt(string(c), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return count
+}
@@ -0,0 +1,254 @@
+package middleware_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/middleware"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type failingCache struct {
+	cache.CacheClient
+}
+
+func (c failingCache) IncrementBy(key string, increment int64, expiration time.Duration) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func windowKey(key string, window time.Duration, offset int64) string {
+	return fmt.Sprintf("%s_test_%s_%d", cache.RateLimitKey, key, time.Now().UnixNano()/int64(window)+offset)
+}
+
+func setupRouter(cacheClient cache.CacheClient, rateLimit middleware.RateLimit) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/", middleware.RateLimiter(cacheClient, zap.NewNop(), rateLimit), func(ctx *gin.Context) {
+		body, _ := io.ReadAll(ctx.Request.Body)
+		ctx.String(http.StatusOK, string(body))
+	})
+
+	return router
+}
+
+func TestRateLimiter_UnderLimit(t *testing.T) {
+	// Arrange
+	rateLimit := middleware.RateLimit{Name: "test", Limit: 10, Window: time.Minute, Key: middleware.ByIP}
+	router := setupRouter(cache.NewMemory(), rateLimit)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	// Action
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "10", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "9", w.Header().Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "10;w=60", w.Header().Get("RateLimit-Policy"))
+	assert.Empty(t, w.Header().Get("Retry-After"))
+}
+
+func TestRateLimiter_OverLimit(t *testing.T) {
+	// Arrange
+	rateLimit := middleware.RateLimit{Name: "test", Limit: 10, Window: time.Minute, Key: middleware.ByIP}
+	router := setupRouter(cache.NewMemory(), rateLimit)
+
+	for i := 0; i < 10; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	// Action
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"field":"rate_limit"`)
+}
+
+func TestRateLimiter_PreviousWindowCounts(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	rateLimit := middleware.RateLimit{Name: "test", Limit: 10, Window: time.Hour, Key: middleware.ByIP}
+	router := setupRouter(cacheClient, rateLimit)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	cacheClient.Set(windowKey("ip_192.0.2.1", time.Hour, -1), "1000", 2*time.Hour)
+
+	// Action
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestRateLimiter_CacheUnavailable(t *testing.T) {
+	// Arrange
+	rateLimit := middleware.RateLimit{Name: "test", Limit: 10, Window: time.Minute, Key: middleware.ByIP}
+	router := setupRouter(failingCache{cache.NewMemory()}, rateLimit)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	// Action
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimiter_WithoutKey(t *testing.T) {
+	// Arrange
+	rateLimit := middleware.RateLimit{Name: "test", Limit: 10, Window: time.Minute, Key: middleware.ByAPIKey}
+	router := setupRouter(cache.NewMemory(), rateLimit)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	// Action
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestByCardFingerprint(t *testing.T) {
+	// Arrange
+	payload := `{"card_details":{"number":"4242424242424242"}}`
+	cacheClient := cache.NewMemory()
+	rateLimit := middleware.RateLimit{Name: "test", Limit: 10, Window: time.Minute, Key: middleware.ByCardFingerprint([]byte("test-secret"))}
+	router := setupRouter(cacheClient, rateLimit)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+
+	// Action
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, payload, w.Body.String())
+
+	count, err := cacheClient.Get(windowKey("card_"+utils.Fingerprint([]byte("test-secret"), "4242424242424242"), time.Minute, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, "1", string(count))
+}
+
+func TestByCardFingerprint_BodyTooLarge(t *testing.T) {
+	// Arrange
+	payload := `{"card_details":{"number":"4242424242424242"},"padding":"` + strings.Repeat("a", middleware.MaxFingerprintBodyBytes) + `"}`
+	rateLimit := middleware.RateLimit{Name: "test", Limit: 10, Window: time.Minute, Key: middleware.ByCardFingerprint([]byte("test-secret"))}
+	router := setupRouter(cache.NewMemory(), rateLimit)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+
+	// Action
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, w.Body.String(), middleware.MaxFingerprintBodyBytes)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestByItemCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		payload   string
+		remaining string
+		expected  int
+	}{
+		{"Weighted by items", `{"items":[{},{},{}]}`, "7", http.StatusOK},
+		{"Without items", `{}`, "9", http.StatusOK},
+		{"Invalid payload", `not json`, "9", http.StatusOK},
+		{"Over limit", `{"items":[{},{},{},{},{},{},{},{},{},{},{}]}`, "0", http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			rateLimit := middleware.RateLimit{Name: "test", Limit: 10, Window: time.Minute, Key: middleware.ByIP, Cost: middleware.ByItemCount("items")}
+			router := setupRouter(cache.NewMemory(), rateLimit)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.payload))
+
+			// Action
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.expected, w.Code)
+			assert.Equal(t, tt.remaining, w.Header().Get("RateLimit-Remaining"))
+			if tt.expected == http.StatusOK {
+				assert.Equal(t, tt.payload, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestFirstOf(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/", nil)
+	ctx.Request.Header.Set("x-mgc-apiKey", utils.GenerateGUID())
+	ctx.Request.RemoteAddr = "10.0.0.1:1234"
+
+	// Action
+	withApiKey, _ := middleware.FirstOf(middleware.ByAPIKey, middleware.ByIP)(ctx)
+	ctx.Request.Header.Del("x-mgc-apiKey")
+	withoutApiKey, _ := middleware.FirstOf(middleware.ByAPIKey, middleware.ByIP)(ctx)
+
+	// Assert
+	assert.True(t, strings.HasPrefix(withApiKey, "key_"))
+	assert.Equal(t, "ip_10.0.0.1", withoutApiKey)
+}
+
+func TestLoadRateLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		envValue       string
+		expectedLimit  int64
+		expectedWindow time.Duration
+	}{
+		{"Default when not set", "", 10, time.Minute},
+		{"Override from environment", "100/1h", 100, time.Hour},
+		{"Default when invalid format", "100", 10, time.Minute},
+		{"Default when invalid limit", "abc/1m", 10, time.Minute},
+		{"Default when invalid window", "100/abc", 10, time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("RATE_LIMIT_TEST", tt.envValue)
+			defer os.Unsetenv("RATE_LIMIT_TEST")
+
+			rateLimit := middleware.LoadRateLimit(middleware.RateLimit{Name: "test", Limit: 10, Window: time.Minute})
+
+			assert.Equal(t, tt.expectedLimit, rateLimit.Limit)
+			assert.Equal(t, tt.expectedWindow, rateLimit.Window)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package money
+
+import "github.com/go-playground/locales/currency"
+
+// currencies maps the ISO 4217 codes to the currencies of the locales package. Currencies introduced after
+// the locales package was released are missing, and are formatted with the registry symbol.
+var currencies = map[string]currency.Type{
+	"AED": currency.AED,
+	"AFN": currency.AFN,
+	"ALL": currency.ALL,
+	"AMD": currency.AMD,
+	"AOA": currency.AOA,
+	"ARS": currency.ARS,
+	"AUD": currency.AUD,
+	"AWG": currency.AWG,
+	"AZN": currency.AZN,
+	"BAM": currency.BAM,
+	"BBD": currency.BBD,
+	"BDT": currency.BDT,
+	"BHD": currency.BHD,
+	"BIF": currency.BIF,
+	"BMD": currency.BMD,
+	"BND": currency.BND,
+	"BOB": currency.BOB,
+	"BRL": currency.BRL,
+	"BSD": currency.BSD,
+	"BTN": currency.BTN,
+	"BWP": currency.BWP,
+	"BYN": currency.BYN,
+	"BZD": currency.BZD,
+	"CAD": currency.CAD,
+	"CDF": currency.CDF,
+	"CHF": currency.CHF,
+	"CLP": currency.CLP,
+	"CNY": currency.CNY,
+	"COP": currency.COP,
+	"CRC": currency.CRC,
+	"CUP": currency.CUP,
+	"CVE": currency.CVE,
+	"CZK": currency.CZK,
+	"DJF": currency.DJF,
+	"DKK": currency.DKK,
+	"DOP": currency.DOP,
+	"DZD": currency.DZD,
+	"EGP": currency.EGP,
+	"ERN": currency.ERN,
+	"ETB": currency.ETB,
+	"EUR": currency.EUR,
+	"FJD": currency.FJD,
+	"FKP": currency.FKP,
+	"GBP": currency.GBP,
+	"GEL": currency.GEL,
+	"GHS": currency.GHS,
+	"GIP": currency.GIP,
+	"GMD": currency.GMD,
+	"GNF": currency.GNF,
+	"GTQ": currency.GTQ,
+	"GYD": currency.GYD,
+	"HKD": currency.HKD,
+	"HNL": currency.HNL,
+	"HTG": currency.HTG,
+	"HUF": currency.HUF,
+	"IDR": currency.IDR,
+	"ILS": currency.ILS,
+	"INR": currency.INR,
+	"IQD": currency.IQD,
+	"IRR": currency.IRR,
+	"ISK": currency.ISK,
+	"JMD": currency.JMD,
+	"JOD": currency.JOD,
+	"JPY": currency.JPY,
+	"KES": currency.KES,
+	"KGS": currency.KGS,
+	"KHR": currency.KHR,
+	"KMF": currency.KMF,
+	"KPW": currency.KPW,
+	"KRW": currency.KRW,
+	"KWD": currency.KWD,
+	"KYD": currency.KYD,
+	"KZT": currency.KZT,
+	"LAK": currency.LAK,
+	"LBP": currency.LBP,
+	"LKR": currency.LKR,
+	"LRD": currency.LRD,
+	"LSL": currency.LSL,
+	"LYD": currency.LYD,
+	"MAD": currency.MAD,
+	"MDL": currency.MDL,
+	"MGA": currency.MGA,
+	"MKD": currency.MKD,
+	"MMK": currency.MMK,
+	"MNT": currency.MNT,
+	"MOP": currency.MOP,
+	"MRU": currency.MRU,
+	"MUR": currency.MUR,
+	"MVR": currency.MVR,
+	"MWK": currency.MWK,
+	"MXN": currency.MXN,
+	"MYR": currency.MYR,
+	"MZN": currency.MZN,
+	"NAD": currency.NAD,
+	"NGN": currency.NGN,
+	"NIO": currency.NIO,
+	"NOK": currency.NOK,
+	"NPR": currency.NPR,
+	"NZD": currency.NZD,
+	"OMR": currency.OMR,
+	"PAB": currency.PAB,
+	"PEN": currency.PEN,
+	"PGK": currency.PGK,
+	"PHP": currency.PHP,
+	"PKR": currency.PKR,
+	"PLN": currency.PLN,
+	"PYG": currency.PYG,
+	"QAR": currency.QAR,
+	"RON": currency.RON,
+	"RSD": currency.RSD,
+	"RUB": currency.RUB,
+	"RWF": currency.RWF,
+	"SAR": currency.SAR,
+	"SBD": currency.SBD,
+	"SCR": currency.SCR,
+	"SDG": currency.SDG,
+	"SEK": currency.SEK,
+	"SGD": currency.SGD,
+	"SHP": currency.SHP,
+	"SOS": currency.SOS,
+	"SRD": currency.SRD,
+	"SSP": currency.SSP,
+	"STN": currency.STN,
+	"SVC": currency.SVC,
+	"SYP": currency.SYP,
+	"SZL": currency.SZL,
+	"THB": currency.THB,
+	"TJS": currency.TJS,
+	"TMT": currency.TMT,
+	"TND": currency.TND,
+	"TOP": currency.TOP,
+	"TRY": currency.TRY,
+	"TTD": currency.TTD,
+	"TWD": currency.TWD,
+	"TZS": currency.TZS,
+	"UAH": currency.UAH,
+	"UGX": currency.UGX,
+	"USD": currency.USD,
+	"UYU": currency.UYU,
+	"UZS": currency.UZS,
+	"VES": currency.VES,
+	"VND": currency.VND,
+	"VUV": currency.VUV,
+	"WST": currency.WST,
+	"XAF": currency.XAF,
+	"XCD": currency.XCD,
+	"XOF": currency.XOF,
+	"XPF": currency.XPF,
+	"YER": currency.YER,
+	"ZAR": currency.ZAR,
+	"ZMW": currency.ZMW,
+}
@@ -0,0 +1,107 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/iso4217"
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/currency"
+)
+
+var (
+	ErrUnsupportedLocale = errors.New("unsupported locale")
+	ErrUnknownCurrency   = errors.New("unknown currency")
+)
+
+// Format formats an amount of a currency for a locale, such as "R$ 1.234,56" for BRL in pt_BR and
+// "$1,234.56" for USD in en_US. The amount is rounded to the minor units of the currency, and the grouping,
+// the decimal separator, the minus sign and the placement of the symbol follow the locale.
+//
+// The symbol is the one the locale uses for the currency, or the ISO 4217 symbol when the locale only
+// knows the currency by its code.
+//
+// Parameters:
+//   - amount: the amount to format.
+//   - code: the ISO 4217 code of the currency.
+//   - locale: the identifier of the locale, such as "pt_BR" or "pt-BR".
+//
+// Returns:
+//   - string: the formatted amount.
+//   - error: ErrUnsupportedLocale or ErrUnknownCurrency if the locale or the currency is not supported.
+func Format(amount float64, code string, locale string) (string, error) {
+	translator, ok := translators[strings.ReplaceAll(locale, "-", "_")]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedLocale, locale)
+	}
+
+	metadata, ok := iso4217.Get(code)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownCurrency, code)
+	}
+
+	minorUnits := uint64(metadata.MinorUnits)
+	prefix, suffix := affixes(translator, code, metadata.Symbol)
+	formatted := prefix + translator.FmtNumber(math.Abs(amount), minorUnits) + suffix
+
+	// Amounts that round to zero are formatted without sign.
+	if amount < 0 && math.Round(-amount*math.Pow10(int(minorUnits))) > 0 {
+		formatted = strings.TrimSuffix(translator.FmtNumber(-1, 0), "1") + formatted
+	}
+
+	return formatted, nil
+}
+
+// Locales returns the identifiers of the supported locales, sorted.
+func Locales() []string {
+	identifiers := make([]string, 0, len(translators))
+	for identifier := range translators {
+		identifiers = append(identifiers, identifier)
+	}
+
+	sort.Strings(identifiers)
+	return identifiers
+}
+
+// affixes returns the text placed before and after the number of a currency amount in the locale.
+// They are read from the currency format of the locale, which holds the placement of the symbol and
+// its spacing, while the symbol itself falls back to the registry one when the locale has no symbol.
+func affixes(translator locales.Translator, code string, symbol string) (string, string) {
+	kind, known := currencies[code]
+	if !known {
+		// Any currency tells where the locale places the symbol.
+		kind = currency.USD
+	}
+
+	placeholder := translator.FmtNumber(1, 2)
+	pattern := translator.FmtCurrency(1, 2, kind)
+	index := strings.Index(pattern, placeholder)
+	if index < 0 {
+		return symbol, ""
+	}
+
+	before, after := pattern[:index], pattern[index+len(placeholder):]
+	if localeSymbol := strings.TrimSpace(before + after); known && localeSymbol != code {
+		symbol = localeSymbol
+	}
+
+	if strings.TrimSpace(before) != "" {
+		return symbol + spacing(before), ""
+	}
+	return "", spacing(after) + symbol
+}
+
+// spacing returns the spaces of an affix, such as the no-break space between a symbol and a number.
+func spacing(affix string) string {
+	var b strings.Builder
+	for _, r := range affix {
+		if unicode.IsSpace(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		locale   string
+		expected string
+	}{
+		{"Brazilian real in Brazil", 1234.56, "BRL", "pt_BR", "R$\u00a01.234,56"},
+		{"US dollar in the United States", 1234.56, "USD", "en_US", "$1,234.56"},
+		{"US dollar in Portuguese", 1234.56, "USD", "pt", "US$\u00a01.234,56"},
+		{"Euro in Germany", 1234.5, "EUR", "de_DE", "1.234,50\u00a0€"},
+		{"Euro in France", 1234567.891, "EUR", "fr-FR", "1\u202f234\u202f567,89\u00a0€"},
+		{"Yen has no minor units", 1234.5, "JPY", "ja", "￥1,234"},
+		{"Dinar has three minor units and the registry symbol", 1234.5678, "KWD", "en", "د.ك1,234.568"},
+		{"Negative amount", -1234.56, "BRL", "pt_BR", "-R$\u00a01.234,56"},
+		{"Negative amount rounding to zero", -0.001, "USD", "en_US", "$0.00"},
+		{"Currency unknown to the locales package", 10, "XCG", "en", "Cg10.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Action
+			formatted, err := Format(tt.amount, tt.currency, tt.locale)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, formatted)
+		})
+	}
+}
+
+func TestFormat_Failure(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		locale   string
+		expected error
+	}{
+		{"Unsupported locale", "BRL", "xx_XX", ErrUnsupportedLocale},
+		{"Unknown currency", "XYZ", "pt_BR", ErrUnknownCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Action
+			formatted, err := Format(1, tt.currency, tt.locale)
+
+			// Assert
+			assert.ErrorIs(t, err, tt.expected)
+			assert.Empty(t, formatted)
+		})
+	}
+}
+
+func TestLocales(t *testing.T) {
+	// Action
+	identifiers := Locales()
+
+	// Assert
+	assert.Contains(t, identifiers, "pt_BR")
+	assert.Contains(t, identifiers, "en_US")
+	assert.IsNonDecreasing(t, identifiers)
+}
@@ -0,0 +1,63 @@
+package money
+
+import (
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/de_CH"
+	"github.com/go-playground/locales/de_DE"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/en_AU"
+	"github.com/go-playground/locales/en_CA"
+	"github.com/go-playground/locales/en_GB"
+	"github.com/go-playground/locales/en_US"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/es_AR"
+	"github.com/go-playground/locales/es_CL"
+	"github.com/go-playground/locales/es_CO"
+	"github.com/go-playground/locales/es_ES"
+	"github.com/go-playground/locales/es_MX"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/fr_CA"
+	"github.com/go-playground/locales/fr_FR"
+	"github.com/go-playground/locales/it"
+	"github.com/go-playground/locales/it_IT"
+	"github.com/go-playground/locales/ja"
+	"github.com/go-playground/locales/ja_JP"
+	"github.com/go-playground/locales/nl"
+	"github.com/go-playground/locales/nl_NL"
+	"github.com/go-playground/locales/pt"
+	"github.com/go-playground/locales/pt_BR"
+	"github.com/go-playground/locales/pt_PT"
+	"github.com/go-playground/locales/zh"
+)
+
+// translators are the supported locales, by their identifier.
+var translators = map[string]locales.Translator{
+	"en":    en.New(),
+	"en_US": en_US.New(),
+	"en_GB": en_GB.New(),
+	"en_CA": en_CA.New(),
+	"en_AU": en_AU.New(),
+	"pt":    pt.New(),
+	"pt_BR": pt_BR.New(),
+	"pt_PT": pt_PT.New(),
+	"es":    es.New(),
+	"es_ES": es_ES.New(),
+	"es_MX": es_MX.New(),
+	"es_AR": es_AR.New(),
+	"es_CO": es_CO.New(),
+	"es_CL": es_CL.New(),
+	"fr":    fr.New(),
+	"fr_FR": fr_FR.New(),
+	"fr_CA": fr_CA.New(),
+	"de":    de.New(),
+	"de_DE": de_DE.New(),
+	"de_CH": de_CH.New(),
+	"it":    it.New(),
+	"it_IT": it_IT.New(),
+	"ja":    ja.New(),
+	"ja_JP": ja_JP.New(),
+	"zh":    zh.New(),
+	"nl":    nl.New(),
+	"nl_NL": nl_NL.New(),
+}
@@ -0,0 +1,131 @@
+package money
+
+import (
+	"math/big"
+	"strconv"
+)
+
+type RoundingMode string
+
+const (
+	// HalfEven rounds to the nearest value, and ties to the even neighbor, as in 2.5 to 2 and 3.5 to 4.
+	HalfEven RoundingMode = "half_even"
+	// HalfUp rounds to the nearest value, and ties away from zero, as in 2.5 to 3 and -2.5 to -3.
+	HalfUp RoundingMode = "half_up"
+	// Floor rounds towards negative infinity.
+	Floor RoundingMode = "floor"
+	// Ceiling rounds towards positive infinity.
+	Ceiling RoundingMode = "ceiling"
+)
+
+// powersOfTen caches the scales of the decimal places currencies have, from 0 to 3, and rates commonly have.
+var powersOfTen = func() []*big.Int {
+	powers := make([]*big.Int, 19)
+	for i := range powers {
+		powers[i] = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(i)), nil)
+	}
+	return powers
+}()
+
+// Rat converts a float64 to the exact decimal it is written as, so 0.85 is 85/100 rather than the
+// binary approximation of 0.85 that float64 holds.
+//
+// Parameters:
+//   - value: the value to convert, which must be finite.
+//
+// Returns:
+//   - *big.Rat: the decimal value.
+func Rat(value float64) *big.Rat {
+	rat, _ := new(big.Rat).SetString(strconv.FormatFloat(value, 'g', -1, 64))
+	return rat
+}
+
+// Round rounds a value to a number of decimal places, such as the minor units of a currency.
+//
+// Parameters:
+//   - value: the value to round.
+//   - places: the number of decimal places.
+//   - mode: how the value is rounded, defaulting to HalfEven when empty.
+//
+// Returns:
+//   - *big.Rat: the rounded value.
+func Round(value *big.Rat, places int, mode RoundingMode) *big.Rat {
+	var scale *big.Int
+	if places < len(powersOfTen) {
+		scale = powersOfTen[places]
+	} else {
+		scale = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+	}
+	scaled := new(big.Int).Mul(value.Num(), scale)
+
+	// The denominator is positive, so the quotient is the floor of the scaled value and the remainder
+	// its non-negative fractional part.
+	quotient, remainder := new(big.Int).DivMod(scaled, value.Denom(), new(big.Int))
+
+	if remainder.Sign() != 0 && roundsUp(quotient, remainder, value, mode) {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+
+	return new(big.Rat).SetFrac(quotient, scale)
+}
+
+// roundsUp reports whether a value with a non-zero fractional part is rounded to the ceiling of its scaled value.
+func roundsUp(floor *big.Int, remainder *big.Int, value *big.Rat, mode RoundingMode) bool {
+	switch mode {
+	case Floor:
+		return false
+	case Ceiling:
+		return true
+	}
+
+	half := new(big.Int).Lsh(remainder, 1).Cmp(value.Denom())
+	switch {
+	case half > 0:
+		return true
+	case half < 0:
+		return false
+	case mode == HalfUp:
+		return value.Sign() > 0
+	default:
+		return floor.Bit(0) == 1
+	}
+}
+
+// DecimalString returns the exact representation of a value: a decimal when its expansion terminates,
+// such as "5.5", or a fraction otherwise, such as "30264/5567".
+//
+// Parameters:
+//   - value: the value to represent.
+//
+// Returns:
+//   - string: the exact representation of the value.
+func DecimalString(value *big.Rat) string {
+	if value.IsInt() {
+		return value.Num().String()
+	}
+
+	// The expansion terminates when the denominator only has the factors 2 and 5, and it has as many
+	// decimal places as the largest power of them.
+	denominator := new(big.Int).Set(value.Denom())
+	places := 0
+	for _, factor := range []int64{2, 5} {
+		count := 0
+		divisor := big.NewInt(factor)
+		remainder := new(big.Int)
+		for {
+			quotient, mod := new(big.Int).QuoRem(denominator, divisor, remainder)
+			if mod.Sign() != 0 {
+				break
+			}
+			denominator = quotient
+			count++
+		}
+		places = max(places, count)
+	}
+
+	if denominator.Cmp(big.NewInt(1)) != 0 {
+		return value.RatString()
+	}
+
+	return value.FloatString(places)
+}
@@ -0,0 +1,79 @@
+package money
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRat(t *testing.T) {
+	// Action
+	rat := Rat(0.85)
+
+	// Assert
+	assert.Equal(t, "17/20", rat.String())
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		value    string
+		places   int
+		mode     RoundingMode
+		expected string
+	}{
+		{"2.345", 2, HalfEven, "2.34"},
+		{"2.355", 2, HalfEven, "2.36"},
+		{"2.3451", 2, HalfEven, "2.35"},
+		{"-2.345", 2, HalfEven, "-2.34"},
+		{"2.345", 2, "", "2.34"},
+		{"2.345", 2, HalfUp, "2.35"},
+		{"-2.345", 2, HalfUp, "-2.35"},
+		{"2.3449", 2, HalfUp, "2.34"},
+		{"2.349", 2, Floor, "2.34"},
+		{"-2.341", 2, Floor, "-2.35"},
+		{"2.341", 2, Ceiling, "2.35"},
+		{"-2.349", 2, Ceiling, "-2.34"},
+		{"2.34", 2, Ceiling, "2.34"},
+		{"1234.5", 0, HalfEven, "1234"},
+		{"1234.5678", 3, HalfUp, "1234.568"},
+		{"1/3", 2, HalfUp, "0.33"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode)+" "+tt.value, func(t *testing.T) {
+			// Arrange
+			value, _ := new(big.Rat).SetString(tt.value)
+			expected, _ := new(big.Rat).SetString(tt.expected)
+
+			// Action
+			rounded := Round(value, tt.places, tt.mode)
+
+			// Assert
+			assert.Equal(t, expected.String(), rounded.String())
+		})
+	}
+}
+
+func TestDecimalString(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"5", "5"},
+		{"11/2", "5.5"},
+		{"17/20", "0.85"},
+		{"-1/8", "-0.125"},
+		{"60528/11134", "30264/5567"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// Arrange
+			value, _ := new(big.Rat).SetString(tt.value)
+
+			// Action & Assert
+			assert.Equal(t, tt.expected, DecimalString(value))
+		})
+	}
+}
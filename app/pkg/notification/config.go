@@ -0,0 +1,63 @@
+package notification
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	MaxAttempts          int
+	BaseBackoff          time.Duration
+	Timeout              time.Duration
+	DisableAfter         int
+	MaxDeliveries        int
+	AllowPrivateNetworks bool
+	Consumer             string
+	BatchSize            int64
+	Block                time.Duration
+	ReclaimIdle          time.Duration
+	ReclaimInterval      time.Duration
+}
+
+// LoadConfig loads the merchant notification configuration from the environment variables.
+//
+// Environment Variables:
+//   - NOTIFICATION_MAX_ATTEMPTS: the maximum delivery attempts of an event.
+//   - NOTIFICATION_BASE_BACKOFF: the wait before the first retry, doubled on each retry, e.g. "1s".
+//   - NOTIFICATION_TIMEOUT: the timeout of each delivery attempt, e.g. "10s".
+//   - NOTIFICATION_DISABLE_AFTER: the consecutive failed deliveries after which an endpoint is disabled.
+//   - NOTIFICATION_MAX_DELIVERIES: the number of delivery logs kept per endpoint.
+//   - NOTIFICATION_ALLOW_PRIVATE_NETWORKS: "true" to allow endpoints on loopback and private addresses,
+//     for development only.
+//   - NOTIFICATION_CONSUMER: the name of the process in the delivery consumer group, defaulting to the host name
+//     and the process ID.
+//   - NOTIFICATION_BATCH_SIZE: the maximum number of deliveries read at once.
+//   - NOTIFICATION_BLOCK: the maximum time to wait for new deliveries on each read, e.g. "1s".
+//   - NOTIFICATION_RECLAIM_IDLE: the time after which deliveries left unacknowledged by another process are
+//     reclaimed, e.g. "1m".
+//   - NOTIFICATION_RECLAIM_INTERVAL: the interval between checks for deliveries to reclaim, e.g. "30s".
+//
+// Returns:
+//   - Config: the merchant notification configuration.
+func LoadConfig() Config {
+	hostname, _ := os.Hostname()
+	allowPrivateNetworks, _ := strconv.ParseBool(os.Getenv("NOTIFICATION_ALLOW_PRIVATE_NETWORKS"))
+
+	return Config{
+		MaxAttempts:          utils.GetEnvInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+		BaseBackoff:          utils.GetEnvDuration("NOTIFICATION_BASE_BACKOFF", time.Second),
+		Timeout:              utils.GetEnvDuration("NOTIFICATION_TIMEOUT", 10*time.Second),
+		DisableAfter:         utils.GetEnvInt("NOTIFICATION_DISABLE_AFTER", 10),
+		MaxDeliveries:        utils.GetEnvInt("NOTIFICATION_MAX_DELIVERIES", 100),
+		AllowPrivateNetworks: allowPrivateNetworks,
+		Consumer:             utils.GetEnvString("NOTIFICATION_CONSUMER", fmt.Sprintf("%s-%d", hostname, os.Getpid())),
+		BatchSize:            int64(utils.GetEnvInt("NOTIFICATION_BATCH_SIZE", 10)),
+		Block:                utils.GetEnvDuration("NOTIFICATION_BLOCK", time.Second),
+		ReclaimIdle:          utils.GetEnvDuration("NOTIFICATION_RECLAIM_IDLE", time.Minute),
+		ReclaimInterval:      utils.GetEnvDuration("NOTIFICATION_RECLAIM_INTERVAL", 30*time.Second),
+	}
+}
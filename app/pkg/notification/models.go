@@ -0,0 +1,41 @@
+package notification
+
+import "encoding/json"
+
+type Endpoint struct {
+	Id                  string   `json:"id"`
+	MerchantId          string   `json:"merchant_id"`
+	Url                 string   `json:"url" binding:"required,url"`
+	EventTypes          []string `json:"event_types" binding:"required,min=1"`
+	Secret              string   `json:"secret,omitempty"`
+	Enabled             bool     `json:"enabled"`
+	ConsecutiveFailures int      `json:"consecutive_failures"`
+	CreatedAt           string   `json:"created_at"`
+	DisabledAt          string   `json:"disabled_at,omitempty"`
+}
+
+type Event struct {
+	Id        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt string          `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+type Delivery struct {
+	Id          string `json:"id"`
+	EndpointId  string `json:"endpoint_id"`
+	Event       Event  `json:"event"`
+	Attempt     int    `json:"attempt"`
+	Success     bool   `json:"success"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	Error       string `json:"error,omitempty"`
+	DurationMs  int64  `json:"duration_ms"`
+	DeliveredAt string `json:"delivered_at"`
+}
+
+type DeliveryJob struct {
+	MerchantId string `json:"merchant_id"`
+	EndpointId string `json:"endpoint_id"`
+	Event      Event  `json:"event"`
+	Attempt    int    `json:"attempt"`
+}
@@ -0,0 +1,112 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidEndpointUrl = errors.New("invalid endpoint url")
+	ErrForbiddenAddress   = errors.New("endpoint address is not public")
+)
+
+// reservedPrefixes are the ranges not routable on the internet that are not covered by the netip.Addr checks,
+// such as the carrier-grade NAT range and the documentation ranges.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// validateUrl checks that an endpoint url is an absolute http or https url whose host resolves only to public
+// addresses, so merchants cannot make the api call its own network, such as the cloud metadata service.
+// The host is only checked to be present when private networks are allowed.
+func (p *notificationService) validateUrl(rawUrl string) error {
+	endpointUrl, err := url.Parse(rawUrl)
+	if err != nil || (endpointUrl.Scheme != "http" && endpointUrl.Scheme != "https") || endpointUrl.Hostname() == "" {
+		return fmt.Errorf("%w: an absolute http or https url is required", ErrInvalidEndpointUrl)
+	}
+
+	if p.config.AllowPrivateNetworks {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.config.Timeout)
+	defer cancel()
+
+	addrs, err := p.resolver.LookupNetIP(ctx, "ip", endpointUrl.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w: %s cannot be resolved", ErrInvalidEndpointUrl, endpointUrl.Hostname())
+	}
+
+	for _, addr := range addrs {
+		if !isPublicAddress(addr) {
+			return fmt.Errorf("%w: %s resolves to a non-public address", ErrInvalidEndpointUrl, endpointUrl.Hostname())
+		}
+	}
+
+	return nil
+}
+
+// newClient creates the http client of the deliveries. Unless private networks are allowed, its connections are
+// checked when they are made, after the host is resolved, so a host resolving to a public address when the
+// endpoint was registered cannot later point the deliveries to a private one. Redirects are not followed.
+func newClient(config Config) *http.Client {
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	if !config.AllowPrivateNetworks {
+		dialer.Control = func(network string, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublicAddress(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: config.Timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: config.Timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// isPublicAddress reports whether an address is routable on the internet, excluding the loopback, private,
+// link-local, multicast, unspecified and reserved addresses.
+func isPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,618 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/queue"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	allEvents = "*"
+
+	// failuresExpiration bounds how long the consecutive failures of an endpoint are counted without a success.
+	failuresExpiration = 30 * 24 * time.Hour
+)
+
+var (
+	ErrEndpointNotFound = errors.New("endpoint not found")
+	ErrDeliveryNotFound = errors.New("delivery not found")
+)
+
+type Publisher interface {
+	Publish(merchantId string, eventType string, data interface{})
+}
+
+type NotificationService interface {
+	Publisher
+	CreateEndpoint(merchantId string, endpoint Endpoint) (*Endpoint, error)
+	GetAllEndpoints(merchantId string) ([]Endpoint, error)
+	DeleteEndpoint(merchantId string, id string) error
+	EnableEndpoint(merchantId string, id string) (*Endpoint, error)
+	GetAllDeliveries(merchantId string, endpointId string) ([]Delivery, error)
+	Redeliver(merchantId string, endpointId string, deliveryId string) (*Delivery, error)
+	Run(ctx context.Context)
+}
+
+type notificationService struct {
+	cache    cache.CacheClient
+	queue    queue.QueueClient
+	logger   *zap.Logger
+	config   Config
+	client   *http.Client
+	resolver *net.Resolver
+}
+
+// New creates a new instance of notificationService with the provided cache client, queue client, logger and configuration.
+// It returns a pointer to the newly created notificationService.
+//
+// Parameters:
+//   - cache: an instance of cache.CacheClient used to store endpoints and delivery logs.
+//   - queue: an instance of queue.QueueClient the deliveries and their retries are enqueued to.
+//   - logger: an instance of zap.Logger used for logging.
+//   - config: the merchant notification configuration.
+//
+// Returns:
+//   - *notificationService: a pointer to the initialized notificationService.
+func New(cache cache.CacheClient, queue queue.QueueClient, logger *zap.Logger, config Config) *notificationService {
+	return &notificationService{
+		cache:    cache,
+		queue:    queue,
+		logger:   logger,
+		config:   config,
+		client:   newClient(config),
+		resolver: net.DefaultResolver,
+	}
+}
+
+// CreateEndpoint registers a callback endpoint of a merchant for the given event types.
+// A signing secret is generated for the endpoint and returned only by this method.
+//
+// Parameters:
+//   - merchantId: the merchant the endpoint belongs to.
+//   - endpoint: the endpoint URL and the event types it subscribes to, "*" subscribes to all events.
+//
+// Returns:
+//   - *Endpoint: the registered endpoint, including its signing secret.
+//   - error: ErrInvalidEndpointUrl if the URL is not http or https or resolves to a non-public address,
+//     or an error if the endpoint could not be stored.
+func (p *notificationService) CreateEndpoint(merchantId string, endpoint Endpoint) (*Endpoint, error) {
+	if err := p.validateUrl(endpoint.Url); err != nil {
+		return nil, err
+	}
+
+	endpoint.Id = utils.GenerateGUID()
+	endpoint.MerchantId = merchantId
+	endpoint.Secret = fmt.Sprintf("whsec_%s", strings.ReplaceAll(utils.GenerateGUID(), "-", ""))
+	endpoint.Enabled = true
+	endpoint.ConsecutiveFailures = 0
+	endpoint.CreatedAt = time.Now().Format(time.RFC3339)
+	endpoint.DisabledAt = ""
+
+	if err := p.setEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+
+	return &endpoint, nil
+}
+
+// GetAllEndpoints retrieves the endpoints of a merchant, sorted by creation date.
+// The signing secrets are not returned.
+//
+// Parameters:
+//   - merchantId: the merchant the endpoints belong to.
+//
+// Returns:
+//   - []Endpoint: the registered endpoints.
+//   - error: an error if the endpoints could not be retrieved.
+func (p *notificationService) GetAllEndpoints(merchantId string) ([]Endpoint, error) {
+	endpoints, err := p.getEndpoints(merchantId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range endpoints {
+		endpoints[i].Secret = ""
+		endpoints[i].ConsecutiveFailures = p.getFailures(endpoints[i].Id)
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].CreatedAt < endpoints[j].CreatedAt
+	})
+
+	return endpoints, nil
+}
+
+// DeleteEndpoint removes an endpoint of a merchant and its delivery logs.
+// Deliveries already enqueued to the endpoint are discarded.
+//
+// Parameters:
+//   - merchantId: the merchant the endpoint belongs to.
+//   - id: the endpoint id.
+//
+// Returns:
+//   - error: ErrEndpointNotFound if the merchant has no such endpoint, or an error if it could not be removed.
+func (p *notificationService) DeleteEndpoint(merchantId string, id string) error {
+	removed, err := p.cache.HDelete(endpointsKey(merchantId), id)
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		return ErrEndpointNotFound
+	}
+
+	if _, err := p.cache.Delete(deliveriesKey(id)); err != nil {
+		return err
+	}
+
+	_, err = p.cache.Delete(failuresKey(id))
+	return err
+}
+
+// EnableEndpoint enables an endpoint of a merchant that was disabled after repeated delivery failures
+// and resets its failure count.
+//
+// Parameters:
+//   - merchantId: the merchant the endpoint belongs to.
+//   - id: the endpoint id.
+//
+// Returns:
+//   - *Endpoint: the enabled endpoint.
+//   - error: ErrEndpointNotFound if the merchant has no such endpoint, or an error if it could not be stored.
+func (p *notificationService) EnableEndpoint(merchantId string, id string) (*Endpoint, error) {
+	endpoint, err := p.getEndpoint(merchantId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.Enabled = true
+	endpoint.DisabledAt = ""
+
+	if err := p.setEndpoint(*endpoint); err != nil {
+		return nil, err
+	}
+
+	if _, err := p.cache.Delete(failuresKey(id)); err != nil {
+		return nil, err
+	}
+
+	endpoint.Secret = ""
+	endpoint.ConsecutiveFailures = 0
+	return endpoint, nil
+}
+
+// GetAllDeliveries retrieves the delivery logs of an endpoint of a merchant, newest first.
+//
+// Parameters:
+//   - merchantId: the merchant the endpoint belongs to.
+//   - endpointId: the endpoint id.
+//
+// Returns:
+//   - []Delivery: the delivery logs of the endpoint.
+//   - error: ErrEndpointNotFound if the merchant has no such endpoint, or an error if the logs could not be retrieved.
+func (p *notificationService) GetAllDeliveries(merchantId string, endpointId string) ([]Delivery, error) {
+	if _, err := p.getEndpoint(merchantId, endpointId); err != nil {
+		return nil, err
+	}
+
+	return p.getDeliveries(endpointId)
+}
+
+// Redeliver sends the event of a previous delivery to an endpoint of a merchant again, once, even if
+// the endpoint is disabled. The attempt is recorded in the delivery logs.
+//
+// Parameters:
+//   - merchantId: the merchant the endpoint belongs to.
+//   - endpointId: the endpoint id.
+//   - deliveryId: the id of the delivery whose event is sent again.
+//
+// Returns:
+//   - *Delivery: the result of the new delivery.
+//   - error: ErrEndpointNotFound or ErrDeliveryNotFound if they do not exist, or an error if the logs could not be retrieved.
+func (p *notificationService) Redeliver(merchantId string, endpointId string, deliveryId string) (*Delivery, error) {
+	endpoint, err := p.getEndpoint(merchantId, endpointId)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := p.getDeliveries(endpointId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, delivery := range deliveries {
+		if delivery.Id == deliveryId {
+			result := p.send(*endpoint, delivery.Event, 1)
+			p.saveDelivery(result)
+			p.recordResult(*endpoint, result.Success)
+			return &result, nil
+		}
+	}
+
+	return nil, ErrDeliveryNotFound
+}
+
+// Publish enqueues the delivery of an event with the given type and data to every enabled endpoint of the
+// merchant subscribed to it. Deliveries are sent by Run and retried with exponential backoff; failures are
+// logged and never returned to the caller. Events without a merchant are not delivered.
+//
+// Parameters:
+//   - merchantId: the merchant the event belongs to.
+//   - eventType: the event type, such as "transaction.success".
+//   - data: the event data, serialized as JSON.
+func (p *notificationService) Publish(merchantId string, eventType string, data interface{}) {
+	if utils.IsEmptyOrNull(merchantId) {
+		return
+	}
+
+	endpoints, err := p.getEndpoints(merchantId)
+	if err != nil {
+		p.logger.Error("Failed to get notification endpoints", zap.String("merchant_id", merchantId), zap.String("event_type", eventType), zap.Error(err))
+		return
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		p.logger.Error("Failed to serialize notification event", zap.String("event_type", eventType), zap.Error(err))
+		return
+	}
+
+	event := Event{
+		Id:        utils.GenerateGUID(),
+		Type:      eventType,
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Data:      payload,
+	}
+
+	for _, endpoint := range endpoints {
+		if !endpoint.Enabled || !subscribed(endpoint, eventType) {
+			continue
+		}
+
+		job := DeliveryJob{MerchantId: merchantId, EndpointId: endpoint.Id, Event: event, Attempt: 1}
+		if _, err := p.queue.Enqueue(queue.NotificationsStream, utils.ToJSON(job)); err != nil {
+			p.logger.Error("Failed to enqueue notification delivery", zap.String("endpoint_id", endpoint.Id), zap.String("event_id", event.Id), zap.Error(err))
+		}
+	}
+}
+
+// Run sends the enqueued deliveries until the context is canceled. Retries wait for their backoff scheduled in
+// the queue, so they survive restarts, and deliveries left unacknowledged by stopped processes are periodically
+// reclaimed, so every delivery is sent at least once. The consumer group is created first, retrying while the
+// queue is unavailable.
+//
+// Parameters:
+//   - ctx: the context whose cancellation stops the deliveries.
+func (p *notificationService) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := p.queue.CreateGroup(queue.NotificationsStream, queue.NotificationsGroup)
+		if err == nil {
+			break
+		}
+
+		p.logger.Error("Failed to create notification consumer group", zap.Error(err))
+		p.wait(ctx)
+	}
+
+	lastReclaim := time.Time{}
+	for ctx.Err() == nil {
+		if _, err := p.queue.Promote(queue.NotificationsStream, time.Now(), p.config.BatchSize); err != nil {
+			p.logger.Error("Failed to promote notification retries", zap.Error(err))
+		}
+
+		if time.Since(lastReclaim) >= p.config.ReclaimInterval {
+			p.reclaim()
+			lastReclaim = time.Now()
+		}
+
+		messages, err := p.queue.Read(queue.NotificationsStream, queue.NotificationsGroup, p.config.Consumer, p.config.BatchSize, p.config.Block)
+		if err != nil {
+			p.logger.Error("Failed to read notification deliveries", zap.Error(err))
+			p.wait(ctx)
+			continue
+		}
+
+		for _, message := range messages {
+			p.handle(message)
+		}
+	}
+}
+
+// TransactionEventType returns the type of the event published when a transaction status is added,
+// such as "transaction.success".
+func TransactionEventType(status string) string {
+	return fmt.Sprintf("transaction.%s", status)
+}
+
+// Sign returns the signature of a notification, sent in the X-Mgc-Signature header.
+// The signature has the format "t=<timestamp>,v1=<signature>", where signature is the
+// hex-encoded HMAC-SHA256 of "<timestamp>.<body>" using the endpoint secret.
+//
+// Parameters:
+//   - secret: the endpoint signing secret.
+//   - timestamp: the Unix timestamp of the delivery.
+//   - body: the notification body.
+//
+// Returns:
+//   - string: the notification signature.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d.", timestamp)))
+	mac.Write(body)
+
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// handle makes a delivery attempt and acknowledges it once its outcome is recorded. A failed attempt schedules
+// the next one after an exponential backoff, until the maximum attempts are reached. Attempts whose retry cannot be
+// scheduled are left pending, to be reclaimed later.
+func (p *notificationService) handle(message queue.Message) {
+	var job DeliveryJob
+	if err := json.Unmarshal(message.Payload, &job); err != nil {
+		p.logger.Error("Discarding invalid notification delivery", zap.String("message_id", message.Id), zap.Error(err))
+		p.ack(message)
+		return
+	}
+
+	endpoint, err := p.getEndpoint(job.MerchantId, job.EndpointId)
+	if errors.Is(err, ErrEndpointNotFound) {
+		p.logger.Info("Discarding delivery to removed endpoint", zap.String("endpoint_id", job.EndpointId), zap.String("event_id", job.Event.Id))
+		p.ack(message)
+		return
+	}
+	if err != nil {
+		p.logger.Error("Failed to get notification endpoint", zap.String("endpoint_id", job.EndpointId), zap.Error(err))
+		return
+	}
+
+	if !endpoint.Enabled {
+		p.ack(message)
+		return
+	}
+
+	delivery := p.send(*endpoint, job.Event, job.Attempt)
+	p.saveDelivery(delivery)
+
+	if delivery.Success {
+		p.recordResult(*endpoint, true)
+		p.ack(message)
+		return
+	}
+
+	p.logger.Warn("Notification delivery failed", zap.String("endpoint_id", endpoint.Id), zap.String("event_id", job.Event.Id),
+		zap.Int("attempt", job.Attempt), zap.Int("status_code", delivery.StatusCode), zap.String("error", delivery.Error))
+
+	if job.Attempt < p.config.MaxAttempts {
+		backoff := p.config.BaseBackoff * time.Duration(1<<(job.Attempt-1))
+		job.Attempt++
+
+		if err := p.queue.Schedule(queue.NotificationsStream, utils.ToJSON(job), time.Now().Add(backoff)); err != nil {
+			p.logger.Error("Failed to schedule notification retry", zap.String("endpoint_id", endpoint.Id), zap.String("event_id", job.Event.Id), zap.Error(err))
+			return
+		}
+
+		p.ack(message)
+		return
+	}
+
+	p.recordResult(*endpoint, false)
+	p.ack(message)
+}
+
+// reclaim claims and sends the deliveries left unacknowledged by other processes for longer than the reclaim idle time.
+func (p *notificationService) reclaim() {
+	messages, err := p.queue.Reclaim(queue.NotificationsStream, queue.NotificationsGroup, p.config.Consumer, p.config.ReclaimIdle, p.config.BatchSize)
+	if err != nil {
+		p.logger.Error("Failed to reclaim notification deliveries", zap.Error(err))
+		return
+	}
+
+	for _, message := range messages {
+		p.logger.Warn("Reclaimed stuck notification delivery", zap.String("message_id", message.Id))
+		p.handle(message)
+	}
+}
+
+// wait pauses the deliveries after a queue failure, for the block duration or until the context is canceled.
+func (p *notificationService) wait(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(p.config.Block):
+	}
+}
+
+// ack acknowledges a handled delivery, logging failures.
+func (p *notificationService) ack(message queue.Message) {
+	if err := p.queue.Ack(queue.NotificationsStream, queue.NotificationsGroup, message.Id); err != nil {
+		p.logger.Error("Failed to acknowledge notification delivery", zap.String("message_id", message.Id), zap.Error(err))
+	}
+}
+
+// send makes a single signed delivery attempt of an event to an endpoint.
+func (p *notificationService) send(endpoint Endpoint, event Event, attempt int) Delivery {
+	delivery := Delivery{
+		Id:          utils.GenerateGUID(),
+		EndpointId:  endpoint.Id,
+		Event:       event,
+		Attempt:     attempt,
+		DeliveredAt: time.Now().Format(time.RFC3339),
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint.Url, bytes.NewReader(body))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Mgc-Event-Id", event.Id)
+	req.Header.Set("X-Mgc-Event-Type", event.Type)
+	req.Header.Set("X-Mgc-Signature", Sign(endpoint.Secret, time.Now().Unix(), body))
+
+	start := time.Now()
+	resp, err := p.client.Do(req)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	delivery.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.Success {
+		delivery.Error = fmt.Sprintf("endpoint responded with status: %s", resp.Status)
+	}
+
+	return delivery
+}
+
+// recordResult resets the failure count of an endpoint after a successful delivery, or increments
+// it after a failed one, disabling the endpoint when the count reaches the configured limit.
+func (p *notificationService) recordResult(endpoint Endpoint, success bool) {
+	if success {
+		if _, err := p.cache.Delete(failuresKey(endpoint.Id)); err != nil {
+			p.logger.Error("Failed to reset notification endpoint failures", zap.String("endpoint_id", endpoint.Id), zap.Error(err))
+		}
+		return
+	}
+
+	failures, err := p.cache.Increment(failuresKey(endpoint.Id), failuresExpiration)
+	if err != nil {
+		p.logger.Error("Failed to count notification endpoint failures", zap.String("endpoint_id", endpoint.Id), zap.Error(err))
+		return
+	}
+
+	if !endpoint.Enabled || failures < int64(p.config.DisableAfter) {
+		return
+	}
+
+	endpoint.Enabled = false
+	endpoint.DisabledAt = time.Now().Format(time.RFC3339)
+	if err := p.setEndpoint(endpoint); err != nil {
+		p.logger.Error("Failed to disable notification endpoint", zap.String("endpoint_id", endpoint.Id), zap.Error(err))
+		return
+	}
+
+	p.logger.Warn("Notification endpoint disabled after repeated failures", zap.String("endpoint_id", endpoint.Id),
+		zap.Int64("consecutive_failures", failures))
+}
+
+// saveDelivery adds a delivery to the logs of its endpoint, keeping the newest ones up to the configured limit.
+func (p *notificationService) saveDelivery(delivery Delivery) {
+	if err := p.cache.AppendCapped(deliveriesKey(delivery.EndpointId), utils.ToJSON(delivery), int64(p.config.MaxDeliveries)); err != nil {
+		p.logger.Error("Failed to store notification delivery", zap.String("endpoint_id", delivery.EndpointId), zap.Error(err))
+	}
+}
+
+func (p *notificationService) getEndpoint(merchantId string, id string) (*Endpoint, error) {
+	c, err := p.cache.HGet(endpointsKey(merchantId), id)
+	if err != nil {
+		if err.Error() == cache.ErrCacheMiss.Error() {
+			return nil, ErrEndpointNotFound
+		}
+		return nil, err
+	}
+
+	var endpoint Endpoint
+	if err := json.Unmarshal(c, &endpoint); err != nil {
+		return nil, err
+	}
+
+	return &endpoint, nil
+}
+
+func (p *notificationService) getEndpoints(merchantId string) ([]Endpoint, error) {
+	values, err := p.cache.HGetAll(endpointsKey(merchantId))
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]Endpoint, 0, len(values))
+	for _, value := range values {
+		var endpoint Endpoint
+		if err := json.Unmarshal(value, &endpoint); err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
+func (p *notificationService) setEndpoint(endpoint Endpoint) error {
+	endpoint.ConsecutiveFailures = 0
+	return p.cache.HSet(endpointsKey(endpoint.MerchantId), endpoint.Id, utils.ToJSON(endpoint))
+}
+
+func (p *notificationService) getFailures(endpointId string) int {
+	c, err := p.cache.Get(failuresKey(endpointId))
+	if err != nil {
+		return 0
+	}
+
+	failures, _ := strconv.Atoi(string(c))
+	return failures
+}
+
+func (p *notificationService) getDeliveries(endpointId string) ([]Delivery, error) {
+	values, err := p.cache.GetList(deliveriesKey(endpointId))
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]Delivery, 0, len(values))
+	for i := len(values) - 1; i >= 0; i-- {
+		var delivery Delivery
+		if err := json.Unmarshal(values[i], &delivery); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func endpointsKey(merchantId string) string {
+	return fmt.Sprintf("%s_%s", cache.NotificationEndpointsKey, merchantId)
+}
+
+func deliveriesKey(endpointId string) string {
+	return fmt.Sprintf("%s_%s", cache.NotificationDeliveriesKey, endpointId)
+}
+
+func failuresKey(endpointId string) string {
+	return fmt.Sprintf("%s_%s", cache.NotificationFailuresKey, endpointId)
+}
+
+func subscribed(endpoint Endpoint, eventType string) bool {
+	for _, subscribedType := range endpoint.EventTypes {
+		if subscribedType == allEvents || subscribedType == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,338 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/queue"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func storeEndpoints(cacheClient cache.CacheClient, endpoints ...Endpoint) {
+	for _, endpoint := range endpoints {
+		cacheClient.HSet(endpointsKey(endpoint.MerchantId), endpoint.Id, utils.ToJSON(endpoint))
+	}
+}
+
+func testConfig() Config {
+	return Config{
+		MaxAttempts:          3,
+		BaseBackoff:          time.Millisecond,
+		Timeout:              time.Second,
+		DisableAfter:         2,
+		MaxDeliveries:        10,
+		AllowPrivateNetworks: true,
+		Consumer:             "consumer1",
+		BatchSize:            10,
+		Block:                10 * time.Millisecond,
+		ReclaimIdle:          time.Minute,
+		ReclaimInterval:      time.Minute,
+	}
+}
+
+func testEndpoint(url string) Endpoint {
+	return Endpoint{
+		Id:         "endpoint1",
+		MerchantId: "merchant1",
+		Url:        url,
+		EventTypes: []string{"transaction.success"},
+		Secret:     "whsec_test",
+		Enabled:    true,
+	}
+}
+
+func TestCreateEndpoint_Success(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, queue.NewMemory(), zap.NewNop(), testConfig())
+
+	// Action
+	endpoint, err := service.CreateEndpoint("merchant1", Endpoint{Url: "https://merchant.test/hook", EventTypes: []string{"*"}})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, endpoint.Id)
+	assert.Equal(t, "merchant1", endpoint.MerchantId)
+	assert.True(t, strings.HasPrefix(endpoint.Secret, "whsec_"))
+	assert.True(t, endpoint.Enabled)
+
+	endpoints, _ := service.GetAllEndpoints("merchant1")
+	assert.Len(t, endpoints, 1)
+
+	others, _ := service.GetAllEndpoints("merchant2")
+	assert.Empty(t, others)
+}
+
+func TestCreateEndpoint_NonPublicAddress(t *testing.T) {
+	config := testConfig()
+	config.AllowPrivateNetworks = false
+	service := New(cache.NewMemory(), queue.NewMemory(), zap.NewNop(), config)
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"Loopback", "http://127.0.0.1:8080/hook"},
+		{"Private", "https://10.0.0.5/hook"},
+		{"Metadata", "http://169.254.169.254/latest/meta-data"},
+		{"IPv6 loopback", "http://[::1]/hook"},
+		{"IPv4-mapped IPv6", "http://[::ffff:127.0.0.1]/hook"},
+		{"Carrier-grade NAT", "http://100.64.0.1/hook"},
+		{"Unsupported scheme", "ftp://8.8.8.8/hook"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Action
+			endpoint, err := service.CreateEndpoint("merchant1", Endpoint{Url: tt.url, EventTypes: []string{"*"}})
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidEndpointUrl)
+			assert.Nil(t, endpoint)
+		})
+	}
+}
+
+func TestSend_NonPublicAddress(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := testConfig()
+	config.AllowPrivateNetworks = false
+	service := New(cache.NewMemory(), queue.NewMemory(), zap.NewNop(), config)
+
+	// Action
+	delivery := service.send(testEndpoint(server.URL), Event{Id: "event1", Type: "transaction.success"}, 1)
+
+	// Assert
+	assert.False(t, delivery.Success)
+	assert.Contains(t, delivery.Error, ErrForbiddenAddress.Error())
+}
+
+func TestGetAllEndpoints_HidesSecret(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, queue.NewMemory(), zap.NewNop(), testConfig())
+	storeEndpoints(cacheClient, testEndpoint("https://merchant.test/hook"))
+
+	// Action
+	endpoints, err := service.GetAllEndpoints("merchant1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, endpoints, 1)
+	assert.Empty(t, endpoints[0].Secret)
+}
+
+func TestDeleteEndpoint_NotFound(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, queue.NewMemory(), zap.NewNop(), testConfig())
+	storeEndpoints(cacheClient, testEndpoint("https://merchant.test/hook"))
+
+	// Action
+	errUnknown := service.DeleteEndpoint("merchant1", "unknown")
+	errOtherMerchant := service.DeleteEndpoint("merchant2", "endpoint1")
+
+	// Assert
+	assert.ErrorIs(t, errUnknown, ErrEndpointNotFound)
+	assert.ErrorIs(t, errOtherMerchant, ErrEndpointNotFound)
+}
+
+func TestSend_SignedDelivery(t *testing.T) {
+	// Arrange
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	service := New(cache.NewMemory(), queue.NewMemory(), zap.NewNop(), testConfig())
+	event := Event{Id: "event1", Type: "transaction.success", Data: json.RawMessage(`{"id":"pi_123"}`)}
+
+	// Action
+	delivery := service.send(testEndpoint(server.URL), event, 1)
+
+	// Assert
+	assert.True(t, delivery.Success)
+	assert.Equal(t, http.StatusOK, delivery.StatusCode)
+	assert.Equal(t, "event1", received.Header.Get("X-Mgc-Event-Id"))
+	assert.Equal(t, "transaction.success", received.Header.Get("X-Mgc-Event-Type"))
+
+	signature := received.Header.Get("X-Mgc-Signature")
+	timestamp, _ := strconv.ParseInt(strings.TrimPrefix(strings.Split(signature, ",")[0], "t="), 10, 64)
+	assert.Equal(t, Sign("whsec_test", timestamp, body), signature)
+}
+
+func TestPublish_DeliversToMerchantEndpoints(t *testing.T) {
+	// Arrange
+	received := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, queue.NewMemory(), zap.NewNop(), testConfig())
+
+	other := testEndpoint(server.URL + "/merchant2")
+	other.Id = "endpoint2"
+	other.MerchantId = "merchant2"
+	storeEndpoints(cacheClient, testEndpoint(server.URL+"/merchant1"), other)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go service.Run(ctx)
+
+	// Action
+	service.Publish("merchant1", "transaction.success", map[string]string{"id": "pi_123"})
+
+	// Assert
+	select {
+	case path := <-received:
+		assert.Equal(t, "/merchant1", path)
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered")
+	}
+
+	select {
+	case path := <-received:
+		t.Fatalf("event delivered to %s", path)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRun_RetriesAndDisablesEndpoint(t *testing.T) {
+	// Arrange
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, queue.NewMemory(), zap.NewNop(), testConfig())
+
+	endpoint := testEndpoint(server.URL)
+	storeEndpoints(cacheClient, endpoint)
+	cacheClient.Set(failuresKey(endpoint.Id), 1, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go service.Run(ctx)
+
+	// Action
+	service.Publish("merchant1", "transaction.success", map[string]string{"id": "pi_123"})
+
+	// Assert
+	assert.Eventually(t, func() bool {
+		stored, _ := service.getEndpoint("merchant1", endpoint.Id)
+		return !stored.Enabled
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(3), attempts.Load())
+
+	deliveries, _ := service.GetAllDeliveries("merchant1", endpoint.Id)
+	assert.Len(t, deliveries, 3)
+	assert.Equal(t, 3, deliveries[0].Attempt)
+
+	endpoints, _ := service.GetAllEndpoints("merchant1")
+	assert.Equal(t, 2, endpoints[0].ConsecutiveFailures)
+	assert.NotEmpty(t, endpoints[0].DisabledAt)
+}
+
+func TestHandle_RemovedEndpoint(t *testing.T) {
+	// Arrange
+	queueClient := queue.NewMemory()
+	queueClient.CreateGroup(queue.NotificationsStream, queue.NotificationsGroup)
+	service := New(cache.NewMemory(), queueClient, zap.NewNop(), testConfig())
+
+	queueClient.Enqueue(queue.NotificationsStream, utils.ToJSON(DeliveryJob{MerchantId: "merchant1", EndpointId: "endpoint1", Attempt: 1}))
+	messages, _ := queueClient.Read(queue.NotificationsStream, queue.NotificationsGroup, "consumer1", 10, 0)
+
+	// Action
+	service.handle(messages[0])
+
+	// Assert
+	reclaimed, _ := queueClient.Reclaim(queue.NotificationsStream, queue.NotificationsGroup, "consumer2", 0, 10)
+	assert.Empty(t, reclaimed)
+}
+
+func TestRedeliver_Success(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, queue.NewMemory(), zap.NewNop(), testConfig())
+
+	endpoint := testEndpoint(server.URL)
+	storeEndpoints(cacheClient, endpoint)
+	cacheClient.Append(deliveriesKey(endpoint.Id), utils.ToJSON(Delivery{Id: "delivery1", EndpointId: endpoint.Id, Event: Event{Id: "event1", Type: "transaction.success"}}))
+
+	// Action
+	delivery, err := service.Redeliver(endpoint.MerchantId, endpoint.Id, "delivery1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, delivery.Success)
+	assert.Equal(t, "event1", delivery.Event.Id)
+	assert.NotEqual(t, "delivery1", delivery.Id)
+}
+
+func TestRedeliver_DeliveryNotFound(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, queue.NewMemory(), zap.NewNop(), testConfig())
+
+	endpoint := testEndpoint("https://merchant.test/hook")
+	storeEndpoints(cacheClient, endpoint)
+
+	// Action
+	delivery, err := service.Redeliver(endpoint.MerchantId, endpoint.Id, "unknown")
+
+	// Assert
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+	assert.Nil(t, delivery)
+}
+
+func TestSubscribed(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventTypes []string
+		eventType  string
+		want       bool
+	}{
+		{"Subscribed event type", []string{"transaction.success"}, "transaction.success", true},
+		{"Not subscribed event type", []string{"transaction.success"}, "transaction.pending", false},
+		{"All events", []string{"*"}, "transaction.pending", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subscribed(Endpoint{EventTypes: tt.eventTypes}, tt.eventType); got != tt.want {
+				t.Errorf("subscribed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package pending
+
+import (
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+type Config struct {
+	Expiry        time.Duration
+	SweepInterval time.Duration
+}
+
+// LoadConfig loads the pending events configuration from the environment variables.
+//
+// Environment Variables:
+//   - PENDING_EVENT_EXPIRY: how long an event for an unknown transaction is kept before it expires, e.g. "1h".
+//   - PENDING_EVENT_SWEEP_INTERVAL: how often parked events are applied to created transactions or expired, e.g. "1m".
+//
+// Returns:
+//   - Config: the pending events configuration.
+func LoadConfig() Config {
+	return Config{
+		Expiry:        utils.GetEnvDuration("PENDING_EVENT_EXPIRY", time.Hour),
+		SweepInterval: utils.GetEnvDuration("PENDING_EVENT_SWEEP_INTERVAL", time.Minute),
+	}
+}
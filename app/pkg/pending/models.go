@@ -0,0 +1,13 @@
+package pending
+
+import "github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+
+type Event struct {
+	TransactionId string       `json:"transaction_id"`
+	Status        string       `json:"status"`
+	OccurredAt    string       `json:"occurred_at"`
+	Code          string       `json:"code,omitempty"`
+	Message       string       `json:"message,omitempty"`
+	ParkedAt      string       `json:"parked_at"`
+	Origin        audit.Origin `json:"origin"`
+}
@@ -0,0 +1,237 @@
+package pending
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// events counts the parked, applied and expired events, published in /debug/vars.
+var events = expvar.NewMap("pending_events")
+
+// ApplyFunc applies the parked events of a transaction, returning false if the transaction does not exist yet.
+type ApplyFunc func(transactionId string, events []Event) (bool, error)
+
+type PendingService interface {
+	Park(events ...Event) error
+	Take(transactionId string) ([]Event, error)
+	Sweep(apply ApplyFunc) error
+	Run(ctx context.Context, apply ApplyFunc)
+}
+
+type pendingService struct {
+	cache  cache.CacheClient
+	logger *zap.Logger
+	config Config
+	now    func() time.Time
+}
+
+// New creates a new instance of pendingService with the provided cache client, logger and configuration.
+// It returns a pointer to the newly created pendingService.
+//
+// Parameters:
+//   - cache: an instance of cache.CacheClient where events for unknown transactions are parked, in a list per
+//     transaction, with an index of the transactions with parked events.
+//   - logger: an instance of zap.Logger used to alert of expired events.
+//   - config: the pending events configuration.
+//
+// Returns:
+//   - *pendingService: a pointer to the newly created pendingService.
+func New(cache cache.CacheClient, logger *zap.Logger, config Config) *pendingService {
+	return &pendingService{
+		cache:  cache,
+		logger: logger,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Park keeps provider events received for transactions that do not exist yet, until the transactions are created.
+// Events already parked keep their original parking time, so re-parked events still expire. The events of a
+// transaction are removed from the cache once the expiry and two sweep intervals have passed since the last of them
+// was parked, leaving the sweeps time to alert of them first.
+//
+// Parameters:
+//   - events: the events to be parked.
+//
+// Returns:
+//   - error: an error if the events could not be parked.
+func (p *pendingService) Park(parked ...Event) error {
+	byTransaction := map[string][]interface{}{}
+	for _, event := range parked {
+		if event.ParkedAt == "" {
+			event.ParkedAt = p.now().Format(time.RFC3339)
+			events.Add("parked", 1)
+		}
+		byTransaction[event.TransactionId] = append(byTransaction[event.TransactionId], utils.ToJSON(event))
+	}
+
+	for transactionId, items := range byTransaction {
+		if err := p.cache.AppendExpiring(eventsKey(transactionId), p.config.Expiry+2*p.config.SweepInterval, items...); err != nil {
+			return err
+		}
+		if err := p.cache.HSet(cache.PendingEventsIndexKey, transactionId, p.now().Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Take removes and returns the events parked for a transaction, ordered by the time they occurred at the provider.
+// The events are removed as they are read, so concurrent calls never take the same event twice, and events of a
+// provider event parked more than once, such as retried deliveries, are returned once.
+//
+// Parameters:
+//   - transactionId: the ID of the transaction.
+//
+// Returns:
+//   - []Event: the parked events, or an empty slice if there are none.
+//   - error: an error if the events could not be retrieved or removed.
+func (p *pendingService) Take(transactionId string) ([]Event, error) {
+	// The index entry is removed first, so an event parked meanwhile either is taken or indexes the transaction again.
+	if _, err := p.cache.HDelete(cache.PendingEventsIndexKey, transactionId); err != nil {
+		return nil, err
+	}
+
+	items, err := p.cache.TakeList(eventsKey(transactionId))
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make([]Event, 0, len(items))
+	for _, item := range items {
+		var event Event
+		if err := json.Unmarshal(item, &event); err != nil {
+			p.logger.Error("Discarding invalid parked event", zap.String("transaction_id", transactionId), zap.Error(err))
+			continue
+		}
+		if isParked(taken, event) {
+			continue
+		}
+		taken = append(taken, event)
+	}
+
+	sort.SliceStable(taken, func(i, j int) bool {
+		return occurredAt(taken[i]).Before(occurredAt(taken[j]))
+	})
+
+	return taken, nil
+}
+
+// Sweep applies the parked events of the transactions created since the events were parked, and discards the events
+// parked for longer than the configured expiry, logging an alert for each of them.
+//
+// Parameters:
+//   - apply: the function applying the parked events of a transaction.
+//
+// Returns:
+//   - error: an error if the transactions with parked events could not be retrieved. Failures of single transactions
+//     are logged.
+func (p *pendingService) Sweep(apply ApplyFunc) error {
+	transactionIds, err := p.cache.HGetAll(cache.PendingEventsIndexKey)
+	if err != nil {
+		return err
+	}
+
+	for transactionId := range transactionIds {
+		taken, err := p.Take(transactionId)
+		if err != nil {
+			p.logger.Error("Failed to take parked events", zap.String("transaction_id", transactionId), zap.Error(err))
+			continue
+		}
+		if len(taken) == 0 {
+			continue
+		}
+
+		applied, err := apply(transactionId, taken)
+		if err != nil {
+			p.logger.Error("Failed to apply parked events", zap.String("transaction_id", transactionId), zap.Error(err))
+		}
+		if applied {
+			events.Add("applied", int64(len(taken)))
+			continue
+		}
+
+		if err := p.Park(p.expire(taken)...); err != nil {
+			p.logger.Error("Failed to park events again", zap.String("transaction_id", transactionId), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// Run sweeps the parked events at the configured interval until the context is canceled.
+//
+// Parameters:
+//   - ctx: the context whose cancellation stops the sweeps.
+//   - apply: the function applying the parked events of a transaction.
+func (p *pendingService) Run(ctx context.Context, apply ApplyFunc) {
+	ticker := time.NewTicker(p.config.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Sweep(apply); err != nil {
+				p.logger.Error("Failed to sweep parked events", zap.Error(err))
+			}
+		}
+	}
+}
+
+// expire returns the events that have not expired, alerting of the expired ones.
+func (p *pendingService) expire(parked []Event) []Event {
+	kept := make([]Event, 0, len(parked))
+	for _, event := range parked {
+		parkedAt, err := time.Parse(time.RFC3339, event.ParkedAt)
+		if err == nil && p.now().Sub(parkedAt) < p.config.Expiry {
+			kept = append(kept, event)
+			continue
+		}
+
+		events.Add("expired", 1)
+		p.logger.Error("ALERT: parked event expired before its transaction was created",
+			zap.Bool("alert", true),
+			zap.String("transaction_id", event.TransactionId),
+			zap.String("status", event.Status),
+			zap.String("occurred_at", event.OccurredAt),
+			zap.String("parked_at", event.ParkedAt),
+			zap.String("actor_id", event.Origin.Actor.Id),
+			zap.String("correlation_id", event.Origin.CorrelationId))
+	}
+	return kept
+}
+
+// isParked reports whether the status of the same provider event is already parked.
+func isParked(parked []Event, event Event) bool {
+	if event.Origin.Actor.Id == "" {
+		return false
+	}
+
+	for _, current := range parked {
+		if current.Origin.Actor == event.Origin.Actor && current.Status == event.Status {
+			return true
+		}
+	}
+	return false
+}
+
+func occurredAt(event Event) time.Time {
+	value, _ := time.Parse(time.RFC3339, event.OccurredAt)
+	return value
+}
+
+// eventsKey returns the cache key of the list of events parked for a transaction.
+func eventsKey(transactionId string) string {
+	return fmt.Sprintf("%s_%s", cache.PendingEventsKey, transactionId)
+}
@@ -0,0 +1,155 @@
+package pending
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/audit"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func testConfig() Config {
+	return Config{
+		Expiry:        time.Hour,
+		SweepInterval: time.Minute,
+	}
+}
+
+func savedEvents(t *testing.T, cacheClient cache.CacheClient, transactionId string) []Event {
+	items, err := cacheClient.GetList(eventsKey(transactionId))
+	assert.NoError(t, err)
+
+	saved := []Event{}
+	for _, item := range items {
+		var event Event
+		assert.NoError(t, json.Unmarshal(item, &event))
+		saved = append(saved, event)
+	}
+	return saved
+}
+
+func storeEvents(cacheClient cache.CacheClient, events ...Event) {
+	for _, event := range events {
+		cacheClient.Append(eventsKey(event.TransactionId), utils.ToJSON(event))
+		cacheClient.HSet(cache.PendingEventsIndexKey, event.TransactionId, event.ParkedAt)
+	}
+}
+
+func TestPark_SetsParkingTime(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, zap.NewNop(), testConfig())
+	service.now = func() time.Time { return time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC) }
+
+	// Action
+	err := service.Park(Event{TransactionId: "transaction1", Status: "success", OccurredAt: "2024-10-01T09:59:00Z"})
+
+	// Assert
+	assert.NoError(t, err)
+	saved := savedEvents(t, cacheClient, "transaction1")
+	assert.Len(t, saved, 1)
+	assert.Equal(t, "2024-10-01T10:00:00Z", saved[0].ParkedAt)
+}
+
+func TestPark_IndexesTransaction(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, zap.NewNop(), testConfig())
+
+	// Action
+	err := service.Park(Event{TransactionId: "transaction1", Status: "success", OccurredAt: "2024-10-01T09:59:00Z"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, savedEvents(t, cacheClient, "transaction1"), 1)
+	index, _ := cacheClient.HGetAll(cache.PendingEventsIndexKey)
+	assert.Contains(t, index, "transaction1")
+}
+
+func TestTake_SkipsEventsParkedTwice(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, zap.NewNop(), testConfig())
+	parked := Event{TransactionId: "transaction1", Status: "success", OccurredAt: "2024-10-01T09:59:00Z", ParkedAt: "2024-10-01T10:00:00Z", Origin: audit.WebhookEvent("evt_123", "")}
+	retried := parked
+	retried.ParkedAt = "2024-10-01T10:05:00Z"
+	storeEvents(cacheClient, parked, retried)
+
+	// Action
+	events, err := service.Take("transaction1")
+	again, errAgain := service.Take("transaction1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []Event{parked}, events)
+	assert.NoError(t, errAgain)
+	assert.Empty(t, again)
+	index, _ := cacheClient.HGetAll(cache.PendingEventsIndexKey)
+	assert.NotContains(t, index, "transaction1")
+}
+
+func TestTake_OrdersByOccurrence(t *testing.T) {
+	// Arrange
+	cacheClient := cache.NewMemory()
+	service := New(cacheClient, zap.NewNop(), testConfig())
+	storeEvents(cacheClient,
+		Event{TransactionId: "transaction1", Status: "success", OccurredAt: "2024-10-01T10:00:05Z"},
+		Event{TransactionId: "transaction1", Status: "created", OccurredAt: "2024-10-01T10:00:00Z"},
+	)
+
+	// Action
+	events, err := service.Take("transaction1")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "created", events[0].Status)
+	assert.Equal(t, "success", events[1].Status)
+	assert.Empty(t, savedEvents(t, cacheClient, "transaction1"))
+}
+
+func TestSweep(t *testing.T) {
+	tests := []struct {
+		name     string
+		applied  bool
+		parkedAt string
+		kept     bool
+	}{
+		{"Applied", true, "2024-10-01T09:30:00Z", false},
+		{"Not applied", false, "2024-10-01T09:30:00Z", true},
+		{"Expired", false, "2024-10-01T08:00:00Z", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			cacheClient := cache.NewMemory()
+			service := New(cacheClient, zap.NewNop(), testConfig())
+			service.now = func() time.Time { return time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC) }
+
+			event := Event{TransactionId: "transaction1", Status: "success", OccurredAt: "2024-10-01T07:59:00Z", ParkedAt: tt.parkedAt}
+			storeEvents(cacheClient, event)
+
+			var applied []Event
+			apply := func(transactionId string, events []Event) (bool, error) {
+				applied = events
+				return tt.applied, nil
+			}
+
+			// Action
+			err := service.Sweep(apply)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, []Event{event}, applied)
+			if tt.kept {
+				assert.Equal(t, []Event{event}, savedEvents(t, cacheClient, "transaction1"))
+			} else {
+				assert.Empty(t, savedEvents(t, cacheClient, "transaction1"))
+			}
+		})
+	}
+}
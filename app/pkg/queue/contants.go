@@ -0,0 +1,9 @@
+package queue
+
+const (
+	PaymentsStream = "payments_stream"
+	PaymentsGroup  = "payment_workers"
+
+	NotificationsStream = "notifications_stream"
+	NotificationsGroup  = "notification_workers"
+)
@@ -0,0 +1,368 @@
+package queue
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+)
+
+type memoryMessage struct {
+	sequence uint64
+	id       string
+	payload  []byte
+}
+
+type memoryPending struct {
+	consumer    string
+	deliveredAt time.Time
+}
+
+type memoryGroup struct {
+	lastDelivered uint64
+	pending       map[string]*memoryPending
+}
+
+type memoryStream struct {
+	messages []memoryMessage
+	groups   map[string]*memoryGroup
+}
+
+type memoryScheduled struct {
+	at      time.Time
+	payload []byte
+}
+
+type memoryClient struct {
+	mutex     sync.Mutex
+	streams   map[string]*memoryStream
+	scheduled map[string][]memoryScheduled
+	sequence  uint64
+	signal    chan struct{}
+	now       func() time.Time
+}
+
+// NewMemory creates a new instance of memoryClient, a QueueClient keeping the messages in the process memory with the
+// semantics of the Redis Streams client: consumer groups, pending messages until acknowledged, reclaims and scheduled
+// messages. It lets a single node run without Redis, e.g. in development and in end-to-end tests. Messages are lost
+// when the process stops, so it must not be used where another process consumes the stream.
+// Returns a pointer to the initialized memoryClient.
+func NewMemory() *memoryClient {
+	return &memoryClient{
+		streams:   map[string]*memoryStream{},
+		scheduled: map[string][]memoryScheduled{},
+		signal:    make(chan struct{}),
+		now:       time.Now,
+	}
+}
+
+// NewClient creates the QueueClient matching the cache driver, so a single node running with the memory cache
+// driver keeps its queues in the process memory as well.
+//
+// Parameters:
+//
+//	config - The cache configuration.
+//
+// Returns:
+//
+//	QueueClient - The queue client, backed by Redis Streams unless the memory cache driver is used.
+func NewClient(config cache.Config) QueueClient {
+	if config.Driver == cache.MemoryDriver {
+		return NewMemory()
+	}
+	return New()
+}
+
+// CreateGroup creates a consumer group reading the stream from its beginning, creating the stream
+// if it does not exist. It does nothing if the group already exists.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	group - The name of the consumer group.
+//
+// Returns:
+//
+//	error - Always nil.
+func (c *memoryClient) CreateGroup(stream string, group string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s := c.stream(stream)
+	if _, exists := s.groups[group]; !exists {
+		s.groups[group] = &memoryGroup{pending: map[string]*memoryPending{}}
+	}
+	return nil
+}
+
+// Enqueue appends a message with the given payload to the stream.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	payload - The payload of the message, a string or a byte slice.
+//
+// Returns:
+//
+//	string - The ID of the message.
+//	error - An error if the payload is neither a string nor a byte slice.
+func (c *memoryClient) Enqueue(stream string, payload interface{}) (string, error) {
+	value, err := toPayload(payload)
+	if err != nil {
+		return "", err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.enqueue(stream, value), nil
+}
+
+// Read reads messages never delivered to the consumer group, waiting until a message arrives or the block
+// duration elapses. Read messages stay pending for the consumer until they are acknowledged.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	group - The name of the consumer group.
+//	consumer - The name of the consumer within the group.
+//	count - The maximum number of messages to read.
+//	block - The maximum time to wait for messages.
+//
+// Returns:
+//
+//	[]Message - The messages read, empty if none arrived in time.
+//	error - An error if the consumer group does not exist.
+func (c *memoryClient) Read(stream string, group string, consumer string, count int64, block time.Duration) ([]Message, error) {
+	timer := time.NewTimer(block)
+	defer timer.Stop()
+
+	for {
+		c.mutex.Lock()
+		messages, err := c.read(stream, group, consumer, count)
+		signal := c.signal
+		c.mutex.Unlock()
+
+		if err != nil || len(messages) > 0 || block <= 0 {
+			return messages, err
+		}
+
+		select {
+		case <-signal:
+		case <-timer.C:
+			return []Message{}, nil
+		}
+	}
+}
+
+// Reclaim transfers to the consumer the messages of the group left pending by other consumers for at least minIdle.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	group - The name of the consumer group.
+//	consumer - The name of the consumer claiming the messages.
+//	minIdle - The minimum time since the messages were last delivered.
+//	count - The maximum number of messages to claim.
+//
+// Returns:
+//
+//	[]Message - The claimed messages.
+//	error - An error if the consumer group does not exist.
+func (c *memoryClient) Reclaim(stream string, group string, consumer string, minIdle time.Duration, count int64) ([]Message, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s, g, err := c.group(stream, group)
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.now()
+	messages := []Message{}
+	for _, message := range s.messages {
+		if int64(len(messages)) >= count {
+			break
+		}
+
+		pending, exists := g.pending[message.id]
+		if !exists || now.Sub(pending.deliveredAt) < minIdle {
+			continue
+		}
+
+		pending.consumer = consumer
+		pending.deliveredAt = now
+		messages = append(messages, message.toMessage())
+	}
+
+	return messages, nil
+}
+
+// Ack acknowledges a message of the consumer group and removes it from the stream.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	group - The name of the consumer group.
+//	id - The ID of the message.
+//
+// Returns:
+//
+//	error - An error if the consumer group does not exist.
+func (c *memoryClient) Ack(stream string, group string, id string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s, g, err := c.group(stream, group)
+	if err != nil {
+		return err
+	}
+
+	delete(g.pending, id)
+	for i, message := range s.messages {
+		if message.id == id {
+			s.messages = append(s.messages[:i:i], s.messages[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Schedule stores a message with the given payload to be appended to the stream at the given time by Promote.
+// A payload already scheduled is only rescheduled.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	payload - The payload of the message, a string or a byte slice.
+//	at - The time from which the message is appended to the stream.
+//
+// Returns:
+//
+//	error - An error if the payload is neither a string nor a byte slice.
+func (c *memoryClient) Schedule(stream string, payload interface{}, at time.Time) error {
+	value, err := toPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	scheduled := c.scheduled[stream]
+	for i, item := range scheduled {
+		if string(item.payload) == string(value) {
+			scheduled = append(scheduled[:i:i], scheduled[i+1:]...)
+			break
+		}
+	}
+
+	scheduled = append(scheduled, memoryScheduled{at: at, payload: value})
+	sort.SliceStable(scheduled, func(i, j int) bool {
+		return scheduled[i].at.Before(scheduled[j].at)
+	})
+	c.scheduled[stream] = scheduled
+	return nil
+}
+
+// Promote appends to the stream the scheduled messages that are due, oldest first.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	now - The current time.
+//	count - The maximum number of messages to promote.
+//
+// Returns:
+//
+//	int - The number of messages appended to the stream.
+//	error - Always nil.
+func (c *memoryClient) Promote(stream string, now time.Time, count int64) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	scheduled := c.scheduled[stream]
+	promoted := 0
+	for promoted < len(scheduled) && int64(promoted) < count && !scheduled[promoted].at.After(now) {
+		c.enqueue(stream, scheduled[promoted].payload)
+		promoted++
+	}
+
+	c.scheduled[stream] = scheduled[promoted:]
+	return promoted, nil
+}
+
+// enqueue appends a message to the stream and wakes up the blocked readers. It must be called holding the mutex.
+func (c *memoryClient) enqueue(stream string, payload []byte) string {
+	c.sequence++
+	id := fmt.Sprintf("%d-%d", c.now().UnixMilli(), c.sequence)
+
+	s := c.stream(stream)
+	s.messages = append(s.messages, memoryMessage{sequence: c.sequence, id: id, payload: payload})
+
+	close(c.signal)
+	c.signal = make(chan struct{})
+	return id
+}
+
+// read delivers to the consumer the messages never delivered to the group. It must be called holding the mutex.
+func (c *memoryClient) read(stream string, group string, consumer string, count int64) ([]Message, error) {
+	s, g, err := c.group(stream, group)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []Message{}
+	for _, message := range s.messages {
+		if int64(len(messages)) >= count {
+			break
+		}
+		if message.sequence <= g.lastDelivered {
+			continue
+		}
+
+		g.lastDelivered = message.sequence
+		g.pending[message.id] = &memoryPending{consumer: consumer, deliveredAt: c.now()}
+		messages = append(messages, message.toMessage())
+	}
+
+	return messages, nil
+}
+
+// stream returns a stream, creating it if it does not exist. It must be called holding the mutex.
+func (c *memoryClient) stream(stream string) *memoryStream {
+	s, exists := c.streams[stream]
+	if !exists {
+		s = &memoryStream{groups: map[string]*memoryGroup{}}
+		c.streams[stream] = s
+	}
+	return s
+}
+
+// group returns a stream and its consumer group, answering a missing group with the same error as Redis.
+// It must be called holding the mutex.
+func (c *memoryClient) group(stream string, group string) (*memoryStream, *memoryGroup, error) {
+	if s, exists := c.streams[stream]; exists {
+		if g, exists := s.groups[group]; exists {
+			return s, g, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("NOGROUP No such key '%s' or consumer group '%s'", stream, group)
+}
+
+func (m memoryMessage) toMessage() Message {
+	return Message{Id: m.id, Payload: append([]byte(nil), m.payload...)}
+}
+
+// toPayload converts a payload to the bytes stored for it.
+func toPayload(payload interface{}) ([]byte, error) {
+	switch value := payload.(type) {
+	case string:
+		return []byte(value), nil
+	case []byte:
+		return append([]byte(nil), value...), nil
+	}
+	return nil, fmt.Errorf("queue: can't store payload of type %T", payload)
+}
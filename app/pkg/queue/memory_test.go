@@ -0,0 +1,132 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMemory() (*memoryClient, *time.Time) {
+	now := time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC)
+	client := NewMemory()
+	client.now = func() time.Time { return now }
+	return client, &now
+}
+
+func TestMemory_EnqueueReadAck(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+	client.CreateGroup("stream1", "group1")
+	client.Enqueue("stream1", "job1")
+	client.Enqueue("stream1", []byte("job2"))
+
+	// Action
+	messages, err := client.Read("stream1", "group1", "consumer1", 10, 0)
+	again, _ := client.Read("stream1", "group1", "consumer1", 10, 0)
+	errAck := client.Ack("stream1", "group1", messages[0].Id)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "job1", string(messages[0].Payload))
+	assert.Equal(t, "job2", string(messages[1].Payload))
+	assert.Empty(t, again)
+	assert.NoError(t, errAck)
+	assert.Len(t, client.streams["stream1"].messages, 1)
+}
+
+func TestMemory_Read_WaitsForMessages(t *testing.T) {
+	// Arrange
+	client := NewMemory()
+	client.CreateGroup("stream1", "group1")
+
+	// Action
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		client.Enqueue("stream1", "job1")
+	}()
+	messages, err := client.Read("stream1", "group1", "consumer1", 10, time.Second)
+	timedOut, errTimedOut := client.Read("stream1", "group1", "consumer1", 10, 10*time.Millisecond)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.NoError(t, errTimedOut)
+	assert.Empty(t, timedOut)
+}
+
+func TestMemory_Read_WithoutGroup(t *testing.T) {
+	// Arrange
+	client, _ := newTestMemory()
+
+	// Action
+	_, err := client.Read("stream1", "group1", "consumer1", 10, 0)
+
+	// Assert
+	assert.ErrorContains(t, err, "NOGROUP")
+}
+
+func TestMemory_Reclaim(t *testing.T) {
+	// Arrange
+	client, now := newTestMemory()
+	client.CreateGroup("stream1", "group1")
+	client.Enqueue("stream1", "job1")
+	client.Read("stream1", "group1", "consumer1", 10, 0)
+
+	// Action
+	early, _ := client.Reclaim("stream1", "group1", "consumer2", time.Minute, 10)
+	*now = now.Add(time.Minute)
+	claimed, err := client.Reclaim("stream1", "group1", "consumer2", time.Minute, 10)
+
+	// Assert
+	assert.Empty(t, early)
+	assert.NoError(t, err)
+	assert.Len(t, claimed, 1)
+	assert.Equal(t, "consumer2", client.streams["stream1"].groups["group1"].pending[claimed[0].Id].consumer)
+}
+
+func TestMemory_SchedulePromote(t *testing.T) {
+	// Arrange
+	client, now := newTestMemory()
+	client.CreateGroup("stream1", "group1")
+	client.Schedule("stream1", "job2", now.Add(2*time.Second))
+	client.Schedule("stream1", "job1", now.Add(time.Second))
+
+	// Action
+	none, _ := client.Promote("stream1", *now, 10)
+	promoted, err := client.Promote("stream1", now.Add(2*time.Second), 10)
+	messages, _ := client.Read("stream1", "group1", "consumer1", 10, 0)
+
+	// Assert
+	assert.Equal(t, 0, none)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, promoted)
+	assert.Equal(t, "job1", string(messages[0].Payload))
+	assert.Equal(t, "job2", string(messages[1].Payload))
+	assert.Empty(t, client.scheduled["stream1"])
+}
+
+func TestMemory_UnsupportedPayload(t *testing.T) {
+	// Arrange
+	client, now := newTestMemory()
+
+	// Action
+	_, errEnqueue := client.Enqueue("stream1", struct{}{})
+	errSchedule := client.Schedule("stream1", 1, *now)
+
+	// Assert
+	assert.Error(t, errEnqueue)
+	assert.Error(t, errSchedule)
+}
+
+func TestNewClient(t *testing.T) {
+	// Action
+	memory := NewClient(cache.Config{Driver: cache.MemoryDriver})
+	redis := NewClient(cache.Config{Driver: cache.RedisDriver})
+
+	// Assert
+	assert.IsType(t, &memoryClient{}, memory)
+	assert.IsType(t, &queueClient{}, redis)
+}
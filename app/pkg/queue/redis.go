@@ -0,0 +1,241 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const payloadField = "payload"
+
+// promoteScript moves the scheduled messages that are due from the sorted set of the stream to the stream itself,
+// so a message is never lost nor promoted twice between the two writes.
+var promoteScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, payload in ipairs(due) do
+	redis.call('XADD', KEYS[2], '*', ARGV[3], payload)
+	redis.call('ZREM', KEYS[1], payload)
+end
+return #due
+`)
+
+type Message struct {
+	Id      string
+	Payload []byte
+}
+
+type QueueClient interface {
+	CreateGroup(stream string, group string) error
+	Enqueue(stream string, payload interface{}) (string, error)
+	Read(stream string, group string, consumer string, count int64, block time.Duration) ([]Message, error)
+	Reclaim(stream string, group string, consumer string, minIdle time.Duration, count int64) ([]Message, error)
+	Ack(stream string, group string, id string) error
+	Schedule(stream string, payload interface{}, at time.Time) error
+	Promote(stream string, now time.Time, count int64) (int, error)
+}
+
+type queueClient struct {
+	queue   *redis.Client
+	context context.Context
+}
+
+// New creates a new instance of queueClient backed by Redis Streams.
+// It initializes the Redis client using the address and password
+// from the environment variables REDIS_HOST_ADDRESS and REDIS_HOST_PASSWORD.
+// Returns a pointer to the initialized queueClient.
+func New() *queueClient {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:6379", os.Getenv("REDIS_HOST_ADDRESS")),
+		Password: os.Getenv("REDIS_HOST_PASSWORD"),
+		DB:       0,
+	})
+
+	return &queueClient{
+		context: context.Background(),
+		queue:   rdb,
+	}
+}
+
+// CreateGroup creates a consumer group reading the stream from its beginning, creating the stream
+// if it does not exist. It does nothing if the group already exists.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	group - The name of the consumer group.
+//
+// Returns:
+//
+//	error - An error if the group cannot be created.
+func (c *queueClient) CreateGroup(stream string, group string) error {
+	err := c.queue.XGroupCreateMkStream(c.context, stream, group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// Enqueue appends a message with the given payload to the stream.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	payload - The payload of the message.
+//
+// Returns:
+//
+//	string - The ID of the message.
+//	error - An error if the message cannot be appended.
+func (c *queueClient) Enqueue(stream string, payload interface{}) (string, error) {
+	return c.queue.XAdd(c.context, &redis.XAddArgs{
+		Stream: stream,
+		Values: map[string]interface{}{payloadField: payload},
+	}).Result()
+}
+
+// Read reads messages never delivered to the consumer group, blocking until a message arrives or the block
+// duration elapses. Read messages stay pending for the consumer until they are acknowledged.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	group - The name of the consumer group.
+//	consumer - The name of the consumer within the group.
+//	count - The maximum number of messages to read.
+//	block - The maximum time to wait for messages.
+//
+// Returns:
+//
+//	[]Message - The messages read, empty if none arrived in time.
+//	error - An error if the messages cannot be read.
+func (c *queueClient) Read(stream string, group string, consumer string, count int64, block time.Duration) ([]Message, error) {
+	streams, err := c.queue.XReadGroup(c.context, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+
+	if errors.Is(err, redis.Nil) {
+		return []Message{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []Message{}
+	for _, s := range streams {
+		messages = append(messages, toMessages(s.Messages)...)
+	}
+	return messages, nil
+}
+
+// Reclaim transfers to the consumer the messages of the group left pending by other consumers for at least minIdle,
+// such as messages of a consumer that stopped before acknowledging them.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	group - The name of the consumer group.
+//	consumer - The name of the consumer claiming the messages.
+//	minIdle - The minimum time since the messages were last delivered.
+//	count - The maximum number of messages to claim.
+//
+// Returns:
+//
+//	[]Message - The claimed messages.
+//	error - An error if the messages cannot be claimed.
+func (c *queueClient) Reclaim(stream string, group string, consumer string, minIdle time.Duration, count int64) ([]Message, error) {
+	messages, _, err := c.queue.XAutoClaim(c.context, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    "0-0",
+		Count:    count,
+	}).Result()
+
+	if err != nil {
+		return nil, err
+	}
+	return toMessages(messages), nil
+}
+
+// Ack acknowledges a message of the consumer group and removes it from the stream,
+// so payloads are not kept once processed.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	group - The name of the consumer group.
+//	id - The ID of the message.
+//
+// Returns:
+//
+//	error - An error if the message cannot be acknowledged.
+func (c *queueClient) Ack(stream string, group string, id string) error {
+	pipe := c.queue.TxPipeline()
+	pipe.XAck(c.context, stream, group, id)
+	pipe.XDel(c.context, stream, id)
+
+	_, err := pipe.Exec(c.context)
+	return err
+}
+
+// Schedule stores a message with the given payload to be appended to the stream at the given time by Promote,
+// such as a retry waiting for its backoff. Scheduled messages are kept in Redis, so they survive restarts.
+// Payloads must be unique, as a payload already scheduled is only rescheduled.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	payload - The payload of the message.
+//	at - The time from which the message is appended to the stream.
+//
+// Returns:
+//
+//	error - An error if the message cannot be scheduled.
+func (c *queueClient) Schedule(stream string, payload interface{}, at time.Time) error {
+	return c.queue.ZAdd(c.context, scheduledKey(stream), redis.Z{Score: float64(at.UnixMilli()), Member: payload}).Err()
+}
+
+// Promote appends to the stream the scheduled messages that are due, oldest first.
+//
+// Parameters:
+//
+//	stream - The name of the stream.
+//	now - The current time.
+//	count - The maximum number of messages to promote.
+//
+// Returns:
+//
+//	int - The number of messages appended to the stream.
+//	error - An error if the messages cannot be promoted.
+func (c *queueClient) Promote(stream string, now time.Time, count int64) (int, error) {
+	promoted, err := promoteScript.Run(c.context, c.queue, []string{scheduledKey(stream), stream}, now.UnixMilli(), count, payloadField).Int()
+	if err != nil {
+		return 0, err
+	}
+	return promoted, nil
+}
+
+// scheduledKey returns the key of the sorted set keeping the scheduled messages of a stream.
+func scheduledKey(stream string) string {
+	return fmt.Sprintf("%s_scheduled", stream)
+}
+
+// toMessages converts Redis stream messages to queue messages.
+func toMessages(values []redis.XMessage) []Message {
+	messages := make([]Message, 0, len(values))
+	for _, value := range values {
+		payload, _ := value.Values[payloadField].(string)
+		messages = append(messages, Message{Id: value.ID, Payload: []byte(payload)})
+	}
+	return messages
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetEnvString returns the value of the environment variable, or the default value when it is not set or is blank.
+//
+// Parameters:
+//   - key: the name of the environment variable.
+//   - valueDefault: the value returned when the variable is not set.
+//
+// Returns:
+//   - string: the value of the variable or the default value.
+func GetEnvString(key string, valueDefault string) string {
+	value := os.Getenv(key)
+	if IsEmptyOrNull(value) {
+		return valueDefault
+	}
+	return value
+}
+
+// GetEnvInt returns the value of the environment variable as a positive integer, or the default value when it is
+// not set, is not an integer or is not positive.
+//
+// Parameters:
+//   - key: the name of the environment variable.
+//   - valueDefault: the value returned when the variable is not set or is invalid.
+//
+// Returns:
+//   - int: the value of the variable or the default value.
+func GetEnvInt(key string, valueDefault int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return valueDefault
+	}
+	return value
+}
+
+// GetEnvDuration returns the value of the environment variable as a positive duration, e.g. "5m", or the default
+// value when it is not set, is not a duration or is not positive.
+//
+// Parameters:
+//   - key: the name of the environment variable.
+//   - valueDefault: the value returned when the variable is not set or is invalid.
+//
+// Returns:
+//   - time.Duration: the value of the variable or the default value.
+func GetEnvDuration(key string, valueDefault time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return valueDefault
+	}
+	return value
+}
+
+// GetEnvList returns the trimmed, non-blank items of the comma-separated environment variable, or the default value
+// when it is not set.
+//
+// Parameters:
+//   - key: the name of the environment variable.
+//   - valueDefault: the value returned when the variable is not set.
+//
+// Returns:
+//   - []string: the items of the variable or the default value.
+func GetEnvList(key string, valueDefault []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return valueDefault
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,38 @@
+package utils_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+)
+
+func TestGetEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		got      func() interface{}
+		expected interface{}
+	}{
+		{"String", "value", func() interface{} { return utils.GetEnvString("TEST_ENV", "default") }, "value"},
+		{"Blank string", "  ", func() interface{} { return utils.GetEnvString("TEST_ENV", "default") }, "default"},
+		{"Int", "42", func() interface{} { return utils.GetEnvInt("TEST_ENV", 7) }, 42},
+		{"Invalid int", "forty", func() interface{} { return utils.GetEnvInt("TEST_ENV", 7) }, 7},
+		{"Non-positive int", "0", func() interface{} { return utils.GetEnvInt("TEST_ENV", 7) }, 7},
+		{"Duration", "90s", func() interface{} { return utils.GetEnvDuration("TEST_ENV", time.Minute) }, 90 * time.Second},
+		{"Invalid duration", "soon", func() interface{} { return utils.GetEnvDuration("TEST_ENV", time.Minute) }, time.Minute},
+		{"List", " a, b ,,c ", func() interface{} { return utils.GetEnvList("TEST_ENV", []string{"d"}) }, []string{"a", "b", "c"}},
+		{"Unset list", "", func() interface{} { return utils.GetEnvList("TEST_ENV", []string{"d"}) }, []string{"d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_ENV", tt.value)
+
+			if got := tt.got(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("GetEnv() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package utils
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -70,6 +74,38 @@ func GenerateGUID() string {
 	return uuid.New().String()
 }
 
+// Hash returns the hex-encoded SHA-256 hash of the given value.
+// It is used to build cache keys from sensitive values, such as card numbers and API keys,
+// without storing the values themselves.
+func Hash(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+// Fingerprint returns the hex-encoded HMAC-SHA256 of the given value keyed with a server secret.
+// Unlike Hash, it cannot be reversed by hashing every candidate value, so it is used to build cache keys
+// from values with a small space of candidates, such as card numbers.
+func Fingerprint(secret []byte, value string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// LoadFingerprintSecret returns the secret of the card fingerprints, read from the environment variable
+// CARD_FINGERPRINT_SECRET. Every replica must share the same secret so they compute the same fingerprints.
+//
+// Returns:
+//   - []byte: the fingerprint secret.
+//   - error: an error if the variable is not set.
+func LoadFingerprintSecret() ([]byte, error) {
+	secret := os.Getenv("CARD_FINGERPRINT_SECRET")
+	if IsEmptyOrNull(secret) {
+		return nil, errors.New("missing required environment variable: CARD_FINGERPRINT_SECRET")
+	}
+
+	return []byte(secret), nil
+}
+
 // ToJSONReader converts a given payload to a JSON-encoded io.Reader.
 // It takes an interface{} as input, marshals it into JSON, and returns
 // an io.Reader containing the JSON data.
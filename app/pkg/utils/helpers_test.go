@@ -102,6 +102,50 @@ func TestGenerateGUID(t *testing.T) {
 	}
 }
 
+func TestHash(t *testing.T) {
+	hash := utils.Hash("4242424242424242")
+
+	if len(hash) != 64 {
+		t.Errorf("Hash() returned %d characters, want 64", len(hash))
+	}
+
+	if hash != utils.Hash("4242424242424242") {
+		t.Errorf("Hash() is not deterministic")
+	}
+
+	if hash == utils.Hash("5555555555554444") {
+		t.Errorf("Hash() returned the same hash for different values")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	fingerprint := utils.Fingerprint([]byte("secret"), "4242424242424242")
+
+	if fingerprint == utils.Hash("4242424242424242") {
+		t.Errorf("Fingerprint() returned the unkeyed hash of the value")
+	}
+
+	if fingerprint != utils.Fingerprint([]byte("secret"), "4242424242424242") {
+		t.Errorf("Fingerprint() is not deterministic")
+	}
+
+	if fingerprint == utils.Fingerprint([]byte("other"), "4242424242424242") {
+		t.Errorf("Fingerprint() returned the same fingerprint for different secrets")
+	}
+}
+
+func TestLoadFingerprintSecret(t *testing.T) {
+	t.Setenv("CARD_FINGERPRINT_SECRET", "")
+	if _, err := utils.LoadFingerprintSecret(); err == nil {
+		t.Errorf("LoadFingerprintSecret() returned no error without a secret")
+	}
+
+	t.Setenv("CARD_FINGERPRINT_SECRET", "secret")
+	if secret, err := utils.LoadFingerprintSecret(); err != nil || string(secret) != "secret" {
+		t.Errorf("LoadFingerprintSecret() = %s, %v, want secret", secret, err)
+	}
+}
+
 func TestToJSONReader(t *testing.T) {
 	tests := []struct {
 		name     string
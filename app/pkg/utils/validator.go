@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/iso4217"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
@@ -79,6 +80,45 @@ var cardExpirate validator.Func = func(fl validator.FieldLevel) bool {
 	return true
 }
 
+// iso4217Code validates that a field is the alphabetic code of an active ISO 4217 currency, in upper case.
+// It replaces the built-in iso4217 validation of the validator, so the codes accepted are the ones of the registry.
+var iso4217Code validator.Func = func(fl validator.FieldLevel) bool {
+	value, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	return iso4217.IsValid(value)
+}
+
+// iso4217AnyCase validates that a field is the alphabetic code of an active ISO 4217 currency, in any case.
+// Callers are expected to normalize the value to upper case once it is bound.
+var iso4217AnyCase validator.Func = func(fl validator.FieldLevel) bool {
+	value, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	return iso4217.IsValid(strings.ToUpper(value))
+}
+
+// iso4217List validates that a field is a comma-separated list of alphabetic codes of active ISO 4217 currencies,
+// in any case and with optional spaces around the codes. Callers are expected to split and normalize the codes.
+var iso4217List validator.Func = func(fl validator.FieldLevel) bool {
+	value, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	for _, code := range strings.Split(value, ",") {
+		if !iso4217.IsValid(strings.ToUpper(strings.TrimSpace(code))) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // init initializes the validator engine with English translations.
 // It sets up the translation system using the "en" locale and registers
 // the default translations for the validator.
@@ -105,6 +145,30 @@ func init() {
 			t, _ := ut.T("cexpirate", fe.Field())
 			return t
 		})
+
+		value.RegisterValidation("iso4217", iso4217Code)
+		value.RegisterTranslation("iso4217", transl, func(ut ut.Translator) error {
+			return ut.Add("iso4217", "{0} must be a valid ISO 4217 currency code", true)
+		}, func(ut ut.Translator, fe validator.FieldError) string {
+			t, _ := ut.T("iso4217", fe.Field())
+			return t
+		})
+
+		value.RegisterValidation("iso4217_anycase", iso4217AnyCase)
+		value.RegisterTranslation("iso4217_anycase", transl, func(ut ut.Translator) error {
+			return ut.Add("iso4217_anycase", "{0} must be a valid ISO 4217 currency code", true)
+		}, func(ut ut.Translator, fe validator.FieldError) string {
+			t, _ := ut.T("iso4217_anycase", fe.Field())
+			return t
+		})
+
+		value.RegisterValidation("iso4217_list", iso4217List)
+		value.RegisterTranslation("iso4217_list", transl, func(ut ut.Translator) error {
+			return ut.Add("iso4217_list", "{0} must be a comma-separated list of valid ISO 4217 currency codes", true)
+		}, func(ut ut.Translator, fe validator.FieldError) string {
+			t, _ := ut.T("iso4217_list", fe.Field())
+			return t
+		})
 	}
 }
 
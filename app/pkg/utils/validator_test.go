@@ -0,0 +1,104 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/CarlosSoaresDev/magalu-cloud-challenge/pkg/utils"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestISO4217Validation(t *testing.T) {
+	type payload struct {
+		Currency string `json:"currency" binding:"required,iso4217"`
+	}
+
+	tests := []struct {
+		name     string
+		currency string
+		valid    bool
+	}{
+		{"Active currency", "BRL", true},
+		{"Lower case", "brl", false},
+		{"Unknown code", "XYZ", false},
+		{"Withdrawn currency", "HRK", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Action
+			err := binding.Validator.ValidateStruct(payload{Currency: tt.currency})
+
+			// Assert
+			if tt.valid {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, []utils.Errors{{Field: "payload.currency", Message: "Currency must be a valid ISO 4217 currency code"}}, utils.ValidatorError(err))
+		})
+	}
+}
+
+func TestISO4217AnyCaseValidation(t *testing.T) {
+	type payload struct {
+		Currency string `json:"currency" binding:"required,iso4217_anycase"`
+	}
+
+	tests := []struct {
+		name     string
+		currency string
+		valid    bool
+	}{
+		{"Upper case", "BRL", true},
+		{"Lower case", "brl", true},
+		{"Mixed case", "Usd", true},
+		{"Unknown code", "xyz", false},
+		{"Withdrawn currency", "hrk", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Action
+			err := binding.Validator.ValidateStruct(payload{Currency: tt.currency})
+
+			// Assert
+			if tt.valid {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, []utils.Errors{{Field: "payload.currency", Message: "Currency must be a valid ISO 4217 currency code"}}, utils.ValidatorError(err))
+		})
+	}
+}
+
+func TestISO4217ListValidation(t *testing.T) {
+	type query struct {
+		Symbols string `form:"symbols" binding:"required,iso4217_list"`
+	}
+
+	tests := []struct {
+		name    string
+		symbols string
+		valid   bool
+	}{
+		{"Single code", "BRL", true},
+		{"Codes with spaces and any case", " brl, Eur ,USD", true},
+		{"Unknown code", "BRL,XYZ", false},
+		{"Empty code", "BRL,,EUR", false},
+		{"Trailing comma", "BRL,", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Action
+			err := binding.Validator.ValidateStruct(query{Symbols: tt.symbols})
+
+			// Assert
+			if tt.valid {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, []utils.Errors{{Field: "query.symbols", Message: "Symbols must be a comma-separated list of valid ISO 4217 currency codes"}}, utils.ValidatorError(err))
+		})
+	}
+}